@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	appbot "github.com/ShenokZlob/collector-ouphe/bot-service/internal/app/bot"
+	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/config"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/redis/go-redis/v9"
@@ -16,21 +18,31 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	log, err := logger.NewZapLogger(false)
+	cfg, err := config.Load()
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	log, err := logger.New(cfg.Logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 	defer log.Sync()
 
 	log.Info("Init Redis...")
 	redisClient := redis.NewClient(&redis.Options{
-		Addr: os.Getenv("REDIS_ADDR"),
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
 	})
 
 	log.Info("Init bot...")
-	app, err := appbot.NewAppBot(os.Getenv("BOT_TOKEN"), os.Getenv("COLLECTOR_URL"), log, redisClient)
+	app, err := appbot.NewAppBot(cfg, log, redisClient)
 	if err != nil {
 		log.Error("Failed to create app bot", logger.Error(err))
+		log.Sync()
+		os.Exit(1)
 	}
 
 	log.Info("Runing app...")
@@ -3,6 +3,7 @@ package appbot
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/app"
 	authHandler "github.com/ShenokZlob/collector-ouphe/bot-service/internal/auth/handler"
@@ -11,8 +12,12 @@ import (
 	cardsearchUsecase "github.com/ShenokZlob/collector-ouphe/bot-service/internal/cardsearch/usecase"
 	collectionHandler "github.com/ShenokZlob/collector-ouphe/bot-service/internal/collection/handler"
 	collectionUsecase "github.com/ShenokZlob/collector-ouphe/bot-service/internal/collection/usecase"
+	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/config"
 	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/session"
+	valueHandler "github.com/ShenokZlob/collector-ouphe/bot-service/internal/value/handler"
+	valueUsecase "github.com/ShenokZlob/collector-ouphe/bot-service/internal/value/usecase"
 	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -26,16 +31,24 @@ type AppBot struct {
 	f   *fsm.FSM
 }
 
-func NewAppBot(token string, collectorURL string, log logger.Logger, redisClient *redis.Client) (*AppBot, error) {
+func NewAppBot(cfg *config.Config, log logger.Logger, redisClient *redis.Client) (*AppBot, error) {
 	appbot := &AppBot{}
 
 	// Initialize other dependencies
 	appbot.log = log
 	cache := app.InitCache(redisClient)
-	collectorClient := collectorclient.NewHTTPCollectorClient(collectorURL, log)
+	clientOpts := []collectorclient.Option{
+		collectorclient.WithTimeout(cfg.Collector.Timeout),
+		collectorclient.WithRetry(cfg.Collector.RetryAttempts, 200*time.Millisecond, 2*time.Second),
+		collectorclient.WithCircuitBreaker(cfg.Collector.BreakerThreshold, cfg.Collector.BreakerCooldown),
+	}
 
 	// Auth
-	authUse := authUsecase.NewAuthUsecase(log, collectorClient, cache)
+	// The auth usecase renews expired tokens for the main client, so it gets its own one.
+	authClient := collectorclient.NewHTTPCollectorClient(cfg.CollectorURL, log, clientOpts...)
+	authUse := authUsecase.NewAuthUsecase(log, authClient, cache)
+	collectorClient := collectorclient.NewHTTPCollectorClient(cfg.CollectorURL, log,
+		append(clientOpts, collectorclient.WithTokenSource(authUse), collectorclient.WithChangeSource(cards.ChangeSourceBot))...)
 	authHand := authHandler.NewAuthHandler(authUse, log)
 
 	// Collection
@@ -46,6 +59,10 @@ func NewAppBot(token string, collectorURL string, log logger.Logger, redisClient
 	csUse := cardsearchUsecase.NewCardSearchUsecaseImpl(log)
 	csHand := cardsearchHandler.NewCardSearchHandler(log, csUse)
 
+	// Value
+	valUse := valueUsecase.NewValueUsecaseImpl(log, collectorClient)
+	valHand := valueHandler.NewValueHandler(log, valUse)
+
 	// Init FSM and its callbacks
 	appbot.log.Info("Initializing FSM")
 	appbot.f = fsm.New(session.StateDefault, map[fsm.StateID]fsm.Callback{})
@@ -67,7 +84,7 @@ func NewAppBot(token string, collectorURL string, log logger.Logger, redisClient
 
 	// Initialize bot
 	var err error
-	appbot.b, err = bot.New(token, opts...)
+	appbot.b, err = bot.New(cfg.BotToken, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +96,7 @@ func NewAppBot(token string, collectorURL string, log logger.Logger, redisClient
 		{Command: "collection_new", Description: "Create new collection /command <name>"},
 		{Command: "collection_rename", Description: "Rename collection /command <old name> <new name>"},
 		{Command: "collection_delete", Description: "Delete collection /command <name>"},
+		{Command: "value", Description: "Value history chart /command [day|week|month] [collection name]"},
 		{Command: "register", Description: "Register your account"},
 		{Command: "help", Description: "Help"},
 	}
@@ -104,6 +122,9 @@ func NewAppBot(token string, collectorURL string, log logger.Logger, redisClient
 	// Card Search
 	appbot.b.RegisterHandler(bot.HandlerTypeMessageText, "search", bot.MatchTypeCommand, csHand.HandleSearchCommand)
 
+	// Value
+	appbot.b.RegisterHandler(bot.HandlerTypeMessageText, "value", bot.MatchTypeCommand, valHand.ValueHistoryCommand)
+
 	return appbot, nil
 }
 
@@ -15,8 +15,8 @@ type AuthHandler struct {
 }
 
 type AuthUsecase interface {
-	RegisterUser(dto.UserInfo) (string, error)
-	IsRegistered(telegramID int64) (string, bool)
+	RegisterUser(ctx context.Context, user dto.UserInfo) (string, error)
+	IsRegistered(ctx context.Context, telegramID int64) (string, bool)
 }
 
 func NewAuthHandler(usecase AuthUsecase, log logger.Logger) *AuthHandler {
@@ -28,7 +28,7 @@ func NewAuthHandler(usecase AuthUsecase, log logger.Logger) *AuthHandler {
 
 // HandleRegister handles the registration of a new user.
 func (h *AuthHandler) HandleRegister(ctx context.Context, b *bot.Bot, update *models.Update) {
-	token, err := h.usecase.RegisterUser(dto.UserInfo{
+	token, err := h.usecase.RegisterUser(ctx, dto.UserInfo{
 		TelegramID: update.Message.From.ID,
 		FirstName:  update.Message.From.FirstName,
 		LastName:   update.Message.From.LastName,
@@ -16,7 +16,7 @@ func (h *AuthHandler) RegistrationMiddleware(next bot.HandlerFunc) bot.HandlerFu
 			return
 		}
 
-		token, isReg := h.usecase.IsRegistered(update.Message.From.ID)
+		token, isReg := h.usecase.IsRegistered(ctx, update.Message.From.ID)
 
 		// Command /register
 		if update.Message.Text == "/register" {
@@ -33,7 +33,7 @@ func (h *AuthHandler) RegistrationMiddleware(next bot.HandlerFunc) bot.HandlerFu
 
 		// All other commands
 		if !isReg {
-			h.log.Info("User not registered", logger.Int("user_id", int(update.Message.From.ID)))
+			h.log.Info("User not registered", logger.Int64("user_id", update.Message.From.ID))
 			b.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID: update.Message.Chat.ID,
 				Text:   "Вы не зарегистрированы. Пожалуйста, используйте команду /register.",
@@ -42,6 +42,7 @@ func (h *AuthHandler) RegistrationMiddleware(next bot.HandlerFunc) bot.HandlerFu
 		}
 
 		ctx = authctx.WithJWT(ctx, token)
+		ctx = authctx.WithTelegramID(ctx, update.Message.From.ID)
 
 		next(ctx, b, update)
 	}
@@ -6,19 +6,29 @@ import (
 
 	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/auth/dto"
 	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/session"
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
 	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
 
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 )
 
+// Cache keeps the tokens of the users by telegram ID.
+// It is implemented by *session.Cache.
+type Cache interface {
+	Set(ctx context.Context, key string, value interface{}) error
+	Get(ctx context.Context, key string) (string, error)
+}
+
+var _ Cache = (*session.Cache)(nil)
+
 type authUsecaseImpl struct {
 	log             logger.Logger
 	collectorClient collectorclient.CollectorClientAuth
-	cache           *session.Cache
+	cache           Cache
 }
 
-func NewAuthUsecase(log logger.Logger, client collectorclient.CollectorClientAuth, cache *session.Cache) *authUsecaseImpl {
+func NewAuthUsecase(log logger.Logger, client collectorclient.CollectorClientAuth, cache Cache) *authUsecaseImpl {
 	return &authUsecaseImpl{
 		log:             log,
 		collectorClient: client,
@@ -30,10 +40,10 @@ func NewAuthUsecase(log logger.Logger, client collectorclient.CollectorClientAut
 // It returns the token if the registration is successful.
 // If the user is already registered, it returns an empty string and nil error.
 // If an error occurs during registration, it returns an empty string and the error.
-func (a *authUsecaseImpl) RegisterUser(user dto.UserInfo) (string, error) {
-	a.log.Info("Registering user", logger.String("method", "RegisterUser"), logger.String("telegram_id", fmt.Sprint(user.TelegramID)))
+func (a *authUsecaseImpl) RegisterUser(ctx context.Context, user dto.UserInfo) (string, error) {
+	a.log.Info("Registering user", logger.String("method", "RegisterUser"), logger.Int64("telegram_id", user.TelegramID))
 
-	reqData, err := a.collectorClient.RegisterUser(&auth.RegisterRequest{
+	reqData, err := a.collectorClient.RegisterUser(ctx, &auth.RegisterRequest{
 		TelegramID: user.TelegramID,
 		Username:   user.Username,
 		FirstName:  user.FirstName,
@@ -44,22 +54,22 @@ func (a *authUsecaseImpl) RegisterUser(user dto.UserInfo) (string, error) {
 		return "", err
 	}
 
-	a.log.Info("Save new user in cache", logger.String("telegram_id", fmt.Sprint(user.TelegramID)))
-	a.cache.Set(context.TODO(), fmt.Sprint(user.TelegramID), reqData.Token)
+	a.log.Info("Save new user in cache", logger.Int64("telegram_id", user.TelegramID))
+	a.cache.Set(ctx, fmt.Sprint(user.TelegramID), reqData.Token)
 
 	return reqData.Token, nil
 }
 
 // IsRegistered return token JWT (if it exist)
-func (a *authUsecaseImpl) IsRegistered(telegramID int64) (string, bool) {
+func (a *authUsecaseImpl) IsRegistered(ctx context.Context, telegramID int64) (string, bool) {
 	// Check in the cache (Redis)
-	value, err := a.checkInCache(fmt.Sprint(telegramID))
+	value, err := a.checkInCache(ctx, fmt.Sprint(telegramID))
 	if err == nil {
 		return value, true
 	}
 
 	// Check in the collector service
-	respData, err := a.collectorClient.CheckUser(&auth.CheckUserRequest{
+	respData, err := a.collectorClient.CheckUser(ctx, &auth.CheckUserRequest{
 		TelegramID: telegramID,
 	})
 	if err != nil {
@@ -69,16 +79,59 @@ func (a *authUsecaseImpl) IsRegistered(telegramID int64) (string, bool) {
 
 	// If the user is registered, save the token in the cache
 	if respData.Success {
-		a.log.Info("User found in collector service", logger.String("telegram_id", fmt.Sprint(telegramID)))
-		a.cache.Set(context.TODO(), fmt.Sprint(telegramID), respData.Token)
+		a.log.Info("User found in collector service", logger.Int64("telegram_id", telegramID))
+		a.cache.Set(ctx, fmt.Sprint(telegramID), respData.Token)
 	}
 
 	return respData.Token, respData.Success
 }
 
-func (a *authUsecaseImpl) checkInCache(telegramID string) (string, error) {
+// Token implements collectorclient.TokenSource.
+// It prefers the token put in ctx by the registration middleware and falls back to the cache.
+func (a *authUsecaseImpl) Token(ctx context.Context) (string, error) {
+	if token, ok := authctx.GetJWT(ctx); ok && token != "" {
+		return token, nil
+	}
+
+	telegramID, ok := authctx.GetTelegramID(ctx)
+	if !ok {
+		return "", collectorclient.ErrMissingToken
+	}
+	token, err := a.checkInCache(ctx, fmt.Sprint(telegramID))
+	if err != nil {
+		return "", collectorclient.ErrMissingToken
+	}
+	return token, nil
+}
+
+// Refresh implements collectorclient.TokenSource.
+// It asks the collector service for a new token of the user in ctx and updates the cache.
+func (a *authUsecaseImpl) Refresh(ctx context.Context) (string, error) {
+	telegramID, ok := authctx.GetTelegramID(ctx)
+	if !ok {
+		return "", collectorclient.ErrMissingToken
+	}
+
+	a.log.Info("Refreshing token", logger.Int64("telegram_id", telegramID))
+	respData, err := a.collectorClient.CheckUser(ctx, &auth.CheckUserRequest{
+		TelegramID: telegramID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !respData.Success || respData.Token == "" {
+		return "", collectorclient.ErrUnauthorized
+	}
+
+	if err := a.cache.Set(ctx, fmt.Sprint(telegramID), respData.Token); err != nil {
+		a.log.Warn("Failed to save refreshed token in cache", logger.Error(err))
+	}
+	return respData.Token, nil
+}
+
+func (a *authUsecaseImpl) checkInCache(ctx context.Context, telegramID string) (string, error) {
 	// Check in the cache (Redis)
-	value, err := a.cache.Get(context.TODO(), telegramID)
+	value, err := a.cache.Get(ctx, telegramID)
 	if err != nil {
 		a.log.Error("Failed to get user from cache", logger.Error(err))
 		return "", err
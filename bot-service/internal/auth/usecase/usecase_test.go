@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/fake"
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryCache is a Cache kept in a map instead of Redis.
+type memoryCache struct {
+	values map[string]string
+	setErr error
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, value interface{}) error {
+	if c.setErr != nil {
+		return c.setErr
+	}
+	c.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) (string, error) {
+	value, ok := c.values[key]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return value, nil
+}
+
+// go test github.com/ShenokZlob/collector-ouphe/bot-service/internal/auth/usecase -run AuthUsecaseTestSuite
+type AuthUsecaseTestSuite struct {
+	suite.Suite
+	server  *fake.Server
+	client  *collectorclient.HTTPCollectorClient
+	cache   *memoryCache
+	usecase *authUsecaseImpl
+	ctx     context.Context
+}
+
+func TestAuthUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, &AuthUsecaseTestSuite{})
+}
+
+func (s *AuthUsecaseTestSuite) SetupTest() {
+	s.server = fake.NewServer()
+	s.client = collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{})
+	s.cache = &memoryCache{values: map[string]string{}}
+	s.usecase = NewAuthUsecase(logger.SilentLogger{}, s.client, s.cache)
+	s.ctx = authctx.WithTelegramID(context.Background(), 42)
+}
+
+func (s *AuthUsecaseTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *AuthUsecaseTestSuite) TestTokenFromContext() {
+	s.cache.values["42"] = "cached"
+
+	token, err := s.usecase.Token(authctx.WithJWT(s.ctx, "jwt"))
+	s.Require().NoError(err)
+	s.Equal("jwt", token)
+}
+
+func (s *AuthUsecaseTestSuite) TestTokenCacheHit() {
+	s.cache.values["42"] = "cached"
+
+	token, err := s.usecase.Token(s.ctx)
+	s.Require().NoError(err)
+	s.Equal("cached", token)
+}
+
+func (s *AuthUsecaseTestSuite) TestTokenCacheMiss() {
+	_, err := s.usecase.Token(s.ctx)
+	s.ErrorIs(err, collectorclient.ErrMissingToken)
+
+	_, err = s.usecase.Token(context.Background())
+	s.ErrorIs(err, collectorclient.ErrMissingToken)
+}
+
+func (s *AuthUsecaseTestSuite) TestRefresh() {
+	_, err := s.client.RegisterUser(context.Background(), &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.Require().NoError(err)
+	s.cache.values["42"] = "expired"
+
+	token, err := s.usecase.Refresh(s.ctx)
+	s.Require().NoError(err)
+	s.NotEmpty(token)
+	s.NotEqual("expired", token)
+	s.Equal(token, s.cache.values["42"])
+
+	cached, err := s.usecase.Token(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(token, cached)
+
+	_, err = s.client.GetUserCollections(authctx.WithJWT(context.Background(), token))
+	s.NoError(err)
+}
+
+func (s *AuthUsecaseTestSuite) TestRefreshCacheError() {
+	_, err := s.client.RegisterUser(context.Background(), &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.Require().NoError(err)
+	s.cache.setErr = errors.New("redis is down")
+
+	token, err := s.usecase.Refresh(s.ctx)
+	s.Require().NoError(err)
+	s.NotEmpty(token)
+}
+
+func (s *AuthUsecaseTestSuite) TestRefreshError() {
+	s.cache.values["42"] = "expired"
+
+	_, err := s.usecase.Refresh(s.ctx)
+	s.Error(err)
+	s.Equal("expired", s.cache.values["42"])
+
+	_, err = s.usecase.Refresh(context.Background())
+	s.ErrorIs(err, collectorclient.ErrMissingToken)
+}
@@ -0,0 +1,158 @@
+// Package chart draws line charts as PNG images with the standard library only,
+// so the bot can send charts without an external rendering service.
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Point is a value on a day.
+type Point struct {
+	Date  time.Time
+	Value float64
+}
+
+// Size and layout of charts, in pixels.
+const (
+	width        = 800
+	height       = 400
+	marginLeft   = 110
+	marginRight  = 24
+	marginTop    = 20
+	marginBottom = 40
+	// valueTicks is the number of grid lines above the lowest one.
+	valueTicks = 5
+	// maxDateLabels keeps the dates below the chart from overlapping.
+	maxDateLabels = 8
+)
+
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gridColor       = color.RGBA{0xe6, 0xe6, 0xe6, 0xff}
+	axisColor       = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	textColor       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	riseColor       = color.RGBA{0x2e, 0x9e, 0x4f, 0xff}
+	fallColor       = color.RGBA{0xd1, 0x3b, 0x3b, 0xff}
+)
+
+// Line draws the points as a line chart: values on the left, dates as dd.mm
+// below. The line is green if the last value is not below the first, red otherwise.
+func Line(points []Point) ([]byte, error) {
+	if len(points) == 0 {
+		return nil, errors.New("chart: no points")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+	plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+
+	lo, hi := valueRange(points)
+	y := func(v float64) int {
+		return plot.Max.Y - int(math.Round((v-lo)/(hi-lo)*float64(plot.Dy())))
+	}
+	x := func(i int) int {
+		if len(points) == 1 {
+			return plot.Min.X + plot.Dx()/2
+		}
+		return plot.Min.X + i*plot.Dx()/(len(points)-1)
+	}
+
+	decimals := 2
+	if hi-lo >= 100 {
+		decimals = 0
+	}
+	for t := 0; t <= valueTicks; t++ {
+		v := lo + (hi-lo)*float64(t)/valueTicks
+		fillRect(img, image.Rect(plot.Min.X, y(v), plot.Max.X, y(v)+1), gridColor)
+		label := strconv.FormatFloat(v, 'f', decimals, 64)
+		drawText(img, plot.Min.X-8-textWidth(label), y(v)-glyphHeight*glyphScale/2, label)
+	}
+	fillRect(img, image.Rect(plot.Min.X, plot.Min.Y, plot.Min.X+1, plot.Max.Y+1), axisColor)
+	fillRect(img, image.Rect(plot.Min.X, plot.Max.Y, plot.Max.X, plot.Max.Y+1), axisColor)
+
+	step := (len(points) + maxDateLabels - 1) / maxDateLabels
+	for i := 0; i < len(points); i += step {
+		label := points[i].Date.Format("02.01")
+		drawText(img, min(x(i)-textWidth(label)/2, width-textWidth(label)-2), plot.Max.Y+12, label)
+	}
+
+	lineColor := riseColor
+	if points[len(points)-1].Value < points[0].Value {
+		lineColor = fallColor
+	}
+	for i := 1; i < len(points); i++ {
+		drawLine(img, x(i-1), y(points[i-1].Value), x(i), y(points[i].Value), lineColor)
+	}
+	for i, p := range points {
+		fillRect(img, image.Rect(x(i)-3, y(p.Value)-3, x(i)+4, y(p.Value)+4), lineColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// valueRange is the range of the value axis: the values with some room above
+// and below, never going below zero for values that don't.
+func valueRange(points []Point) (float64, float64) {
+	lo, hi := points[0].Value, points[0].Value
+	for _, p := range points {
+		lo, hi = min(lo, p.Value), max(hi, p.Value)
+	}
+	pad := (hi - lo) * 0.1
+	if pad == 0 {
+		pad = max(math.Abs(hi)*0.1, 1)
+	}
+	if lo >= 0 {
+		return max(lo-pad, 0), hi + pad
+	}
+	return lo - pad, hi + pad
+}
+
+// drawLine draws a line three pixels thick with Bresenham's algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	for e := dx + dy; ; {
+		fillRect(img, image.Rect(x0-1, y0-1, x0+2, y0+2), c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
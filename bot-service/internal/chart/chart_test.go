@@ -0,0 +1,136 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// go test github.com/ShenokZlob/collector-ouphe/bot-service/internal/chart -run ChartTestSuite
+type ChartTestSuite struct {
+	suite.Suite
+	start time.Time
+}
+
+func TestChartTestSuite(t *testing.T) {
+	suite.Run(t, &ChartTestSuite{})
+}
+
+func (s *ChartTestSuite) SetupTest() {
+	s.start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// series makes n points, next advancing the date of each point.
+func (s *ChartTestSuite) series(n int, next func(time.Time) time.Time, value func(i int) float64) []Point {
+	points := make([]Point, 0, n)
+	for i, date := 0, s.start; i < n; i, date = i+1, next(date) {
+		points = append(points, Point{Date: date, Value: value(i)})
+	}
+	return points
+}
+
+// render draws points and decodes the PNG back.
+func (s *ChartTestSuite) render(points []Point) *image.RGBA {
+	data, err := Line(points)
+	s.Require().NoError(err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	s.Require().NoError(err)
+	s.Require().Equal(image.Rect(0, 0, width, height), img.Bounds())
+
+	rgba := image.NewRGBA(img.Bounds())
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			rgba.Set(x, y, img.At(x, y))
+		}
+	}
+	return rgba
+}
+
+// count is the number of pixels of color c inside r.
+func count(img *image.RGBA, r image.Rectangle, c color.RGBA) int {
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (s *ChartTestSuite) TestSeries() {
+	for _, tc := range []struct {
+		name string
+		next func(time.Time) time.Time
+		n    int
+	}{
+		{"daily", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, 30},
+		{"weekly", func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }, 12},
+		{"monthly", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, 12},
+	} {
+		s.Run(tc.name, func() {
+			points := s.series(tc.n, tc.next, func(i int) float64 { return 100 + float64(i*i) })
+			img := s.render(points)
+
+			plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+			s.Positive(count(img, plot, riseColor), "the line is green when the value grows")
+			s.Zero(count(img, img.Bounds(), fallColor))
+			s.Positive(count(img, image.Rect(0, 0, marginLeft, height), textColor), "values are labeled on the left")
+			s.Positive(count(img, image.Rect(0, plot.Max.Y+1, width, height), textColor), "dates are labeled below")
+
+			// The first and the last points are at the edges of the plot.
+			lo, hi := valueRange(points)
+			y := func(v float64) int { return plot.Max.Y - int(float64(plot.Dy())*(v-lo)/(hi-lo)+0.5) }
+			s.Equal(riseColor, img.RGBAAt(plot.Min.X, y(points[0].Value)))
+			s.Equal(riseColor, img.RGBAAt(plot.Max.X, y(points[len(points)-1].Value)))
+		})
+	}
+}
+
+func (s *ChartTestSuite) TestFallingSeries() {
+	img := s.render(s.series(7, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, func(i int) float64 { return 10 - float64(i) }))
+	s.Positive(count(img, img.Bounds(), fallColor), "the line is red when the last value is below the first")
+	s.Zero(count(img, img.Bounds(), riseColor))
+}
+
+func (s *ChartTestSuite) TestEmpty() {
+	_, err := Line(nil)
+	s.Error(err)
+	_, err = Line([]Point{})
+	s.Error(err)
+}
+
+func (s *ChartTestSuite) TestSinglePoint() {
+	img := s.render([]Point{{Date: s.start, Value: 5}})
+
+	// A lone point is drawn in the middle of the plot, and the value axis
+	// still has a range around it.
+	plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+	s.Equal(riseColor, img.RGBAAt(plot.Min.X+plot.Dx()/2, plot.Min.Y+plot.Dy()/2))
+	s.Equal(7*7, count(img, img.Bounds(), riseColor), "only the marker of the point, no line")
+
+	lo, hi := valueRange([]Point{{Value: 5}})
+	s.Equal(4.0, lo)
+	s.Equal(6.0, hi)
+}
+
+func (s *ChartTestSuite) TestValueRange() {
+	lo, hi := valueRange([]Point{{Value: 0}, {Value: 0}})
+	s.Equal(0.0, lo, "the axis doesn't go below zero for values that don't")
+	s.Equal(1.0, hi)
+
+	lo, hi = valueRange([]Point{{Value: 1}, {Value: 11}})
+	s.Equal(0.0, lo)
+	s.Equal(12.0, hi)
+
+	lo, hi = valueRange([]Point{{Value: -10}, {Value: 10}})
+	s.Equal(-12.0, lo)
+	s.Equal(12.0, hi)
+}
@@ -30,7 +30,7 @@ func (u *collectionUsecaseImpl) GetCollecionsList(ctx context.Context) ([]string
 		return nil, err
 	}
 
-	collectionsNames := make([]string, len(collections))
+	collectionsNames := make([]string, 0, len(collections))
 	for _, v := range collections {
 		collectionsNames = append(collectionsNames, v.Name)
 	}
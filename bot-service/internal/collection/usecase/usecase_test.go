@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/fake"
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+// go test github.com/ShenokZlob/collector-ouphe/bot-service/internal/collection/usecase -run CollectionUsecaseTestSuite
+type CollectionUsecaseTestSuite struct {
+	suite.Suite
+	server  *fake.Server
+	usecase *collectionUsecaseImpl
+	ctx     context.Context
+}
+
+func TestCollectionUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, &CollectionUsecaseTestSuite{})
+}
+
+func (s *CollectionUsecaseTestSuite) SetupTest() {
+	s.server = fake.NewServer()
+	client := collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{})
+	s.usecase = NewCollectionUsecaseImpl(logger.SilentLogger{}, client)
+
+	resp, err := client.RegisterUser(context.Background(), &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.Require().NoError(err)
+	s.ctx = authctx.WithJWT(context.Background(), resp.Token)
+}
+
+func (s *CollectionUsecaseTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *CollectionUsecaseTestSuite) TestCreateAndList() {
+	names, err := s.usecase.GetCollecionsList(s.ctx)
+	s.Require().NoError(err)
+	s.Empty(names)
+
+	_, err = s.usecase.CreateaCollection(s.ctx, "Bulk")
+	s.Require().NoError(err)
+	_, err = s.usecase.CreateaCollection(s.ctx, "Deck")
+	s.Require().NoError(err)
+
+	names, err = s.usecase.GetCollecionsList(s.ctx)
+	s.Require().NoError(err)
+	s.Equal([]string{"Bulk", "Deck"}, names)
+}
+
+func (s *CollectionUsecaseTestSuite) TestRenameCollection() {
+	_, err := s.usecase.CreateaCollection(s.ctx, "Deck")
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.usecase.RenameCollection(s.ctx, "Deck", "Elves"))
+
+	names, err := s.usecase.GetCollecionsList(s.ctx)
+	s.Require().NoError(err)
+	s.Equal([]string{"Elves"}, names)
+
+	err = s.usecase.RenameCollection(s.ctx, "Deck", "Goblins")
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *CollectionUsecaseTestSuite) TestDeleteCollection() {
+	_, err := s.usecase.CreateaCollection(s.ctx, "Bulk")
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.usecase.DeleteCollection(s.ctx, "Bulk"))
+
+	names, err := s.usecase.GetCollecionsList(s.ctx)
+	s.Require().NoError(err)
+	s.Empty(names)
+
+	s.ErrorIs(s.usecase.DeleteCollection(s.ctx, "Bulk"), collectorclient.ErrNotFound)
+}
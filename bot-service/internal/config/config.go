@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+// Config is the typed configuration of bot-service.
+// Values come from env; secrets can be read from <ENV>_FILE instead.
+type Config struct {
+	BotToken     string
+	CollectorURL string
+	Collector    CollectorConfig
+	Redis        RedisConfig
+	Logger       logger.Config
+}
+
+// CollectorConfig tunes the HTTP client of collector-service.
+type CollectorConfig struct {
+	Timeout          time.Duration
+	RetryAttempts    int
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type RedisConfig struct {
+	Addr     string
+	Password string
+}
+
+// ValidationError lists every required env variable that is missing.
+type ValidationError struct {
+	Missing []string
+}
+
+func (e *ValidationError) Error() string {
+	return "config: missing required fields: " + strings.Join(e.Missing, ", ")
+}
+
+const (
+	defaultCollectorURL              = "http://localhost:8080"
+	defaultCollectorTimeout          = 5 * time.Second
+	defaultCollectorRetryAttempts    = 3
+	defaultCollectorBreakerThreshold = 5
+	defaultCollectorBreakerCooldown  = 30 * time.Second
+	defaultRedisAddr                 = "localhost:6379"
+	defaultLogLevel                  = "info"
+)
+
+// Load reads the configuration from the process environment.
+func Load() (*Config, error) {
+	return LoadFromEnv(os.Getenv)
+}
+
+// LoadFromEnv reads the configuration through getenv and validates it.
+func LoadFromEnv(getenv func(string) string) (*Config, error) {
+	var err error
+	cfg := &Config{
+		CollectorURL: envOr(getenv, "COLLECTOR_URL", defaultCollectorURL),
+		Redis: RedisConfig{
+			Addr: envOr(getenv, "REDIS_ADDR", defaultRedisAddr),
+		},
+		Logger: logger.Config{
+			Production: getenv("LOG_PRODUCTION") == "true",
+			Level:      envOr(getenv, "LOG_LEVEL", defaultLogLevel),
+		},
+	}
+
+	if cfg.Collector.Timeout, err = envDuration(getenv, "COLLECTOR_TIMEOUT", defaultCollectorTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.Collector.RetryAttempts, err = envInt(getenv, "COLLECTOR_RETRY_ATTEMPTS", defaultCollectorRetryAttempts); err != nil {
+		return nil, err
+	}
+	if cfg.Collector.BreakerThreshold, err = envInt(getenv, "COLLECTOR_BREAKER_THRESHOLD", defaultCollectorBreakerThreshold); err != nil {
+		return nil, err
+	}
+	if cfg.Collector.BreakerCooldown, err = envDuration(getenv, "COLLECTOR_BREAKER_COOLDOWN", defaultCollectorBreakerCooldown); err != nil {
+		return nil, err
+	}
+
+	if cfg.BotToken, err = secret(getenv, "BOT_TOKEN"); err != nil {
+		return nil, err
+	}
+	if cfg.Redis.Password, err = secret(getenv, "REDIS_PASSWORD"); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	var missing []string
+	if c.BotToken == "" {
+		missing = append(missing, "BOT_TOKEN")
+	}
+	if c.CollectorURL == "" {
+		missing = append(missing, "COLLECTOR_URL")
+	}
+	if c.Redis.Addr == "" {
+		missing = append(missing, "REDIS_ADDR")
+	}
+	if len(missing) > 0 {
+		return &ValidationError{Missing: missing}
+	}
+	return nil
+}
+
+func envOr(getenv func(string) string, key, fallback string) string {
+	if value := strings.TrimSpace(getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envDuration(getenv func(string) string, key string, fallback time.Duration) (time.Duration, error) {
+	value := strings.TrimSpace(getenv(key))
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return d, nil
+}
+
+func envInt(getenv func(string) string, key string, fallback int) (int, error) {
+	value := strings.TrimSpace(getenv(key))
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// secret returns the value of key, or the content of the file named by key_FILE.
+func secret(getenv func(string) string, key string) (string, error) {
+	if value := strings.TrimSpace(getenv(key)); value != "" {
+		return value, nil
+	}
+
+	path := getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("config: %s_FILE: %w", key, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", errors.New("config: " + key + "_FILE points to an empty file")
+	}
+	return value, nil
+}
@@ -0,0 +1,81 @@
+// go test ./internal/config -v
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envMap(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestLoadFromEnv_Defaults(t *testing.T) {
+	cfg, err := LoadFromEnv(envMap(map[string]string{"BOT_TOKEN": "123:abc"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "123:abc", cfg.BotToken)
+	assert.Equal(t, defaultCollectorURL, cfg.CollectorURL)
+	assert.Equal(t, defaultRedisAddr, cfg.Redis.Addr)
+	assert.Equal(t, defaultLogLevel, cfg.Logger.Level)
+	assert.False(t, cfg.Logger.Production)
+	assert.Equal(t, defaultCollectorTimeout, cfg.Collector.Timeout)
+	assert.Equal(t, defaultCollectorRetryAttempts, cfg.Collector.RetryAttempts)
+}
+
+func TestLoadFromEnv_Overrides(t *testing.T) {
+	cfg, err := LoadFromEnv(envMap(map[string]string{
+		"BOT_TOKEN":      "123:abc",
+		"COLLECTOR_URL":  "http://collector-service:8080",
+		"REDIS_ADDR":     "redis:6379",
+		"LOG_PRODUCTION": "true",
+		"LOG_LEVEL":      "debug",
+
+		"COLLECTOR_TIMEOUT":           "2s",
+		"COLLECTOR_RETRY_ATTEMPTS":    "1",
+		"COLLECTOR_BREAKER_THRESHOLD": "0",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://collector-service:8080", cfg.CollectorURL)
+	assert.Equal(t, "redis:6379", cfg.Redis.Addr)
+	assert.True(t, cfg.Logger.Production)
+	assert.Equal(t, "debug", cfg.Logger.Level)
+	assert.Equal(t, 2*time.Second, cfg.Collector.Timeout)
+	assert.Equal(t, 1, cfg.Collector.RetryAttempts)
+	assert.Equal(t, 0, cfg.Collector.BreakerThreshold)
+}
+
+func TestLoadFromEnv_InvalidCollectorSettings(t *testing.T) {
+	_, err := LoadFromEnv(envMap(map[string]string{"BOT_TOKEN": "123:abc", "COLLECTOR_TIMEOUT": "soon"}))
+	require.ErrorContains(t, err, "COLLECTOR_TIMEOUT")
+
+	_, err = LoadFromEnv(envMap(map[string]string{"BOT_TOKEN": "123:abc", "COLLECTOR_RETRY_ATTEMPTS": "many"}))
+	require.ErrorContains(t, err, "COLLECTOR_RETRY_ATTEMPTS")
+}
+
+func TestLoadFromEnv_MissingToken(t *testing.T) {
+	_, err := LoadFromEnv(envMap(nil))
+	require.Error(t, err)
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, []string{"BOT_TOKEN"}, vErr.Missing)
+}
+
+func TestLoadFromEnv_SecretFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "bot_token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("123:from-file\n"), 0o600))
+
+	cfg, err := LoadFromEnv(envMap(map[string]string{"BOT_TOKEN_FILE": tokenFile}))
+	require.NoError(t, err)
+	assert.Equal(t, "123:from-file", cfg.BotToken)
+
+	_, err = LoadFromEnv(envMap(map[string]string{"BOT_TOKEN_FILE": tokenFile + ".missing"}))
+	require.Error(t, err)
+}
@@ -8,7 +8,10 @@ import (
 	"context"
 
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/wishlist"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -39,83 +42,134 @@ func (_m *MockCollectorClient) EXPECT() *MockCollectorClient_Expecter {
 	return &MockCollectorClient_Expecter{mock: &_m.Mock}
 }
 
-// CheckUser provides a mock function for the type MockCollectorClient
-func (_mock *MockCollectorClient) CheckUser(reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error) {
-	ret := _mock.Called(reqData)
+// AddAcquisition provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) AddAcquisition(ctx context.Context, collectionID string, scryfallID string, req *cards.AcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error) {
+	ret := _mock.Called(ctx, collectionID, scryfallID, req, entry)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckUser")
+		panic("no return value specified for AddAcquisition")
 	}
 
-	var r0 *auth.CheckUserResponse
+	var r0 *cards.Acquisition
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*auth.CheckUserRequest) (*auth.CheckUserResponse, error)); ok {
-		return returnFunc(reqData)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.AcquisitionRequest, *cards.CardEntryQuery) (*cards.Acquisition, error)); ok {
+		return returnFunc(ctx, collectionID, scryfallID, req, entry)
 	}
-	if returnFunc, ok := ret.Get(0).(func(*auth.CheckUserRequest) *auth.CheckUserResponse); ok {
-		r0 = returnFunc(reqData)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.AcquisitionRequest, *cards.CardEntryQuery) *cards.Acquisition); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, req, entry)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.CheckUserResponse)
+			r0 = ret.Get(0).(*cards.Acquisition)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(*auth.CheckUserRequest) error); ok {
-		r1 = returnFunc(reqData)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *cards.AcquisitionRequest, *cards.CardEntryQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, scryfallID, req, entry)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockCollectorClient_CheckUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckUser'
-type MockCollectorClient_CheckUser_Call struct {
+// MockCollectorClient_AddAcquisition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAcquisition'
+type MockCollectorClient_AddAcquisition_Call struct {
 	*mock.Call
 }
 
-// CheckUser is a helper method to define mock.On call
-//   - reqData
-func (_e *MockCollectorClient_Expecter) CheckUser(reqData interface{}) *MockCollectorClient_CheckUser_Call {
-	return &MockCollectorClient_CheckUser_Call{Call: _e.mock.On("CheckUser", reqData)}
+// AddAcquisition is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - req
+//   - entry
+func (_e *MockCollectorClient_Expecter) AddAcquisition(ctx interface{}, collectionID interface{}, scryfallID interface{}, req interface{}, entry interface{}) *MockCollectorClient_AddAcquisition_Call {
+	return &MockCollectorClient_AddAcquisition_Call{Call: _e.mock.On("AddAcquisition", ctx, collectionID, scryfallID, req, entry)}
 }
 
-func (_c *MockCollectorClient_CheckUser_Call) Run(run func(reqData *auth.CheckUserRequest)) *MockCollectorClient_CheckUser_Call {
+func (_c *MockCollectorClient_AddAcquisition_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.AcquisitionRequest, entry *cards.CardEntryQuery)) *MockCollectorClient_AddAcquisition_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*auth.CheckUserRequest))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.AcquisitionRequest), args[4].(*cards.CardEntryQuery))
 	})
 	return _c
 }
 
-func (_c *MockCollectorClient_CheckUser_Call) Return(checkUserResponse *auth.CheckUserResponse, err error) *MockCollectorClient_CheckUser_Call {
-	_c.Call.Return(checkUserResponse, err)
+func (_c *MockCollectorClient_AddAcquisition_Call) Return(acquisition *cards.Acquisition, err error) *MockCollectorClient_AddAcquisition_Call {
+	_c.Call.Return(acquisition, err)
 	return _c
 }
 
-func (_c *MockCollectorClient_CheckUser_Call) RunAndReturn(run func(reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error)) *MockCollectorClient_CheckUser_Call {
+func (_c *MockCollectorClient_AddAcquisition_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.AcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error)) *MockCollectorClient_AddAcquisition_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateCollection provides a mock function for the type MockCollectorClient
-func (_mock *MockCollectorClient) CreateCollection(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error) {
+// AddCardToCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) AddCardToCollection(ctx context.Context, collectionID string, req *cards.AddCardRequest) error {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCardToCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *cards.AddCardRequest) error); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_AddCardToCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCardToCollection'
+type MockCollectorClient_AddCardToCollection_Call struct {
+	*mock.Call
+}
+
+// AddCardToCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) AddCardToCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_AddCardToCollection_Call {
+	return &MockCollectorClient_AddCardToCollection_Call{Call: _e.mock.On("AddCardToCollection", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_AddCardToCollection_Call) Run(run func(ctx context.Context, collectionID string, req *cards.AddCardRequest)) *MockCollectorClient_AddCardToCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*cards.AddCardRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_AddCardToCollection_Call) Return(err error) *MockCollectorClient_AddCardToCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_AddCardToCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *cards.AddCardRequest) error) *MockCollectorClient_AddCardToCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddWishlistEntry provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) AddWishlistEntry(ctx context.Context, req *wishlist.AddEntryRequest) (*wishlist.Entry, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateCollection")
+		panic("no return value specified for AddWishlistEntry")
 	}
 
-	var r0 *collections.Collection
+	var r0 *wishlist.Entry
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.CreateCollectionRequest) (*collections.Collection, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *wishlist.AddEntryRequest) (*wishlist.Entry, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.CreateCollectionRequest) *collections.Collection); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *wishlist.AddEntryRequest) *wishlist.Entry); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*collections.Collection)
+			r0 = ret.Get(0).(*wishlist.Entry)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *collections.CreateCollectionRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *wishlist.AddEntryRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -123,236 +177,3022 @@ func (_mock *MockCollectorClient) CreateCollection(ctx context.Context, req *col
 	return r0, r1
 }
 
-// MockCollectorClient_CreateCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCollection'
-type MockCollectorClient_CreateCollection_Call struct {
+// MockCollectorClient_AddWishlistEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddWishlistEntry'
+type MockCollectorClient_AddWishlistEntry_Call struct {
 	*mock.Call
 }
 
-// CreateCollection is a helper method to define mock.On call
+// AddWishlistEntry is a helper method to define mock.On call
 //   - ctx
 //   - req
-func (_e *MockCollectorClient_Expecter) CreateCollection(ctx interface{}, req interface{}) *MockCollectorClient_CreateCollection_Call {
-	return &MockCollectorClient_CreateCollection_Call{Call: _e.mock.On("CreateCollection", ctx, req)}
+func (_e *MockCollectorClient_Expecter) AddWishlistEntry(ctx interface{}, req interface{}) *MockCollectorClient_AddWishlistEntry_Call {
+	return &MockCollectorClient_AddWishlistEntry_Call{Call: _e.mock.On("AddWishlistEntry", ctx, req)}
 }
 
-func (_c *MockCollectorClient_CreateCollection_Call) Run(run func(ctx context.Context, req *collections.CreateCollectionRequest)) *MockCollectorClient_CreateCollection_Call {
+func (_c *MockCollectorClient_AddWishlistEntry_Call) Run(run func(ctx context.Context, req *wishlist.AddEntryRequest)) *MockCollectorClient_AddWishlistEntry_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*collections.CreateCollectionRequest))
+		run(args[0].(context.Context), args[1].(*wishlist.AddEntryRequest))
 	})
 	return _c
 }
 
-func (_c *MockCollectorClient_CreateCollection_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_CreateCollection_Call {
-	_c.Call.Return(collection, err)
+func (_c *MockCollectorClient_AddWishlistEntry_Call) Return(entry *wishlist.Entry, err error) *MockCollectorClient_AddWishlistEntry_Call {
+	_c.Call.Return(entry, err)
 	return _c
 }
 
-func (_c *MockCollectorClient_CreateCollection_Call) RunAndReturn(run func(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error)) *MockCollectorClient_CreateCollection_Call {
+func (_c *MockCollectorClient_AddWishlistEntry_Call) RunAndReturn(run func(ctx context.Context, req *wishlist.AddEntryRequest) (*wishlist.Entry, error)) *MockCollectorClient_AddWishlistEntry_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteCollection provides a mock function for the type MockCollectorClient
-func (_mock *MockCollectorClient) DeleteCollection(ctx context.Context, collectionID string) error {
-	ret := _mock.Called(ctx, collectionID)
+// BatchCardsInCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) BatchCardsInCollection(ctx context.Context, collectionID string, req *cards.BatchCardsRequest) (*cards.BatchCardsResponse, error) {
+	ret := _mock.Called(ctx, collectionID, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteCollection")
+		panic("no return value specified for BatchCardsInCollection")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = returnFunc(ctx, collectionID)
+	var r0 *cards.BatchCardsResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *cards.BatchCardsRequest) (*cards.BatchCardsResponse, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *cards.BatchCardsRequest) *cards.BatchCardsResponse); ok {
+		r0 = returnFunc(ctx, collectionID, req)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cards.BatchCardsResponse)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *cards.BatchCardsRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockCollectorClient_DeleteCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCollection'
-type MockCollectorClient_DeleteCollection_Call struct {
+// MockCollectorClient_BatchCardsInCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchCardsInCollection'
+type MockCollectorClient_BatchCardsInCollection_Call struct {
 	*mock.Call
 }
 
-// DeleteCollection is a helper method to define mock.On call
+// BatchCardsInCollection is a helper method to define mock.On call
 //   - ctx
 //   - collectionID
-func (_e *MockCollectorClient_Expecter) DeleteCollection(ctx interface{}, collectionID interface{}) *MockCollectorClient_DeleteCollection_Call {
-	return &MockCollectorClient_DeleteCollection_Call{Call: _e.mock.On("DeleteCollection", ctx, collectionID)}
+//   - req
+func (_e *MockCollectorClient_Expecter) BatchCardsInCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_BatchCardsInCollection_Call {
+	return &MockCollectorClient_BatchCardsInCollection_Call{Call: _e.mock.On("BatchCardsInCollection", ctx, collectionID, req)}
 }
 
-func (_c *MockCollectorClient_DeleteCollection_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_DeleteCollection_Call {
+func (_c *MockCollectorClient_BatchCardsInCollection_Call) Run(run func(ctx context.Context, collectionID string, req *cards.BatchCardsRequest)) *MockCollectorClient_BatchCardsInCollection_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(*cards.BatchCardsRequest))
 	})
 	return _c
 }
 
-func (_c *MockCollectorClient_DeleteCollection_Call) Return(err error) *MockCollectorClient_DeleteCollection_Call {
-	_c.Call.Return(err)
+func (_c *MockCollectorClient_BatchCardsInCollection_Call) Return(batchCardsResponse *cards.BatchCardsResponse, err error) *MockCollectorClient_BatchCardsInCollection_Call {
+	_c.Call.Return(batchCardsResponse, err)
 	return _c
 }
 
-func (_c *MockCollectorClient_DeleteCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string) error) *MockCollectorClient_DeleteCollection_Call {
+func (_c *MockCollectorClient_BatchCardsInCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *cards.BatchCardsRequest) (*cards.BatchCardsResponse, error)) *MockCollectorClient_BatchCardsInCollection_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserCollections provides a mock function for the type MockCollectorClient
-func (_mock *MockCollectorClient) GetUserCollections(ctx context.Context) ([]collections.Collection, error) {
-	ret := _mock.Called(ctx)
+// CheckBuildability provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) CheckBuildability(ctx context.Context, req *collections.BuildabilityRequest) (*collections.DeckBuildability, error) {
+	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserCollections")
+		panic("no return value specified for CheckBuildability")
 	}
 
-	var r0 []collections.Collection
+	var r0 *collections.DeckBuildability
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]collections.Collection, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.BuildabilityRequest) (*collections.DeckBuildability, error)); ok {
+		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []collections.Collection); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.BuildabilityRequest) *collections.DeckBuildability); ok {
+		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]collections.Collection)
+			r0 = ret.Get(0).(*collections.DeckBuildability)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *collections.BuildabilityRequest) error); ok {
+		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockCollectorClient_GetUserCollections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserCollections'
-type MockCollectorClient_GetUserCollections_Call struct {
+// MockCollectorClient_CheckBuildability_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckBuildability'
+type MockCollectorClient_CheckBuildability_Call struct {
 	*mock.Call
 }
 
-// GetUserCollections is a helper method to define mock.On call
+// CheckBuildability is a helper method to define mock.On call
 //   - ctx
-func (_e *MockCollectorClient_Expecter) GetUserCollections(ctx interface{}) *MockCollectorClient_GetUserCollections_Call {
-	return &MockCollectorClient_GetUserCollections_Call{Call: _e.mock.On("GetUserCollections", ctx)}
+//   - req
+func (_e *MockCollectorClient_Expecter) CheckBuildability(ctx interface{}, req interface{}) *MockCollectorClient_CheckBuildability_Call {
+	return &MockCollectorClient_CheckBuildability_Call{Call: _e.mock.On("CheckBuildability", ctx, req)}
 }
 
-func (_c *MockCollectorClient_GetUserCollections_Call) Run(run func(ctx context.Context)) *MockCollectorClient_GetUserCollections_Call {
+func (_c *MockCollectorClient_CheckBuildability_Call) Run(run func(ctx context.Context, req *collections.BuildabilityRequest)) *MockCollectorClient_CheckBuildability_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(*collections.BuildabilityRequest))
 	})
 	return _c
 }
 
-func (_c *MockCollectorClient_GetUserCollections_Call) Return(collections1 []collections.Collection, err error) *MockCollectorClient_GetUserCollections_Call {
-	_c.Call.Return(collections1, err)
+func (_c *MockCollectorClient_CheckBuildability_Call) Return(deckBuildability *collections.DeckBuildability, err error) *MockCollectorClient_CheckBuildability_Call {
+	_c.Call.Return(deckBuildability, err)
 	return _c
 }
 
-func (_c *MockCollectorClient_GetUserCollections_Call) RunAndReturn(run func(ctx context.Context) ([]collections.Collection, error)) *MockCollectorClient_GetUserCollections_Call {
+func (_c *MockCollectorClient_CheckBuildability_Call) RunAndReturn(run func(ctx context.Context, req *collections.BuildabilityRequest) (*collections.DeckBuildability, error)) *MockCollectorClient_CheckBuildability_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RegisterUser provides a mock function for the type MockCollectorClient
-func (_mock *MockCollectorClient) RegisterUser(reqData *auth.RegisterRequest) (*auth.RegisterResponse, error) {
-	ret := _mock.Called(reqData)
+// CheckUser provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) CheckUser(ctx context.Context, reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error) {
+	ret := _mock.Called(ctx, reqData)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RegisterUser")
+		panic("no return value specified for CheckUser")
 	}
 
-	var r0 *auth.RegisterResponse
+	var r0 *auth.CheckUserResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*auth.RegisterRequest) (*auth.RegisterResponse, error)); ok {
-		return returnFunc(reqData)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.CheckUserRequest) (*auth.CheckUserResponse, error)); ok {
+		return returnFunc(ctx, reqData)
 	}
-	if returnFunc, ok := ret.Get(0).(func(*auth.RegisterRequest) *auth.RegisterResponse); ok {
-		r0 = returnFunc(reqData)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.CheckUserRequest) *auth.CheckUserResponse); ok {
+		r0 = returnFunc(ctx, reqData)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.RegisterResponse)
+			r0 = ret.Get(0).(*auth.CheckUserResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(*auth.RegisterRequest) error); ok {
-		r1 = returnFunc(reqData)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *auth.CheckUserRequest) error); ok {
+		r1 = returnFunc(ctx, reqData)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockCollectorClient_RegisterUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterUser'
-type MockCollectorClient_RegisterUser_Call struct {
+// MockCollectorClient_CheckUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckUser'
+type MockCollectorClient_CheckUser_Call struct {
 	*mock.Call
 }
 
-// RegisterUser is a helper method to define mock.On call
+// CheckUser is a helper method to define mock.On call
+//   - ctx
 //   - reqData
-func (_e *MockCollectorClient_Expecter) RegisterUser(reqData interface{}) *MockCollectorClient_RegisterUser_Call {
-	return &MockCollectorClient_RegisterUser_Call{Call: _e.mock.On("RegisterUser", reqData)}
+func (_e *MockCollectorClient_Expecter) CheckUser(ctx interface{}, reqData interface{}) *MockCollectorClient_CheckUser_Call {
+	return &MockCollectorClient_CheckUser_Call{Call: _e.mock.On("CheckUser", ctx, reqData)}
 }
 
-func (_c *MockCollectorClient_RegisterUser_Call) Run(run func(reqData *auth.RegisterRequest)) *MockCollectorClient_RegisterUser_Call {
+func (_c *MockCollectorClient_CheckUser_Call) Run(run func(ctx context.Context, reqData *auth.CheckUserRequest)) *MockCollectorClient_CheckUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*auth.RegisterRequest))
+		run(args[0].(context.Context), args[1].(*auth.CheckUserRequest))
 	})
 	return _c
 }
 
-func (_c *MockCollectorClient_RegisterUser_Call) Return(registerResponse *auth.RegisterResponse, err error) *MockCollectorClient_RegisterUser_Call {
-	_c.Call.Return(registerResponse, err)
+func (_c *MockCollectorClient_CheckUser_Call) Return(checkUserResponse *auth.CheckUserResponse, err error) *MockCollectorClient_CheckUser_Call {
+	_c.Call.Return(checkUserResponse, err)
 	return _c
 }
 
-func (_c *MockCollectorClient_RegisterUser_Call) RunAndReturn(run func(reqData *auth.RegisterRequest) (*auth.RegisterResponse, error)) *MockCollectorClient_RegisterUser_Call {
+func (_c *MockCollectorClient_CheckUser_Call) RunAndReturn(run func(ctx context.Context, reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error)) *MockCollectorClient_CheckUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RenameCollection provides a mock function for the type MockCollectorClient
-func (_mock *MockCollectorClient) RenameCollection(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error {
-	ret := _mock.Called(ctx, collectionID, req)
+// CreateCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) CreateCollection(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RenameCollection")
+		panic("no return value specified for CreateCollection")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.RenameCollectionRequest) error); ok {
-		r0 = returnFunc(ctx, collectionID, req)
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.CreateCollectionRequest) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.CreateCollectionRequest) *collections.Collection); ok {
+		r0 = returnFunc(ctx, req)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *collections.CreateCollectionRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockCollectorClient_RenameCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenameCollection'
-type MockCollectorClient_RenameCollection_Call struct {
+// MockCollectorClient_CreateCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCollection'
+type MockCollectorClient_CreateCollection_Call struct {
 	*mock.Call
 }
 
-// RenameCollection is a helper method to define mock.On call
+// CreateCollection is a helper method to define mock.On call
 //   - ctx
-//   - collectionID
 //   - req
-func (_e *MockCollectorClient_Expecter) RenameCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_RenameCollection_Call {
-	return &MockCollectorClient_RenameCollection_Call{Call: _e.mock.On("RenameCollection", ctx, collectionID, req)}
+func (_e *MockCollectorClient_Expecter) CreateCollection(ctx interface{}, req interface{}) *MockCollectorClient_CreateCollection_Call {
+	return &MockCollectorClient_CreateCollection_Call{Call: _e.mock.On("CreateCollection", ctx, req)}
 }
 
-func (_c *MockCollectorClient_RenameCollection_Call) Run(run func(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest)) *MockCollectorClient_RenameCollection_Call {
+func (_c *MockCollectorClient_CreateCollection_Call) Run(run func(ctx context.Context, req *collections.CreateCollectionRequest)) *MockCollectorClient_CreateCollection_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*collections.RenameCollectionRequest))
+		run(args[0].(context.Context), args[1].(*collections.CreateCollectionRequest))
 	})
 	return _c
 }
 
-func (_c *MockCollectorClient_RenameCollection_Call) Return(err error) *MockCollectorClient_RenameCollection_Call {
-	_c.Call.Return(err)
+func (_c *MockCollectorClient_CreateCollection_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_CreateCollection_Call {
+	_c.Call.Return(collection, err)
 	return _c
 }
 
-func (_c *MockCollectorClient_RenameCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error) *MockCollectorClient_RenameCollection_Call {
+func (_c *MockCollectorClient_CreateCollection_Call) RunAndReturn(run func(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error)) *MockCollectorClient_CreateCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateLocation provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) CreateLocation(ctx context.Context, req *locations.CreateLocationRequest) (*locations.StorageLocation, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLocation")
+	}
+
+	var r0 *locations.StorageLocation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *locations.CreateLocationRequest) (*locations.StorageLocation, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *locations.CreateLocationRequest) *locations.StorageLocation); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*locations.StorageLocation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *locations.CreateLocationRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_CreateLocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateLocation'
+type MockCollectorClient_CreateLocation_Call struct {
+	*mock.Call
+}
+
+// CreateLocation is a helper method to define mock.On call
+//   - ctx
+//   - req
+func (_e *MockCollectorClient_Expecter) CreateLocation(ctx interface{}, req interface{}) *MockCollectorClient_CreateLocation_Call {
+	return &MockCollectorClient_CreateLocation_Call{Call: _e.mock.On("CreateLocation", ctx, req)}
+}
+
+func (_c *MockCollectorClient_CreateLocation_Call) Run(run func(ctx context.Context, req *locations.CreateLocationRequest)) *MockCollectorClient_CreateLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*locations.CreateLocationRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_CreateLocation_Call) Return(storageLocation *locations.StorageLocation, err error) *MockCollectorClient_CreateLocation_Call {
+	_c.Call.Return(storageLocation, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_CreateLocation_Call) RunAndReturn(run func(ctx context.Context, req *locations.CreateLocationRequest) (*locations.StorageLocation, error)) *MockCollectorClient_CreateLocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSnapshot provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) CreateSnapshot(ctx context.Context, collectionID string, req *collections.CreateSnapshotRequest) (*collections.Snapshot, error) {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSnapshot")
+	}
+
+	var r0 *collections.Snapshot
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.CreateSnapshotRequest) (*collections.Snapshot, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.CreateSnapshotRequest) *collections.Snapshot); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Snapshot)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.CreateSnapshotRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_CreateSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSnapshot'
+type MockCollectorClient_CreateSnapshot_Call struct {
+	*mock.Call
+}
+
+// CreateSnapshot is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) CreateSnapshot(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_CreateSnapshot_Call {
+	return &MockCollectorClient_CreateSnapshot_Call{Call: _e.mock.On("CreateSnapshot", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_CreateSnapshot_Call) Run(run func(ctx context.Context, collectionID string, req *collections.CreateSnapshotRequest)) *MockCollectorClient_CreateSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.CreateSnapshotRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_CreateSnapshot_Call) Return(snapshot *collections.Snapshot, err error) *MockCollectorClient_CreateSnapshot_Call {
+	_c.Call.Return(snapshot, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_CreateSnapshot_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.CreateSnapshotRequest) (*collections.Snapshot, error)) *MockCollectorClient_CreateSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAcquisition provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DeleteAcquisition(ctx context.Context, collectionID string, scryfallID string, acquisitionID string, entry *cards.CardEntryQuery) error {
+	ret := _mock.Called(ctx, collectionID, scryfallID, acquisitionID, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAcquisition")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, *cards.CardEntryQuery) error); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, acquisitionID, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_DeleteAcquisition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAcquisition'
+type MockCollectorClient_DeleteAcquisition_Call struct {
+	*mock.Call
+}
+
+// DeleteAcquisition is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - acquisitionID
+//   - entry
+func (_e *MockCollectorClient_Expecter) DeleteAcquisition(ctx interface{}, collectionID interface{}, scryfallID interface{}, acquisitionID interface{}, entry interface{}) *MockCollectorClient_DeleteAcquisition_Call {
+	return &MockCollectorClient_DeleteAcquisition_Call{Call: _e.mock.On("DeleteAcquisition", ctx, collectionID, scryfallID, acquisitionID, entry)}
+}
+
+func (_c *MockCollectorClient_DeleteAcquisition_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, acquisitionID string, entry *cards.CardEntryQuery)) *MockCollectorClient_DeleteAcquisition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteAcquisition_Call) Return(err error) *MockCollectorClient_DeleteAcquisition_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteAcquisition_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, acquisitionID string, entry *cards.CardEntryQuery) error) *MockCollectorClient_DeleteAcquisition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCardFromCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DeleteCardFromCollection(ctx context.Context, collectionID string, scryfallID string, entry *cards.CardEntryQuery) error {
+	ret := _mock.Called(ctx, collectionID, scryfallID, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCardFromCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.CardEntryQuery) error); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_DeleteCardFromCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCardFromCollection'
+type MockCollectorClient_DeleteCardFromCollection_Call struct {
+	*mock.Call
+}
+
+// DeleteCardFromCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - entry
+func (_e *MockCollectorClient_Expecter) DeleteCardFromCollection(ctx interface{}, collectionID interface{}, scryfallID interface{}, entry interface{}) *MockCollectorClient_DeleteCardFromCollection_Call {
+	return &MockCollectorClient_DeleteCardFromCollection_Call{Call: _e.mock.On("DeleteCardFromCollection", ctx, collectionID, scryfallID, entry)}
+}
+
+func (_c *MockCollectorClient_DeleteCardFromCollection_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, entry *cards.CardEntryQuery)) *MockCollectorClient_DeleteCardFromCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteCardFromCollection_Call) Return(err error) *MockCollectorClient_DeleteCardFromCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteCardFromCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, entry *cards.CardEntryQuery) error) *MockCollectorClient_DeleteCardFromCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DeleteCollection(ctx context.Context, collectionID string) error {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_DeleteCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCollection'
+type MockCollectorClient_DeleteCollection_Call struct {
+	*mock.Call
+}
+
+// DeleteCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) DeleteCollection(ctx interface{}, collectionID interface{}) *MockCollectorClient_DeleteCollection_Call {
+	return &MockCollectorClient_DeleteCollection_Call{Call: _e.mock.On("DeleteCollection", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_DeleteCollection_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_DeleteCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteCollection_Call) Return(err error) *MockCollectorClient_DeleteCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string) error) *MockCollectorClient_DeleteCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteLocation provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DeleteLocation(ctx context.Context, locationID string) error {
+	ret := _mock.Called(ctx, locationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLocation")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, locationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_DeleteLocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLocation'
+type MockCollectorClient_DeleteLocation_Call struct {
+	*mock.Call
+}
+
+// DeleteLocation is a helper method to define mock.On call
+//   - ctx
+//   - locationID
+func (_e *MockCollectorClient_Expecter) DeleteLocation(ctx interface{}, locationID interface{}) *MockCollectorClient_DeleteLocation_Call {
+	return &MockCollectorClient_DeleteLocation_Call{Call: _e.mock.On("DeleteLocation", ctx, locationID)}
+}
+
+func (_c *MockCollectorClient_DeleteLocation_Call) Run(run func(ctx context.Context, locationID string)) *MockCollectorClient_DeleteLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteLocation_Call) Return(err error) *MockCollectorClient_DeleteLocation_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteLocation_Call) RunAndReturn(run func(ctx context.Context, locationID string) error) *MockCollectorClient_DeleteLocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSnapshot provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DeleteSnapshot(ctx context.Context, collectionID string, snapshotID string) error {
+	ret := _mock.Called(ctx, collectionID, snapshotID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSnapshot")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, collectionID, snapshotID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_DeleteSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSnapshot'
+type MockCollectorClient_DeleteSnapshot_Call struct {
+	*mock.Call
+}
+
+// DeleteSnapshot is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - snapshotID
+func (_e *MockCollectorClient_Expecter) DeleteSnapshot(ctx interface{}, collectionID interface{}, snapshotID interface{}) *MockCollectorClient_DeleteSnapshot_Call {
+	return &MockCollectorClient_DeleteSnapshot_Call{Call: _e.mock.On("DeleteSnapshot", ctx, collectionID, snapshotID)}
+}
+
+func (_c *MockCollectorClient_DeleteSnapshot_Call) Run(run func(ctx context.Context, collectionID string, snapshotID string)) *MockCollectorClient_DeleteSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteSnapshot_Call) Return(err error) *MockCollectorClient_DeleteSnapshot_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteSnapshot_Call) RunAndReturn(run func(ctx context.Context, collectionID string, snapshotID string) error) *MockCollectorClient_DeleteSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWishlistEntry provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DeleteWishlistEntry(ctx context.Context, entryID string) error {
+	ret := _mock.Called(ctx, entryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWishlistEntry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, entryID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_DeleteWishlistEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWishlistEntry'
+type MockCollectorClient_DeleteWishlistEntry_Call struct {
+	*mock.Call
+}
+
+// DeleteWishlistEntry is a helper method to define mock.On call
+//   - ctx
+//   - entryID
+func (_e *MockCollectorClient_Expecter) DeleteWishlistEntry(ctx interface{}, entryID interface{}) *MockCollectorClient_DeleteWishlistEntry_Call {
+	return &MockCollectorClient_DeleteWishlistEntry_Call{Call: _e.mock.On("DeleteWishlistEntry", ctx, entryID)}
+}
+
+func (_c *MockCollectorClient_DeleteWishlistEntry_Call) Run(run func(ctx context.Context, entryID string)) *MockCollectorClient_DeleteWishlistEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteWishlistEntry_Call) Return(err error) *MockCollectorClient_DeleteWishlistEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DeleteWishlistEntry_Call) RunAndReturn(run func(ctx context.Context, entryID string) error) *MockCollectorClient_DeleteWishlistEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DiffSnapshot provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DiffSnapshot(ctx context.Context, collectionID string, snapshotID string, toSnapshotID string) (*collections.SnapshotDiff, error) {
+	ret := _mock.Called(ctx, collectionID, snapshotID, toSnapshotID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffSnapshot")
+	}
+
+	var r0 *collections.SnapshotDiff
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*collections.SnapshotDiff, error)); ok {
+		return returnFunc(ctx, collectionID, snapshotID, toSnapshotID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *collections.SnapshotDiff); ok {
+		r0 = returnFunc(ctx, collectionID, snapshotID, toSnapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.SnapshotDiff)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, collectionID, snapshotID, toSnapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_DiffSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiffSnapshot'
+type MockCollectorClient_DiffSnapshot_Call struct {
+	*mock.Call
+}
+
+// DiffSnapshot is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - snapshotID
+//   - toSnapshotID
+func (_e *MockCollectorClient_Expecter) DiffSnapshot(ctx interface{}, collectionID interface{}, snapshotID interface{}, toSnapshotID interface{}) *MockCollectorClient_DiffSnapshot_Call {
+	return &MockCollectorClient_DiffSnapshot_Call{Call: _e.mock.On("DiffSnapshot", ctx, collectionID, snapshotID, toSnapshotID)}
+}
+
+func (_c *MockCollectorClient_DiffSnapshot_Call) Run(run func(ctx context.Context, collectionID string, snapshotID string, toSnapshotID string)) *MockCollectorClient_DiffSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DiffSnapshot_Call) Return(snapshotDiff *collections.SnapshotDiff, err error) *MockCollectorClient_DiffSnapshot_Call {
+	_c.Call.Return(snapshotDiff, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DiffSnapshot_Call) RunAndReturn(run func(ctx context.Context, collectionID string, snapshotID string, toSnapshotID string) (*collections.SnapshotDiff, error)) *MockCollectorClient_DiffSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DuplicateCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) DuplicateCollection(ctx context.Context, collectionID string, req *collections.DuplicateCollectionRequest) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DuplicateCollection")
+	}
+
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.DuplicateCollectionRequest) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.DuplicateCollectionRequest) *collections.Collection); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.DuplicateCollectionRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_DuplicateCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DuplicateCollection'
+type MockCollectorClient_DuplicateCollection_Call struct {
+	*mock.Call
+}
+
+// DuplicateCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) DuplicateCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_DuplicateCollection_Call {
+	return &MockCollectorClient_DuplicateCollection_Call{Call: _e.mock.On("DuplicateCollection", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_DuplicateCollection_Call) Run(run func(ctx context.Context, collectionID string, req *collections.DuplicateCollectionRequest)) *MockCollectorClient_DuplicateCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.DuplicateCollectionRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_DuplicateCollection_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_DuplicateCollection_Call {
+	_c.Call.Return(collection, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_DuplicateCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.DuplicateCollectionRequest) (*collections.Collection, error)) *MockCollectorClient_DuplicateCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindOwnedCards provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) FindOwnedCards(ctx context.Context, query *cards.OwnedCardsQuery) (*cards.OwnedCards, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOwnedCards")
+	}
+
+	var r0 *cards.OwnedCards
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *cards.OwnedCardsQuery) (*cards.OwnedCards, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *cards.OwnedCardsQuery) *cards.OwnedCards); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cards.OwnedCards)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *cards.OwnedCardsQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_FindOwnedCards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindOwnedCards'
+type MockCollectorClient_FindOwnedCards_Call struct {
+	*mock.Call
+}
+
+// FindOwnedCards is a helper method to define mock.On call
+//   - ctx
+//   - query
+func (_e *MockCollectorClient_Expecter) FindOwnedCards(ctx interface{}, query interface{}) *MockCollectorClient_FindOwnedCards_Call {
+	return &MockCollectorClient_FindOwnedCards_Call{Call: _e.mock.On("FindOwnedCards", ctx, query)}
+}
+
+func (_c *MockCollectorClient_FindOwnedCards_Call) Run(run func(ctx context.Context, query *cards.OwnedCardsQuery)) *MockCollectorClient_FindOwnedCards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*cards.OwnedCardsQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_FindOwnedCards_Call) Return(ownedCards *cards.OwnedCards, err error) *MockCollectorClient_FindOwnedCards_Call {
+	_c.Call.Return(ownedCards, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_FindOwnedCards_Call) RunAndReturn(run func(ctx context.Context, query *cards.OwnedCardsQuery) (*cards.OwnedCards, error)) *MockCollectorClient_FindOwnedCards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCardInCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetCardInCollection(ctx context.Context, collectionID string, scryfallID string, entry *cards.CardEntryQuery) (*cards.CardEntry, error) {
+	ret := _mock.Called(ctx, collectionID, scryfallID, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCardInCollection")
+	}
+
+	var r0 *cards.CardEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.CardEntryQuery) (*cards.CardEntry, error)); ok {
+		return returnFunc(ctx, collectionID, scryfallID, entry)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.CardEntryQuery) *cards.CardEntry); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, entry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cards.CardEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *cards.CardEntryQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, scryfallID, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetCardInCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCardInCollection'
+type MockCollectorClient_GetCardInCollection_Call struct {
+	*mock.Call
+}
+
+// GetCardInCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - entry
+func (_e *MockCollectorClient_Expecter) GetCardInCollection(ctx interface{}, collectionID interface{}, scryfallID interface{}, entry interface{}) *MockCollectorClient_GetCardInCollection_Call {
+	return &MockCollectorClient_GetCardInCollection_Call{Call: _e.mock.On("GetCardInCollection", ctx, collectionID, scryfallID, entry)}
+}
+
+func (_c *MockCollectorClient_GetCardInCollection_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, entry *cards.CardEntryQuery)) *MockCollectorClient_GetCardInCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCardInCollection_Call) Return(cardEntry *cards.CardEntry, err error) *MockCollectorClient_GetCardInCollection_Call {
+	_c.Call.Return(cardEntry, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCardInCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, entry *cards.CardEntryQuery) (*cards.CardEntry, error)) *MockCollectorClient_GetCardInCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetCollection(ctx context.Context, collectionID string) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollection")
+	}
+
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, collectionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *collections.Collection); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCollection'
+type MockCollectorClient_GetCollection_Call struct {
+	*mock.Call
+}
+
+// GetCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) GetCollection(ctx interface{}, collectionID interface{}) *MockCollectorClient_GetCollection_Call {
+	return &MockCollectorClient_GetCollection_Call{Call: _e.mock.On("GetCollection", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_GetCollection_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_GetCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollection_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_GetCollection_Call {
+	_c.Call.Return(collection, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string) (*collections.Collection, error)) *MockCollectorClient_GetCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCollectionProfitLoss provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetCollectionProfitLoss(ctx context.Context, collectionID string, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error) {
+	ret := _mock.Called(ctx, collectionID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollectionProfitLoss")
+	}
+
+	var r0 *collections.ProfitLoss
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.ProfitLossQuery) (*collections.ProfitLoss, error)); ok {
+		return returnFunc(ctx, collectionID, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.ProfitLossQuery) *collections.ProfitLoss); ok {
+		r0 = returnFunc(ctx, collectionID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.ProfitLoss)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.ProfitLossQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetCollectionProfitLoss_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCollectionProfitLoss'
+type MockCollectorClient_GetCollectionProfitLoss_Call struct {
+	*mock.Call
+}
+
+// GetCollectionProfitLoss is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - query
+func (_e *MockCollectorClient_Expecter) GetCollectionProfitLoss(ctx interface{}, collectionID interface{}, query interface{}) *MockCollectorClient_GetCollectionProfitLoss_Call {
+	return &MockCollectorClient_GetCollectionProfitLoss_Call{Call: _e.mock.On("GetCollectionProfitLoss", ctx, collectionID, query)}
+}
+
+func (_c *MockCollectorClient_GetCollectionProfitLoss_Call) Run(run func(ctx context.Context, collectionID string, query *collections.ProfitLossQuery)) *MockCollectorClient_GetCollectionProfitLoss_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.ProfitLossQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollectionProfitLoss_Call) Return(profitLoss *collections.ProfitLoss, err error) *MockCollectorClient_GetCollectionProfitLoss_Call {
+	_c.Call.Return(profitLoss, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollectionProfitLoss_Call) RunAndReturn(run func(ctx context.Context, collectionID string, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error)) *MockCollectorClient_GetCollectionProfitLoss_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCollectionValue provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetCollectionValue(ctx context.Context, collectionID string, query *collections.CollectionValueQuery) (*collections.CollectionValue, error) {
+	ret := _mock.Called(ctx, collectionID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollectionValue")
+	}
+
+	var r0 *collections.CollectionValue
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.CollectionValueQuery) (*collections.CollectionValue, error)); ok {
+		return returnFunc(ctx, collectionID, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.CollectionValueQuery) *collections.CollectionValue); ok {
+		r0 = returnFunc(ctx, collectionID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.CollectionValue)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.CollectionValueQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetCollectionValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCollectionValue'
+type MockCollectorClient_GetCollectionValue_Call struct {
+	*mock.Call
+}
+
+// GetCollectionValue is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - query
+func (_e *MockCollectorClient_Expecter) GetCollectionValue(ctx interface{}, collectionID interface{}, query interface{}) *MockCollectorClient_GetCollectionValue_Call {
+	return &MockCollectorClient_GetCollectionValue_Call{Call: _e.mock.On("GetCollectionValue", ctx, collectionID, query)}
+}
+
+func (_c *MockCollectorClient_GetCollectionValue_Call) Run(run func(ctx context.Context, collectionID string, query *collections.CollectionValueQuery)) *MockCollectorClient_GetCollectionValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.CollectionValueQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollectionValue_Call) Return(collectionValue *collections.CollectionValue, err error) *MockCollectorClient_GetCollectionValue_Call {
+	_c.Call.Return(collectionValue, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollectionValue_Call) RunAndReturn(run func(ctx context.Context, collectionID string, query *collections.CollectionValueQuery) (*collections.CollectionValue, error)) *MockCollectorClient_GetCollectionValue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCollectionValueHistory provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetCollectionValueHistory(ctx context.Context, collectionID string, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error) {
+	ret := _mock.Called(ctx, collectionID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollectionValueHistory")
+	}
+
+	var r0 *collections.ValueHistory
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.ValueHistoryQuery) (*collections.ValueHistory, error)); ok {
+		return returnFunc(ctx, collectionID, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.ValueHistoryQuery) *collections.ValueHistory); ok {
+		r0 = returnFunc(ctx, collectionID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.ValueHistory)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.ValueHistoryQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetCollectionValueHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCollectionValueHistory'
+type MockCollectorClient_GetCollectionValueHistory_Call struct {
+	*mock.Call
+}
+
+// GetCollectionValueHistory is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - query
+func (_e *MockCollectorClient_Expecter) GetCollectionValueHistory(ctx interface{}, collectionID interface{}, query interface{}) *MockCollectorClient_GetCollectionValueHistory_Call {
+	return &MockCollectorClient_GetCollectionValueHistory_Call{Call: _e.mock.On("GetCollectionValueHistory", ctx, collectionID, query)}
+}
+
+func (_c *MockCollectorClient_GetCollectionValueHistory_Call) Run(run func(ctx context.Context, collectionID string, query *collections.ValueHistoryQuery)) *MockCollectorClient_GetCollectionValueHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.ValueHistoryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollectionValueHistory_Call) Return(valueHistory *collections.ValueHistory, err error) *MockCollectorClient_GetCollectionValueHistory_Call {
+	_c.Call.Return(valueHistory, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetCollectionValueHistory_Call) RunAndReturn(run func(ctx context.Context, collectionID string, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error)) *MockCollectorClient_GetCollectionValueHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExchangeRates provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetExchangeRates(ctx context.Context) (*collections.ExchangeRates, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExchangeRates")
+	}
+
+	var r0 *collections.ExchangeRates
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*collections.ExchangeRates, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *collections.ExchangeRates); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.ExchangeRates)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetExchangeRates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExchangeRates'
+type MockCollectorClient_GetExchangeRates_Call struct {
+	*mock.Call
+}
+
+// GetExchangeRates is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) GetExchangeRates(ctx interface{}) *MockCollectorClient_GetExchangeRates_Call {
+	return &MockCollectorClient_GetExchangeRates_Call{Call: _e.mock.On("GetExchangeRates", ctx)}
+}
+
+func (_c *MockCollectorClient_GetExchangeRates_Call) Run(run func(ctx context.Context)) *MockCollectorClient_GetExchangeRates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetExchangeRates_Call) Return(exchangeRates *collections.ExchangeRates, err error) *MockCollectorClient_GetExchangeRates_Call {
+	_c.Call.Return(exchangeRates, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetExchangeRates_Call) RunAndReturn(run func(ctx context.Context) (*collections.ExchangeRates, error)) *MockCollectorClient_GetExchangeRates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProfitLoss provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetProfitLoss(ctx context.Context, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfitLoss")
+	}
+
+	var r0 *collections.ProfitLoss
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.ProfitLossQuery) (*collections.ProfitLoss, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.ProfitLossQuery) *collections.ProfitLoss); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.ProfitLoss)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *collections.ProfitLossQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetProfitLoss_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProfitLoss'
+type MockCollectorClient_GetProfitLoss_Call struct {
+	*mock.Call
+}
+
+// GetProfitLoss is a helper method to define mock.On call
+//   - ctx
+//   - query
+func (_e *MockCollectorClient_Expecter) GetProfitLoss(ctx interface{}, query interface{}) *MockCollectorClient_GetProfitLoss_Call {
+	return &MockCollectorClient_GetProfitLoss_Call{Call: _e.mock.On("GetProfitLoss", ctx, query)}
+}
+
+func (_c *MockCollectorClient_GetProfitLoss_Call) Run(run func(ctx context.Context, query *collections.ProfitLossQuery)) *MockCollectorClient_GetProfitLoss_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*collections.ProfitLossQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetProfitLoss_Call) Return(profitLoss *collections.ProfitLoss, err error) *MockCollectorClient_GetProfitLoss_Call {
+	_c.Call.Return(profitLoss, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetProfitLoss_Call) RunAndReturn(run func(ctx context.Context, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error)) *MockCollectorClient_GetProfitLoss_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSnapshot provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetSnapshot(ctx context.Context, collectionID string, snapshotID string) (*collections.Snapshot, error) {
+	ret := _mock.Called(ctx, collectionID, snapshotID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSnapshot")
+	}
+
+	var r0 *collections.Snapshot
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*collections.Snapshot, error)); ok {
+		return returnFunc(ctx, collectionID, snapshotID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *collections.Snapshot); ok {
+		r0 = returnFunc(ctx, collectionID, snapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Snapshot)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, collectionID, snapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSnapshot'
+type MockCollectorClient_GetSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetSnapshot is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - snapshotID
+func (_e *MockCollectorClient_Expecter) GetSnapshot(ctx interface{}, collectionID interface{}, snapshotID interface{}) *MockCollectorClient_GetSnapshot_Call {
+	return &MockCollectorClient_GetSnapshot_Call{Call: _e.mock.On("GetSnapshot", ctx, collectionID, snapshotID)}
+}
+
+func (_c *MockCollectorClient_GetSnapshot_Call) Run(run func(ctx context.Context, collectionID string, snapshotID string)) *MockCollectorClient_GetSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetSnapshot_Call) Return(snapshot *collections.Snapshot, err error) *MockCollectorClient_GetSnapshot_Call {
+	_c.Call.Return(snapshot, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetSnapshot_Call) RunAndReturn(run func(ctx context.Context, collectionID string, snapshotID string) (*collections.Snapshot, error)) *MockCollectorClient_GetSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserCollections provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetUserCollections(ctx context.Context) ([]collections.Collection, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserCollections")
+	}
+
+	var r0 []collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]collections.Collection, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []collections.Collection); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetUserCollections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserCollections'
+type MockCollectorClient_GetUserCollections_Call struct {
+	*mock.Call
+}
+
+// GetUserCollections is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) GetUserCollections(ctx interface{}) *MockCollectorClient_GetUserCollections_Call {
+	return &MockCollectorClient_GetUserCollections_Call{Call: _e.mock.On("GetUserCollections", ctx)}
+}
+
+func (_c *MockCollectorClient_GetUserCollections_Call) Run(run func(ctx context.Context)) *MockCollectorClient_GetUserCollections_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetUserCollections_Call) Return(collections1 []collections.Collection, err error) *MockCollectorClient_GetUserCollections_Call {
+	_c.Call.Return(collections1, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetUserCollections_Call) RunAndReturn(run func(ctx context.Context) ([]collections.Collection, error)) *MockCollectorClient_GetUserCollections_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserSettings provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetUserSettings(ctx context.Context) (*auth.UserSettings, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserSettings")
+	}
+
+	var r0 *auth.UserSettings
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*auth.UserSettings, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *auth.UserSettings); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.UserSettings)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetUserSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSettings'
+type MockCollectorClient_GetUserSettings_Call struct {
+	*mock.Call
+}
+
+// GetUserSettings is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) GetUserSettings(ctx interface{}) *MockCollectorClient_GetUserSettings_Call {
+	return &MockCollectorClient_GetUserSettings_Call{Call: _e.mock.On("GetUserSettings", ctx)}
+}
+
+func (_c *MockCollectorClient_GetUserSettings_Call) Run(run func(ctx context.Context)) *MockCollectorClient_GetUserSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetUserSettings_Call) Return(userSettings *auth.UserSettings, err error) *MockCollectorClient_GetUserSettings_Call {
+	_c.Call.Return(userSettings, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetUserSettings_Call) RunAndReturn(run func(ctx context.Context) (*auth.UserSettings, error)) *MockCollectorClient_GetUserSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUsersCollectionByName provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetUsersCollectionByName(ctx context.Context, name string) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersCollectionByName")
+	}
+
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *collections.Collection); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetUsersCollectionByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUsersCollectionByName'
+type MockCollectorClient_GetUsersCollectionByName_Call struct {
+	*mock.Call
+}
+
+// GetUsersCollectionByName is a helper method to define mock.On call
+//   - ctx
+//   - name
+func (_e *MockCollectorClient_Expecter) GetUsersCollectionByName(ctx interface{}, name interface{}) *MockCollectorClient_GetUsersCollectionByName_Call {
+	return &MockCollectorClient_GetUsersCollectionByName_Call{Call: _e.mock.On("GetUsersCollectionByName", ctx, name)}
+}
+
+func (_c *MockCollectorClient_GetUsersCollectionByName_Call) Run(run func(ctx context.Context, name string)) *MockCollectorClient_GetUsersCollectionByName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetUsersCollectionByName_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_GetUsersCollectionByName_Call {
+	_c.Call.Return(collection, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetUsersCollectionByName_Call) RunAndReturn(run func(ctx context.Context, name string) (*collections.Collection, error)) *MockCollectorClient_GetUsersCollectionByName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetValueHistory provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) GetValueHistory(ctx context.Context, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetValueHistory")
+	}
+
+	var r0 *collections.ValueHistory
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.ValueHistoryQuery) (*collections.ValueHistory, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *collections.ValueHistoryQuery) *collections.ValueHistory); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.ValueHistory)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *collections.ValueHistoryQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_GetValueHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetValueHistory'
+type MockCollectorClient_GetValueHistory_Call struct {
+	*mock.Call
+}
+
+// GetValueHistory is a helper method to define mock.On call
+//   - ctx
+//   - query
+func (_e *MockCollectorClient_Expecter) GetValueHistory(ctx interface{}, query interface{}) *MockCollectorClient_GetValueHistory_Call {
+	return &MockCollectorClient_GetValueHistory_Call{Call: _e.mock.On("GetValueHistory", ctx, query)}
+}
+
+func (_c *MockCollectorClient_GetValueHistory_Call) Run(run func(ctx context.Context, query *collections.ValueHistoryQuery)) *MockCollectorClient_GetValueHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*collections.ValueHistoryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_GetValueHistory_Call) Return(valueHistory *collections.ValueHistory, err error) *MockCollectorClient_GetValueHistory_Call {
+	_c.Call.Return(valueHistory, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_GetValueHistory_Call) RunAndReturn(run func(ctx context.Context, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error)) *MockCollectorClient_GetValueHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAllocationConflicts provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListAllocationConflicts(ctx context.Context) ([]collections.AllocationConflict, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAllocationConflicts")
+	}
+
+	var r0 []collections.AllocationConflict
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]collections.AllocationConflict, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []collections.AllocationConflict); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]collections.AllocationConflict)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListAllocationConflicts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAllocationConflicts'
+type MockCollectorClient_ListAllocationConflicts_Call struct {
+	*mock.Call
+}
+
+// ListAllocationConflicts is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) ListAllocationConflicts(ctx interface{}) *MockCollectorClient_ListAllocationConflicts_Call {
+	return &MockCollectorClient_ListAllocationConflicts_Call{Call: _e.mock.On("ListAllocationConflicts", ctx)}
+}
+
+func (_c *MockCollectorClient_ListAllocationConflicts_Call) Run(run func(ctx context.Context)) *MockCollectorClient_ListAllocationConflicts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListAllocationConflicts_Call) Return(allocationConflicts []collections.AllocationConflict, err error) *MockCollectorClient_ListAllocationConflicts_Call {
+	_c.Call.Return(allocationConflicts, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListAllocationConflicts_Call) RunAndReturn(run func(ctx context.Context) ([]collections.AllocationConflict, error)) *MockCollectorClient_ListAllocationConflicts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAllocations provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListAllocations(ctx context.Context, collectionID string) ([]collections.Allocation, error) {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAllocations")
+	}
+
+	var r0 []collections.Allocation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]collections.Allocation, error)); ok {
+		return returnFunc(ctx, collectionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []collections.Allocation); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]collections.Allocation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListAllocations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAllocations'
+type MockCollectorClient_ListAllocations_Call struct {
+	*mock.Call
+}
+
+// ListAllocations is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) ListAllocations(ctx interface{}, collectionID interface{}) *MockCollectorClient_ListAllocations_Call {
+	return &MockCollectorClient_ListAllocations_Call{Call: _e.mock.On("ListAllocations", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_ListAllocations_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_ListAllocations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListAllocations_Call) Return(allocations []collections.Allocation, err error) *MockCollectorClient_ListAllocations_Call {
+	_c.Call.Return(allocations, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListAllocations_Call) RunAndReturn(run func(ctx context.Context, collectionID string) ([]collections.Allocation, error)) *MockCollectorClient_ListAllocations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCardHistory provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListCardHistory(ctx context.Context, collectionID string, query *cards.CardHistoryQuery) ([]cards.CardHistoryEntry, error) {
+	ret := _mock.Called(ctx, collectionID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCardHistory")
+	}
+
+	var r0 []cards.CardHistoryEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *cards.CardHistoryQuery) ([]cards.CardHistoryEntry, error)); ok {
+		return returnFunc(ctx, collectionID, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *cards.CardHistoryQuery) []cards.CardHistoryEntry); ok {
+		r0 = returnFunc(ctx, collectionID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cards.CardHistoryEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *cards.CardHistoryQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListCardHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCardHistory'
+type MockCollectorClient_ListCardHistory_Call struct {
+	*mock.Call
+}
+
+// ListCardHistory is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - query
+func (_e *MockCollectorClient_Expecter) ListCardHistory(ctx interface{}, collectionID interface{}, query interface{}) *MockCollectorClient_ListCardHistory_Call {
+	return &MockCollectorClient_ListCardHistory_Call{Call: _e.mock.On("ListCardHistory", ctx, collectionID, query)}
+}
+
+func (_c *MockCollectorClient_ListCardHistory_Call) Run(run func(ctx context.Context, collectionID string, query *cards.CardHistoryQuery)) *MockCollectorClient_ListCardHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*cards.CardHistoryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListCardHistory_Call) Return(cardHistoryEntrys []cards.CardHistoryEntry, err error) *MockCollectorClient_ListCardHistory_Call {
+	_c.Call.Return(cardHistoryEntrys, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListCardHistory_Call) RunAndReturn(run func(ctx context.Context, collectionID string, query *cards.CardHistoryQuery) ([]cards.CardHistoryEntry, error)) *MockCollectorClient_ListCardHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCardsInCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListCardsInCollection(ctx context.Context, collectionID string) ([]cards.Card, error) {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCardsInCollection")
+	}
+
+	var r0 []cards.Card
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]cards.Card, error)); ok {
+		return returnFunc(ctx, collectionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []cards.Card); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cards.Card)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListCardsInCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCardsInCollection'
+type MockCollectorClient_ListCardsInCollection_Call struct {
+	*mock.Call
+}
+
+// ListCardsInCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) ListCardsInCollection(ctx interface{}, collectionID interface{}) *MockCollectorClient_ListCardsInCollection_Call {
+	return &MockCollectorClient_ListCardsInCollection_Call{Call: _e.mock.On("ListCardsInCollection", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_ListCardsInCollection_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_ListCardsInCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListCardsInCollection_Call) Return(cards1 []cards.Card, err error) *MockCollectorClient_ListCardsInCollection_Call {
+	_c.Call.Return(cards1, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListCardsInCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string) ([]cards.Card, error)) *MockCollectorClient_ListCardsInCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLocationCards provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListLocationCards(ctx context.Context, locationID string, query *locations.LocationCardsQuery) (*locations.LocationCards, error) {
+	ret := _mock.Called(ctx, locationID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLocationCards")
+	}
+
+	var r0 *locations.LocationCards
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *locations.LocationCardsQuery) (*locations.LocationCards, error)); ok {
+		return returnFunc(ctx, locationID, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *locations.LocationCardsQuery) *locations.LocationCards); ok {
+		r0 = returnFunc(ctx, locationID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*locations.LocationCards)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *locations.LocationCardsQuery) error); ok {
+		r1 = returnFunc(ctx, locationID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListLocationCards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLocationCards'
+type MockCollectorClient_ListLocationCards_Call struct {
+	*mock.Call
+}
+
+// ListLocationCards is a helper method to define mock.On call
+//   - ctx
+//   - locationID
+//   - query
+func (_e *MockCollectorClient_Expecter) ListLocationCards(ctx interface{}, locationID interface{}, query interface{}) *MockCollectorClient_ListLocationCards_Call {
+	return &MockCollectorClient_ListLocationCards_Call{Call: _e.mock.On("ListLocationCards", ctx, locationID, query)}
+}
+
+func (_c *MockCollectorClient_ListLocationCards_Call) Run(run func(ctx context.Context, locationID string, query *locations.LocationCardsQuery)) *MockCollectorClient_ListLocationCards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*locations.LocationCardsQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListLocationCards_Call) Return(locationCards *locations.LocationCards, err error) *MockCollectorClient_ListLocationCards_Call {
+	_c.Call.Return(locationCards, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListLocationCards_Call) RunAndReturn(run func(ctx context.Context, locationID string, query *locations.LocationCardsQuery) (*locations.LocationCards, error)) *MockCollectorClient_ListLocationCards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLocations provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListLocations(ctx context.Context) ([]locations.StorageLocation, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLocations")
+	}
+
+	var r0 []locations.StorageLocation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]locations.StorageLocation, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []locations.StorageLocation); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]locations.StorageLocation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListLocations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLocations'
+type MockCollectorClient_ListLocations_Call struct {
+	*mock.Call
+}
+
+// ListLocations is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) ListLocations(ctx interface{}) *MockCollectorClient_ListLocations_Call {
+	return &MockCollectorClient_ListLocations_Call{Call: _e.mock.On("ListLocations", ctx)}
+}
+
+func (_c *MockCollectorClient_ListLocations_Call) Run(run func(ctx context.Context)) *MockCollectorClient_ListLocations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListLocations_Call) Return(storageLocations []locations.StorageLocation, err error) *MockCollectorClient_ListLocations_Call {
+	_c.Call.Return(storageLocations, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListLocations_Call) RunAndReturn(run func(ctx context.Context) ([]locations.StorageLocation, error)) *MockCollectorClient_ListLocations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListMissingCards provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListMissingCards(ctx context.Context) ([]wishlist.MissingEntry, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMissingCards")
+	}
+
+	var r0 []wishlist.MissingEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]wishlist.MissingEntry, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []wishlist.MissingEntry); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]wishlist.MissingEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListMissingCards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListMissingCards'
+type MockCollectorClient_ListMissingCards_Call struct {
+	*mock.Call
+}
+
+// ListMissingCards is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) ListMissingCards(ctx interface{}) *MockCollectorClient_ListMissingCards_Call {
+	return &MockCollectorClient_ListMissingCards_Call{Call: _e.mock.On("ListMissingCards", ctx)}
+}
+
+func (_c *MockCollectorClient_ListMissingCards_Call) Run(run func(ctx context.Context)) *MockCollectorClient_ListMissingCards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListMissingCards_Call) Return(missingEntrys []wishlist.MissingEntry, err error) *MockCollectorClient_ListMissingCards_Call {
+	_c.Call.Return(missingEntrys, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListMissingCards_Call) RunAndReturn(run func(ctx context.Context) ([]wishlist.MissingEntry, error)) *MockCollectorClient_ListMissingCards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSnapshots provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListSnapshots(ctx context.Context, collectionID string) ([]collections.Snapshot, error) {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSnapshots")
+	}
+
+	var r0 []collections.Snapshot
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]collections.Snapshot, error)); ok {
+		return returnFunc(ctx, collectionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []collections.Snapshot); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]collections.Snapshot)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSnapshots'
+type MockCollectorClient_ListSnapshots_Call struct {
+	*mock.Call
+}
+
+// ListSnapshots is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) ListSnapshots(ctx interface{}, collectionID interface{}) *MockCollectorClient_ListSnapshots_Call {
+	return &MockCollectorClient_ListSnapshots_Call{Call: _e.mock.On("ListSnapshots", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_ListSnapshots_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_ListSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListSnapshots_Call) Return(snapshots []collections.Snapshot, err error) *MockCollectorClient_ListSnapshots_Call {
+	_c.Call.Return(snapshots, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListSnapshots_Call) RunAndReturn(run func(ctx context.Context, collectionID string) ([]collections.Snapshot, error)) *MockCollectorClient_ListSnapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTrash provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListTrash(ctx context.Context) ([]collections.DeletedCollection, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrash")
+	}
+
+	var r0 []collections.DeletedCollection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]collections.DeletedCollection, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []collections.DeletedCollection); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]collections.DeletedCollection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListTrash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTrash'
+type MockCollectorClient_ListTrash_Call struct {
+	*mock.Call
+}
+
+// ListTrash is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) ListTrash(ctx interface{}) *MockCollectorClient_ListTrash_Call {
+	return &MockCollectorClient_ListTrash_Call{Call: _e.mock.On("ListTrash", ctx)}
+}
+
+func (_c *MockCollectorClient_ListTrash_Call) Run(run func(ctx context.Context)) *MockCollectorClient_ListTrash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListTrash_Call) Return(deletedCollections []collections.DeletedCollection, err error) *MockCollectorClient_ListTrash_Call {
+	_c.Call.Return(deletedCollections, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListTrash_Call) RunAndReturn(run func(ctx context.Context) ([]collections.DeletedCollection, error)) *MockCollectorClient_ListTrash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWishlist provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ListWishlist(ctx context.Context) ([]wishlist.Entry, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWishlist")
+	}
+
+	var r0 []wishlist.Entry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]wishlist.Entry, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []wishlist.Entry); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]wishlist.Entry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ListWishlist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWishlist'
+type MockCollectorClient_ListWishlist_Call struct {
+	*mock.Call
+}
+
+// ListWishlist is a helper method to define mock.On call
+//   - ctx
+func (_e *MockCollectorClient_Expecter) ListWishlist(ctx interface{}) *MockCollectorClient_ListWishlist_Call {
+	return &MockCollectorClient_ListWishlist_Call{Call: _e.mock.On("ListWishlist", ctx)}
+}
+
+func (_c *MockCollectorClient_ListWishlist_Call) Run(run func(ctx context.Context)) *MockCollectorClient_ListWishlist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ListWishlist_Call) Return(entrys []wishlist.Entry, err error) *MockCollectorClient_ListWishlist_Call {
+	_c.Call.Return(entrys, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ListWishlist_Call) RunAndReturn(run func(ctx context.Context) ([]wishlist.Entry, error)) *MockCollectorClient_ListWishlist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergeCollections provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) MergeCollections(ctx context.Context, collectionID string, req *collections.MergeCollectionRequest) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeCollections")
+	}
+
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.MergeCollectionRequest) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.MergeCollectionRequest) *collections.Collection); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.MergeCollectionRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_MergeCollections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeCollections'
+type MockCollectorClient_MergeCollections_Call struct {
+	*mock.Call
+}
+
+// MergeCollections is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) MergeCollections(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_MergeCollections_Call {
+	return &MockCollectorClient_MergeCollections_Call{Call: _e.mock.On("MergeCollections", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_MergeCollections_Call) Run(run func(ctx context.Context, collectionID string, req *collections.MergeCollectionRequest)) *MockCollectorClient_MergeCollections_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.MergeCollectionRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_MergeCollections_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_MergeCollections_Call {
+	_c.Call.Return(collection, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_MergeCollections_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.MergeCollectionRequest) (*collections.Collection, error)) *MockCollectorClient_MergeCollections_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterUser provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) RegisterUser(ctx context.Context, reqData *auth.RegisterRequest) (*auth.RegisterResponse, error) {
+	ret := _mock.Called(ctx, reqData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterUser")
+	}
+
+	var r0 *auth.RegisterResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.RegisterRequest) (*auth.RegisterResponse, error)); ok {
+		return returnFunc(ctx, reqData)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.RegisterRequest) *auth.RegisterResponse); ok {
+		r0 = returnFunc(ctx, reqData)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.RegisterResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *auth.RegisterRequest) error); ok {
+		r1 = returnFunc(ctx, reqData)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_RegisterUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterUser'
+type MockCollectorClient_RegisterUser_Call struct {
+	*mock.Call
+}
+
+// RegisterUser is a helper method to define mock.On call
+//   - ctx
+//   - reqData
+func (_e *MockCollectorClient_Expecter) RegisterUser(ctx interface{}, reqData interface{}) *MockCollectorClient_RegisterUser_Call {
+	return &MockCollectorClient_RegisterUser_Call{Call: _e.mock.On("RegisterUser", ctx, reqData)}
+}
+
+func (_c *MockCollectorClient_RegisterUser_Call) Run(run func(ctx context.Context, reqData *auth.RegisterRequest)) *MockCollectorClient_RegisterUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*auth.RegisterRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_RegisterUser_Call) Return(registerResponse *auth.RegisterResponse, err error) *MockCollectorClient_RegisterUser_Call {
+	_c.Call.Return(registerResponse, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_RegisterUser_Call) RunAndReturn(run func(ctx context.Context, reqData *auth.RegisterRequest) (*auth.RegisterResponse, error)) *MockCollectorClient_RegisterUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseCard provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ReleaseCard(ctx context.Context, collectionID string, allocationID string) error {
+	ret := _mock.Called(ctx, collectionID, allocationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseCard")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, collectionID, allocationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_ReleaseCard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseCard'
+type MockCollectorClient_ReleaseCard_Call struct {
+	*mock.Call
+}
+
+// ReleaseCard is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - allocationID
+func (_e *MockCollectorClient_Expecter) ReleaseCard(ctx interface{}, collectionID interface{}, allocationID interface{}) *MockCollectorClient_ReleaseCard_Call {
+	return &MockCollectorClient_ReleaseCard_Call{Call: _e.mock.On("ReleaseCard", ctx, collectionID, allocationID)}
+}
+
+func (_c *MockCollectorClient_ReleaseCard_Call) Run(run func(ctx context.Context, collectionID string, allocationID string)) *MockCollectorClient_ReleaseCard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ReleaseCard_Call) Return(err error) *MockCollectorClient_ReleaseCard_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ReleaseCard_Call) RunAndReturn(run func(ctx context.Context, collectionID string, allocationID string) error) *MockCollectorClient_ReleaseCard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RelocateCards provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) RelocateCards(ctx context.Context, collectionID string, req *locations.RelocateCardsRequest) (*locations.RelocateCardsResponse, error) {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RelocateCards")
+	}
+
+	var r0 *locations.RelocateCardsResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *locations.RelocateCardsRequest) (*locations.RelocateCardsResponse, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *locations.RelocateCardsRequest) *locations.RelocateCardsResponse); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*locations.RelocateCardsResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *locations.RelocateCardsRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_RelocateCards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RelocateCards'
+type MockCollectorClient_RelocateCards_Call struct {
+	*mock.Call
+}
+
+// RelocateCards is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) RelocateCards(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_RelocateCards_Call {
+	return &MockCollectorClient_RelocateCards_Call{Call: _e.mock.On("RelocateCards", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_RelocateCards_Call) Run(run func(ctx context.Context, collectionID string, req *locations.RelocateCardsRequest)) *MockCollectorClient_RelocateCards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*locations.RelocateCardsRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_RelocateCards_Call) Return(relocateCardsResponse *locations.RelocateCardsResponse, err error) *MockCollectorClient_RelocateCards_Call {
+	_c.Call.Return(relocateCardsResponse, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_RelocateCards_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *locations.RelocateCardsRequest) (*locations.RelocateCardsResponse, error)) *MockCollectorClient_RelocateCards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenameCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) RenameCollection(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.RenameCollectionRequest) error); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_RenameCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenameCollection'
+type MockCollectorClient_RenameCollection_Call struct {
+	*mock.Call
+}
+
+// RenameCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) RenameCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_RenameCollection_Call {
+	return &MockCollectorClient_RenameCollection_Call{Call: _e.mock.On("RenameCollection", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_RenameCollection_Call) Run(run func(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest)) *MockCollectorClient_RenameCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.RenameCollectionRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_RenameCollection_Call) Return(err error) *MockCollectorClient_RenameCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_RenameCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error) *MockCollectorClient_RenameCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReserveCard provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ReserveCard(ctx context.Context, collectionID string, req *collections.ReserveCardRequest) (*collections.Allocation, error) {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveCard")
+	}
+
+	var r0 *collections.Allocation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.ReserveCardRequest) (*collections.Allocation, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.ReserveCardRequest) *collections.Allocation); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Allocation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.ReserveCardRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ReserveCard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveCard'
+type MockCollectorClient_ReserveCard_Call struct {
+	*mock.Call
+}
+
+// ReserveCard is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) ReserveCard(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_ReserveCard_Call {
+	return &MockCollectorClient_ReserveCard_Call{Call: _e.mock.On("ReserveCard", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_ReserveCard_Call) Run(run func(ctx context.Context, collectionID string, req *collections.ReserveCardRequest)) *MockCollectorClient_ReserveCard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.ReserveCardRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ReserveCard_Call) Return(allocation *collections.Allocation, err error) *MockCollectorClient_ReserveCard_Call {
+	_c.Call.Return(allocation, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ReserveCard_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.ReserveCardRequest) (*collections.Allocation, error)) *MockCollectorClient_ReserveCard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) RestoreCollection(ctx context.Context, collectionID string) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreCollection")
+	}
+
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, collectionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *collections.Collection); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_RestoreCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreCollection'
+type MockCollectorClient_RestoreCollection_Call struct {
+	*mock.Call
+}
+
+// RestoreCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) RestoreCollection(ctx interface{}, collectionID interface{}) *MockCollectorClient_RestoreCollection_Call {
+	return &MockCollectorClient_RestoreCollection_Call{Call: _e.mock.On("RestoreCollection", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_RestoreCollection_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_RestoreCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_RestoreCollection_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_RestoreCollection_Call {
+	_c.Call.Return(collection, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_RestoreCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string) (*collections.Collection, error)) *MockCollectorClient_RestoreCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetCardCountInCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) SetCardCountInCollection(ctx context.Context, collectionID string, scryfallID string, req *cards.SetCardCountRequest, entry *cards.CardEntryQuery) error {
+	ret := _mock.Called(ctx, collectionID, scryfallID, req, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCardCountInCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.SetCardCountRequest, *cards.CardEntryQuery) error); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, req, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_SetCardCountInCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCardCountInCollection'
+type MockCollectorClient_SetCardCountInCollection_Call struct {
+	*mock.Call
+}
+
+// SetCardCountInCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - req
+//   - entry
+func (_e *MockCollectorClient_Expecter) SetCardCountInCollection(ctx interface{}, collectionID interface{}, scryfallID interface{}, req interface{}, entry interface{}) *MockCollectorClient_SetCardCountInCollection_Call {
+	return &MockCollectorClient_SetCardCountInCollection_Call{Call: _e.mock.On("SetCardCountInCollection", ctx, collectionID, scryfallID, req, entry)}
+}
+
+func (_c *MockCollectorClient_SetCardCountInCollection_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.SetCardCountRequest, entry *cards.CardEntryQuery)) *MockCollectorClient_SetCardCountInCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.SetCardCountRequest), args[4].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_SetCardCountInCollection_Call) Return(err error) *MockCollectorClient_SetCardCountInCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_SetCardCountInCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.SetCardCountRequest, entry *cards.CardEntryQuery) error) *MockCollectorClient_SetCardCountInCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SplitCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) SplitCollection(ctx context.Context, collectionID string, req *collections.SplitCollectionRequest) (*collections.Collection, error) {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitCollection")
+	}
+
+	var r0 *collections.Collection
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.SplitCollectionRequest) (*collections.Collection, error)); ok {
+		return returnFunc(ctx, collectionID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.SplitCollectionRequest) *collections.Collection); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.Collection)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *collections.SplitCollectionRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_SplitCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SplitCollection'
+type MockCollectorClient_SplitCollection_Call struct {
+	*mock.Call
+}
+
+// SplitCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) SplitCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_SplitCollection_Call {
+	return &MockCollectorClient_SplitCollection_Call{Call: _e.mock.On("SplitCollection", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_SplitCollection_Call) Run(run func(ctx context.Context, collectionID string, req *collections.SplitCollectionRequest)) *MockCollectorClient_SplitCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.SplitCollectionRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_SplitCollection_Call) Return(collection *collections.Collection, err error) *MockCollectorClient_SplitCollection_Call {
+	_c.Call.Return(collection, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_SplitCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.SplitCollectionRequest) (*collections.Collection, error)) *MockCollectorClient_SplitCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransferCard provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) TransferCard(ctx context.Context, collectionID string, scryfallID string, req *cards.TransferCardRequest, entry *cards.CardEntryQuery) (*cards.TransferCardResponse, error) {
+	ret := _mock.Called(ctx, collectionID, scryfallID, req, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferCard")
+	}
+
+	var r0 *cards.TransferCardResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.TransferCardRequest, *cards.CardEntryQuery) (*cards.TransferCardResponse, error)); ok {
+		return returnFunc(ctx, collectionID, scryfallID, req, entry)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.TransferCardRequest, *cards.CardEntryQuery) *cards.TransferCardResponse); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, req, entry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cards.TransferCardResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *cards.TransferCardRequest, *cards.CardEntryQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, scryfallID, req, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_TransferCard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferCard'
+type MockCollectorClient_TransferCard_Call struct {
+	*mock.Call
+}
+
+// TransferCard is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - req
+//   - entry
+func (_e *MockCollectorClient_Expecter) TransferCard(ctx interface{}, collectionID interface{}, scryfallID interface{}, req interface{}, entry interface{}) *MockCollectorClient_TransferCard_Call {
+	return &MockCollectorClient_TransferCard_Call{Call: _e.mock.On("TransferCard", ctx, collectionID, scryfallID, req, entry)}
+}
+
+func (_c *MockCollectorClient_TransferCard_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.TransferCardRequest, entry *cards.CardEntryQuery)) *MockCollectorClient_TransferCard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.TransferCardRequest), args[4].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_TransferCard_Call) Return(transferCardResponse *cards.TransferCardResponse, err error) *MockCollectorClient_TransferCard_Call {
+	_c.Call.Return(transferCardResponse, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_TransferCard_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.TransferCardRequest, entry *cards.CardEntryQuery) (*cards.TransferCardResponse, error)) *MockCollectorClient_TransferCard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UndoCardChange provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) UndoCardChange(ctx context.Context, collectionID string, entryID string, req *cards.UndoChangeRequest) (*cards.UndoChangeResponse, error) {
+	ret := _mock.Called(ctx, collectionID, entryID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UndoCardChange")
+	}
+
+	var r0 *cards.UndoChangeResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.UndoChangeRequest) (*cards.UndoChangeResponse, error)); ok {
+		return returnFunc(ctx, collectionID, entryID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.UndoChangeRequest) *cards.UndoChangeResponse); ok {
+		r0 = returnFunc(ctx, collectionID, entryID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cards.UndoChangeResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *cards.UndoChangeRequest) error); ok {
+		r1 = returnFunc(ctx, collectionID, entryID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_UndoCardChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UndoCardChange'
+type MockCollectorClient_UndoCardChange_Call struct {
+	*mock.Call
+}
+
+// UndoCardChange is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - entryID
+//   - req
+func (_e *MockCollectorClient_Expecter) UndoCardChange(ctx interface{}, collectionID interface{}, entryID interface{}, req interface{}) *MockCollectorClient_UndoCardChange_Call {
+	return &MockCollectorClient_UndoCardChange_Call{Call: _e.mock.On("UndoCardChange", ctx, collectionID, entryID, req)}
+}
+
+func (_c *MockCollectorClient_UndoCardChange_Call) Run(run func(ctx context.Context, collectionID string, entryID string, req *cards.UndoChangeRequest)) *MockCollectorClient_UndoCardChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.UndoChangeRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_UndoCardChange_Call) Return(undoChangeResponse *cards.UndoChangeResponse, err error) *MockCollectorClient_UndoCardChange_Call {
+	_c.Call.Return(undoChangeResponse, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_UndoCardChange_Call) RunAndReturn(run func(ctx context.Context, collectionID string, entryID string, req *cards.UndoChangeRequest) (*cards.UndoChangeResponse, error)) *MockCollectorClient_UndoCardChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAcquisition provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) UpdateAcquisition(ctx context.Context, collectionID string, scryfallID string, acquisitionID string, req *cards.UpdateAcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error) {
+	ret := _mock.Called(ctx, collectionID, scryfallID, acquisitionID, req, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAcquisition")
+	}
+
+	var r0 *cards.Acquisition
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, *cards.UpdateAcquisitionRequest, *cards.CardEntryQuery) (*cards.Acquisition, error)); ok {
+		return returnFunc(ctx, collectionID, scryfallID, acquisitionID, req, entry)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, *cards.UpdateAcquisitionRequest, *cards.CardEntryQuery) *cards.Acquisition); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, acquisitionID, req, entry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cards.Acquisition)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, *cards.UpdateAcquisitionRequest, *cards.CardEntryQuery) error); ok {
+		r1 = returnFunc(ctx, collectionID, scryfallID, acquisitionID, req, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_UpdateAcquisition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAcquisition'
+type MockCollectorClient_UpdateAcquisition_Call struct {
+	*mock.Call
+}
+
+// UpdateAcquisition is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - acquisitionID
+//   - req
+//   - entry
+func (_e *MockCollectorClient_Expecter) UpdateAcquisition(ctx interface{}, collectionID interface{}, scryfallID interface{}, acquisitionID interface{}, req interface{}, entry interface{}) *MockCollectorClient_UpdateAcquisition_Call {
+	return &MockCollectorClient_UpdateAcquisition_Call{Call: _e.mock.On("UpdateAcquisition", ctx, collectionID, scryfallID, acquisitionID, req, entry)}
+}
+
+func (_c *MockCollectorClient_UpdateAcquisition_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, acquisitionID string, req *cards.UpdateAcquisitionRequest, entry *cards.CardEntryQuery)) *MockCollectorClient_UpdateAcquisition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*cards.UpdateAcquisitionRequest), args[5].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateAcquisition_Call) Return(acquisition *cards.Acquisition, err error) *MockCollectorClient_UpdateAcquisition_Call {
+	_c.Call.Return(acquisition, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateAcquisition_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, acquisitionID string, req *cards.UpdateAcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error)) *MockCollectorClient_UpdateAcquisition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCardInCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) UpdateCardInCollection(ctx context.Context, collectionID string, scryfallID string, req *cards.UpdateCardRequest, entry *cards.CardEntryQuery) error {
+	ret := _mock.Called(ctx, collectionID, scryfallID, req, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCardInCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *cards.UpdateCardRequest, *cards.CardEntryQuery) error); ok {
+		r0 = returnFunc(ctx, collectionID, scryfallID, req, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_UpdateCardInCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCardInCollection'
+type MockCollectorClient_UpdateCardInCollection_Call struct {
+	*mock.Call
+}
+
+// UpdateCardInCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - scryfallID
+//   - req
+//   - entry
+func (_e *MockCollectorClient_Expecter) UpdateCardInCollection(ctx interface{}, collectionID interface{}, scryfallID interface{}, req interface{}, entry interface{}) *MockCollectorClient_UpdateCardInCollection_Call {
+	return &MockCollectorClient_UpdateCardInCollection_Call{Call: _e.mock.On("UpdateCardInCollection", ctx, collectionID, scryfallID, req, entry)}
+}
+
+func (_c *MockCollectorClient_UpdateCardInCollection_Call) Run(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.UpdateCardRequest, entry *cards.CardEntryQuery)) *MockCollectorClient_UpdateCardInCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*cards.UpdateCardRequest), args[4].(*cards.CardEntryQuery))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateCardInCollection_Call) Return(err error) *MockCollectorClient_UpdateCardInCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateCardInCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, scryfallID string, req *cards.UpdateCardRequest, entry *cards.CardEntryQuery) error) *MockCollectorClient_UpdateCardInCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCollection provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) UpdateCollection(ctx context.Context, collectionID string, req *collections.UpdateCollectionRequest) error {
+	ret := _mock.Called(ctx, collectionID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *collections.UpdateCollectionRequest) error); ok {
+		r0 = returnFunc(ctx, collectionID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCollectorClient_UpdateCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCollection'
+type MockCollectorClient_UpdateCollection_Call struct {
+	*mock.Call
+}
+
+// UpdateCollection is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+//   - req
+func (_e *MockCollectorClient_Expecter) UpdateCollection(ctx interface{}, collectionID interface{}, req interface{}) *MockCollectorClient_UpdateCollection_Call {
+	return &MockCollectorClient_UpdateCollection_Call{Call: _e.mock.On("UpdateCollection", ctx, collectionID, req)}
+}
+
+func (_c *MockCollectorClient_UpdateCollection_Call) Run(run func(ctx context.Context, collectionID string, req *collections.UpdateCollectionRequest)) *MockCollectorClient_UpdateCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*collections.UpdateCollectionRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateCollection_Call) Return(err error) *MockCollectorClient_UpdateCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateCollection_Call) RunAndReturn(run func(ctx context.Context, collectionID string, req *collections.UpdateCollectionRequest) error) *MockCollectorClient_UpdateCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserSettings provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) UpdateUserSettings(ctx context.Context, req *auth.UpdateSettingsRequest) (*auth.UserSettings, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserSettings")
+	}
+
+	var r0 *auth.UserSettings
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.UpdateSettingsRequest) (*auth.UserSettings, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.UpdateSettingsRequest) *auth.UserSettings); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.UserSettings)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *auth.UpdateSettingsRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_UpdateUserSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserSettings'
+type MockCollectorClient_UpdateUserSettings_Call struct {
+	*mock.Call
+}
+
+// UpdateUserSettings is a helper method to define mock.On call
+//   - ctx
+//   - req
+func (_e *MockCollectorClient_Expecter) UpdateUserSettings(ctx interface{}, req interface{}) *MockCollectorClient_UpdateUserSettings_Call {
+	return &MockCollectorClient_UpdateUserSettings_Call{Call: _e.mock.On("UpdateUserSettings", ctx, req)}
+}
+
+func (_c *MockCollectorClient_UpdateUserSettings_Call) Run(run func(ctx context.Context, req *auth.UpdateSettingsRequest)) *MockCollectorClient_UpdateUserSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*auth.UpdateSettingsRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateUserSettings_Call) Return(userSettings *auth.UserSettings, err error) *MockCollectorClient_UpdateUserSettings_Call {
+	_c.Call.Return(userSettings, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateUserSettings_Call) RunAndReturn(run func(ctx context.Context, req *auth.UpdateSettingsRequest) (*auth.UserSettings, error)) *MockCollectorClient_UpdateUserSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWishlistEntry provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) UpdateWishlistEntry(ctx context.Context, entryID string, req *wishlist.UpdateEntryRequest) (*wishlist.Entry, error) {
+	ret := _mock.Called(ctx, entryID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWishlistEntry")
+	}
+
+	var r0 *wishlist.Entry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *wishlist.UpdateEntryRequest) (*wishlist.Entry, error)); ok {
+		return returnFunc(ctx, entryID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *wishlist.UpdateEntryRequest) *wishlist.Entry); ok {
+		r0 = returnFunc(ctx, entryID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wishlist.Entry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *wishlist.UpdateEntryRequest) error); ok {
+		r1 = returnFunc(ctx, entryID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_UpdateWishlistEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWishlistEntry'
+type MockCollectorClient_UpdateWishlistEntry_Call struct {
+	*mock.Call
+}
+
+// UpdateWishlistEntry is a helper method to define mock.On call
+//   - ctx
+//   - entryID
+//   - req
+func (_e *MockCollectorClient_Expecter) UpdateWishlistEntry(ctx interface{}, entryID interface{}, req interface{}) *MockCollectorClient_UpdateWishlistEntry_Call {
+	return &MockCollectorClient_UpdateWishlistEntry_Call{Call: _e.mock.On("UpdateWishlistEntry", ctx, entryID, req)}
+}
+
+func (_c *MockCollectorClient_UpdateWishlistEntry_Call) Run(run func(ctx context.Context, entryID string, req *wishlist.UpdateEntryRequest)) *MockCollectorClient_UpdateWishlistEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*wishlist.UpdateEntryRequest))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateWishlistEntry_Call) Return(entry *wishlist.Entry, err error) *MockCollectorClient_UpdateWishlistEntry_Call {
+	_c.Call.Return(entry, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_UpdateWishlistEntry_Call) RunAndReturn(run func(ctx context.Context, entryID string, req *wishlist.UpdateEntryRequest) (*wishlist.Entry, error)) *MockCollectorClient_UpdateWishlistEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateDeck provides a mock function for the type MockCollectorClient
+func (_mock *MockCollectorClient) ValidateDeck(ctx context.Context, collectionID string) (*collections.DeckValidation, error) {
+	ret := _mock.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateDeck")
+	}
+
+	var r0 *collections.DeckValidation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*collections.DeckValidation, error)); ok {
+		return returnFunc(ctx, collectionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *collections.DeckValidation); ok {
+		r0 = returnFunc(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*collections.DeckValidation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollectorClient_ValidateDeck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateDeck'
+type MockCollectorClient_ValidateDeck_Call struct {
+	*mock.Call
+}
+
+// ValidateDeck is a helper method to define mock.On call
+//   - ctx
+//   - collectionID
+func (_e *MockCollectorClient_Expecter) ValidateDeck(ctx interface{}, collectionID interface{}) *MockCollectorClient_ValidateDeck_Call {
+	return &MockCollectorClient_ValidateDeck_Call{Call: _e.mock.On("ValidateDeck", ctx, collectionID)}
+}
+
+func (_c *MockCollectorClient_ValidateDeck_Call) Run(run func(ctx context.Context, collectionID string)) *MockCollectorClient_ValidateDeck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorClient_ValidateDeck_Call) Return(deckValidation *collections.DeckValidation, err error) *MockCollectorClient_ValidateDeck_Call {
+	_c.Call.Return(deckValidation, err)
+	return _c
+}
+
+func (_c *MockCollectorClient_ValidateDeck_Call) RunAndReturn(run func(ctx context.Context, collectionID string) (*collections.DeckValidation, error)) *MockCollectorClient_ValidateDeck_Call {
 	_c.Call.Return(run)
 	return _c
 }
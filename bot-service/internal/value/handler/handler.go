@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ShenokZlob/collector-ouphe/bot-service/internal/chart"
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// intervals are the intervals the value command accepts before the collection name.
+var intervals = []string{collections.IntervalDay, collections.IntervalWeek, collections.IntervalMonth}
+
+type ValueHandler struct {
+	log     logger.Logger
+	usecase ValueUsecase
+}
+
+type ValueUsecase interface {
+	ValueHistory(ctx context.Context, collectionName, interval string) (*collections.ValueHistory, error)
+}
+
+func NewValueHandler(log logger.Logger, usecase ValueUsecase) *ValueHandler {
+	return &ValueHandler{
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+// ValueHistoryCommand sends a chart of how the value of a collection, or of all
+// collections without a name, changed: /value [day|week|month] [collection name]
+func (h *ValueHandler) ValueHistoryCommand(ctx context.Context, b *bot.Bot, update *models.Update) {
+	h.log.Info("ValueHistoryCommand executing")
+	chatID := update.Message.Chat.ID
+
+	args := strings.Fields(update.Message.Text)[1:]
+	interval := collections.IntervalDay
+	if len(args) > 0 && slices.Contains(intervals, strings.ToLower(args[0])) {
+		interval = strings.ToLower(args[0])
+		args = args[1:]
+	}
+	collectionName := strings.Join(args, " ")
+
+	history, err := h.usecase.ValueHistory(ctx, collectionName, interval)
+	if err != nil {
+		h.log.Error("Failed to get value history", logger.Error(err), logger.String("collection_name", collectionName))
+		text := "Не получилось получить историю стоимости :("
+		if errors.Is(err, collectorclient.ErrNotFound) {
+			text = "У вас нет такой коллекции!"
+		}
+		b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+		return
+	}
+
+	if !slices.ContainsFunc(history.Points, func(p collections.ValuePoint) bool { return p.PricedCount > 0 }) {
+		b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   "Цены этих карт пока неизвестны.",
+		})
+		return
+	}
+
+	points := make([]chart.Point, 0, len(history.Points))
+	for _, p := range history.Points {
+		points = append(points, chart.Point{Date: p.Date, Value: p.Value})
+	}
+	image, err := chart.Line(points)
+	if err != nil {
+		h.log.Error("Failed to draw value chart", logger.Error(err))
+		b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   "Не получилось нарисовать график :(",
+		})
+		return
+	}
+
+	b.SendPhoto(ctx, &bot.SendPhotoParams{
+		ChatID:  chatID,
+		Photo:   &models.InputFileUpload{Filename: "value.png", Data: bytes.NewReader(image)},
+		Caption: formatValueHistory(collectionName, history),
+	})
+}
+
+func formatValueHistory(collectionName string, history *collections.ValueHistory) string {
+	currency := strings.ToUpper(history.Currency)
+	last := history.Points[len(history.Points)-1]
+
+	var sb strings.Builder
+	if collectionName == "" {
+		sb.WriteString("Стоимость всех коллекций")
+	} else {
+		sb.WriteString("Стоимость коллекции «" + collectionName + "»")
+	}
+	fmt.Fprintf(&sb, " с %s по %s: %.2f %s\n", history.From.Format("02.01.2006"), history.To.Format("02.01.2006"), last.Value, currency)
+	fmt.Fprintf(&sb, "Изменение: %s %s", formatChange(history.Change, history.ChangePercent), currency)
+	if history.RatesDate != nil {
+		fmt.Fprintf(&sb, "\nПо курсу на %s", history.RatesDate.Format("02.01.2006"))
+	}
+
+	if len(history.Movers) > 0 {
+		sb.WriteString("\n\nБольше всего изменились:")
+		for _, m := range history.Movers {
+			fmt.Fprintf(&sb, "\n• %s ×%d: %s", m.Name, m.Count, formatChange(m.Change, m.ChangePercent))
+		}
+	}
+	return sb.String()
+}
+
+// formatChange shows a change with its sign and, if known, its percent.
+func formatChange(change float64, percent *float64) string {
+	text := fmt.Sprintf("%+.2f", change)
+	if percent != nil {
+		text += fmt.Sprintf(" (%+.2f%%)", *percent)
+	}
+	return text
+}
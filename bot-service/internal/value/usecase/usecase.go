@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type valueUsecaseImpl struct {
+	log             logger.Logger
+	collectorClient collectorclient.CollectorClientCollections
+}
+
+func NewValueUsecaseImpl(log logger.Logger, client collectorclient.CollectorClientCollections) *valueUsecaseImpl {
+	return &valueUsecaseImpl{
+		log:             log,
+		collectorClient: client,
+	}
+}
+
+// ValueHistory returns the value history of the user's collection with the given
+// name, or of all the user's collections if the name is empty.
+func (u *valueUsecaseImpl) ValueHistory(ctx context.Context, collectionName, interval string) (*collections.ValueHistory, error) {
+	u.log.Info("Get value history", logger.String("method", "ValueHistory"), logger.String("interval", interval))
+
+	query := &collections.ValueHistoryQuery{Interval: interval}
+	if collectionName == "" {
+		history, err := u.collectorClient.GetValueHistory(ctx, query)
+		if err != nil {
+			u.log.Error("Error when accessing the collector service", logger.Error(err))
+			return nil, err
+		}
+		return history, nil
+	}
+
+	collection, err := u.collectorClient.GetUsersCollectionByName(ctx, collectionName)
+	if err != nil {
+		u.log.Error("Error when accessing the collector service", logger.Error(err))
+		return nil, err
+	}
+
+	history, err := u.collectorClient.GetCollectionValueHistory(ctx, collection.ID, query)
+	if err != nil {
+		u.log.Error("Error when accessing the collector service", logger.Error(err))
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/fake"
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+// go test github.com/ShenokZlob/collector-ouphe/bot-service/internal/value/usecase -run ValueUsecaseTestSuite
+type ValueUsecaseTestSuite struct {
+	suite.Suite
+	server  *fake.Server
+	client  *collectorclient.HTTPCollectorClient
+	usecase *valueUsecaseImpl
+	ctx     context.Context
+}
+
+func TestValueUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, &ValueUsecaseTestSuite{})
+}
+
+func (s *ValueUsecaseTestSuite) SetupTest() {
+	s.server = fake.NewServer()
+	s.client = collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{})
+	s.usecase = NewValueUsecaseImpl(logger.SilentLogger{}, s.client)
+
+	resp, err := s.client.RegisterUser(context.Background(), &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.Require().NoError(err)
+	s.ctx = authctx.WithJWT(context.Background(), resp.Token)
+}
+
+func (s *ValueUsecaseTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ValueUsecaseTestSuite) TestValueHistory() {
+	binder, err := s.client.CreateCollection(s.ctx, &collections.CreateCollectionRequest{Name: "Binder"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(s.ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 2}))
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	s.server.SetPrices("ring", today.AddDate(0, 0, -1), fake.Prices{USD: 1})
+	s.server.SetPrices("ring", today, fake.Prices{USD: 1.5})
+
+	history, err := s.usecase.ValueHistory(s.ctx, "Binder", collections.IntervalDay)
+	s.Require().NoError(err)
+	s.Equal(binder.ID, history.CollectionID)
+	s.Require().Len(history.Points, 30)
+	s.Equal(0.0, history.Points[28].Value, "the rings were added today")
+	s.Equal(3.0, history.Points[29].Value)
+	s.Equal(3.0, history.Points[29].Change)
+
+	history, err = s.usecase.ValueHistory(s.ctx, "", collections.IntervalWeek)
+	s.Require().NoError(err)
+	s.Empty(history.CollectionID)
+	s.Equal(collections.IntervalWeek, history.Interval)
+	s.Equal(3.0, history.Points[len(history.Points)-1].Value)
+
+	_, err = s.usecase.ValueHistory(s.ctx, "Deck", collections.IntervalDay)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
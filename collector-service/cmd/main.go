@@ -13,6 +13,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -25,22 +26,30 @@ import (
 )
 
 func main() {
-	log, err := logger.NewZapLogger(false)
+	cfg, err := config.InitConfig()
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	defer log.Sync()
 
-	log.Info("Starting collector service")
+	log, err := logger.New(cfg.Logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer log.Sync()
 
-	log.Info("Read config")
-	cfg := config.InitConfig()
+	log.Info("Starting collector service", logger.Bool("production", cfg.Logger.Production))
 
 	log.Info("Init database")
 	db := app.InitDataBase(cfg)
 
+	log.Info("Init card catalog")
+	cards := app.InitCatalog(cfg)
+	log.Info("Card catalog loaded", logger.Int("cards", cards.Len()))
+
 	log.Info("Init app server")
-	appServer := app.InitServer(cfg, log, db)
+	appServer := app.InitServer(cfg, log, db, cards)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer stop()
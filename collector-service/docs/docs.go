@@ -15,6 +15,97 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/allocations/conflicts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить карты, зарезервированные для колод больше раз, чем их копий в коллекции-источнике,\nи колоды, в которых они лежат",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Allocations"
+                ],
+                "summary": "Get allocation conflicts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/collections.AllocationConflict"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cards/owned": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Найти карту во всех коллекциях пользователя, кроме вишлистов: где лежат копии и сколько их по печатям.\nНужен ровно один параметр: scryfall_id ищет эту печать, oracle_id и name — все печати карты",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Find owned cards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID печати",
+                        "name": "scryfall_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Oracle ID карты",
+                        "name": "oracle_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название карты или ее лицевой стороны",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cards.OwnedCards"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/collections": {
             "get": {
                 "security": [
@@ -22,7 +113,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить список коллекций текущего пользователя",
+                "description": "Получить список коллекций текущего пользователя с метаданными и количеством карт.\nЗакрепленные коллекции идут первыми, затем по sort_order и по дате создания",
                 "produces": [
                     "application/json"
                 ],
@@ -54,7 +145,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создать новую коллекцию с указанным именем",
+                "description": "Создать новую коллекцию с указанным именем и метаданными",
                 "consumes": [
                     "application/json"
                 ],
@@ -98,14 +189,107 @@ const docTemplate = `{
                 }
             }
         },
+        "/collections/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить коллекции текущего пользователя в корзине, последние удаленные первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "List deleted collections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/collections.DeletedCollection"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/collections/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить коллекцию по ID. Версия коллекции приходит в ETag;\nс If-None-Match ответ 304 без тела, если коллекция не менялась",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Get collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, которая уже есть у клиента",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Collection"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Удалить коллекцию по ID",
+                "description": "Переместить коллекцию в корзину по ID. Ее можно восстановить, пока она не удалена окончательно",
                 "produces": [
                     "application/json"
                 ],
@@ -120,12 +304,24 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -137,6 +333,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/collections.ErrorResponse"
                         }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
                     }
                 }
             },
@@ -146,7 +348,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Переименовать коллекцию по ID",
+                "description": "Изменить имя и метаданные коллекции по ID, непереданные поля не меняются",
                 "consumes": [
                     "application/json"
                 ],
@@ -156,7 +358,7 @@ const docTemplate = `{
                 "tags": [
                     "Collections"
                 ],
-                "summary": "Rename collection",
+                "summary": "Update collection",
                 "parameters": [
                     {
                         "type": "string",
@@ -166,21 +368,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Новое имя коллекции",
+                        "description": "Изменяемые поля",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/collections.RenameCollectionRequest"
+                            "$ref": "#/definitions/collections.UpdateCollectionRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/collections.Collection"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -199,30 +404,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/collections.ErrorResponse"
                         }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/collections/{name}": {
+        "/collections/{id}/allocations": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить коллекцию по имени",
+                "description": "Получить резервы колоды: какие копии из каких коллекций в ней лежат",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Collections"
+                    "Allocations"
                 ],
-                "summary": "Get collection by name",
+                "summary": "Get deck allocations",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Collection name",
-                        "name": "name",
+                        "description": "Collection ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -231,7 +442,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/collections.Collection"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/collections.Allocation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
                         }
                     },
                     "401": {
@@ -247,11 +467,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/login": {
+            },
             "post": {
-                "description": "Логин по Telegram ID, возвращает JWT",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Зарезервировать копии карты из коллекции-источника для колоды. Одна печать из одного источника\nрезервируется для колоды один раз",
                 "consumes": [
                     "application/json"
                 ],
@@ -259,252 +482,5393 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Allocations"
                 ],
-                "summary": "Login user",
+                "summary": "Reserve card for deck",
                 "parameters": [
                     {
-                        "description": "Telegram ID для логина",
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Карта, источник и количество копий",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.CheckUserRequest"
+                            "$ref": "#/definitions/collections.ReserveCardRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/auth.CheckUserResponse"
+                            "$ref": "#/definitions/collections.Allocation"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ResponseErr"
+                            "$ref": "#/definitions/collections.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ResponseErr"
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/register": {
-            "post": {
-                "description": "Регистрация пользователя, возвращает JWT",
-                "consumes": [
-                    "application/json"
+        "/collections/{id}/allocations/{allocation_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Снять резерв колоды",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Allocations"
                 ],
-                "summary": "Register user",
+                "summary": "Release card reservation",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Allocation ID",
+                        "name": "allocation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterRequest"
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/cards": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить список карт в коллекции",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "List cards in collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, которая уже есть у клиента",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cards.Card"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавить карту в коллекцию; если она уже есть, количество увеличивается.\nС reserve_from коллекция должна быть колодой, а добавленные копии резервируются из указанной коллекции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Add card to collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Карта и количество копий",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cards.AddCardRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created",
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterResponse"
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/cards/{card_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить карту коллекции с другими печатями этой карты и данными каталога",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Get card in collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, которая уже есть у клиента",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cards.CardEntry"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ResponseErr"
-                        }
-                    }
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить карту из коллекции",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Delete card from collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Частично обновить карту в коллекции: количество, заметку, состояние или зону колоды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Update card in collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cards.UpdateCardRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/cards/{card_id}/acquisitions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Записать покупку копий карты коллекции: дату, количество, цену копии, валюту, продавца и статус.\nПо покупкам считается себестоимость карт в отчете о прибыли и убытках. У карт вишлиста покупок нет",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Add card acquisition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Покупка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cards.AcquisitionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/cards.Acquisition"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/cards/{card_id}/acquisitions/{acquisition_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить покупку копий карты",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Delete card acquisition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Acquisition ID",
+                        "name": "acquisition_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить покупку копий карты, например отметить, что заказанные копии пришли",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Update card acquisition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Acquisition ID",
+                        "name": "acquisition_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Поля для изменения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cards.UpdateAcquisitionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cards.Acquisition"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/cards/{card_id}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Перенести или скопировать копии карты в другую коллекцию пользователя одной транзакцией.\nЗона сохраняется только при переносе в колоду, в других коллекциях у карт зон нет",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Transfer card to another collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scryfall ID",
+                        "name": "card_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зона колоды карты, по умолчанию mainboard",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отделка карты, по умолчанию nonfoil",
+                        "name": "finish",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Целевая коллекция, количество и режим",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cards.TransferCardRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cards.TransferCardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/cards:batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выполнить пакет операций add, set и remove над картами коллекции одной записью в базу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Batch card operations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Операции и режим",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cards.BatchCardsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cards.BatchCardsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/duplicate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создать копию коллекции со всеми картами под новым именем",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Duplicate collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Имя копии",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.DuplicateCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить историю изменений карт коллекции, от новых записей к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "List card history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только изменения этой карты",
+                        "name": "scryfall_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID последней записи предыдущей страницы",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько записей вернуть, до 200 (по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cards.CardHistoryEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/history/{entry_id}/undo": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменить изменение из истории коллекции или все изменения того же запроса.\nОтмена записывается в историю как новые записи; одно изменение нельзя отменить дважды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cards"
+                ],
+                "summary": "Undo card change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "History entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Отменить весь запрос",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cards.UndoChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cards.UndoChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавить в коллекцию все карты другой коллекции, суммируя количество одинаковых карт",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Merge collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Исходная коллекция",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.MergeCollectionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/profit-loss": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сравнить себестоимость полученных копий карт коллекции с их стоимостью по последним известным ценам\nи посчитать прибыль от копий, удаленных из коллекции или отданных в обмен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get collection profit and loss",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта: usd, eur или rub",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ProfitLoss"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/relocate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переложить карты коллекции в место хранения. Без scryfall_ids перекладываются все карты,\nа без location карты лежат там же, где коллекция",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Locations"
+                ],
+                "summary": "Relocate collection cards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Карты и новое место",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/locations.RelocateCardsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/locations.RelocateCardsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Вернуть коллекцию из корзины со всеми картами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Restore collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить снимки коллекции без карт, новые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Snapshots"
+                ],
+                "summary": "List snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/collections.Snapshot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сохранить текущие карты коллекции как именованный снимок",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Snapshots"
+                ],
+                "summary": "Create snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Имя снимка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.CreateSnapshotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Snapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/snapshots/{snapshot_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить снимок коллекции с картами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Snapshots"
+                ],
+                "summary": "Get snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Snapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить снимок коллекции",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Snapshots"
+                ],
+                "summary": "Delete snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/snapshots/{snapshot_id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сравнить снимок с другим снимком той же коллекции или, без to, с текущими картами коллекции",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Snapshots"
+                ],
+                "summary": "Diff snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID более позднего снимка",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.SnapshotDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/split": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Перенести карты, подходящие под фильтр, в новую коллекцию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Split collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Имя новой коллекции и фильтр",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.SplitCollectionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Источник изменения: api, import, bot или trade",
+                        "name": "X-Change-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, изменения которой ожидает клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/validation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Проверить колоду по правилам ее формата: размер колоды и сайдборда, лимит копий, синглтон,\nкомандиры и их цветовая идентичность, запрещенные и ограниченные карты.\nКоллекция должна быть колодой (kind deck) с указанным форматом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Decks"
+                ],
+                "summary": "Validate deck",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.DeckValidation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/value": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оценить коллекцию по последним известным ценам. Цена копии зависит от ее отделки:\nобычной, foil или etched. Цены обновляются раз в день из файлов Scryfall и MTGJSON",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get collection value",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта: usd, eur, tix или rub",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько самых дорогих карт вернуть",
+                        "name": "top",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.CollectionValue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/value/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "История стоимости коллекции по дням, неделям или месяцам с изменениями в процентах\nи картами, стоимость которых изменилась больше всего",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get collection value history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта: usd, eur, tix или rub",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Интервал точек: day, week или month",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день, например 2025-03-01",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день, по умолчанию сегодня",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько карт с самым большим изменением вернуть",
+                        "name": "movers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ValueHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить коллекцию по имени",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Get collection by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag коллекции, которая уже есть у клиента",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.Collection"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/decks/buildability": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Проверить, можно ли собрать колоду из карт других коллекций пользователя: какие карты есть и в каких\nколлекциях, каких не хватает и сколько они стоят. Колода — коллекция пользователя или вставленный список",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Decks"
+                ],
+                "summary": "Check deck buildability",
+                "parameters": [
+                    {
+                        "description": "Колода и нужна ли точная печать",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.BuildabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.DeckBuildability"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Последние курсы валют, по которым пересчитываются цены и себестоимость",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ExchangeRates"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/locations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить коробки и альбомы пользователя по имени",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Locations"
+                ],
+                "summary": "Get storage locations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/locations.StorageLocation"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создать коробку или альбом. Имена мест хранения у пользователя не повторяются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Locations"
+                ],
+                "summary": "Create storage location",
+                "parameters": [
+                    {
+                        "description": "Имя и вид места хранения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/locations.CreateLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/locations.StorageLocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/locations/{location_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить коробку или альбом. Место хранения, в котором лежат коллекции или карты, удалить нельзя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Locations"
+                ],
+                "summary": "Delete storage location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/locations/{location_id}/cards": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить карты, которые лежат в коробке или альбоме, по разделам, страницам и ячейкам.\nКарты без своего места лежат там же, где их коллекция",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Locations"
+                ],
+                "summary": "Get storage location cards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только карты раздела",
+                        "name": "section",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только карты страницы альбома",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/locations.LocationCards"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Логин по Telegram ID, возвращает JWT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Telegram ID для логина",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.CheckUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.CheckUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/profit-loss": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отчет о прибыли и убытках по всем коллекциям пользователя, кроме вишлистов, с итогами каждой коллекции.\nКопии, зарезервированные для колод, считаются один раз",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get profit and loss",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Валюта: usd, eur или rub",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ProfitLoss"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Регистрация пользователя, возвращает JWT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register user",
+                "parameters": [
+                    {
+                        "description": "Данные для регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/settings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить настройки пользователя: валюту, в которой по умолчанию считаются стоимость и прибыль",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить настройки пользователя. currency — usd, eur или rub; пустая строка возвращает usd",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user settings",
+                "parameters": [
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.UpdateSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/telegram/{telegram_id}": {
+            "get": {
+                "description": "Проверяет существование пользователя и возвращает JWT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Check user by Telegram ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Telegram ID",
+                        "name": "telegram_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.CheckUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/value/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "История стоимости всех коллекций пользователя, кроме коллекций типа wishlist.\nКопии, зарезервированные для колод, считаются один раз",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get value history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Валюта: usd, eur, tix или rub",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Интервал точек: day, week или month",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день, например 2025-03-01",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день, по умолчанию сегодня",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько карт с самым большим изменением вернуть",
+                        "name": "movers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ValueHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить список желаний: сначала карты с высоким приоритетом, затем по дате добавления",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Get wishlist",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/wishlist.Entry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавить карту в список желаний. Одну и ту же карту можно добавить только один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Add card to wishlist",
+                "parameters": [
+                    {
+                        "description": "Карта и сколько ее нужно",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wishlist.AddEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/wishlist.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/missing": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить карты из списка желаний, которых не хватает. Копии считаются по всем коллекциям\nпользователя, кроме коллекций типа wishlist; отделка копий не учитывается",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Get missing cards",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/wishlist.MissingEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{entry_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Убрать карту из списка желаний",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Delete wishlist entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить количество, цену, ее валюту, отделку или приоритет карты в списке желаний",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Update wishlist entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wishlist.UpdateEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/wishlist.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/collections.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.CheckUserRequest": {
+            "description": "Запрос для проверки существующего пользователя по Telegram ID",
+            "type": "object",
+            "required": [
+                "telegram_id"
+            ],
+            "properties": {
+                "telegram_id": {
+                    "type": "integer",
+                    "example": 123456789
+                }
+            }
+        },
+        "auth.CheckUserResponse": {
+            "description": "Ответ с токеном и флагом успеха",
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbG..."
+                }
+            }
+        },
+        "auth.RegisterRequest": {
+            "description": "Регистрация пользователя по Telegram ID и данным профиля",
+            "type": "object",
+            "required": [
+                "first_name",
+                "telegram_id"
+            ],
+            "properties": {
+                "first_name": {
+                    "type": "string",
+                    "example": "Ivan"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Ivanov"
+                },
+                "telegram_id": {
+                    "type": "integer",
+                    "example": 123456789
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ivan123"
+                }
+            }
+        },
+        "auth.RegisterResponse": {
+            "description": "Ответ с JWT-токеном",
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbG..."
+                }
+            }
+        },
+        "auth.UpdateSettingsRequest": {
+            "description": "Меняет только переданные поля",
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "eur"
+                }
+            }
+        },
+        "auth.UserSettings": {
+            "description": "currency — валюта отчетов и цен по умолчанию: usd, eur или rub",
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "rub"
+                }
+            }
+        },
+        "cards.Acquisition": {
+            "description": "Сколько копий куплено, когда, у кого и по какой цене за копию. status — ordered, пока копии не пришли, и received после; в себестоимость идут только полученные копии, по средней цене копии. Покупки переходят в другую коллекцию вместе с картой, только если она переносится целиком",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-03-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f70"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "status": {
+                    "type": "string",
+                    "example": "received"
+                },
+                "vendor": {
+                    "type": "string",
+                    "example": "Card Kingdom"
+                }
+            }
+        },
+        "cards.AcquisitionRequest": {
+            "description": "price — цена одной копии, currency — usd или eur. date по умолчанию — сейчас, status — received",
+            "type": "object",
+            "required": [
+                "count",
+                "currency"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-03-01T00:00:00Z"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 1.5
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ordered",
+                        "received"
+                    ],
+                    "example": "ordered"
+                },
+                "vendor": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Card Kingdom"
+                }
+            }
+        },
+        "cards.AddCardRequest": {
+            "description": "Добавляет карту; если она уже есть в этой зоне колоды с той же отделкой, количество увеличивается. Копии печати в разных зонах или с разной отделкой — разные карты коллекции. reserve_from — коллекция, из которой добавленные в колоду копии резервируются; карта должна в ней быть",
+            "type": "object",
+            "required": [
+                "count",
+                "scryfall_id"
+            ],
+            "properties": {
+                "card_url": {
+                    "type": "string",
+                    "example": "https://scryfall.com/card/tsp/157/fury-sliver"
+                },
+                "count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fury Sliver"
+                },
+                "reserve_from": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.BatchCardsRequest": {
+            "description": "Операции выполняются по порядку и сохраняются одной записью. atomic (по умолчанию): если хоть одна операция не прошла, ничего не сохраняется; best_effort: неудачные операции пропускаются, остальные сохраняются",
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ],
+                    "example": "atomic"
+                },
+                "operations": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/cards.CardOperation"
+                    }
+                }
+            }
+        },
+        "cards.BatchCardsResponse": {
+            "description": "Результаты операций в том же порядке, что и в запросе",
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "committed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.CardOperationResult"
+                    }
+                }
+            }
+        },
+        "cards.Card": {
+            "description": "Карта в коллекции пользователя с количеством копий. location — свое место карты; без него карта лежит там же, где коллекция. finish — foil или etched, без него карта nonfoil. acquisitions — покупки копий карты, старые первыми",
+            "type": "object",
+            "properties": {
+                "acquisitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.Acquisition"
+                    }
+                },
+                "added_at": {
+                    "type": "string"
+                },
+                "card_url": {
+                    "type": "string",
+                    "example": "https://scryfall.com/card/tsp/157/fury-sliver"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "NM"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fury Sliver"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Signed by the artist"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.CardEntry": {
+            "description": "Карта в коллекции с другими печатями той же карты и данными каталога. location — где лежит карта: ее место или место коллекции; acquisitions — покупки копий карты",
+            "type": "object",
+            "properties": {
+                "acquisitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.Acquisition"
+                    }
+                },
+                "added_at": {
+                    "type": "string"
+                },
+                "card_url": {
+                    "type": "string",
+                    "example": "https://scryfall.com/card/tsp/157/fury-sliver"
+                },
+                "catalog": {
+                    "$ref": "#/definitions/cards.CatalogCard"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "NM"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fury Sliver"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Signed by the artist"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.CardVariant"
+                    }
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.CardHistoryEntry": {
+            "description": "Изменение количества копий карты в коллекции. Записи, сделанные одним запросом, имеют общий batch_id. action — add, update, remove, batch, transfer, merge, split или undo; source — api, import, bot или trade",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "add"
+                },
+                "batch_id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f5f"
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "60c72b2f9b1e8d001c8e4f5a"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-23T12:00:00Z"
+                },
+                "delta": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f60"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fury Sliver"
+                },
+                "related_collection_id": {
+                    "type": "string",
+                    "example": "60c72b2f9b1e8d001c8e4f5c"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "api"
+                },
+                "undo_of": {
+                    "type": "string",
+                    "example": ""
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60c72b2f9b1e8d001c8e4f5b"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.CardOperation": {
+            "description": "add — добавить копии; set — установить количество (0 удаляет карту); remove — убрать count копий или всю карту, если count не указан. zone — зона колоды карты, без нее mainboard; finish — отделка, без нее nonfoil",
+            "type": "object",
+            "properties": {
+                "card_url": {
+                    "type": "string",
+                    "example": "https://scryfall.com/card/tsp/157/fury-sliver"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fury Sliver"
+                },
+                "op": {
+                    "type": "string",
+                    "example": "add"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.CardOperationResult": {
+            "description": "status — HTTP-статус операции; count — сколько копий осталось после неё",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "op": {
+                    "type": "string",
+                    "example": "add"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.CardVariant": {
+            "description": "Печать карты в коллекции; в записи карты — другая печать той же карты (по oracle_id или имени). location — где лежит печать",
+            "type": "object",
+            "properties": {
+                "collector_number": {
+                    "type": "string",
+                    "example": "157"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "LP"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fury Sliver"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "a2f1ae9a-4b0c-4dce-8a8c-2a3fbd1c8e4c"
+                },
+                "set": {
+                    "type": "string",
+                    "example": "tsp"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "cards.CatalogCard": {
+            "description": "Справочные данные о печати карты",
+            "type": "object",
+            "properties": {
+                "collector_number": {
+                    "type": "string",
+                    "example": "157"
+                },
+                "color_identity": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "colors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finishes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://cards.scryfall.io/normal/front/0/0/0000579f.jpg"
+                },
+                "lang": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "mana_cost": {
+                    "type": "string",
+                    "example": "{5}{R}"
+                },
+                "oracle_id": {
+                    "type": "string",
+                    "example": "b2b3b1b4-1d7e-4b5f-9e8e-0a5c0f1d5f8a"
+                },
+                "oracle_text": {
+                    "type": "string",
+                    "example": "All Sliver creatures have double strike."
+                },
+                "rarity": {
+                    "type": "string",
+                    "example": "uncommon"
+                },
+                "scryfall_uri": {
+                    "type": "string",
+                    "example": "https://scryfall.com/card/tsp/157/fury-sliver"
+                },
+                "set": {
+                    "type": "string",
+                    "example": "tsp"
+                },
+                "set_name": {
+                    "type": "string",
+                    "example": "Time Spiral"
+                },
+                "type_line": {
+                    "type": "string",
+                    "example": "Creature — Sliver"
+                }
+            }
+        },
+        "cards.OwnedCards": {
+            "description": "Коллекции пользователя с копиями карты, кроме вишлистов, и места хранения печатей",
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.OwnedCollection"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "cards.OwnedCollection": {
+            "description": "Коллекция с копиями карты по печатям",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                },
+                "collection_name": {
+                    "type": "string",
+                    "example": "Red binder"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "binder"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.CardVariant"
+                    }
+                }
+            }
+        },
+        "cards.TransferCardRequest": {
+            "description": "move (по умолчанию) переносит копии, copy оставляет исходную коллекцию как есть",
+            "type": "object",
+            "required": [
+                "count",
+                "to_collection_id"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "move",
+                        "copy"
+                    ],
+                    "example": "move"
+                },
+                "to_collection_id": {
+                    "type": "string",
+                    "example": "60c72b2f9b1e8d001c8e4f5a"
+                }
+            }
+        },
+        "cards.TransferCardResponse": {
+            "description": "Сколько копий карты осталось в исходной коллекции и стало в целевой",
+            "type": "object",
+            "properties": {
+                "from_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "to_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "cards.UndoChangeRequest": {
+            "description": "batch — отменить все изменения, сделанные тем же запросом, а не только эту запись",
+            "type": "object",
+            "properties": {
+                "batch": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "cards.UndoChangeResponse": {
+            "description": "Каждая запись ссылается на отмененную через undo_of",
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cards.CardHistoryEntry"
+                    }
+                }
+            }
+        },
+        "cards.UpdateAcquisitionRequest": {
+            "description": "Меняет только переданные поля, например status, когда заказанные копии пришли",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-03-01T00:00:00Z"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 1.5
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ordered",
+                        "received"
+                    ],
+                    "example": "received"
+                },
+                "vendor": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Card Kingdom"
+                }
+            }
+        },
+        "cards.UpdateCardRequest": {
+            "description": "Меняет только переданные поля; пустая строка очищает заметку или состояние и возвращает карту в mainboard. count 0 убирает карту из коллекции, как DELETE; остальные поля тогда не учитываются. Карту нельзя перенести в зону или сменить ей отделку, если там уже есть эта печать с такой отделкой. Состояние — одно из NM, LP, MP, HP, DMG; зона — mainboard, sideboard, commander, companion или maybeboard; отделка — nonfoil, foil или etched",
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string",
+                    "example": "LP"
+                },
+                "count": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Signed by the artist"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "sideboard"
+                }
+            }
+        },
+        "collections.AllocatedDeck": {
+            "type": "object",
+            "properties": {
+                "allocation_id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f62"
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e5"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Krenko"
+                }
+            }
+        },
+        "collections.Allocation": {
+            "description": "Копии печати из коллекции-источника, которые физически лежат в колоде",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e5"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f62"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sol Ring"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "source_collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                }
+            }
+        },
+        "collections.AllocationConflict": {
+            "description": "owned — копий печати в коллекции-источнике, allocated — зарезервировано для колод, over — лишних резервов. Резерв колоды не превышает число копий карты в ней",
+            "type": "object",
+            "properties": {
+                "allocated": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "decks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.AllocatedDeck"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sol Ring"
+                },
+                "over": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "owned": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "source_collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                },
+                "source_collection_name": {
+                    "type": "string",
+                    "example": "Commander staples"
+                }
+            }
+        },
+        "collections.BuildabilityCard": {
+            "description": "sources — из каких коллекций и каких печатей берутся копии; price — цена одной недостающей копии. Сначала берутся копии, зарезервированные для этой колоды, затем свободные, затем зарезервированные для других колод",
+            "type": "object",
+            "properties": {
+                "covered": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "missing": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lightning Bolt"
+                },
+                "needed": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "price": {
+                    "type": "number",
+                    "example": 1.99
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardSource"
+                    }
+                }
+            }
+        },
+        "collections.BuildabilityRequest": {
+            "description": "Колода задается коллекцией (collection_id) или списком (decklist) — одним из двух. Список — по карте в строке: \"4 Lightning Bolt\" или \"1 Lightning Bolt (M10) 146\"; секции Sideboard, Commander, Maybeboard и префикс \"SB:\" понимаются, карты из maybeboard не учитываются. any_printing засчитывает любую печать карты, а не только указанную; строки без печати подходят под любую",
+            "type": "object",
+            "properties": {
+                "any_printing": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "decklist": {
+                    "type": "string",
+                    "example": "4 Lightning Bolt"
+                }
+            }
+        },
+        "collections.CardDiff": {
+            "description": "Количество копий печати карты в одной отделке до и после; delta — разница. Смена отделки — это удаление в одной отделке и добавление в другой",
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "before": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "delta": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lightning Bolt"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                }
+            }
+        },
+        "collections.CardFilter": {
+            "description": "Карта подходит, если совпадают все заданные поля; для списков достаточно одного значения. name ищет подстроку без учета регистра; sets и rarities берутся из каталога",
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "NM"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "sliver"
+                },
+                "rarities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rare"
+                    ]
+                },
+                "scryfall_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dsk"
+                    ]
+                }
+            }
+        },
+        "collections.CardMove": {
+            "description": "start_price и end_price — цена одной копии в первой и последней точках, change — изменение цены копий, которые есть в последней точке",
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number",
+                    "example": 1
+                },
+                "change_percent": {
+                    "type": "number",
+                    "example": 33.33
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "end_price": {
+                    "type": "number",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sol Ring"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "start_price": {
+                    "type": "number",
+                    "example": 1.5
+                }
+            }
+        },
+        "collections.CardProfitLoss": {
+            "description": "count — копии с известной себестоимостью, unit_cost — средняя цена покупки копии, market_value — их стоимость по последней известной цене. Карты с самым большим изменением первыми",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "cost_basis": {
+                    "type": "number",
+                    "example": 3
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "gain": {
+                    "type": "number",
+                    "example": 1
+                },
+                "gain_percent": {
+                    "type": "number",
+                    "example": 33.33
+                },
+                "market_value": {
+                    "type": "number",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sol Ring"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "unit_cost": {
+                    "type": "number",
+                    "example": 1.5
+                }
+            }
+        },
+        "collections.CardSource": {
+            "description": "reserved — копии зарезервированы для проверяемой колоды. deck_id и deck_name — колода, для которой зарезервированы копии: карту нужно забрать из нее",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                },
+                "collection_name": {
+                    "type": "string",
+                    "example": "Red binder"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "deck_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e5"
+                },
+                "deck_name": {
+                    "type": "string",
+                    "example": "Krenko"
+                },
+                "reserved": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                }
+            }
+        },
+        "collections.CardValue": {
+            "description": "unit_price — цена одной копии с отделкой карты, value — цена всех копий, priced_at — день цены",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sol Ring"
+                },
+                "priced_at": {
+                    "type": "string"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "unit_price": {
+                    "type": "number",
+                    "example": 3.5
+                },
+                "value": {
+                    "type": "number",
+                    "example": 7
+                }
+            }
+        },
+        "collections.Collection": {
+            "description": "Модель коллекции с ID, именем, метаданными и количеством карт. card_count — число всех копий, unique_cards — число разных карт. version растет с каждым изменением коллекции и ее карт; он же приходит в ETag. location — место, где лежат карты коллекции без своего места",
+            "type": "object",
+            "properties": {
+                "card_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "cover_card": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Trade binder for FNM"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "modern"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "binder"
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My cool collection"
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "trade"
+                    ]
+                },
+                "unique_cards": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "collections.CollectionProfitLoss": {
+            "description": "Итоги коллекции в отчете по всем коллекциям пользователя",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "cost_basis": {
+                    "type": "number",
+                    "example": 120
+                },
+                "market_value": {
+                    "type": "number",
+                    "example": 152.4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Red binder"
+                },
+                "ordered_cost": {
+                    "type": "number",
+                    "example": 15
+                },
+                "realized_gain": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "unrealized_gain": {
+                    "type": "number",
+                    "example": 32.4
+                },
+                "unrealized_gain_percent": {
+                    "type": "number",
+                    "example": 27
+                }
+            }
+        },
+        "collections.CollectionValue": {
+            "description": "Стоимость коллекции по последним известным ценам каждой печати с учетом отделки копий. total, priced_count и unpriced_count считаются по всей коллекции, даже если задан top; cards — оцененные карты, самые дорогие первыми, unpriced — имена (или Scryfall ID) карт без известной цены. priced_at — день самой свежей из использованных цен. Цены в rub пересчитываются из usd по последнему курсу, rates_date — день курса, если цены пересчитывались",
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardValue"
+                    }
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "priced_at": {
+                    "type": "string"
+                },
+                "priced_count": {
+                    "type": "integer",
+                    "example": 98
+                },
+                "rates_date": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 152.4
+                },
+                "unpriced": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Llanowar Elves"
+                    ]
+                },
+                "unpriced_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "collections.CreateCollectionRequest": {
+            "description": "Запрос для создания коллекции с указанным именем и необязательными описанием, типом, форматом и тегами. Формат имеет смысл для колод: по нему проверяется их легальность",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Trade binder for FNM"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "modern"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "binder",
+                        "deck",
+                        "cube",
+                        "trade",
+                        "wishlist"
+                    ],
+                    "example": "binder"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My cool collection"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "trade"
+                    ]
+                }
+            }
+        },
+        "collections.CreateSnapshotRequest": {
+            "description": "Сохраняет текущие карты коллекции под именем, уникальным в пределах коллекции",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Before rotation"
+                }
+            }
+        },
+        "collections.DeckBuildability": {
+            "description": "Карты колоды, сколько их копий нашлось в других коллекциях пользователя (кроме коллекций типа wishlist) и сколько не хватает. estimated_cost — цена недостающих копий по ценам Scryfall в долларах, unpriced — недостающие карты без известной цены",
+            "type": "object",
+            "properties": {
+                "any_printing": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "buildable": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.BuildabilityCard"
+                    }
+                },
+                "covered": {
+                    "type": "integer",
+                    "example": 56
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "estimated_cost": {
+                    "type": "number",
+                    "example": 7.96
+                },
+                "missing": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "needed": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "unpriced": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Llanowar Elves"
+                    ]
+                }
+            }
+        },
+        "collections.DeckProblem": {
+            "description": "rule — одно из deck_size, sideboard_size, commander, companion, copies, restricted, banned, not_legal, color_identity; cards — карты, которые его нарушают",
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Lightning Bolt"
+                    ]
+                },
+                "message": {
+                    "type": "string",
+                    "example": "At most 4 copies of a card are allowed"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "copies"
+                }
+            }
+        },
+        "collections.DeckValidation": {
+            "description": "Размер колоды и сайдборда, лимит копий, синглтон, командиры и их цветовая идентичность, запрещенные и ограниченные карты. mainboard считает и командиров; карты из maybeboard не проверяются. unchecked — карты, которых нет в каталоге: для них известны только размер и копии",
+            "type": "object",
+            "properties": {
+                "color_identity": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "W"
+                    ]
+                },
+                "commanders": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Kenrith",
+                        " the Returned King"
+                    ]
+                },
+                "format": {
+                    "type": "string",
+                    "example": "modern"
+                },
+                "legal": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "mainboard": {
+                    "type": "integer",
+                    "example": 58
+                },
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.DeckProblem"
+                    }
+                },
+                "sideboard": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "unchecked": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Llanowar Elves"
+                    ]
+                }
+            }
+        },
+        "collections.DeletedCollection": {
+            "description": "Коллекция в корзине и время ее удаления",
+            "type": "object",
+            "properties": {
+                "card_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "cover_card": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Trade binder for FNM"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "modern"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "binder"
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My cool collection"
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "trade"
+                    ]
+                },
+                "unique_cards": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "collections.DuplicateCollectionRequest": {
+            "description": "Создает копию коллекции со всеми картами под новым именем",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "My cool collection (copy)"
+                }
+            }
+        },
+        "collections.ErrorResponse": {
+            "description": "Структура ответа при ошибке",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "unauthorized"
+                },
+                "status": {
+                    "description": "Optional, can be used to indicate HTTP status code",
+                    "type": "integer"
+                }
+            }
+        },
+        "collections.ExchangeRates": {
+            "description": "Сколько единиц каждой валюты стоит одна единица base на день date; курсы обновляются вместе с ценами",
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "rates": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "collections.MergeCollectionRequest": {
+            "description": "Карты исходной коллекции добавляются в текущую, количество одинаковых карт суммируется. Исходная коллекция удаляется, если не указан keep_source",
+            "type": "object",
+            "required": [
+                "source_collection_id"
+            ],
+            "properties": {
+                "keep_source": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "source_collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                }
+            }
+        },
+        "collections.ProfitLoss": {
+            "description": "Себестоимость полученных копий против их стоимости по последним известным ценам. cost_basis, market_value и unrealized_gain считаются по копиям, у которых известны и себестоимость, и цена; realized_gain — по копиям, которые удалены из коллекций или отданы в обмен, по цене дня удаления. ordered_cost — стоимость заказанных, но еще не полученных копий. Покупки в другой валюте пересчитываются по последнему курсу, rates_date — его день; без курса такие покупки не учитываются. collections — отчет по каждой коллекции, кроме вишлистов, только в отчете по всем коллекциям; untracked — карты без себестоимости в валюте отчета, unpriced — карты с себестоимостью, но без цены",
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardProfitLoss"
+                    }
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CollectionProfitLoss"
+                    }
+                },
+                "cost_basis": {
+                    "type": "number",
+                    "example": 120
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "market_value": {
+                    "type": "number",
+                    "example": 152.4
+                },
+                "ordered_cost": {
+                    "type": "number",
+                    "example": 15
+                },
+                "rates_date": {
+                    "type": "string"
+                },
+                "realized": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.RealizedGain"
+                    }
+                },
+                "realized_gain": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "unpriced": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Collector Ouphe"
+                    ]
+                },
+                "unrealized_gain": {
+                    "type": "number",
+                    "example": 32.4
+                },
+                "unrealized_gain_percent": {
+                    "type": "number",
+                    "example": 27
+                },
+                "untracked": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Llanowar Elves"
+                    ]
+                }
+            }
+        },
+        "collections.RealizedGain": {
+            "description": "Копии с известной себестоимостью, удаленные из коллекции или отданные в обмен (source — trade). proceeds — их стоимость по цене дня удаления; без известной цены proceeds и gain нет. Перенос между коллекциями и отмененные изменения не считаются. Новые первыми",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "cost_basis": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "date": {
+                    "type": "string"
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "gain": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "gain_percent": {
+                    "type": "number",
+                    "example": 100
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sol Ring"
+                },
+                "proceeds": {
+                    "type": "number",
+                    "example": 3
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "trade"
+                }
+            }
+        },
+        "collections.ReserveCardRequest": {
+            "description": "Резервирует копии печати из коллекции-источника (не колоды и не wishlist) для колоды, в которой есть карта. Для колоды нельзя зарезервировать больше копий, чем в ней есть; из источника — можно, это будет конфликт",
+            "type": "object",
+            "required": [
+                "count",
+                "scryfall_id",
+                "source_collection_id"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "source_collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                }
+            }
+        },
+        "collections.Snapshot": {
+            "description": "Именованный снимок карт коллекции. Список снимков возвращается без карт",
+            "type": "object",
+            "properties": {
+                "card_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.SnapshotCard"
+                    }
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e5"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Before rotation"
+                },
+                "unique_cards": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "collections.SnapshotCard": {
+            "description": "Печать карты в одной отделке и количество ее копий во всех зонах на момент снимка; пустая отделка — nonfoil",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lightning Bolt"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                }
+            }
+        },
+        "collections.SnapshotDiff": {
+            "description": "Карты, которые появились, пропали или изменили количество. Пустой to означает текущее состояние коллекции",
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardDiff"
+                    }
+                },
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardDiff"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e5"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardDiff"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e6"
+                }
+            }
+        },
+        "collections.SplitCollectionRequest": {
+            "description": "Переносит карты, подходящие под фильтр, в новую коллекцию с указанным именем",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/collections.CardFilter"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Duskmourn"
+                }
+            }
+        },
+        "collections.UpdateCollectionRequest": {
+            "description": "Меняет только переданные поля. Пустая строка очищает описание, тип, формат или обложку, пустой список — теги. cover_card должна быть картой этой коллекции. location — место карт коллекции без своего места, пустой location_id убирает коллекцию с места",
+            "type": "object",
+            "properties": {
+                "cover_card": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Trade binder for FNM"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "modern"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "deck"
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Renamed collection"
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "modern"
+                    ]
                 }
             }
         },
-        "/user/telegram/{telegram_id}": {
-            "get": {
-                "description": "Проверяет существование пользователя и возвращает JWT",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Check user by Telegram ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Telegram ID",
-                        "name": "telegram_id",
-                        "in": "path",
-                        "required": true
+        "collections.ValueHistory": {
+            "description": "Как менялась стоимость карт: точка — стоимость карт, которые были на конец дня, недели (с понедельника) или месяца, по ценам того времени; date — первый день периода. Состав карт восстанавливается по истории изменений, карты, добавленные до её появления, считаются бывшими всегда; коллекции в корзине не учитываются. Цена карты, которая не обновлялась больше 30 дней, не учитывается. change и change_percent — изменение от первой точки до последней, movers — карты с самым большим изменением. Цены во всех точках пересчитываются по последнему курсу, rates_date — его день",
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "change_percent": {
+                    "type": "number",
+                    "example": 8.2
+                },
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e3"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "week"
+                },
+                "movers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CardMove"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/auth.CheckUserResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ResponseErr"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ResponseErr"
-                        }
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.ValuePoint"
                     }
+                },
+                "rates_date": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.CheckUserRequest": {
-            "description": "Запрос для проверки существующего пользователя по Telegram ID",
+        },
+        "collections.ValuePoint": {
+            "description": "change и change_percent — изменение от предыдущей точки; процента нет, если прежняя стоимость нулевая",
             "type": "object",
-            "required": [
-                "telegram_id"
-            ],
             "properties": {
-                "telegram_id": {
+                "change": {
+                    "type": "number",
+                    "example": -1.2
+                },
+                "change_percent": {
+                    "type": "number",
+                    "example": -0.78
+                },
+                "date": {
+                    "type": "string"
+                },
+                "priced_count": {
                     "type": "integer",
-                    "example": 123456789
+                    "example": 98
+                },
+                "value": {
+                    "type": "number",
+                    "example": 152.4
                 }
             }
         },
-        "auth.CheckUserResponse": {
-            "description": "Ответ с токеном и флагом успеха",
+        "models.ResponseErr": {
             "type": "object",
             "properties": {
-                "success": {
-                    "type": "boolean"
+                "message": {
+                    "type": "string"
                 },
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbG..."
+                "status": {
+                    "type": "integer"
                 }
             }
         },
-        "auth.RegisterRequest": {
-            "description": "Регистрация пользователя по Telegram ID и данным профиля",
+        "locations.CreateLocationRequest": {
+            "description": "Коробка (box) или альбом (binder). Имя уникально у пользователя",
             "type": "object",
             "required": [
-                "first_name",
-                "telegram_id"
+                "kind",
+                "name"
             ],
             "properties": {
-                "first_name": {
+                "description": {
                     "type": "string",
-                    "example": "Ivan"
+                    "maxLength": 1000,
+                    "example": "Under the desk"
                 },
-                "last_name": {
+                "kind": {
                     "type": "string",
-                    "example": "Ivanov"
+                    "enum": [
+                        "box",
+                        "binder"
+                    ],
+                    "example": "box"
                 },
-                "telegram_id": {
+                "name": {
+                    "type": "string",
+                    "example": "Red box"
+                }
+            }
+        },
+        "locations.LocatedCard": {
+            "description": "Карта коллекции и ее место. inherited — у карты нет своего места, она лежит там же, где коллекция",
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string",
+                    "example": "64a9b66b2db8b91234a6e8e4"
+                },
+                "collection_name": {
+                    "type": "string",
+                    "example": "Trades"
+                },
+                "count": {
                     "type": "integer",
-                    "example": 123456789
+                    "example": 2
                 },
-                "username": {
+                "inherited": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "name": {
                     "type": "string",
-                    "example": "ivan123"
+                    "example": "Fury Sliver"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "0000579f-7b35-4ed3-b44c-db2a538066fe"
                 }
             }
         },
-        "auth.RegisterResponse": {
-            "description": "Ответ с JWT-токеном",
+        "locations.Location": {
+            "description": "Место хранения и, если известны, раздел, страница и ячейка. Страницы и ячейки есть только у альбомов. location_name заполняется в ответах, где известно место хранения",
             "type": "object",
             "properties": {
-                "token": {
+                "location_id": {
                     "type": "string",
-                    "example": "eyJhbG..."
+                    "example": "66f1c2a79b1e8d001c8e4f70"
+                },
+                "location_name": {
+                    "type": "string",
+                    "example": "Red box"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "section": {
+                    "type": "string",
+                    "example": "Red"
+                },
+                "slot": {
+                    "type": "integer",
+                    "example": 5
                 }
             }
         },
-        "collections.Collection": {
-            "description": "Модель коллекции с ID и именем",
+        "locations.LocationCards": {
+            "description": "Карты места хранения по разделам, страницам и ячейкам",
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/locations.LocatedCard"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "location": {
+                    "$ref": "#/definitions/locations.StorageLocation"
+                }
+            }
+        },
+        "locations.RelocateCardsRequest": {
+            "description": "Перемещает карты scryfall_ids, а без них — все карты коллекции. Без location карты теряют свое место и лежат там же, где коллекция",
+            "type": "object",
+            "properties": {
+                "location": {
+                    "$ref": "#/definitions/locations.Location"
+                },
+                "scryfall_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "locations.RelocateCardsResponse": {
+            "description": "Сколько карт перемещено",
+            "type": "object",
+            "properties": {
+                "relocated": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "locations.StorageLocation": {
+            "description": "Коробка или альбом, в которых лежат карты",
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-23T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Under the desk"
+                },
                 "id": {
                     "type": "string",
-                    "example": "64a9b66b2db8b91234a6e8e3"
+                    "example": "66f1c2a79b1e8d001c8e4f70"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "box"
                 },
                 "name": {
                     "type": "string",
-                    "example": "My cool collection"
+                    "example": "Red box"
                 }
             }
         },
-        "collections.CreateCollectionRequest": {
-            "description": "Запрос для создания коллекции с указанным именем",
+        "wishlist.AddEntryRequest": {
+            "description": "Карта задается печатью (scryfall_id) или, с any_printing либо oracle_id, любой своей печатью. Карте, которой нет в каталоге, для any_printing нужно имя. finish — nonfoil, foil или etched; priority — от 1 до 5, по умолчанию 3; max_price 0 — без ограничения цены. currency — валюта max_price: usd, eur или rub, по умолчанию валюта из настроек пользователя",
             "type": "object",
             "required": [
-                "name"
+                "quantity"
             ],
             "properties": {
+                "any_printing": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "nonfoil"
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 2.5
+                },
                 "name": {
                     "type": "string",
-                    "example": "My cool collection"
+                    "example": "Lightning Bolt"
+                },
+                "oracle_id": {
+                    "type": "string",
+                    "example": "4457ed35-7c10-48c8-9776-456485fdf070"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 4
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
                 }
             }
         },
-        "collections.ErrorResponse": {
-            "description": "Структура ответа при ошибке",
+        "wishlist.Entry": {
+            "description": "Карта, которую пользователь хочет получить, и сколько ее копий нужно. В списке max_price пересчитывается в валюту из настроек пользователя по последнему курсу, rates_date — день курса, если цена пересчитывалась",
             "type": "object",
             "properties": {
-                "message": {
+                "any_printing": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
                     "type": "string",
-                    "example": "unauthorized"
+                    "example": "2025-01-02T15:04:05Z"
                 },
-                "status": {
-                    "description": "Optional, can be used to indicate HTTP status code",
-                    "type": "integer"
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "nonfoil"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f61"
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 2.5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lightning Bolt"
+                },
+                "oracle_id": {
+                    "type": "string",
+                    "example": "4457ed35-7c10-48c8-9776-456485fdf070"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "rates_date": {
+                    "type": "string"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
                 }
             }
         },
-        "collections.RenameCollectionRequest": {
-            "description": "Запрос для изменения названия коллекции",
+        "wishlist.MissingEntry": {
+            "description": "owned — сколько копий уже есть в коллекциях пользователя, missing — сколько еще нужно",
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
+                "any_printing": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "usd"
+                },
+                "finish": {
+                    "type": "string",
+                    "example": "nonfoil"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "66f1c2a79b1e8d001c8e4f61"
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 2.5
+                },
+                "missing": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "name": {
                     "type": "string",
-                    "example": "Renamed collection"
+                    "example": "Lightning Bolt"
+                },
+                "oracle_id": {
+                    "type": "string",
+                    "example": "4457ed35-7c10-48c8-9776-456485fdf070"
+                },
+                "owned": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "rates_date": {
+                    "type": "string"
+                },
+                "scryfall_id": {
+                    "type": "string",
+                    "example": "e3285e6b-3e79-4d7c-bf96-d920f973b122"
                 }
             }
         },
-        "models.ResponseErr": {
+        "wishlist.UpdateEntryRequest": {
+            "description": "Меняет только переданные поля; пустая строка в finish означает любую отделку",
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "currency": {
+                    "type": "string",
+                    "example": "eur"
                 },
-                "status": {
-                    "type": "integer"
+                "finish": {
+                    "type": "string",
+                    "example": "foil"
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 3
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         }
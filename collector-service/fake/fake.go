@@ -0,0 +1,110 @@
+// Package fake provides an in-process collector-service for tests.
+//
+// Server is the real router of collector-service over repositories.MemoryRepository,
+// served by httptest, so tests of collectorclient users run against the same
+// routes, status codes and error bodies as the real service.
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/app"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/repositories"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Server is a running fake collector-service. Close it when done.
+type Server struct {
+	*httptest.Server
+
+	repo *repositories.MemoryRepository
+
+	mu sync.RWMutex
+	// keys counts the signing keys, so that every key is new.
+	keys   int
+	router http.Handler
+}
+
+// NewServer starts a fake collector-service with no users and no catalog data.
+func NewServer() *Server {
+	gin.SetMode(gin.TestMode)
+	s := &Server{repo: repositories.NewMemoryRepository()}
+	s.rotateKey()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	router := s.router
+	s.mu.RUnlock()
+	router.ServeHTTP(w, r)
+}
+
+// rotateKey builds the router with a new JWT signing key over the same data.
+func (s *Server) rotateKey() {
+	s.keys++
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: fmt.Sprintf("fake-secret-%d", s.keys), TokenTTL: time.Hour},
+	}
+	s.router = app.NewRouter(cfg, logger.SilentLogger{}, s.repo, catalog.New())
+}
+
+// ExpireTokens invalidates every issued token, as if they had expired
+// or the signing key had been rotated. Users can log in again.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotateKey()
+}
+
+// Prices are the market prices of a printing on a day; a zero price is not known.
+type Prices struct {
+	USD, USDFoil, USDEtched float64
+	EUR, EURFoil            float64
+	Tix                     float64
+}
+
+// SetPrices stores the prices of a printing on the day of date, as the daily
+// price import of collector-service does. Collections are valued by the
+// latest day known for every printing.
+func (s *Server) SetPrices(scryfallID string, date time.Time, prices Prices) {
+	respErr := s.repo.SaveCardPrices([]*models.CardPrice{{
+		ScryfallID: scryfallID,
+		Date:       catalog.Day(date),
+		Prices: catalog.Prices{
+			USD: prices.USD, USDFoil: prices.USDFoil, USDEtched: prices.USDEtched,
+			EUR: prices.EUR, EURFoil: prices.EURFoil, Tix: prices.Tix,
+		},
+	}})
+	if respErr != nil {
+		panic(respErr.Message)
+	}
+}
+
+// SetExchangeRates stores how many units of every currency one unit of base buys
+// on the day of date, as the rates import of collector-service does. Amounts are
+// converted at the newest table only.
+func (s *Server) SetExchangeRates(base string, date time.Time, rates map[string]float64) {
+	table := &catalog.ExchangeRates{
+		Base:  strings.ToLower(base),
+		Date:  catalog.Day(date),
+		Rates: make(map[string]float64, len(rates)+1),
+	}
+	for currency, rate := range rates {
+		table.Rates[strings.ToLower(currency)] = rate
+	}
+	table.Rates[table.Base] = 1
+	if respErr := s.repo.SaveExchangeRates(table); respErr != nil {
+		panic(respErr.Message)
+	}
+}
@@ -0,0 +1,55 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// loginTokenSource renews tokens by logging in again, like the bot does.
+type loginTokenSource struct {
+	collectorclient.ContextTokenSource
+	client     *collectorclient.HTTPCollectorClient
+	telegramID int64
+}
+
+func (ts loginTokenSource) Refresh(ctx context.Context) (string, error) {
+	resp, err := ts.client.CheckUser(ctx, &auth.CheckUserRequest{TelegramID: ts.telegramID})
+	if err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+func TestExpireTokens(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	authClient := collectorclient.NewHTTPCollectorClient(server.URL, logger.SilentLogger{})
+	resp, err := authClient.RegisterUser(context.Background(), &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	require.NoError(t, err)
+	ctx := authctx.WithJWT(context.Background(), resp.Token)
+
+	client := collectorclient.NewHTTPCollectorClient(server.URL, logger.SilentLogger{})
+	_, err = client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Bulk"})
+	require.NoError(t, err)
+
+	server.ExpireTokens()
+	_, err = client.GetUserCollections(ctx)
+	require.ErrorIs(t, err, collectorclient.ErrUnauthorized)
+
+	client = collectorclient.NewHTTPCollectorClient(server.URL, logger.SilentLogger{},
+		collectorclient.WithTokenSource(loginTokenSource{client: authClient, telegramID: 42}))
+	list, err := client.GetUserCollections(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	token, _ := authctx.GetJWT(ctx)
+	require.NotEqual(t, resp.Token, token)
+}
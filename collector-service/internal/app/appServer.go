@@ -3,17 +3,12 @@ package app
 import (
 	"context"
 	"net/http"
-	"os"
 
-	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/controllers"
-	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/middleware"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/repositories"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/services"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
-	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -21,56 +16,22 @@ type App struct {
 	host   string
 	log    logger.Logger
 	server *http.Server
+	trash  *trashPurger
+	prices *priceImporter
 }
 
-func InitServer(config *viper.Viper, log logger.Logger, db *mongo.Client) *App {
-	host := config.GetString("server_http.host")
+func InitServer(cfg *config.Config, log logger.Logger, db *mongo.Client, cards *catalog.Catalog) *App {
+	host := cfg.ServerHTTP.Host
 
 	// Init repository
 	rep := repositories.NewRepository(db)
-
-	// Init services
-	servAuth := services.NewAuthService(rep, log)
-	servCollections := services.NewCollectionsService(rep, log)
-	servCards := services.NewCardsService(rep, log)
-
-	// Init controllers
-	ctrlAuth := controllers.NewAuthController(servAuth, log)
-	ctrlCollections := controllers.NewCollectionsController(servCollections, log)
-	ctrlCards := controllers.NewCardsController(servCards, log)
-
-	router := gin.Default()
-	router.Use(gin.Recovery())
-
-	// Setup Swagger
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Middleware
-	mid := middleware.NewJWTMiddleware(os.Getenv("JWT_SECRET"), log)
-	authMiddleware := mid.Authorization()
-
-	// Public routes
-	public := router.Group("/")
-	{
-		public.POST("/register", ctrlAuth.Register)
-		public.GET("/user/telegram/:telegram_id", ctrlAuth.Who)
-		public.POST("/login", ctrlAuth.Login)
+	if err := rep.CreateIndexes(context.TODO()); err != nil {
+		panic(err)
 	}
 
-	// Protected routes
-	authorized := router.Group("/", authMiddleware)
-	{
-		authorized.GET("/collections", ctrlCollections.GetCollections)
-		authorized.POST("/collections", ctrlCollections.CreateCollection)
-		authorized.PATCH("/collections/:id", ctrlCollections.RenameCollection)
-		authorized.DELETE("/collections/:id", ctrlCollections.DeleteCollection)
-		authorized.GET("/collections/name/:name", ctrlCollections.GetCollectionByName)
-
-		authorized.GET("/collections/:id/cards", ctrlCards.ListCardsInCollection)
-		authorized.POST("/collections/:id/cards", ctrlCards.AddCardToCollection)
-		authorized.PATCH("/collections/:id/cards/:id", ctrlCards.SetCardCountInCollection)
-		authorized.DELETE("/collections/:id/cards/:id", ctrlCards.DeleteCardFromCollection)
-	}
+	router := NewRouter(cfg, log, rep, cards)
+	trash := newTrashPurger(services.NewCollectionsService(rep, cards, log), cfg.Trash, log)
+	prices := newPriceImporter(services.NewPricesService(rep, cards, log), cfg.Catalog.ScryfallBulkPath, cfg.Prices, log)
 
 	server := &http.Server{
 		Addr:    host,
@@ -81,10 +42,15 @@ func InitServer(config *viper.Viper, log logger.Logger, db *mongo.Client) *App {
 		host:   host,
 		log:    log,
 		server: server,
+		trash:  trash,
+		prices: prices,
 	}
 }
 
 func (a *App) Run() {
+	go a.trash.Run()
+	go a.prices.Run()
+
 	a.log.Info("Running server", logger.String("host", a.host))
 	if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		a.log.Error("ListenAndServe", logger.Error(err))
@@ -92,6 +58,9 @@ func (a *App) Run() {
 }
 
 func (a *App) Stop(ctx context.Context) {
+	a.trash.Stop()
+	a.prices.Stop()
+
 	a.log.Info("Stopping server", logger.String("host", a.host))
 	if err := a.server.Shutdown(ctx); err != nil {
 		a.log.Error("Server Shutdown Failed", logger.Error(err))
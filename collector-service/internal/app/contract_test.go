@@ -0,0 +1,2531 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/repositories"
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+	"github.com/ShenokZlob/collector-ouphe/pkg/collectorclient"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/wishlist"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// ContractTestSuite runs scenarios through collectorclient against the real
+// router over MemoryRepository, the server collector-service/fake gives the
+// tests of other services.
+//
+// go test github.com/ShenokZlob/collector-ouphe/collector-service/internal/app -run Contract
+type ContractTestSuite struct {
+	suite.Suite
+	newServer func() *httptest.Server
+	server    *httptest.Server
+	client    *collectorclient.HTTPCollectorClient
+	// setPrices stores the prices of a printing on a day in the server made last.
+	setPrices func(scryfallID string, date time.Time, prices catalog.Prices)
+	// setRates stores the exchange rates of a day in the server made last.
+	setRates func(base string, date time.Time, rates map[string]float64)
+}
+
+func TestContractRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: "contract-secret", TokenTTL: time.Hour},
+	}
+
+	s := &ContractTestSuite{}
+	s.newServer = func() *httptest.Server {
+		rep := repositories.NewMemoryRepository()
+		s.setPrices = func(scryfallID string, date time.Time, prices catalog.Prices) {
+			respErr := rep.SaveCardPrices([]*models.CardPrice{{ScryfallID: scryfallID, Date: catalog.Day(date), Prices: prices}})
+			s.Require().Nil(respErr)
+		}
+		s.setRates = func(base string, date time.Time, rates map[string]float64) {
+			table := &catalog.ExchangeRates{Base: base, Date: catalog.Day(date), Rates: maps.Clone(rates)}
+			table.Rates[base] = 1
+			s.Require().Nil(rep.SaveExchangeRates(table))
+		}
+		return httptest.NewServer(NewRouter(cfg, logger.SilentLogger{}, rep, catalog.New()))
+	}
+	suite.Run(t, s)
+}
+
+func (s *ContractTestSuite) SetupTest() {
+	s.server = s.newServer()
+	s.client = collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{},
+		collectorclient.WithRetry(1, time.Millisecond, time.Millisecond))
+}
+
+func (s *ContractTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// register creates a user and returns a context authorized as them.
+func (s *ContractTestSuite) register(telegramID int64) context.Context {
+	resp, err := s.client.RegisterUser(context.Background(), &auth.RegisterRequest{
+		TelegramID: telegramID,
+		FirstName:  "Ivan",
+		Username:   "ivan123",
+	})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(resp.Token)
+	return authctx.WithJWT(context.Background(), resp.Token)
+}
+
+func (s *ContractTestSuite) createCollection(ctx context.Context, name string) *collections.Collection {
+	col, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: name})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(col.ID)
+	s.Require().Equal(name, col.Name)
+	return col
+}
+
+func (s *ContractTestSuite) TestRegisterAndLogin() {
+	ctx := context.Background()
+	s.register(42)
+
+	_, err := s.client.RegisterUser(ctx, &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+
+	_, err = s.client.RegisterUser(ctx, &auth.RegisterRequest{TelegramID: 43})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	resp, err := s.client.CheckUser(ctx, &auth.CheckUserRequest{TelegramID: 42})
+	s.Require().NoError(err)
+	s.True(resp.Success)
+	s.NotEmpty(resp.Token)
+
+	_, err = s.client.CheckUser(ctx, &auth.CheckUserRequest{TelegramID: 7})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestUnauthorized() {
+	s.register(42)
+
+	ctx := authctx.WithJWT(context.Background(), "not-a-token")
+	_, err := s.client.GetUserCollections(ctx)
+	s.ErrorIs(err, collectorclient.ErrUnauthorized)
+}
+
+func (s *ContractTestSuite) TestCollectionsLifecycle() {
+	ctx := s.register(42)
+
+	list, err := s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	bulk := s.createCollection(ctx, "Bulk")
+	deck := s.createCollection(ctx, "Deck")
+
+	list, err = s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+	s.Equal([]collections.Collection{*bulk, *deck}, list)
+
+	found, err := s.client.GetUsersCollectionByName(ctx, "Deck")
+	s.Require().NoError(err)
+	s.Equal(deck, found)
+
+	s.Require().NoError(s.client.RenameCollection(ctx, deck.ID, &collections.RenameCollectionRequest{Name: "Elves"}))
+
+	_, err = s.client.GetUsersCollectionByName(ctx, "Deck")
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	found, err = s.client.GetUsersCollectionByName(ctx, "Elves")
+	s.Require().NoError(err)
+	s.Equal(deck.ID, found.ID)
+
+	list, err = s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+	s.Equal([]collections.Collection{*bulk, {ID: deck.ID, Name: "Elves", Version: 1}}, list)
+
+	s.Require().NoError(s.client.DeleteCollection(ctx, bulk.ID))
+	s.ErrorIs(s.client.DeleteCollection(ctx, bulk.ID), collectorclient.ErrNotFound)
+
+	list, err = s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+	s.Equal([]collections.Collection{{ID: deck.ID, Name: "Elves", Version: 1}}, list)
+}
+
+func (s *ContractTestSuite) TestCollectionsBelongToTheirOwner() {
+	alice := s.register(1)
+	bob := s.register(2)
+	col := s.createCollection(alice, "Bulk")
+
+	list, err := s.client.GetUserCollections(bob)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	_, err = s.client.GetUsersCollectionByName(bob, "Bulk")
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.RenameCollection(bob, col.ID, &collections.RenameCollectionRequest{Name: "Mine"}), collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteCollection(bob, col.ID), collectorclient.ErrNotFound)
+
+	found, err := s.client.GetUsersCollectionByName(alice, "Bulk")
+	s.Require().NoError(err)
+	s.Equal(col, found)
+}
+
+func (s *ContractTestSuite) TestTrash() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	deck := s.createCollection(ctx, "Deck")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+
+	trash, err := s.client.ListTrash(ctx)
+	s.Require().NoError(err)
+	s.Empty(trash)
+
+	s.Require().NoError(s.client.DeleteCollection(ctx, bulk.ID))
+	s.Equal([]string{"Deck"}, s.collectionNames(ctx))
+	_, err = s.client.GetUsersCollectionByName(ctx, "Bulk")
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.ListCardsInCollection(ctx, bulk.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}), collectorclient.ErrNotFound)
+
+	trash, err = s.client.ListTrash(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(trash, 1)
+	s.Equal(collections.Collection{ID: bulk.ID, Name: "Bulk", CardCount: 2, UniqueCards: 1, Version: 2}, trash[0].Collection)
+	s.False(trash[0].DeletedAt.IsZero())
+
+	// The name of a deleted collection is free, so restoring the old one conflicts.
+	newBulk := s.createCollection(ctx, "Bulk")
+	_, err = s.client.RestoreCollection(ctx, bulk.ID)
+	s.ErrorIs(err, collectorclient.ErrConflict)
+
+	s.Require().NoError(s.client.DeleteCollection(ctx, newBulk.ID))
+	restored, err := s.client.RestoreCollection(ctx, bulk.ID)
+	s.Require().NoError(err)
+	s.Equal(bulk.ID, restored.ID)
+	s.Equal(2, restored.CardCount)
+	s.Equal(map[string]int{"bolt": 2}, s.cardCounts(ctx, bulk.ID))
+
+	list, err := s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+	s.Equal([]collections.Collection{*restored, *deck}, list)
+
+	trash, err = s.client.ListTrash(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(trash, 1)
+	s.Equal(newBulk.ID, trash[0].ID)
+
+	_, err = s.client.RestoreCollection(ctx, bulk.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.RestoreCollection(s.register(7), newBulk.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.RestoreCollection(ctx, "not-an-id")
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+}
+
+func (s *ContractTestSuite) TestInvalidCollectionID() {
+	ctx := s.register(42)
+
+	err := s.client.RenameCollection(ctx, "not-an-id", &collections.RenameCollectionRequest{Name: "Elves"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	_, err = s.client.ListCardsInCollection(ctx, "not-an-id")
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+}
+
+func (s *ContractTestSuite) TestCollectionMetadata() {
+	ctx := s.register(42)
+
+	col, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{
+		Name:        "Elves",
+		Description: "Legacy elves",
+		Kind:        collections.KindDeck,
+		Tags:        []string{" legacy ", "green", "legacy"},
+	})
+	s.Require().NoError(err)
+	s.Equal("Legacy elves", col.Description)
+	s.Equal(collections.KindDeck, col.Kind)
+	s.Equal([]string{"legacy", "green"}, col.Tags)
+
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "llanowar", Name: "Llanowar Elves", Count: 4}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+
+	description, coverCard, pinned := "", "ouphe", true
+	s.Require().NoError(s.client.UpdateCollection(ctx, col.ID, &collections.UpdateCollectionRequest{
+		Description: &description,
+		CoverCard:   &coverCard,
+		Pinned:      &pinned,
+	}))
+
+	found, err := s.client.GetUsersCollectionByName(ctx, "Elves")
+	s.Require().NoError(err)
+	s.Equal(collections.Collection{
+		ID:          col.ID,
+		Name:        "Elves",
+		Kind:        collections.KindDeck,
+		Tags:        []string{"legacy", "green"},
+		CoverCard:   "ouphe",
+		Pinned:      true,
+		CardCount:   5,
+		UniqueCards: 2,
+		Version:     3,
+	}, *found)
+}
+
+func (s *ContractTestSuite) TestCollectionMetadataInvalid() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+
+	kind, cover := "shoebox", "missing"
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+	tests := []struct {
+		name string
+		req  *collections.UpdateCollectionRequest
+	}{
+		{"empty", &collections.UpdateCollectionRequest{}},
+		{"unknown kind", &collections.UpdateCollectionRequest{Kind: &kind}},
+		{"blank tag", &collections.UpdateCollectionRequest{Tags: &[]string{"  "}}},
+		{"too many tags", &collections.UpdateCollectionRequest{Tags: &tooMany}},
+		{"cover card not in collection", &collections.UpdateCollectionRequest{CoverCard: &cover}},
+	}
+	for _, tt := range tests {
+		s.ErrorIs(s.client.UpdateCollection(ctx, col.ID, tt.req), collectorclient.ErrBadRequest, tt.name)
+	}
+
+	_, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Box", Kind: kind})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	pinned := true
+	s.ErrorIs(s.client.UpdateCollection(bob, col.ID, &collections.UpdateCollectionRequest{Pinned: &pinned}), collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestCollectionsListOrderAndTotals() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	trade := s.createCollection(ctx, "Trade")
+	deck := s.createCollection(ctx, "Deck")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trade.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 3}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trade.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+
+	pinned, first := true, -1
+	s.Require().NoError(s.client.UpdateCollection(ctx, deck.ID, &collections.UpdateCollectionRequest{Pinned: &pinned}))
+	s.Require().NoError(s.client.UpdateCollection(ctx, trade.ID, &collections.UpdateCollectionRequest{SortOrder: &first}))
+
+	list, err := s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+	s.Equal([]collections.Collection{
+		{ID: deck.ID, Name: "Deck", Pinned: true, Version: 1},
+		{ID: trade.ID, Name: "Trade", SortOrder: -1, CardCount: 4, UniqueCards: 2, Version: 3},
+		{ID: bulk.ID, Name: "Bulk"},
+	}, list)
+}
+
+func (s *ContractTestSuite) TestCollectionVersions() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+	trade := s.createCollection(ctx, "Trade")
+
+	found, err := s.client.GetCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	s.Equal(col, found)
+	_, err = s.client.GetCollection(collectorclient.IfNoneMatch(ctx, col.Version), col.ID)
+	s.ErrorIs(err, collectorclient.ErrNotModified)
+	_, err = s.client.ListCardsInCollection(collectorclient.IfNoneMatch(ctx, col.Version), col.ID)
+	s.ErrorIs(err, collectorclient.ErrNotModified)
+
+	s.Require().NoError(s.client.AddCardToCollection(collectorclient.IfMatch(ctx, col.Version), col.ID,
+		&cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+
+	// Every write expecting the old version is refused and changes nothing.
+	stale := collectorclient.IfMatch(ctx, col.Version)
+	count, name := 1, "Elves"
+	s.ErrorIs(s.client.AddCardToCollection(stale, col.ID, &cards.AddCardRequest{ScryfallID: "bolt", Count: 1}), collectorclient.ErrPreconditionFailed)
+	s.ErrorIs(s.client.SetCardCountInCollection(stale, col.ID, "bolt", &cards.SetCardCountRequest{Count: 1}, nil), collectorclient.ErrPreconditionFailed)
+	s.ErrorIs(s.client.SetCardCountInCollection(stale, col.ID, "missing", &cards.SetCardCountRequest{Count: 1}, nil), collectorclient.ErrPreconditionFailed)
+	s.ErrorIs(s.client.UpdateCardInCollection(stale, col.ID, "bolt", &cards.UpdateCardRequest{Count: &count}, nil), collectorclient.ErrPreconditionFailed)
+	s.ErrorIs(s.client.DeleteCardFromCollection(stale, col.ID, "bolt", nil), collectorclient.ErrPreconditionFailed)
+	_, err = s.client.TransferCard(stale, col.ID, "bolt", &cards.TransferCardRequest{ToCollectionID: trade.ID, Count: 1}, nil)
+	s.ErrorIs(err, collectorclient.ErrPreconditionFailed)
+	_, err = s.client.BatchCardsInCollection(stale, col.ID, &cards.BatchCardsRequest{Operations: []cards.CardOperation{{Op: cards.CardOpAdd, ScryfallID: "bolt", Count: 1}}})
+	s.ErrorIs(err, collectorclient.ErrPreconditionFailed)
+	s.ErrorIs(s.client.UpdateCollection(stale, col.ID, &collections.UpdateCollectionRequest{Name: &name}), collectorclient.ErrPreconditionFailed)
+	s.ErrorIs(s.client.DeleteCollection(stale, col.ID), collectorclient.ErrPreconditionFailed)
+	_, err = s.client.MergeCollections(stale, col.ID, &collections.MergeCollectionRequest{SourceCollectionID: trade.ID})
+	s.ErrorIs(err, collectorclient.ErrPreconditionFailed)
+	_, err = s.client.SplitCollection(stale, col.ID, &collections.SplitCollectionRequest{Name: "Bolts", Filter: collections.CardFilter{ScryfallIDs: []string{"bolt"}}})
+	s.ErrorIs(err, collectorclient.ErrPreconditionFailed)
+	s.Equal(map[string]int{"bolt": 2}, s.cardCounts(ctx, col.ID))
+	s.Equal([]string{"Bulk", "Trade"}, s.collectionNames(ctx))
+
+	found, err = s.client.GetCollection(collectorclient.IfNoneMatch(ctx, col.Version), col.ID)
+	s.Require().NoError(err)
+	s.Equal(col.Version+1, found.Version)
+	_, err = s.client.GetCardInCollection(collectorclient.IfNoneMatch(ctx, found.Version), col.ID, "bolt", nil)
+	s.ErrorIs(err, collectorclient.ErrNotModified)
+
+	// A transfer changes both collections.
+	_, err = s.client.TransferCard(collectorclient.IfMatch(ctx, found.Version), col.ID, "bolt", &cards.TransferCardRequest{ToCollectionID: trade.ID, Count: 1}, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.UpdateCollection(collectorclient.IfMatch(ctx, found.Version+1), col.ID, &collections.UpdateCollectionRequest{Name: &name}))
+	found, err = s.client.GetUsersCollectionByName(ctx, "Elves")
+	s.Require().NoError(err)
+	s.Equal(col.Version+3, found.Version)
+	traded, err := s.client.GetCollection(ctx, trade.ID)
+	s.Require().NoError(err)
+	s.Equal(trade.Version+1, traded.Version)
+
+	// Writes replacing all cards check and bump the version once.
+	_, err = s.client.BatchCardsInCollection(collectorclient.IfMatch(ctx, traded.Version), trade.ID, &cards.BatchCardsRequest{Operations: []cards.CardOperation{{Op: cards.CardOpAdd, ScryfallID: "bolt", Count: 1}}})
+	s.Require().NoError(err)
+	_, err = s.client.MergeCollections(collectorclient.IfMatch(ctx, traded.Version+1), trade.ID, &collections.MergeCollectionRequest{SourceCollectionID: col.ID, KeepSource: true})
+	s.Require().NoError(err)
+	traded, err = s.client.GetCollection(ctx, trade.ID)
+	s.Require().NoError(err)
+	s.Equal(trade.Version+3, traded.Version)
+
+	s.Require().NoError(s.client.DeleteCollection(collectorclient.IfMatch(ctx, found.Version), col.ID))
+	_, err = s.client.GetCollection(ctx, col.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.GetCollection(s.register(7), trade.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.GetCollection(ctx, "not-an-id")
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+}
+
+func (s *ContractTestSuite) TestCards() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+
+	list, err := s.client.ListCardsInCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+
+	list, err = s.client.ListCardsInCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 2)
+	s.Equal("bolt", list[0].ScryfallID)
+	s.Equal("Lightning Bolt", list[0].Name)
+	s.Equal(3, list[0].Count)
+	s.Equal("ouphe", list[1].ScryfallID)
+
+	s.Require().NoError(s.client.SetCardCountInCollection(ctx, col.ID, "bolt", &cards.SetCardCountRequest{Count: 4}, nil))
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, col.ID, "ouphe", nil))
+
+	list, err = s.client.ListCardsInCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Equal("bolt", list[0].ScryfallID)
+	s.Equal(4, list[0].Count)
+
+	err = s.client.SetCardCountInCollection(ctx, col.ID, "ouphe", &cards.SetCardCountRequest{Count: 1}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteCardFromCollection(ctx, col.ID, "ouphe", nil), collectorclient.ErrNotFound)
+
+	// No copies left means no card, and undo brings it back.
+	zero, notes := 0, "gone"
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, col.ID, "bolt", &cards.UpdateCardRequest{Count: &zero, Notes: &notes}, nil))
+	s.Empty(s.cardCounts(ctx, col.ID))
+	history, err := s.client.ListCardHistory(ctx, col.ID, nil)
+	s.Require().NoError(err)
+	s.Equal([]any{"update", -4}, []any{history[0].Action, history[0].Delta})
+	_, err = s.client.UndoCardChange(ctx, col.ID, history[0].ID, nil)
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"bolt": 4}, s.cardCounts(ctx, col.ID))
+	s.ErrorIs(s.client.UpdateCardInCollection(ctx, col.ID, "ouphe", &cards.UpdateCardRequest{Count: &zero}, nil), collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestCardsInMissingCollection() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.DeleteCollection(ctx, col.ID))
+
+	_, err := s.client.ListCardsInCollection(ctx, col.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	err = s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt", Count: 1})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestCardEntry() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt-lea", Name: "Lightning Bolt", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+
+	entry, err := s.client.GetCardInCollection(ctx, col.ID, "bolt-m10", nil)
+	s.Require().NoError(err)
+	s.Equal("Lightning Bolt", entry.Name)
+	s.Equal(2, entry.Count)
+	s.Require().Len(entry.Variants, 1)
+	s.Equal("bolt-lea", entry.Variants[0].ScryfallID)
+	s.Equal(1, entry.Variants[0].Count)
+
+	count, notes, condition := 3, "Binder page 4", "LP"
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, col.ID, "bolt-m10", &cards.UpdateCardRequest{Count: &count, Notes: &notes}, nil))
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, col.ID, "bolt-m10", &cards.UpdateCardRequest{Condition: &condition}, nil))
+
+	entry, err = s.client.GetCardInCollection(ctx, col.ID, "bolt-m10", nil)
+	s.Require().NoError(err)
+	s.Equal(3, entry.Count)
+	s.Equal("Binder page 4", entry.Notes)
+	s.Equal("LP", entry.Condition)
+	s.False(entry.UpdatedAt.IsZero())
+
+	err = s.client.UpdateCardInCollection(ctx, col.ID, "bolt-m10", &cards.UpdateCardRequest{}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	bad := "mint"
+	err = s.client.UpdateCardInCollection(ctx, col.ID, "bolt-m10", &cards.UpdateCardRequest{Condition: &bad}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	_, err = s.client.GetCardInCollection(ctx, col.ID, "missing", nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	err = s.client.UpdateCardInCollection(ctx, col.ID, "missing", &cards.UpdateCardRequest{Count: &count}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+
+	bob := s.register(7)
+	_, err = s.client.GetCardInCollection(bob, col.ID, "bolt-m10", nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestFindOwnedCards() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "bolt-lea", Name: "Lightning Bolt", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "delver", Name: "Delver of Secrets // Insectile Aberration", Count: 1}))
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Burn", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 4, Zone: cards.ZoneSideboard}))
+	wanted, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wanted", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wanted.ID, &cards.AddCardRequest{ScryfallID: "bolt-lea", Name: "Lightning Bolt", Count: 4}))
+	trades := s.createCollection(ctx, "Trades")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trades.ID, &cards.AddCardRequest{ScryfallID: "bolt-2xm", Name: "Lightning Bolt", Count: 1}))
+	s.Require().NoError(s.client.SetCardCountInCollection(ctx, trades.ID, "bolt-2xm", &cards.SetCardCountRequest{Count: 0}, nil))
+
+	found, err := s.client.FindOwnedCards(ctx, &cards.OwnedCardsQuery{Name: " lightning bolt "})
+	s.Require().NoError(err)
+	s.Equal(&cards.OwnedCards{Count: 7, Collections: []cards.OwnedCollection{
+		{CollectionID: binder.ID, CollectionName: "Binder", Count: 3, Variants: []cards.CardVariant{
+			{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 2},
+			{ScryfallID: "bolt-lea", Name: "Lightning Bolt", Count: 1},
+		}},
+		{CollectionID: deck.ID, CollectionName: "Burn", Kind: collections.KindDeck, Count: 4, Variants: []cards.CardVariant{
+			{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 4, Zone: cards.ZoneSideboard},
+		}},
+	}}, found, "wishlists and cards with no copies left don't count")
+
+	found, err = s.client.FindOwnedCards(ctx, &cards.OwnedCardsQuery{ScryfallID: "bolt-lea"})
+	s.Require().NoError(err)
+	s.Equal(1, found.Count)
+	s.Require().Len(found.Collections, 1)
+	s.Equal(binder.ID, found.Collections[0].CollectionID)
+
+	found, err = s.client.FindOwnedCards(ctx, &cards.OwnedCardsQuery{Name: "Delver of Secrets"})
+	s.Require().NoError(err)
+	s.Equal(1, found.Count, "the front face names a multi-faced card")
+
+	found, err = s.client.FindOwnedCards(ctx, &cards.OwnedCardsQuery{Name: "Shock"})
+	s.Require().NoError(err)
+	s.Equal(&cards.OwnedCards{Collections: []cards.OwnedCollection{}}, found)
+
+	for _, query := range []*cards.OwnedCardsQuery{
+		{},
+		{Name: " "},
+		{Name: "Lightning Bolt", ScryfallID: "bolt-m10"},
+	} {
+		_, err := s.client.FindOwnedCards(ctx, query)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, "%+v", query)
+	}
+
+	bob := s.register(7)
+	found, err = s.client.FindOwnedCards(bob, &cards.OwnedCardsQuery{Name: "Lightning Bolt"})
+	s.Require().NoError(err)
+	s.Empty(found.Collections)
+}
+
+func (s *ContractTestSuite) TestCardsBatch() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 3}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "sliver", Name: "Fury Sliver", Count: 1}))
+
+	resp, err := s.client.BatchCardsInCollection(ctx, col.ID, &cards.BatchCardsRequest{Operations: []cards.CardOperation{
+		{Op: cards.CardOpAdd, ScryfallID: "bolt", Count: 2},
+		{Op: cards.CardOpAdd, ScryfallID: "elf", Name: "Llanowar Elves", Count: 4},
+		{Op: cards.CardOpSet, ScryfallID: "ouphe", Count: 1},
+		{Op: cards.CardOpRemove, ScryfallID: "sliver"},
+		{Op: cards.CardOpRemove, ScryfallID: "elf", Count: 1},
+	}})
+	s.Require().NoError(err)
+	s.True(resp.Committed)
+	s.Equal(5, resp.Applied)
+	s.Equal(0, resp.Failed)
+	s.Require().Len(resp.Results, 5)
+	for i, want := range []int{4, 4, 1, 0, 3} {
+		s.Equal(i, resp.Results[i].Index)
+		s.Equal(http.StatusOK, resp.Results[i].Status)
+		s.Equal(want, resp.Results[i].Count)
+	}
+
+	list, err := s.client.ListCardsInCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	counts := make(map[string]int)
+	for _, c := range list {
+		counts[c.ScryfallID] = c.Count
+	}
+	s.Equal(map[string]int{"bolt": 4, "ouphe": 1, "elf": 3}, counts)
+}
+
+func (s *ContractTestSuite) TestCardsBatchAtomic() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+
+	ops := []cards.CardOperation{
+		{Op: cards.CardOpAdd, ScryfallID: "bolt", Name: "Lightning Bolt", Count: 4},
+		{Op: cards.CardOpRemove, ScryfallID: "missing"},
+		{Op: "burn", ScryfallID: "bolt"},
+	}
+	resp, err := s.client.BatchCardsInCollection(ctx, col.ID, &cards.BatchCardsRequest{Operations: ops})
+	s.Require().NoError(err)
+	s.False(resp.Committed)
+	s.Equal(1, resp.Applied)
+	s.Equal(2, resp.Failed)
+	s.Equal(http.StatusOK, resp.Results[0].Status)
+	s.Equal(http.StatusNotFound, resp.Results[1].Status)
+	s.NotEmpty(resp.Results[1].Error)
+	s.Equal(http.StatusBadRequest, resp.Results[2].Status)
+
+	list, err := s.client.ListCardsInCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	resp, err = s.client.BatchCardsInCollection(ctx, col.ID, &cards.BatchCardsRequest{Mode: cards.BatchModeBestEffort, Operations: ops})
+	s.Require().NoError(err)
+	s.True(resp.Committed)
+	s.Equal(1, resp.Applied)
+
+	list, err = s.client.ListCardsInCollection(ctx, col.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Equal("bolt", list[0].ScryfallID)
+	s.Equal(4, list[0].Count)
+}
+
+func (s *ContractTestSuite) TestCardsBatchInvalid() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Bulk")
+
+	_, err := s.client.BatchCardsInCollection(ctx, col.ID, &cards.BatchCardsRequest{})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	_, err = s.client.BatchCardsInCollection(ctx, col.ID, &cards.BatchCardsRequest{
+		Mode:       "eventually",
+		Operations: []cards.CardOperation{{Op: cards.CardOpAdd, ScryfallID: "bolt", Count: 1}},
+	})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	_, err = s.client.BatchCardsInCollection(bob, col.ID, &cards.BatchCardsRequest{
+		Operations: []cards.CardOperation{{Op: cards.CardOpAdd, ScryfallID: "bolt", Count: 1}},
+	})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestTransferCard() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	deck := s.createCollection(ctx, "Elves")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 5}))
+	condition := "LP"
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, bulk.ID, "elf", &cards.UpdateCardRequest{Condition: &condition}, nil))
+	before, err := s.client.GetCardInCollection(ctx, bulk.ID, "elf", nil)
+	s.Require().NoError(err)
+
+	resp, err := s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: deck.ID, Count: 4}, nil)
+	s.Require().NoError(err)
+	s.Equal(&cards.TransferCardResponse{FromCount: 1, ToCount: 4}, resp)
+
+	moved, err := s.client.GetCardInCollection(ctx, deck.ID, "elf", nil)
+	s.Require().NoError(err)
+	s.Equal(4, moved.Count)
+	s.Equal("Llanowar Elves", moved.Name)
+	s.Equal("LP", moved.Condition)
+	s.True(before.AddedAt.Equal(moved.AddedAt), "a move keeps added_at")
+
+	resp, err = s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: deck.ID, Count: 1, Mode: cards.TransferModeCopy}, nil)
+	s.Require().NoError(err)
+	s.Equal(&cards.TransferCardResponse{FromCount: 1, ToCount: 5}, resp)
+
+	resp, err = s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: deck.ID, Count: 1}, nil)
+	s.Require().NoError(err)
+	s.Equal(&cards.TransferCardResponse{FromCount: 0, ToCount: 6}, resp)
+
+	list, err := s.client.ListCardsInCollection(ctx, bulk.ID)
+	s.Require().NoError(err)
+	s.Empty(list)
+}
+
+func (s *ContractTestSuite) TestTransferCardZone() {
+	ctx := s.register(42)
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Elves", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	other, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Goblins", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 3, Zone: cards.ZoneSideboard}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1}))
+	sideboard := &cards.CardEntryQuery{Zone: cards.ZoneSideboard}
+
+	// Only decks keep zones: the copies join the card without one.
+	resp, err := s.client.TransferCard(ctx, deck.ID, "elf", &cards.TransferCardRequest{ToCollectionID: binder.ID, Count: 1}, sideboard)
+	s.Require().NoError(err)
+	s.Equal(&cards.TransferCardResponse{FromCount: 2, ToCount: 2}, resp)
+	list, err := s.client.ListCardsInCollection(ctx, binder.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Empty(list[0].Zone)
+	s.Equal(2, list[0].Count)
+
+	resp, err = s.client.TransferCard(ctx, deck.ID, "elf", &cards.TransferCardRequest{ToCollectionID: other.ID, Count: 1}, sideboard)
+	s.Require().NoError(err)
+	s.Equal(&cards.TransferCardResponse{FromCount: 1, ToCount: 1}, resp)
+	moved, err := s.client.GetCardInCollection(ctx, other.ID, "elf", sideboard)
+	s.Require().NoError(err)
+	s.Equal(cards.ZoneSideboard, moved.Zone)
+
+	// Undoing the move into the binder takes the copy back from the card there.
+	history, err := s.client.ListCardHistory(ctx, binder.ID, nil)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(history)
+	s.Empty(history[0].Zone)
+	_, err = s.client.UndoCardChange(ctx, binder.ID, history[0].ID, nil)
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"elf": 1}, s.cardCounts(ctx, binder.ID))
+}
+
+func (s *ContractTestSuite) TestTransferCardInvalid() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	deck := s.createCollection(ctx, "Elves")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 2}))
+
+	_, err := s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: deck.ID, Count: 3}, nil)
+	s.ErrorIs(err, collectorclient.ErrConflict)
+	_, err = s.client.TransferCard(ctx, bulk.ID, "bolt", &cards.TransferCardRequest{ToCollectionID: deck.ID, Count: 1}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: bulk.ID, Count: 1}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: deck.ID}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	bobs := s.createCollection(bob, "Stolen")
+	_, err = s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: bobs.ID, Count: 1}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.TransferCard(bob, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: bobs.ID, Count: 1}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+
+	list, err := s.client.ListCardsInCollection(ctx, bulk.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Equal(2, list[0].Count)
+}
+
+// cardCounts lists the cards of a collection as scryfall_id → count.
+func (s *ContractTestSuite) cardCounts(ctx context.Context, collectionID string) map[string]int {
+	list, err := s.client.ListCardsInCollection(ctx, collectionID)
+	s.Require().NoError(err)
+
+	counts := make(map[string]int, len(list))
+	for _, c := range list {
+		counts[c.ScryfallID] = c.Count
+	}
+	return counts
+}
+
+// collectionNames lists the names of the user's collections.
+func (s *ContractTestSuite) collectionNames(ctx context.Context) []string {
+	list, err := s.client.GetUserCollections(ctx)
+	s.Require().NoError(err)
+
+	names := make([]string, 0, len(list))
+	for _, c := range list {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func (s *ContractTestSuite) TestMergeCollections() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	trade := s.createCollection(ctx, "Trade")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trade.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 3}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trade.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+
+	merged, err := s.client.MergeCollections(ctx, bulk.ID, &collections.MergeCollectionRequest{SourceCollectionID: trade.ID, KeepSource: true})
+	s.Require().NoError(err)
+	s.Equal(bulk.ID, merged.ID)
+	s.Equal(6, merged.CardCount)
+	s.Equal(2, merged.UniqueCards)
+	s.Equal(map[string]int{"bolt": 5, "ouphe": 1}, s.cardCounts(ctx, bulk.ID))
+	s.Equal(map[string]int{"bolt": 3, "ouphe": 1}, s.cardCounts(ctx, trade.ID))
+
+	_, err = s.client.MergeCollections(ctx, bulk.ID, &collections.MergeCollectionRequest{SourceCollectionID: trade.ID})
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"bolt": 8, "ouphe": 2}, s.cardCounts(ctx, bulk.ID))
+	s.Equal([]string{"Bulk"}, s.collectionNames(ctx))
+	_, err = s.client.ListCardsInCollection(ctx, trade.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+
+	// The merged-away collection goes to the trash like a deleted one.
+	trash, err := s.client.ListTrash(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(trash, 1)
+	s.Equal(trade.ID, trash[0].ID)
+	restored, err := s.client.RestoreCollection(ctx, trade.ID)
+	s.Require().NoError(err)
+	s.Equal(4, restored.CardCount)
+}
+
+func (s *ContractTestSuite) TestMergeCollectionsInvalid() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+
+	_, err := s.client.MergeCollections(ctx, bulk.ID, &collections.MergeCollectionRequest{SourceCollectionID: bulk.ID})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.MergeCollections(ctx, bulk.ID, &collections.MergeCollectionRequest{})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	bobs := s.createCollection(bob, "Bob's")
+	_, err = s.client.MergeCollections(ctx, bulk.ID, &collections.MergeCollectionRequest{SourceCollectionID: bobs.ID})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.Equal([]string{"Bob's"}, s.collectionNames(bob))
+}
+
+func (s *ContractTestSuite) TestDuplicateCollection() {
+	ctx := s.register(42)
+	deck := s.createCollection(ctx, "Elves")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 4}))
+
+	copied, err := s.client.DuplicateCollection(ctx, deck.ID, &collections.DuplicateCollectionRequest{Name: "Elves v2"})
+	s.Require().NoError(err)
+	s.NotEqual(deck.ID, copied.ID)
+	s.Equal("Elves v2", copied.Name)
+	s.Equal(map[string]int{"elf": 4}, s.cardCounts(ctx, copied.ID))
+	s.Equal([]string{"Elves", "Elves v2"}, s.collectionNames(ctx))
+
+	// The copy is independent of the original.
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, copied.ID, "elf", nil))
+	s.Equal(map[string]int{"elf": 4}, s.cardCounts(ctx, deck.ID))
+
+	_, err = s.client.DuplicateCollection(ctx, deck.ID, &collections.DuplicateCollectionRequest{Name: "Elves v2"})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+}
+
+func (s *ContractTestSuite) TestSplitCollection() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "fury", Name: "Fury Sliver", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "might", Name: "Might Sliver", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 4}))
+
+	slivers, err := s.client.SplitCollection(ctx, bulk.ID, &collections.SplitCollectionRequest{
+		Name:   "Slivers",
+		Filter: collections.CardFilter{Name: "SLIVER"},
+	})
+	s.Require().NoError(err)
+	s.Equal("Slivers", slivers.Name)
+	s.Equal(map[string]int{"fury": 2, "might": 1}, s.cardCounts(ctx, slivers.ID))
+	s.Equal(map[string]int{"bolt": 4}, s.cardCounts(ctx, bulk.ID))
+	s.Equal([]string{"Bulk", "Slivers"}, s.collectionNames(ctx))
+
+	_, err = s.client.SplitCollection(ctx, bulk.ID, &collections.SplitCollectionRequest{Name: "Empty"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.SplitCollection(ctx, bulk.ID, &collections.SplitCollectionRequest{
+		Name:   "Nothing",
+		Filter: collections.CardFilter{ScryfallIDs: []string{"fury"}},
+	})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.SplitCollection(ctx, bulk.ID, &collections.SplitCollectionRequest{
+		Name:   "Slivers",
+		Filter: collections.CardFilter{ScryfallIDs: []string{"bolt"}},
+	})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+	s.Equal(map[string]int{"bolt": 4}, s.cardCounts(ctx, bulk.ID))
+}
+
+// historyOf lists the history of a collection as action:delta:source, newest first.
+func (s *ContractTestSuite) historyOf(ctx context.Context, collectionID string, query *cards.CardHistoryQuery) []string {
+	list, err := s.client.ListCardHistory(ctx, collectionID, query)
+	s.Require().NoError(err)
+
+	out := make([]string, 0, len(list))
+	for _, e := range list {
+		out = append(out, fmt.Sprintf("%s:%s:%d:%s", e.Action, e.ScryfallID, e.Delta, e.Source))
+	}
+	return out
+}
+
+func (s *ContractTestSuite) TestCardHistory() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	bot := collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, collectorclient.WithChangeSource(cards.ChangeSourceBot))
+
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(bot.SetCardCountInCollection(ctx, bulk.ID, "bolt", &cards.SetCardCountRequest{Count: 3}, nil))
+	notes := "Binder page 4"
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, bulk.ID, "bolt", &cards.UpdateCardRequest{Notes: &notes}, nil))
+	_, err := s.client.BatchCardsInCollection(ctx, bulk.ID, &cards.BatchCardsRequest{Operations: []cards.CardOperation{
+		{Op: cards.CardOpAdd, ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1},
+		{Op: cards.CardOpRemove, ScryfallID: "bolt", Count: 1},
+	}})
+	s.Require().NoError(err)
+	s.Require().NoError(bot.DeleteCardFromCollection(ctx, bulk.ID, "ouphe", nil))
+
+	s.Equal([]string{
+		"remove:ouphe:-1:bot",
+		"batch:ouphe:1:api",
+		"batch:bolt:-1:api",
+		"update:bolt:1:bot",
+		"add:bolt:2:api",
+	}, s.historyOf(ctx, bulk.ID, nil), "changes that keep the count, like notes, are not recorded")
+	s.Equal([]string{"batch:bolt:-1:api", "update:bolt:1:bot", "add:bolt:2:api"}, s.historyOf(ctx, bulk.ID, &cards.CardHistoryQuery{ScryfallID: "bolt"}))
+
+	page, err := s.client.ListCardHistory(ctx, bulk.ID, &cards.CardHistoryQuery{Limit: 3})
+	s.Require().NoError(err)
+	s.Require().Len(page, 3)
+	s.NotEqual(page[0].BatchID, page[1].BatchID)
+	s.Equal(page[1].BatchID, page[2].BatchID, "one batch request is one history batch")
+	s.Equal(bulk.ID, page[0].CollectionID)
+	s.Equal("Collector Ouphe", page[0].Name)
+	s.NotEmpty(page[0].UserID)
+	s.False(page[0].CreatedAt.IsZero())
+	s.Equal([]string{"update:bolt:1:bot", "add:bolt:2:api"},
+		s.historyOf(ctx, bulk.ID, &cards.CardHistoryQuery{Before: page[2].ID, Limit: 5}))
+
+	_, err = s.client.ListCardHistory(s.register(7), bulk.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestCardHistoryInvalid() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	telepathy := collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, collectorclient.WithChangeSource("telepathy"))
+
+	err := telepathy.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Count: 1})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	s.Empty(s.cardCounts(ctx, bulk.ID))
+
+	_, err = s.client.ListCardHistory(ctx, bulk.ID, &cards.CardHistoryQuery{Limit: 500})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.ListCardHistory(ctx, bulk.ID, &cards.CardHistoryQuery{Before: "latest"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.UndoCardChange(ctx, bulk.ID, "latest", &cards.UndoChangeRequest{})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.UndoCardChange(ctx, bulk.ID, "0123456789abcdef01234567", &cards.UndoChangeRequest{})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestUndoCardChange() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 3}))
+	bought, err := s.client.AddAcquisition(ctx, bulk.ID, "bolt", &cards.AcquisitionRequest{Count: 2, Price: 1, Currency: "usd"}, nil)
+	s.Require().NoError(err)
+	box, err := s.client.CreateLocation(ctx, &locations.CreateLocationRequest{Name: "Box", Kind: locations.KindBox})
+	s.Require().NoError(err)
+	_, err = s.client.RelocateCards(ctx, bulk.ID, &locations.RelocateCardsRequest{ScryfallIDs: []string{"bolt"}, Location: &locations.Location{LocationID: box.ID}})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, bulk.ID, "bolt", nil))
+
+	history, err := s.client.ListCardHistory(ctx, bulk.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(history, 2)
+	removal := history[0]
+
+	resp, err := s.client.UndoCardChange(ctx, bulk.ID, removal.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(resp.Entries, 1)
+	s.Equal(removal.ID, resp.Entries[0].UndoOf)
+	s.Equal(3, resp.Entries[0].Delta)
+	s.Equal("undo", resp.Entries[0].Action)
+	s.Equal(map[string]int{"bolt": 3}, s.cardCounts(ctx, bulk.ID), "the removed card is back")
+
+	card, err := s.client.GetCardInCollection(ctx, bulk.ID, "bolt", nil)
+	s.Require().NoError(err)
+	s.Equal("Lightning Bolt", card.Name)
+	s.Equal([]cards.Acquisition{*bought}, card.Acquisitions, "the purchases are back with the card")
+	s.Equal(&locations.Location{LocationID: box.ID}, card.Location)
+
+	_, err = s.client.UndoCardChange(ctx, bulk.ID, removal.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrConflict)
+
+	_, err = s.client.UndoCardChange(s.register(7), bulk.ID, removal.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+
+	// Undoing the undo brings the removal back.
+	_, err = s.client.UndoCardChange(ctx, bulk.ID, resp.Entries[0].ID, nil)
+	s.Require().NoError(err)
+	s.Empty(s.cardCounts(ctx, bulk.ID))
+
+	_, err = s.client.UndoCardChange(ctx, bulk.ID, history[1].ID, nil)
+	s.ErrorIs(err, collectorclient.ErrConflict, "the added copies are gone")
+	s.Empty(s.cardCounts(ctx, bulk.ID))
+}
+
+func (s *ContractTestSuite) TestUndoCardBatch() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	deck := s.createCollection(ctx, "Deck")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 4}))
+	_, err := s.client.TransferCard(ctx, bulk.ID, "elf", &cards.TransferCardRequest{ToCollectionID: deck.ID, Count: 4}, nil)
+	s.Require().NoError(err)
+
+	history, err := s.client.ListCardHistory(ctx, bulk.ID, nil)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(history)
+	transfer := history[0]
+	s.Equal("transfer", transfer.Action)
+	s.Equal(-4, transfer.Delta)
+	s.Equal(deck.ID, transfer.RelatedCollectionID)
+
+	resp, err := s.client.UndoCardChange(ctx, bulk.ID, transfer.ID, &cards.UndoChangeRequest{Batch: true})
+	s.Require().NoError(err)
+	s.Len(resp.Entries, 2, "both sides of the transfer")
+	s.Equal(map[string]int{"elf": 4}, s.cardCounts(ctx, bulk.ID))
+	s.Empty(s.cardCounts(ctx, deck.ID))
+
+	deckHistory, err := s.client.ListCardHistory(ctx, deck.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(deckHistory, 2)
+	s.Equal(-4, deckHistory[0].Delta)
+	s.Equal("undo", deckHistory[0].Action)
+
+	_, err = s.client.UndoCardChange(ctx, deck.ID, deckHistory[1].ID, nil)
+	s.ErrorIs(err, collectorclient.ErrConflict, "the deck side is already undone")
+}
+
+func (s *ContractTestSuite) TestSnapshots() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}))
+
+	first, err := s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{Name: "Before FNM"})
+	s.Require().NoError(err)
+	s.NotEmpty(first.ID)
+	s.Equal(bulk.ID, first.CollectionID)
+	s.Equal(3, first.CardCount)
+	s.Equal(2, first.UniqueCards)
+	s.Equal([]collections.SnapshotCard{
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1},
+		{ScryfallID: "elf", Name: "Llanowar Elves", Count: 2},
+	}, first.Cards)
+
+	s.Require().NoError(s.client.SetCardCountInCollection(ctx, bulk.ID, "elf", &cards.SetCardCountRequest{Count: 4}, nil))
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, bulk.ID, "bolt", nil))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+
+	diff, err := s.client.DiffSnapshot(ctx, bulk.ID, first.ID, "")
+	s.Require().NoError(err)
+	s.Equal(first.ID, diff.From)
+	s.Empty(diff.To)
+	s.Equal([]collections.CardDiff{{ScryfallID: "ouphe", Name: "Collector Ouphe", After: 1, Delta: 1}}, diff.Added)
+	s.Equal([]collections.CardDiff{{ScryfallID: "bolt", Name: "Lightning Bolt", Before: 1, Delta: -1}}, diff.Removed)
+	s.Equal([]collections.CardDiff{{ScryfallID: "elf", Name: "Llanowar Elves", Before: 2, After: 4, Delta: 2}}, diff.Changed)
+
+	second, err := s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{Name: "After FNM"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1}))
+
+	diff, err = s.client.DiffSnapshot(ctx, bulk.ID, first.ID, second.ID)
+	s.Require().NoError(err)
+	s.Equal(second.ID, diff.To)
+	s.Len(diff.Changed, 1)
+	s.Equal(4, diff.Changed[0].After, "the later snapshot, not the current cards")
+
+	diff, err = s.client.DiffSnapshot(ctx, bulk.ID, second.ID, "")
+	s.Require().NoError(err)
+	s.Empty(diff.Added)
+	s.Empty(diff.Removed)
+	s.Equal([]collections.CardDiff{{ScryfallID: "elf", Name: "Llanowar Elves", Before: 4, After: 5, Delta: 1}}, diff.Changed)
+
+	list, err := s.client.ListSnapshots(ctx, bulk.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 2)
+	s.Equal([]string{second.ID, first.ID}, []string{list[0].ID, list[1].ID}, "newest first")
+	s.Empty(list[0].Cards, "the list has no cards")
+
+	got, err := s.client.GetSnapshot(ctx, bulk.ID, first.ID)
+	s.Require().NoError(err)
+	s.Equal(first.Cards, got.Cards, "snapshots don't follow the collection")
+
+	s.Require().NoError(s.client.DeleteSnapshot(ctx, bulk.ID, first.ID))
+	_, err = s.client.GetSnapshot(ctx, bulk.ID, first.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestSnapshotFinishes() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1, Finish: "foil"}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1, Finish: "foil"}))
+
+	snapshot, err := s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{Name: "Before"})
+	s.Require().NoError(err)
+	s.Equal([]collections.SnapshotCard{
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Finish: "foil", Count: 1},
+		{ScryfallID: "elf", Name: "Llanowar Elves", Count: 2},
+		{ScryfallID: "elf", Name: "Llanowar Elves", Finish: "foil", Count: 1},
+	}, snapshot.Cards, "every finish of a printing is a card of its own")
+
+	// Trading the foil bolt for a nonfoil one shows in the diff.
+	nonfoil := "nonfoil"
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, bulk.ID, "bolt", &cards.UpdateCardRequest{Finish: &nonfoil}, &cards.CardEntryQuery{Finish: "foil"}))
+	diff, err := s.client.DiffSnapshot(ctx, bulk.ID, snapshot.ID, "")
+	s.Require().NoError(err)
+	s.Equal([]collections.CardDiff{{ScryfallID: "bolt", Name: "Lightning Bolt", After: 1, Delta: 1}}, diff.Added)
+	s.Equal([]collections.CardDiff{{ScryfallID: "bolt", Name: "Lightning Bolt", Finish: "foil", Before: 1, Delta: -1}}, diff.Removed)
+	s.Empty(diff.Changed)
+}
+
+func (s *ContractTestSuite) TestSnapshotsInvalid() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	deck := s.createCollection(ctx, "Deck")
+	snapshot, err := s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{Name: "Empty"})
+	s.Require().NoError(err)
+	s.Empty(snapshot.Cards)
+
+	_, err = s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{Name: "Empty"})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+	_, err = s.client.CreateSnapshot(ctx, deck.ID, &collections.CreateSnapshotRequest{Name: "Empty"})
+	s.NoError(err, "names are unique within a collection")
+	_, err = s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.CreateSnapshot(ctx, bulk.ID, &collections.CreateSnapshotRequest{Name: "   "})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.CreateSnapshot(ctx, "nope", &collections.CreateSnapshotRequest{Name: "Empty"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	_, err = s.client.GetSnapshot(ctx, bulk.ID, "nope")
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetSnapshot(ctx, deck.ID, snapshot.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound, "the snapshot belongs to another collection")
+	_, err = s.client.DiffSnapshot(ctx, bulk.ID, snapshot.ID, "nope")
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	s.ErrorIs(s.client.DeleteSnapshot(ctx, deck.ID, snapshot.ID), collectorclient.ErrNotFound)
+
+	bob := s.register(7)
+	_, err = s.client.ListSnapshots(bob, bulk.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.GetSnapshot(bob, bulk.ID, snapshot.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+// missingOf maps the Name of every missing wishlist card to how many copies are missing.
+func (s *ContractTestSuite) missingOf(ctx context.Context) map[string]int {
+	list, err := s.client.ListMissingCards(ctx)
+	s.Require().NoError(err)
+	out := make(map[string]int, len(list))
+	for _, m := range list {
+		s.Equal(m.Quantity-m.Owned, m.Missing)
+		out[m.Name] = m.Missing
+	}
+	return out
+}
+
+func (s *ContractTestSuite) TestWishlist() {
+	ctx := s.register(42)
+
+	list, err := s.client.ListWishlist(ctx)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	bolt, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{
+		ScryfallID: "bolt-m10", Name: "Lightning Bolt", AnyPrinting: true, Quantity: 4, MaxPrice: 2.5,
+	})
+	s.Require().NoError(err)
+	s.NotEmpty(bolt.ID)
+	s.True(bolt.AnyPrinting)
+	s.Equal(wishlist.Entry{
+		ID: bolt.ID, ScryfallID: "bolt-m10", Name: "Lightning Bolt", AnyPrinting: true,
+		Quantity: 4, MaxPrice: 2.5, Currency: "usd", Priority: 3, CreatedAt: bolt.CreatedAt,
+	}, *bolt)
+	ouphe, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{
+		ScryfallID: "ouphe", Name: "Collector Ouphe", Quantity: 2, Finish: wishlist.FinishFoil, Priority: 5,
+	})
+	s.Require().NoError(err)
+	s.False(ouphe.AnyPrinting)
+	elf, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{
+		OracleID: "elf-oracle", Name: "Llanowar Elves", Quantity: 1,
+	})
+	s.Require().NoError(err)
+	s.True(elf.AnyPrinting, "an oracle ID means any printing")
+
+	list, err = s.client.ListWishlist(ctx)
+	s.Require().NoError(err)
+	s.Equal([]wishlist.Entry{*ouphe, *bolt, *elf}, list, "most wanted first, then in order of adding")
+
+	bulk := s.createCollection(ctx, "Bulk")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "bolt-2x2", Name: "Lightning Bolt", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, bulk.ID, &cards.AddCardRequest{ScryfallID: "ouphe-promo", Name: "Collector Ouphe", Count: 3}))
+	deck := s.createCollection(ctx, "Deck")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1}))
+	wants, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wants", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wants.ID, &cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 4}))
+
+	s.Equal(map[string]int{"Collector Ouphe": 2, "Lightning Bolt": 1}, s.missingOf(ctx),
+		"copies count across collections, another printing is not the wanted one, wishlist collections own nothing")
+
+	missing, err := s.client.ListMissingCards(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(missing, 2)
+	s.Equal(wishlist.MissingEntry{Entry: *ouphe, Owned: 0, Missing: 2}, missing[0])
+	s.Equal(3, missing[1].Owned)
+
+	s.Require().NoError(s.client.DeleteCollection(ctx, deck.ID))
+	s.Equal(map[string]int{"Collector Ouphe": 2, "Lightning Bolt": 3, "Llanowar Elves": 1}, s.missingOf(ctx),
+		"cards in the trash are not owned")
+
+	quantity, priority, finish := 1, 1, ""
+	updated, err := s.client.UpdateWishlistEntry(ctx, bolt.ID, &wishlist.UpdateEntryRequest{Quantity: &quantity, Priority: &priority})
+	s.Require().NoError(err)
+	s.Equal(1, updated.Quantity)
+	s.Equal(1, updated.Priority)
+	s.Equal(2.5, updated.MaxPrice, "fields left out are kept")
+	updated, err = s.client.UpdateWishlistEntry(ctx, ouphe.ID, &wishlist.UpdateEntryRequest{Finish: &finish})
+	s.Require().NoError(err)
+	s.Empty(updated.Finish)
+	s.Equal(map[string]int{"Collector Ouphe": 2, "Llanowar Elves": 1}, s.missingOf(ctx))
+
+	s.Require().NoError(s.client.DeleteWishlistEntry(ctx, ouphe.ID))
+	list, err = s.client.ListWishlist(ctx)
+	s.Require().NoError(err)
+	s.Equal([]string{elf.ID, bolt.ID}, []string{list[0].ID, list[1].ID})
+}
+
+func (s *ContractTestSuite) TestWishlistInvalid() {
+	ctx := s.register(42)
+	bolt, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "bolt", Quantity: 4})
+	s.Require().NoError(err)
+
+	for name, req := range map[string]*wishlist.AddEntryRequest{
+		"no quantity":            {ScryfallID: "elf"},
+		"no card":                {Name: "Llanowar Elves", Quantity: 1},
+		"negative price":         {ScryfallID: "elf", Quantity: 1, MaxPrice: -1},
+		"unknown finish":         {ScryfallID: "elf", Quantity: 1, Finish: "shiny"},
+		"priority too high":      {ScryfallID: "elf", Quantity: 1, Priority: 6},
+		"any printing, nameless": {ScryfallID: "elf", AnyPrinting: true, Quantity: 1},
+	} {
+		_, err = s.client.AddWishlistEntry(ctx, req)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, name)
+	}
+	_, err = s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "bolt", Quantity: 1})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+	_, err = s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "bolt", Name: "Lightning Bolt", AnyPrinting: true, Quantity: 1})
+	s.NoError(err, "any printing is another wish than the printing")
+
+	zero, price := 0, -1.0
+	_, err = s.client.UpdateWishlistEntry(ctx, bolt.ID, &wishlist.UpdateEntryRequest{})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.UpdateWishlistEntry(ctx, bolt.ID, &wishlist.UpdateEntryRequest{Quantity: &zero})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.UpdateWishlistEntry(ctx, bolt.ID, &wishlist.UpdateEntryRequest{MaxPrice: &price})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.UpdateWishlistEntry(ctx, "nope", &wishlist.UpdateEntryRequest{Priority: &zero})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	s.ErrorIs(s.client.DeleteWishlistEntry(ctx, "nope"), collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	list, err := s.client.ListWishlist(bob)
+	s.Require().NoError(err)
+	s.Empty(list)
+	one := 1
+	_, err = s.client.UpdateWishlistEntry(bob, bolt.ID, &wishlist.UpdateEntryRequest{Quantity: &one})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteWishlistEntry(bob, bolt.ID), collectorclient.ErrNotFound)
+	_, err = s.client.AddWishlistEntry(bob, &wishlist.AddEntryRequest{ScryfallID: "bolt", Quantity: 1})
+	s.NoError(err, "wishlists are per user")
+}
+
+func (s *ContractTestSuite) TestDeckValidation() {
+	ctx := s.register(42)
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{
+		Name: "Burn", Kind: collections.KindDeck, Format: collections.FormatModern,
+	})
+	s.Require().NoError(err)
+	s.Equal(collections.FormatModern, deck.Format)
+
+	for _, req := range []*cards.AddCardRequest{
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 4},
+		{ScryfallID: "guide", Name: "Goblin Guide", Count: 4},
+		{ScryfallID: "guide-promo", Name: "Goblin Guide", Count: 1, Zone: cards.ZoneSideboard},
+		{ScryfallID: "mountain", Name: "Mountain", Count: 20},
+		{ScryfallID: "blast", Name: "Pyroblast", Count: 2, Zone: cards.ZoneSideboard},
+		{ScryfallID: "shock", Name: "Shock", Count: 10, Zone: cards.ZoneMaybeboard},
+	} {
+		s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, req))
+	}
+
+	report, err := s.client.ValidateDeck(ctx, deck.ID)
+	s.Require().NoError(err)
+	s.Equal(&collections.DeckValidation{
+		Format:    collections.FormatModern,
+		Mainboard: 28,
+		Sideboard: 3,
+		Problems: []collections.DeckProblem{
+			{Rule: collections.DeckRuleSize, Message: "Deck has 28 cards, modern needs at least 60"},
+			{Rule: collections.DeckRuleCopies, Message: "At most 4 copies of a card are allowed", Cards: []string{"Goblin Guide"}},
+		},
+		Unchecked: []string{"Lightning Bolt", "Goblin Guide", "Mountain", "Pyroblast"},
+	}, report, "copies count across printings and zones, the maybeboard is not part of the deck")
+
+	count, mainboard := 0, cards.ZoneMainboard
+	sideboard := &cards.CardEntryQuery{Zone: cards.ZoneSideboard}
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, deck.ID, "guide-promo", &cards.UpdateCardRequest{Count: &count}, sideboard))
+	s.Require().NoError(s.client.SetCardCountInCollection(ctx, deck.ID, "mountain", &cards.SetCardCountRequest{Count: 52}, nil))
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, deck.ID, "blast", &cards.UpdateCardRequest{Zone: &mainboard}, sideboard))
+
+	list, err := s.client.ListCardsInCollection(ctx, deck.ID)
+	s.Require().NoError(err)
+	zones := make(map[string]string)
+	for _, card := range list {
+		zones[card.ScryfallID] = card.Zone
+	}
+	s.Equal(map[string]string{"bolt": "", "guide": "", "mountain": "", "blast": "", "shock": cards.ZoneMaybeboard}, zones,
+		"the mainboard is no zone, and a card without copies is gone")
+
+	report, err = s.client.ValidateDeck(ctx, deck.ID)
+	s.Require().NoError(err)
+	s.True(report.Legal)
+	s.Empty(report.Problems)
+	s.Equal(62, report.Mainboard)
+	s.Zero(report.Sideboard)
+
+	commander := collections.FormatCommander
+	s.Require().NoError(s.client.UpdateCollection(ctx, deck.ID, &collections.UpdateCollectionRequest{Format: &commander}))
+	zone := cards.ZoneCommander
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "krenko", Name: "Krenko, Mob Boss", Count: 1, Zone: zone}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "lurrus", Name: "Lurrus of the Dream-Den", Count: 1, Zone: cards.ZoneCompanion}))
+	report, err = s.client.ValidateDeck(ctx, deck.ID)
+	s.Require().NoError(err)
+	s.False(report.Legal)
+	s.Equal([]string{"Krenko, Mob Boss"}, report.Commanders)
+	s.Equal(63, report.Mainboard)
+	s.Zero(report.Sideboard, "a companion is not in the sideboard of a commander deck")
+	s.Equal([]collections.DeckProblem{
+		{Rule: collections.DeckRuleSize, Message: "Deck has 63 cards, commander needs exactly 100"},
+		{Rule: collections.DeckRuleCopies, Message: "Only one copy of a card is allowed", Cards: []string{"Lightning Bolt", "Goblin Guide", "Pyroblast"}},
+	}, report.Problems, "basic lands are not limited")
+
+	modern := collections.FormatModern
+	s.Require().NoError(s.client.UpdateCollection(ctx, deck.ID, &collections.UpdateCollectionRequest{Format: &modern}))
+	report, err = s.client.ValidateDeck(ctx, deck.ID)
+	s.Require().NoError(err)
+	s.Equal([]collections.DeckProblem{
+		{Rule: collections.DeckRuleCommander, Message: "modern decks have no commander", Cards: []string{"Krenko, Mob Boss"}},
+	}, report.Problems)
+	s.Equal(1, report.Sideboard, "the companion is in the sideboard")
+}
+
+func (s *ContractTestSuite) TestDeckValidationInvalid() {
+	ctx := s.register(42)
+	bulk := s.createCollection(ctx, "Bulk")
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Deck", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+
+	_, err = s.client.ValidateDeck(ctx, bulk.ID)
+	s.ErrorIs(err, collectorclient.ErrBadRequest, "not a deck")
+	_, err = s.client.ValidateDeck(ctx, deck.ID)
+	s.ErrorIs(err, collectorclient.ErrBadRequest, "no format")
+	_, err = s.client.ValidateDeck(ctx, "nope")
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	_, err = s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Brawl", Kind: collections.KindDeck, Format: "brawl"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	format := "brawl"
+	s.ErrorIs(s.client.UpdateCollection(ctx, deck.ID, &collections.UpdateCollectionRequest{Format: &format}), collectorclient.ErrBadRequest)
+
+	err = s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "bolt", Count: 1, Zone: "graveyard"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "bolt", Count: 1}))
+	zone := "exile"
+	err = s.client.UpdateCardInCollection(ctx, deck.ID, "bolt", &cards.UpdateCardRequest{Zone: &zone}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	_, err = s.client.ValidateDeck(bob, deck.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+// zoneCounts maps the deck zones of a printing in a collection to their counts.
+func (s *ContractTestSuite) zoneCounts(ctx context.Context, collectionID, scryfallID string) map[string]int {
+	list, err := s.client.ListCardsInCollection(ctx, collectionID)
+	s.Require().NoError(err)
+
+	counts := make(map[string]int)
+	for _, c := range list {
+		if c.ScryfallID == scryfallID {
+			counts[c.Zone] = c.Count
+		}
+	}
+	return counts
+}
+
+func (s *ContractTestSuite) TestCardZones() {
+	ctx := s.register(42)
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{
+		Name: "Burn", Kind: collections.KindDeck, Format: collections.FormatModern,
+	})
+	s.Require().NoError(err)
+	sideboard := &cards.CardEntryQuery{Zone: cards.ZoneSideboard}
+
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "blast", Name: "Pyroblast", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "blast", Name: "Pyroblast", Count: 3, Zone: cards.ZoneSideboard}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "blast", Name: "Pyroblast", Count: 1, Zone: cards.ZoneMainboard}))
+	s.Equal(map[string]int{"": 3, cards.ZoneSideboard: 3}, s.zoneCounts(ctx, deck.ID, "blast"),
+		"sideboard copies don't add to the mainboard")
+
+	main, err := s.client.GetCardInCollection(ctx, deck.ID, "blast", nil)
+	s.Require().NoError(err)
+	s.Equal(3, main.Count)
+	s.Empty(main.Zone)
+	side, err := s.client.GetCardInCollection(ctx, deck.ID, "blast", sideboard)
+	s.Require().NoError(err)
+	s.Equal(3, side.Count)
+	s.Equal(cards.ZoneSideboard, side.Zone)
+	s.Require().Len(side.Variants, 1, "the mainboard copies are another entry of the card")
+	s.Empty(side.Variants[0].Zone)
+	_, err = s.client.GetCardInCollection(ctx, deck.ID, "blast", &cards.CardEntryQuery{Zone: cards.ZoneCommander})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.GetCardInCollection(ctx, deck.ID, "blast", &cards.CardEntryQuery{Zone: "graveyard"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	one, mainboard := 1, cards.ZoneMainboard
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, deck.ID, "blast", &cards.UpdateCardRequest{Count: &one}, sideboard))
+	s.Equal(map[string]int{"": 3, cards.ZoneSideboard: 1}, s.zoneCounts(ctx, deck.ID, "blast"))
+	err = s.client.UpdateCardInCollection(ctx, deck.ID, "blast", &cards.UpdateCardRequest{Zone: &mainboard}, sideboard)
+	s.ErrorIs(err, collectorclient.ErrConflict, "the card is already in the mainboard")
+
+	resp, err := s.client.BatchCardsInCollection(ctx, deck.ID, &cards.BatchCardsRequest{Operations: []cards.CardOperation{
+		{Op: cards.CardOpRemove, ScryfallID: "blast", Zone: cards.ZoneSideboard},
+		{Op: cards.CardOpAdd, ScryfallID: "blast", Name: "Pyroblast", Zone: cards.ZoneMaybeboard, Count: 2},
+	}})
+	s.Require().NoError(err)
+	s.True(resp.Committed)
+	s.Equal(cards.ZoneSideboard, resp.Results[0].Zone)
+	s.Zero(resp.Results[0].Count)
+	s.Equal(map[string]int{"": 3, cards.ZoneMaybeboard: 2}, s.zoneCounts(ctx, deck.ID, "blast"))
+
+	history, err := s.client.ListCardHistory(ctx, deck.ID, &cards.CardHistoryQuery{ScryfallID: "blast"})
+	s.Require().NoError(err)
+	i := slices.IndexFunc(history, func(e cards.CardHistoryEntry) bool { return e.Zone == cards.ZoneSideboard && e.Delta == -1 })
+	s.Require().GreaterOrEqual(i, 0)
+	_, err = s.client.UndoCardChange(ctx, deck.ID, history[i].ID, &cards.UndoChangeRequest{})
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"": 3, cards.ZoneSideboard: 1, cards.ZoneMaybeboard: 2}, s.zoneCounts(ctx, deck.ID, "blast"),
+		"undo brings the card back to its zone")
+
+	binder := s.createCollection(ctx, "Binder")
+	_, err = s.client.TransferCard(ctx, deck.ID, "blast", &cards.TransferCardRequest{ToCollectionID: binder.ID, Count: 2}, &cards.CardEntryQuery{Zone: cards.ZoneMaybeboard})
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"": 2}, s.zoneCounts(ctx, binder.ID, "blast"), "only decks keep zones")
+
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, deck.ID, "blast", sideboard))
+	s.Equal(map[string]int{"": 3}, s.zoneCounts(ctx, deck.ID, "blast"))
+}
+
+func (s *ContractTestSuite) TestDeckBuildability() {
+	ctx := s.register(42)
+	add := func(col *collections.Collection, reqs ...*cards.AddCardRequest) {
+		for _, req := range reqs {
+			s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, req))
+		}
+	}
+	binder := s.createCollection(ctx, "Red binder")
+	add(binder,
+		&cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 2},
+		&cards.AddCardRequest{ScryfallID: "mountain", Name: "Mountain", Count: 30},
+	)
+	trades := s.createCollection(ctx, "Trades")
+	add(trades, &cards.AddCardRequest{ScryfallID: "bolt-2xm", Name: "Lightning Bolt", Count: 3})
+	wanted, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wanted", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	add(wanted, &cards.AddCardRequest{ScryfallID: "guide", Name: "Goblin Guide", Count: 4})
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Burn", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	add(deck,
+		&cards.AddCardRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 4},
+		&cards.AddCardRequest{ScryfallID: "guide", Name: "Goblin Guide", Count: 4},
+		&cards.AddCardRequest{ScryfallID: "mountain", Name: "Mountain", Count: 20},
+		&cards.AddCardRequest{ScryfallID: "shock", Name: "Shock", Count: 4, Zone: cards.ZoneMaybeboard},
+	)
+
+	report, err := s.client.CheckBuildability(ctx, &collections.BuildabilityRequest{CollectionID: deck.ID})
+	s.Require().NoError(err)
+	s.Equal(&collections.DeckBuildability{
+		Needed: 28, Covered: 22, Missing: 6, Currency: "usd",
+		Unpriced: []string{"Lightning Bolt", "Goblin Guide"},
+		Cards: []collections.BuildabilityCard{
+			{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Needed: 4, Covered: 2, Missing: 2, Sources: []collections.CardSource{
+				{CollectionID: binder.ID, CollectionName: "Red binder", ScryfallID: "bolt-m10", Count: 2},
+			}},
+			{ScryfallID: "guide", Name: "Goblin Guide", Needed: 4, Missing: 4, Sources: []collections.CardSource{}},
+			{ScryfallID: "mountain", Name: "Mountain", Needed: 20, Covered: 20, Sources: []collections.CardSource{
+				{CollectionID: binder.ID, CollectionName: "Red binder", ScryfallID: "mountain", Count: 20},
+			}},
+		},
+	}, report, "the deck itself, wishlists and the maybeboard don't count")
+
+	report, err = s.client.CheckBuildability(ctx, &collections.BuildabilityRequest{CollectionID: deck.ID, AnyPrinting: true})
+	s.Require().NoError(err)
+	s.True(report.AnyPrinting)
+	s.Equal(24, report.Covered)
+	s.Equal([]collections.CardSource{
+		{CollectionID: binder.ID, CollectionName: "Red binder", ScryfallID: "bolt-m10", Count: 2},
+		{CollectionID: trades.ID, CollectionName: "Trades", ScryfallID: "bolt-2xm", Count: 2},
+	}, report.Cards[0].Sources, "the same printing goes first")
+
+	report, err = s.client.CheckBuildability(ctx, &collections.BuildabilityRequest{Decklist: strings.Join([]string{
+		"Deck",
+		"4 Lightning Bolt",
+		"20x Mountain (M10) 242",
+		"",
+		"Sideboard",
+		"2 Goblin Guide",
+		"// ready to go",
+		"Maybeboard",
+		"4 Shock",
+		"SB: 1 lightning bolt",
+	}, "\n")})
+	s.Require().NoError(err)
+	s.True(report.Buildable)
+	s.Empty(report.Unpriced)
+	s.Equal([]collections.BuildabilityCard{
+		{Name: "Lightning Bolt", Needed: 5, Covered: 5, Sources: []collections.CardSource{
+			{CollectionID: binder.ID, CollectionName: "Red binder", ScryfallID: "bolt-m10", Count: 2},
+			{CollectionID: trades.ID, CollectionName: "Trades", ScryfallID: "bolt-2xm", Count: 3},
+		}},
+		{Name: "Mountain", Needed: 20, Covered: 20, Sources: []collections.CardSource{
+			{CollectionID: binder.ID, CollectionName: "Red binder", ScryfallID: "mountain", Count: 20},
+		}},
+		{Name: "Goblin Guide", Needed: 2, Covered: 2, Sources: []collections.CardSource{
+			{CollectionID: deck.ID, CollectionName: "Burn", ScryfallID: "guide", Count: 2},
+		}},
+	}, report.Cards, "lines without a known printing take any, other decks count")
+}
+
+func (s *ContractTestSuite) TestDeckBuildabilityInvalid() {
+	ctx := s.register(42)
+	deck := s.createCollection(ctx, "Deck")
+
+	for _, req := range []*collections.BuildabilityRequest{
+		{},
+		{Decklist: " \n "},
+		{CollectionID: deck.ID, Decklist: "4 Lightning Bolt"},
+		{Decklist: "0 Lightning Bolt"},
+		{Decklist: "// nothing yet\nMaybeboard\n1 Shock"},
+		{CollectionID: "nope"},
+	} {
+		_, err := s.client.CheckBuildability(ctx, req)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, "%+v", req)
+	}
+
+	bob := s.register(7)
+	_, err := s.client.CheckBuildability(bob, &collections.BuildabilityRequest{CollectionID: deck.ID})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestAllocations() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 4}))
+	var decks []*collections.Collection
+	for _, name := range []string{"Elves", "Goblins", "Burn"} {
+		deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: name, Kind: collections.KindDeck})
+		s.Require().NoError(err)
+		s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+		decks = append(decks, deck)
+	}
+	elves, goblins, burn := decks[0], decks[1], decks[2]
+
+	list, err := s.client.ListAllocations(ctx, elves.ID)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	var reserved []*collections.Allocation
+	for _, deck := range decks {
+		a, err := s.client.ReserveCard(ctx, deck.ID, &collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 1})
+		s.Require().NoError(err)
+		s.NotEmpty(a.ID)
+		s.False(a.CreatedAt.IsZero())
+		s.Equal(collections.Allocation{
+			ID: a.ID, CollectionID: deck.ID, SourceCollectionID: binder.ID, ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1, CreatedAt: a.CreatedAt,
+		}, *a)
+		reserved = append(reserved, a)
+	}
+	list, err = s.client.ListAllocations(ctx, elves.ID)
+	s.Require().NoError(err)
+	s.Equal([]collections.Allocation{*reserved[0]}, list)
+
+	s.Require().NoError(s.client.AddCardToCollection(ctx, burn.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 4, ReserveFrom: binder.ID}))
+	list, err = s.client.ListAllocations(ctx, burn.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 2)
+	s.Equal([]any{"bolt", binder.ID, 4}, []any{list[1].ScryfallID, list[1].SourceCollectionID, list[1].Count}, "adding a card can reserve it")
+
+	conflicts, err := s.client.ListAllocationConflicts(ctx)
+	s.Require().NoError(err)
+	s.Equal([]collections.AllocationConflict{{
+		SourceCollectionID: binder.ID, SourceCollectionName: "Binder", ScryfallID: "sol-ring", Name: "Sol Ring",
+		Owned: 2, Allocated: 3, Over: 1,
+		Decks: []collections.AllocatedDeck{
+			{CollectionID: elves.ID, Name: "Elves", AllocationID: reserved[0].ID, Count: 1},
+			{CollectionID: goblins.ID, Name: "Goblins", AllocationID: reserved[1].ID, Count: 1},
+			{CollectionID: burn.ID, Name: "Burn", AllocationID: reserved[2].ID, Count: 1},
+		},
+	}}, conflicts)
+
+	report, err := s.client.CheckBuildability(ctx, &collections.BuildabilityRequest{CollectionID: goblins.ID})
+	s.Require().NoError(err)
+	s.Equal([]collections.CardSource{
+		{CollectionID: binder.ID, CollectionName: "Binder", ScryfallID: "sol-ring", Count: 1, Reserved: true},
+	}, report.Cards[0].Sources, "the copy reserved for the deck goes first")
+
+	report, err = s.client.CheckBuildability(ctx, &collections.BuildabilityRequest{Decklist: "2 Sol Ring\n1 Lightning Bolt"})
+	s.Require().NoError(err)
+	s.Equal([]collections.BuildabilityCard{
+		{Name: "Sol Ring", Needed: 2, Covered: 2, Sources: []collections.CardSource{
+			{CollectionID: binder.ID, CollectionName: "Binder", ScryfallID: "sol-ring", Count: 1, DeckID: elves.ID, DeckName: "Elves"},
+			{CollectionID: binder.ID, CollectionName: "Binder", ScryfallID: "sol-ring", Count: 1, DeckID: goblins.ID, DeckName: "Goblins"},
+		}},
+		{Name: "Lightning Bolt", Needed: 1, Covered: 1, Sources: []collections.CardSource{
+			{CollectionID: binder.ID, CollectionName: "Binder", ScryfallID: "bolt", Count: 1, DeckID: burn.ID, DeckName: "Burn"},
+		}},
+	}, report.Cards, "copies in decks are counted once, in their source, and tell which deck holds them")
+
+	count := 0
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, elves.ID, "sol-ring", &cards.UpdateCardRequest{Count: &count}, nil))
+	conflicts, err = s.client.ListAllocationConflicts(ctx)
+	s.Require().NoError(err)
+	s.Empty(conflicts, "a deck can't hold more reserved copies than it has")
+
+	s.Require().NoError(s.client.AddCardToCollection(ctx, elves.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+	s.Require().NoError(s.client.ReleaseCard(ctx, burn.ID, reserved[2].ID))
+	conflicts, err = s.client.ListAllocationConflicts(ctx)
+	s.Require().NoError(err)
+	s.Empty(conflicts)
+	list, err = s.client.ListAllocations(ctx, burn.ID)
+	s.Require().NoError(err)
+	s.Len(list, 1)
+}
+
+func (s *ContractTestSuite) TestAllocationsInvalid() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}))
+	wanted, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wanted", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wanted.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Elves", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+	other, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Goblins", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, other.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+
+	_, err = s.client.ListAllocations(ctx, binder.ID)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	for _, tc := range []struct {
+		deck string
+		req  collections.ReserveCardRequest
+		err  error
+	}{
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID}, collectorclient.ErrBadRequest},
+		{binder.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 1}, collectorclient.ErrBadRequest},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: deck.ID, Count: 1}, collectorclient.ErrBadRequest},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: other.ID, Count: 1}, collectorclient.ErrBadRequest},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: wanted.ID, Count: 1}, collectorclient.ErrBadRequest},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 2}, collectorclient.ErrBadRequest},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "shock", SourceCollectionID: binder.ID, Count: 1}, collectorclient.ErrNotFound},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "bolt", SourceCollectionID: binder.ID, Count: 1}, collectorclient.ErrNotFound},
+		{deck.ID, collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: "nope", Count: 1}, collectorclient.ErrBadRequest},
+	} {
+		_, err := s.client.ReserveCard(ctx, tc.deck, &tc.req)
+		s.ErrorIs(err, tc.err, "%+v", tc.req)
+	}
+
+	a, err := s.client.ReserveCard(ctx, deck.ID, &collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 1})
+	s.Require().NoError(err)
+	_, err = s.client.ReserveCard(ctx, deck.ID, &collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 1})
+	s.ErrorIs(err, collectorclient.ErrBadRequest, "the deck has one copy")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1}))
+	_, err = s.client.ReserveCard(ctx, deck.ID, &collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 1})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+	err = s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "sol-ring", Name: "Sol Ring", Count: 1, ReserveFrom: binder.ID})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+	err = s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "shock", Name: "Shock", Count: 1, ReserveFrom: binder.ID})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	err = s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1, ReserveFrom: other.ID})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	s.Equal(map[string]int{"sol-ring": 2}, s.cardCounts(ctx, deck.ID), "a failed reservation adds nothing")
+
+	s.ErrorIs(s.client.ReleaseCard(ctx, deck.ID, "nope"), collectorclient.ErrBadRequest)
+	s.ErrorIs(s.client.ReleaseCard(ctx, other.ID, a.ID), collectorclient.ErrNotFound)
+
+	bob := s.register(7)
+	_, err = s.client.ListAllocations(bob, deck.ID)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.ReserveCard(bob, deck.ID, &collections.ReserveCardRequest{ScryfallID: "sol-ring", SourceCollectionID: binder.ID, Count: 1})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.ReleaseCard(bob, deck.ID, a.ID), collectorclient.ErrNotFound)
+	conflicts, err := s.client.ListAllocationConflicts(bob)
+	s.Require().NoError(err)
+	s.Empty(conflicts)
+
+	s.Require().NoError(s.client.ReleaseCard(ctx, deck.ID, a.ID))
+	s.ErrorIs(s.client.ReleaseCard(ctx, deck.ID, a.ID), collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestLocations() {
+	ctx := s.register(42)
+	box, err := s.client.CreateLocation(ctx, &locations.CreateLocationRequest{Name: " Red box ", Kind: locations.KindBox})
+	s.Require().NoError(err)
+	s.Equal("Red box", box.Name)
+	binder, err := s.client.CreateLocation(ctx, &locations.CreateLocationRequest{Name: "Blue binder", Kind: locations.KindBinder, Description: "Rares"})
+	s.Require().NoError(err)
+	list, err := s.client.ListLocations(ctx)
+	s.Require().NoError(err)
+	s.Equal([]locations.StorageLocation{*binder, *box}, list)
+
+	trades := s.createCollection(ctx, "Trades")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trades.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trades.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, trades.ID, &cards.AddCardRequest{ScryfallID: "shock", Name: "Shock", Count: 1}))
+	wanted, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wanted", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wanted.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}))
+	s.Require().NoError(s.client.UpdateCollection(ctx, wanted.ID, &collections.UpdateCollectionRequest{Location: &locations.Location{LocationID: box.ID}}))
+
+	s.Require().NoError(s.client.UpdateCollection(ctx, trades.ID, &collections.UpdateCollectionRequest{
+		Location: &locations.Location{LocationID: box.ID, Section: "Red"},
+	}))
+	col, err := s.client.GetCollection(ctx, trades.ID)
+	s.Require().NoError(err)
+	s.Equal(&locations.Location{LocationID: box.ID, Section: "Red"}, col.Location)
+
+	resp, err := s.client.RelocateCards(ctx, trades.ID, &locations.RelocateCardsRequest{
+		ScryfallIDs: []string{"ring", "ring"},
+		Location:    &locations.Location{LocationID: binder.ID, Page: 3, Slot: 5},
+	})
+	s.Require().NoError(err)
+	s.Equal(1, resp.Relocated)
+	updated, err := s.client.GetCollection(ctx, trades.ID)
+	s.Require().NoError(err)
+	s.Equal(col.Version+1, updated.Version, "relocation changes the collection")
+
+	contents, err := s.client.ListLocationCards(ctx, box.ID, nil)
+	s.Require().NoError(err)
+	s.Equal(&locations.LocationCards{Location: *box, Count: 3, Cards: []locations.LocatedCard{
+		{CollectionID: trades.ID, CollectionName: "Trades", ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2,
+			Location: locations.Location{LocationID: box.ID, LocationName: "Red box", Section: "Red"}, Inherited: true},
+		{CollectionID: trades.ID, CollectionName: "Trades", ScryfallID: "shock", Name: "Shock", Count: 1,
+			Location: locations.Location{LocationID: box.ID, LocationName: "Red box", Section: "Red"}, Inherited: true},
+	}}, contents, "wishlist cards lie nowhere")
+
+	contents, err = s.client.ListLocationCards(ctx, binder.ID, &locations.LocationCardsQuery{Page: 3})
+	s.Require().NoError(err)
+	s.Equal(1, contents.Count)
+	s.Equal(locations.Location{LocationID: binder.ID, LocationName: "Blue binder", Page: 3, Slot: 5}, contents.Cards[0].Location)
+	s.False(contents.Cards[0].Inherited)
+	contents, err = s.client.ListLocationCards(ctx, binder.ID, &locations.LocationCardsQuery{Page: 4})
+	s.Require().NoError(err)
+	s.Empty(contents.Cards)
+
+	entry, err := s.client.GetCardInCollection(ctx, trades.ID, "bolt", nil)
+	s.Require().NoError(err)
+	s.Equal(&locations.Location{LocationID: box.ID, Section: "Red"}, entry.Location)
+	cardList, err := s.client.ListCardsInCollection(ctx, trades.ID)
+	s.Require().NoError(err)
+	for _, c := range cardList {
+		if c.ScryfallID == "ring" {
+			s.Equal(&locations.Location{LocationID: binder.ID, Page: 3, Slot: 5}, c.Location)
+		} else {
+			s.Nil(c.Location, "cards list only their own locations")
+		}
+	}
+	found, err := s.client.FindOwnedCards(ctx, &cards.OwnedCardsQuery{ScryfallID: "ring"})
+	s.Require().NoError(err)
+	s.Require().Len(found.Collections, 1)
+	s.Equal(&locations.Location{LocationID: binder.ID, LocationName: "Blue binder", Page: 3, Slot: 5}, found.Collections[0].Variants[0].Location)
+
+	s.ErrorIs(s.client.DeleteLocation(ctx, binder.ID), collectorclient.ErrConflict)
+	resp, err = s.client.RelocateCards(ctx, trades.ID, &locations.RelocateCardsRequest{})
+	s.Require().NoError(err)
+	s.Equal(3, resp.Relocated, "without location all cards lie where the collection does")
+	contents, err = s.client.ListLocationCards(ctx, box.ID, &locations.LocationCardsQuery{Section: "red"})
+	s.Require().NoError(err)
+	s.Equal(4, contents.Count)
+	s.Require().NoError(s.client.DeleteLocation(ctx, binder.ID))
+
+	s.Require().NoError(s.client.UpdateCollection(ctx, trades.ID, &collections.UpdateCollectionRequest{Location: &locations.Location{}}))
+	col, err = s.client.GetCollection(ctx, trades.ID)
+	s.Require().NoError(err)
+	s.Nil(col.Location)
+	s.ErrorIs(s.client.DeleteLocation(ctx, box.ID), collectorclient.ErrConflict, "the wishlist still lies there")
+	s.Require().NoError(s.client.UpdateCollection(ctx, wanted.ID, &collections.UpdateCollectionRequest{Location: &locations.Location{}}))
+	s.Require().NoError(s.client.DeleteLocation(ctx, box.ID))
+	list, err = s.client.ListLocations(ctx)
+	s.Require().NoError(err)
+	s.Empty(list)
+}
+
+func (s *ContractTestSuite) TestLocationsInvalid() {
+	ctx := s.register(42)
+	box, err := s.client.CreateLocation(ctx, &locations.CreateLocationRequest{Name: "Red box", Kind: locations.KindBox})
+	s.Require().NoError(err)
+	binder, err := s.client.CreateLocation(ctx, &locations.CreateLocationRequest{Name: "Binder", Kind: locations.KindBinder})
+	s.Require().NoError(err)
+	col := s.createCollection(ctx, "Trades")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2}))
+
+	for _, req := range []locations.CreateLocationRequest{
+		{Kind: locations.KindBox},
+		{Name: "  ", Kind: locations.KindBox},
+		{Name: strings.Repeat("a", 101), Kind: locations.KindBox},
+		{Name: "Shelf", Kind: "shelf"},
+		{Name: "Shelf", Kind: locations.KindBox, Description: strings.Repeat("a", 1001)},
+	} {
+		_, err := s.client.CreateLocation(ctx, &req)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, "%+v", req)
+	}
+	_, err = s.client.CreateLocation(ctx, &locations.CreateLocationRequest{Name: "red BOX", Kind: locations.KindBinder})
+	s.ErrorIs(err, collectorclient.ErrConflict)
+
+	for _, tc := range []struct {
+		location locations.Location
+		err      error
+	}{
+		{locations.Location{LocationID: "nope"}, collectorclient.ErrBadRequest},
+		{locations.Location{LocationID: box.ID, Section: strings.Repeat("a", 51)}, collectorclient.ErrBadRequest},
+		{locations.Location{LocationID: binder.ID, Page: -1}, collectorclient.ErrBadRequest},
+		{locations.Location{LocationID: binder.ID, Slot: 2}, collectorclient.ErrBadRequest},
+		{locations.Location{LocationID: box.ID, Page: 1}, collectorclient.ErrBadRequest},
+		{locations.Location{LocationID: col.ID}, collectorclient.ErrNotFound},
+	} {
+		err := s.client.UpdateCollection(ctx, col.ID, &collections.UpdateCollectionRequest{Location: &tc.location})
+		s.ErrorIs(err, tc.err, "%+v", tc.location)
+		_, err = s.client.RelocateCards(ctx, col.ID, &locations.RelocateCardsRequest{Location: &tc.location})
+		s.ErrorIs(err, tc.err, "%+v", tc.location)
+	}
+	_, err = s.client.RelocateCards(ctx, col.ID, &locations.RelocateCardsRequest{ScryfallIDs: []string{"bolt", "shock"}, Location: &locations.Location{LocationID: box.ID}})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.Equal(map[string]int{"bolt": 2}, s.cardCounts(ctx, col.ID))
+	contents, err := s.client.ListLocationCards(ctx, box.ID, nil)
+	s.Require().NoError(err)
+	s.Empty(contents.Cards, "a failed relocation moves nothing")
+
+	_, err = s.client.ListLocationCards(ctx, "nope", nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.ListLocationCards(ctx, col.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteLocation(ctx, "nope"), collectorclient.ErrBadRequest)
+
+	bob := s.register(7)
+	list, err := s.client.ListLocations(bob)
+	s.Require().NoError(err)
+	s.Empty(list)
+	_, err = s.client.ListLocationCards(bob, box.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteLocation(bob, box.ID), collectorclient.ErrNotFound)
+	bobs := s.createCollection(bob, "Trades")
+	err = s.client.UpdateCollection(bob, bobs.ID, &collections.UpdateCollectionRequest{Location: &locations.Location{LocationID: box.ID}})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.RelocateCards(bob, col.ID, &locations.RelocateCardsRequest{Location: &locations.Location{LocationID: box.ID}})
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.CreateLocation(bob, &locations.CreateLocationRequest{Name: "Red box", Kind: locations.KindBox})
+	s.NoError(err, "location names are unique per user")
+}
+
+func (s *ContractTestSuite) TestCollectionValue() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Binder")
+	for _, req := range []*cards.AddCardRequest{
+		{ScryfallID: "ring", Name: "Sol Ring", Count: 2, Finish: "nonfoil"},
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1, Finish: "foil"},
+		{ScryfallID: "elves", Name: "Llanowar Elves", Count: 1, Finish: "etched"},
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 3},
+	} {
+		s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, req))
+	}
+	ring, err := s.client.GetCardInCollection(ctx, col.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Empty(ring.Finish)
+
+	value, err := s.client.GetCollectionValue(ctx, col.ID, nil)
+	s.Require().NoError(err)
+	s.Equal(collections.CollectionValue{
+		CollectionID: col.ID, Currency: "usd", Cards: []collections.CardValue{},
+		Unpriced: []string{"Sol Ring", "Collector Ouphe", "Llanowar Elves", "Lightning Bolt"}, UnpricedCount: 7,
+	}, *value)
+
+	yesterday := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+	s.setPrices("ring", yesterday, catalog.Prices{USD: 1})
+	s.setPrices("ring", today.Add(15*time.Hour), catalog.Prices{USD: 1.255})
+	s.setPrices("ouphe", yesterday, catalog.Prices{USD: 2, USDFoil: 10})
+	s.setPrices("elves", yesterday, catalog.Prices{USD: 0.25, USDEtched: 4, EURFoil: 3})
+
+	value, err = s.client.GetCollectionValue(ctx, col.ID, nil)
+	s.Require().NoError(err)
+	s.Equal(collections.CollectionValue{
+		CollectionID: col.ID, Currency: "usd", Total: 16.51, PricedCount: 4, UnpricedCount: 3, PricedAt: &today,
+		Cards: []collections.CardValue{
+			{ScryfallID: "ouphe", Name: "Collector Ouphe", Finish: "foil", Count: 1, UnitPrice: 10, Value: 10, PricedAt: yesterday},
+			{ScryfallID: "elves", Name: "Llanowar Elves", Finish: "etched", Count: 1, UnitPrice: 4, Value: 4, PricedAt: yesterday},
+			{ScryfallID: "ring", Name: "Sol Ring", Count: 2, UnitPrice: 1.255, Value: 2.51, PricedAt: today},
+		},
+		Unpriced: []string{"Lightning Bolt"},
+	}, *value)
+
+	value, err = s.client.GetCollectionValue(ctx, col.ID, &collections.CollectionValueQuery{Currency: "EUR", Top: 1})
+	s.Require().NoError(err)
+	s.Equal("eur", value.Currency)
+	s.Equal(3.0, value.Total)
+	s.Equal(1, value.PricedCount)
+	s.Equal(6, value.UnpricedCount)
+	s.Require().Len(value.Cards, 1)
+	s.Equal("elves", value.Cards[0].ScryfallID)
+
+	value, err = s.client.GetCollectionValue(ctx, col.ID, &collections.CollectionValueQuery{Top: 2})
+	s.Require().NoError(err)
+	s.Equal(16.51, value.Total)
+	s.Len(value.Cards, 2)
+
+	// A copy is priced by its finish.
+	nonfoil, foil := "nonfoil", &cards.CardEntryQuery{Finish: "foil"}
+	s.Require().NoError(s.client.UpdateCardInCollection(ctx, col.ID, "ouphe", &cards.UpdateCardRequest{Finish: &nonfoil}, foil))
+	value, err = s.client.GetCollectionValue(ctx, col.ID, &collections.CollectionValueQuery{Top: 1})
+	s.Require().NoError(err)
+	s.Equal(8.51, value.Total)
+	s.Equal("elves", value.Cards[0].ScryfallID)
+
+	// Foil and nonfoil copies of a printing are separate cards.
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 2, Finish: "foil"}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+	ouphe, err := s.client.GetCardInCollection(ctx, col.ID, "ouphe", foil)
+	s.Require().NoError(err)
+	s.Equal(2, ouphe.Count)
+	s.Equal("foil", ouphe.Finish)
+	ouphe, err = s.client.GetCardInCollection(ctx, col.ID, "ouphe", nil)
+	s.Require().NoError(err)
+	s.Equal(2, ouphe.Count)
+	s.Empty(ouphe.Finish)
+	err = s.client.UpdateCardInCollection(ctx, col.ID, "ouphe", &cards.UpdateCardRequest{Finish: &nonfoil}, foil)
+	s.ErrorIs(err, collectorclient.ErrConflict, "the printing already has nonfoil copies")
+
+	value, err = s.client.GetCollectionValue(ctx, col.ID, nil)
+	s.Require().NoError(err)
+	s.Equal(30.51, value.Total)
+	s.Equal(7, value.PricedCount)
+	s.Equal([]collections.CardValue{
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Finish: "foil", Count: 2, UnitPrice: 10, Value: 20, PricedAt: yesterday},
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 2, UnitPrice: 2, Value: 4, PricedAt: yesterday},
+		{ScryfallID: "elves", Name: "Llanowar Elves", Finish: "etched", Count: 1, UnitPrice: 4, Value: 4, PricedAt: yesterday},
+		{ScryfallID: "ring", Name: "Sol Ring", Count: 2, UnitPrice: 1.255, Value: 2.51, PricedAt: today},
+	}, value.Cards, "each finish is priced on its own")
+
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, col.ID, "ouphe", foil))
+	value, err = s.client.GetCollectionValue(ctx, col.ID, nil)
+	s.Require().NoError(err)
+	s.Equal(10.51, value.Total)
+}
+
+func (s *ContractTestSuite) TestCollectionValueInvalid() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Binder")
+
+	_, err := s.client.GetCollectionValue(ctx, col.ID, &collections.CollectionValueQuery{Currency: "gbp"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetCollectionValue(ctx, "not-an-id", nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	other := s.register(43)
+	_, err = s.client.GetCollectionValue(other, col.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+
+	err = s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1, Finish: "shiny"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, col.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1}))
+	shiny := "shiny"
+	err = s.client.UpdateCardInCollection(ctx, col.ID, "ring", &cards.UpdateCardRequest{Finish: &shiny}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+}
+
+func (s *ContractTestSuite) TestValueHistory() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 2}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1, Finish: "foil"}))
+
+	// Points value the cards held at their close, so they are all after the cards were added.
+	today := catalog.Day(time.Now())
+	monday := today.AddDate(0, 0, (8-int(today.Weekday()))%7)
+	s.setPrices("ring", monday, catalog.Prices{USD: 1})
+	s.setPrices("ring", monday.AddDate(0, 0, 2), catalog.Prices{USD: 1.5})
+	s.setPrices("ouphe", monday, catalog.Prices{USD: 2, USDFoil: 10})
+	s.setPrices("ouphe", monday.AddDate(0, 0, 3), catalog.Prices{USD: 2, USDFoil: 8})
+
+	percent := func(p float64) *float64 { return &p }
+	history, err := s.client.GetCollectionValueHistory(ctx, binder.ID, &collections.ValueHistoryQuery{From: monday, To: monday.AddDate(0, 0, 3)})
+	s.Require().NoError(err)
+	s.Equal(collections.ValueHistory{
+		CollectionID: binder.ID, Currency: "usd", Interval: "day", From: monday, To: monday.AddDate(0, 0, 3),
+		Points: []collections.ValuePoint{
+			{Date: monday, Value: 12, PricedCount: 3},
+			{Date: monday.AddDate(0, 0, 1), Value: 12, PricedCount: 3, ChangePercent: percent(0)},
+			{Date: monday.AddDate(0, 0, 2), Value: 13, PricedCount: 3, Change: 1, ChangePercent: percent(8.33)},
+			{Date: monday.AddDate(0, 0, 3), Value: 11, PricedCount: 3, Change: -2, ChangePercent: percent(-15.38)},
+		},
+		Change: -1, ChangePercent: percent(-8.33),
+		Movers: []collections.CardMove{
+			{ScryfallID: "ouphe", Name: "Collector Ouphe", Finish: "foil", Count: 1, StartPrice: 10, EndPrice: 8, Change: -2, ChangePercent: percent(-20)},
+			{ScryfallID: "ring", Name: "Sol Ring", Count: 2, StartPrice: 1, EndPrice: 1.5, Change: 1, ChangePercent: percent(50)},
+		},
+	}, *history)
+
+	// Weeks start on Monday and are valued at their close.
+	history, err = s.client.GetCollectionValueHistory(ctx, binder.ID, &collections.ValueHistoryQuery{Interval: "week", From: monday.AddDate(0, 0, 2), To: monday.AddDate(0, 0, 9), Movers: 1})
+	s.Require().NoError(err)
+	s.Require().Len(history.Points, 2)
+	s.Equal([]any{monday, 11.0}, []any{history.Points[0].Date, history.Points[0].Value})
+	s.Equal([]any{monday.AddDate(0, 0, 7), 11.0}, []any{history.Points[1].Date, history.Points[1].Value})
+	s.Empty(history.Movers)
+
+	// Prices older than 30 days are not used.
+	history, err = s.client.GetCollectionValueHistory(ctx, binder.ID, &collections.ValueHistoryQuery{Interval: "month", From: monday, To: monday.AddDate(0, 2, 0)})
+	s.Require().NoError(err)
+	s.Require().Len(history.Points, 3)
+	s.Equal(time.Date(monday.Year(), monday.Month(), 1, 0, 0, 0, 0, time.UTC), history.Points[0].Date)
+	s.Equal(11.0, history.Points[0].Value)
+	s.Equal(0.0, history.Points[2].Value)
+	s.Equal(0, history.Points[2].PricedCount)
+	s.Equal(percent(-100), history.ChangePercent)
+
+	// The history of a user counts reserved copies once and skips wishlists.
+	deck, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Deck", Kind: collections.KindDeck})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1, ReserveFrom: binder.ID}))
+	s.Require().NoError(s.client.AddCardToCollection(ctx, deck.ID, &cards.AddCardRequest{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1}))
+	wants, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wants", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wants.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 4}))
+
+	history, err = s.client.GetValueHistory(ctx, &collections.ValueHistoryQuery{Currency: "usd", From: monday.AddDate(0, 0, 3), To: monday.AddDate(0, 0, 3), Movers: 5})
+	s.Require().NoError(err)
+	s.Empty(history.CollectionID)
+	s.Equal([]collections.ValuePoint{{Date: monday.AddDate(0, 0, 3), Value: 13, PricedCount: 4}}, history.Points)
+	s.Empty(history.Movers)
+
+	defaults, err := s.client.GetValueHistory(ctx, &collections.ValueHistoryQuery{Interval: "week"})
+	s.Require().NoError(err)
+	s.Len(defaults.Points, 12)
+	s.Equal(defaults.To.AddDate(0, 0, -77), defaults.From)
+}
+
+func (s *ContractTestSuite) TestValueHistoryInvalid() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Binder")
+	day := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	for name, query := range map[string]*collections.ValueHistoryQuery{
+		"unknown currency": {Currency: "gbp"},
+		"unknown interval": {Interval: "year"},
+		"from after to":    {From: day, To: day.AddDate(0, 0, -1)},
+		"too many points":  {From: day.AddDate(-2, 0, 0), To: day},
+		"too many movers":  {Movers: 51},
+	} {
+		_, err := s.client.GetCollectionValueHistory(ctx, col.ID, query)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, name)
+		_, err = s.client.GetValueHistory(ctx, query)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, name)
+	}
+
+	_, err := s.client.GetCollectionValueHistory(ctx, col.ID, &collections.ValueHistoryQuery{Interval: "month", From: day.AddDate(-2, 0, 0), To: day})
+	s.Require().NoError(err)
+	_, err = s.client.GetCollectionValueHistory(ctx, "not-an-id", nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetCollectionValueHistory(s.register(43), col.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestAcquisitions() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 3}))
+
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	bought, err := s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Date: march, Count: 2, Price: 1.5, Currency: "USD", Vendor: "Card Kingdom"}, nil)
+	s.Require().NoError(err)
+	s.NotEmpty(bought.ID)
+	s.Equal(cards.Acquisition{ID: bought.ID, Date: march, Count: 2, Price: 1.5, Currency: "usd", Vendor: "Card Kingdom", Status: cards.AcquisitionReceived}, *bought)
+	ordered, err := s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Date: march.AddDate(0, 0, -1), Count: 1, Price: 2, Currency: "eur", Status: cards.AcquisitionOrdered}, nil)
+	s.Require().NoError(err)
+
+	card, err := s.client.GetCardInCollection(ctx, binder.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Equal([]cards.Acquisition{*ordered, *bought}, card.Acquisitions, "acquisitions are sorted by date")
+	list, err := s.client.ListCardsInCollection(ctx, binder.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Equal(card.Acquisitions, list[0].Acquisitions)
+
+	received := cards.AcquisitionReceived
+	price := 1.75
+	updated, err := s.client.UpdateAcquisition(ctx, binder.ID, "ring", ordered.ID, &cards.UpdateAcquisitionRequest{Status: &received, Price: &price}, nil)
+	s.Require().NoError(err)
+	s.Equal(cards.Acquisition{ID: ordered.ID, Date: ordered.Date, Count: 1, Price: 1.75, Currency: "eur", Status: cards.AcquisitionReceived}, *updated)
+
+	// Writes are conditional on the version of the collection.
+	col, err := s.client.GetCollection(ctx, binder.ID)
+	s.Require().NoError(err)
+	_, err = s.client.AddAcquisition(collectorclient.IfMatch(ctx, col.Version-1), binder.ID, "ring", &cards.AcquisitionRequest{Count: 1, Currency: "usd"}, nil)
+	s.ErrorIs(err, collectorclient.ErrPreconditionFailed)
+	s.Require().NoError(s.client.DeleteAcquisition(collectorclient.IfMatch(ctx, col.Version), binder.ID, "ring", ordered.ID, nil))
+	card, err = s.client.GetCardInCollection(ctx, binder.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Equal([]cards.Acquisition{*bought}, card.Acquisitions)
+
+	// Moved copies take their share of the acquisitions, and all of them go
+	// along when the card leaves.
+	trades := s.createCollection(ctx, "Trades")
+	_, err = s.client.TransferCard(ctx, binder.ID, "ring", &cards.TransferCardRequest{ToCollectionID: trades.ID, Count: 1}, nil)
+	s.Require().NoError(err)
+	moved, err := s.client.GetCardInCollection(ctx, trades.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Require().Len(moved.Acquisitions, 1)
+	s.NotEqual(bought.ID, moved.Acquisitions[0].ID, "the moved part of a split acquisition is a new one")
+	part, rest := *bought, *bought
+	part.ID, part.Count, rest.Count = moved.Acquisitions[0].ID, 1, 1
+	s.Equal([]cards.Acquisition{part}, moved.Acquisitions)
+	card, err = s.client.GetCardInCollection(ctx, binder.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Equal([]cards.Acquisition{rest}, card.Acquisitions)
+	_, err = s.client.TransferCard(ctx, binder.ID, "ring", &cards.TransferCardRequest{ToCollectionID: trades.ID, Count: 2}, nil)
+	s.Require().NoError(err)
+	moved, err = s.client.GetCardInCollection(ctx, trades.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Equal(3, moved.Count)
+	s.Equal([]cards.Acquisition{part, rest}, moved.Acquisitions)
+
+	// A duplicate was not bought; a merge carries acquisitions unless the source is kept.
+	duplicate, err := s.client.DuplicateCollection(ctx, trades.ID, &collections.DuplicateCollectionRequest{Name: "Copy"})
+	s.Require().NoError(err)
+	copied, err := s.client.GetCardInCollection(ctx, duplicate.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Empty(copied.Acquisitions)
+	_, err = s.client.MergeCollections(ctx, binder.ID, &collections.MergeCollectionRequest{SourceCollectionID: trades.ID, KeepSource: true})
+	s.Require().NoError(err)
+	card, err = s.client.GetCardInCollection(ctx, binder.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Empty(card.Acquisitions)
+	_, err = s.client.MergeCollections(ctx, duplicate.ID, &collections.MergeCollectionRequest{SourceCollectionID: trades.ID})
+	s.Require().NoError(err)
+	card, err = s.client.GetCardInCollection(ctx, duplicate.ID, "ring", nil)
+	s.Require().NoError(err)
+	s.Equal(6, card.Count)
+	s.Equal([]cards.Acquisition{part, rest}, card.Acquisitions)
+}
+
+func (s *ContractTestSuite) TestAcquisitionsInvalid() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1}))
+
+	for name, req := range map[string]*cards.AcquisitionRequest{
+		"no count":         {Price: 1, Currency: "usd"},
+		"negative price":   {Count: 1, Price: -1, Currency: "usd"},
+		"no currency":      {Count: 1, Price: 1},
+		"unknown currency": {Count: 1, Price: 1, Currency: "tix"},
+		"unknown status":   {Count: 1, Price: 1, Currency: "usd", Status: "lost"},
+		"long vendor":      {Count: 1, Price: 1, Currency: "usd", Vendor: strings.Repeat("v", 101)},
+	} {
+		_, err := s.client.AddAcquisition(ctx, binder.ID, "ring", req, nil)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, name)
+	}
+
+	_, err := s.client.AddAcquisition(ctx, binder.ID, "bolt", &cards.AcquisitionRequest{Count: 1, Currency: "usd"}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	_, err = s.client.AddAcquisition(s.register(43), binder.ID, "ring", &cards.AcquisitionRequest{Count: 1, Currency: "usd"}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	wants, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wants", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wants.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1}))
+	_, err = s.client.AddAcquisition(ctx, wants.ID, "ring", &cards.AcquisitionRequest{Count: 1, Currency: "usd"}, nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	bought, err := s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Count: 1, Currency: "usd"}, nil)
+	s.Require().NoError(err)
+	s.Equal(cards.AcquisitionReceived, bought.Status)
+	s.False(bought.Date.IsZero())
+	gbp, zero, lost := "gbp", 0, "lost"
+	for name, req := range map[string]*cards.UpdateAcquisitionRequest{
+		"unknown currency": {Currency: &gbp},
+		"no count":         {Count: &zero},
+		"unknown status":   {Status: &lost},
+	} {
+		_, err = s.client.UpdateAcquisition(ctx, binder.ID, "ring", bought.ID, req, nil)
+		s.ErrorIs(err, collectorclient.ErrBadRequest, name)
+	}
+	_, err = s.client.UpdateAcquisition(ctx, binder.ID, "ring", "missing", &cards.UpdateAcquisitionRequest{}, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteAcquisition(ctx, binder.ID, "ring", "missing", nil), collectorclient.ErrNotFound)
+	s.ErrorIs(s.client.DeleteAcquisition(ctx, binder.ID, "bolt", bought.ID, nil), collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestProfitLoss() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+	for _, req := range []*cards.AddCardRequest{
+		{ScryfallID: "ring", Name: "Sol Ring", Count: 3},
+		{ScryfallID: "elves", Name: "Llanowar Elves", Count: 1},
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2},
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1, Finish: "foil"},
+	} {
+		s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, req))
+	}
+	foil := &cards.CardEntryQuery{Finish: "foil"}
+	for scryfallID, req := range map[string]*cards.AcquisitionRequest{
+		"ring":  {Count: 2, Price: 1.5, Currency: "usd"},
+		"elves": {Count: 1, Price: 1, Currency: "usd"},
+	} {
+		_, err := s.client.AddAcquisition(ctx, binder.ID, scryfallID, req, nil)
+		s.Require().NoError(err)
+	}
+	_, err := s.client.AddAcquisition(ctx, binder.ID, "ouphe", &cards.AcquisitionRequest{Count: 1, Price: 5, Currency: "eur"}, foil)
+	s.Require().NoError(err)
+	_, err = s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Count: 1, Price: 2, Currency: "usd", Status: cards.AcquisitionOrdered}, nil)
+	s.Require().NoError(err)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	s.setPrices("ring", today, catalog.Prices{USD: 2})
+	s.setPrices("bolt", today, catalog.Prices{USD: 0.5})
+	s.setPrices("ouphe", today, catalog.Prices{USDFoil: 10, EURFoil: 4})
+
+	percent := func(p float64) *float64 { return &p }
+	report, err := s.client.GetCollectionProfitLoss(ctx, binder.ID, nil)
+	s.Require().NoError(err)
+	s.Equal(collections.ProfitLoss{
+		CollectionID: binder.ID, Currency: "usd", CostBasis: 3, MarketValue: 4, UnrealizedGain: 1, UnrealizedGainPercent: percent(33.33), OrderedCost: 2,
+		Cards: []collections.CardProfitLoss{
+			{CollectionID: binder.ID, ScryfallID: "ring", Name: "Sol Ring", Count: 2, UnitCost: 1.5, CostBasis: 3, MarketValue: 4, Gain: 1, GainPercent: percent(33.33)},
+		},
+		Realized:  []collections.RealizedGain{},
+		Untracked: []string{"Lightning Bolt", "Collector Ouphe"},
+		Unpriced:  []string{"Llanowar Elves"},
+	}, *report)
+
+	// Copies without a known cost leave first; gains are realized on the rest.
+	trader := collectorclient.NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, collectorclient.WithChangeSource(cards.ChangeSourceTrade))
+	s.Require().NoError(trader.SetCardCountInCollection(ctx, binder.ID, "ring", &cards.SetCardCountRequest{Count: 1}, nil))
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, binder.ID, "elves", nil))
+
+	report, err = s.client.GetCollectionProfitLoss(ctx, binder.ID, &collections.ProfitLossQuery{Currency: "USD"})
+	s.Require().NoError(err)
+	s.Equal(1.5, report.CostBasis)
+	s.Equal(2.0, report.MarketValue)
+	s.Equal(0.5, report.RealizedGain)
+	s.Equal(2.0, report.OrderedCost)
+	s.Empty(report.Unpriced)
+	s.Require().Len(report.Realized, 2)
+	elves, ring := report.Realized[0], report.Realized[1]
+	s.Equal([]any{"elves", 1, 1.0, cards.ChangeSourceAPI}, []any{elves.ScryfallID, elves.Count, elves.CostBasis, elves.Source})
+	s.Nil(elves.Proceeds, "elves have no price")
+	s.Nil(elves.Gain)
+	s.Equal([]any{binder.ID, "ring", "Sol Ring", 1, 1.5, cards.ChangeSourceTrade}, []any{ring.CollectionID, ring.ScryfallID, ring.Name, ring.Count, ring.CostBasis, ring.Source})
+	s.Equal(percent(2), ring.Proceeds)
+	s.Equal(percent(0.5), ring.Gain)
+	s.Equal(percent(33.33), ring.GainPercent)
+	s.Equal(today, ring.Date.UTC().Truncate(24*time.Hour))
+
+	// Undone removals and moves between collections realize nothing; an
+	// undone removal brings the cost of the card back.
+	history, err := s.client.ListCardHistory(ctx, binder.ID, &cards.CardHistoryQuery{ScryfallID: "elves"})
+	s.Require().NoError(err)
+	_, err = s.client.UndoCardChange(ctx, binder.ID, history[0].ID, nil)
+	s.Require().NoError(err)
+	card, err := s.client.GetCardInCollection(ctx, binder.ID, "elves", nil)
+	s.Require().NoError(err)
+	s.Require().Len(card.Acquisitions, 1)
+	s.Equal(1.0, card.Acquisitions[0].Price)
+	trades := s.createCollection(ctx, "Trades")
+	_, err = s.client.TransferCard(ctx, binder.ID, "ouphe", &cards.TransferCardRequest{ToCollectionID: trades.ID, Count: 1}, foil)
+	s.Require().NoError(err)
+	wants, err := s.client.CreateCollection(ctx, &collections.CreateCollectionRequest{Name: "Wants", Kind: collections.KindWishlist})
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.AddCardToCollection(ctx, wants.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 4}))
+
+	report, err = s.client.GetProfitLoss(ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(report.CollectionID)
+	s.Equal([]collections.CollectionProfitLoss{
+		{CollectionID: binder.ID, Name: "Binder", CostBasis: 1.5, MarketValue: 2, UnrealizedGain: 0.5, UnrealizedGainPercent: percent(33.33), RealizedGain: 0.5, OrderedCost: 2},
+		{CollectionID: trades.ID, Name: "Trades"},
+	}, report.Collections)
+	s.Equal(0.5, report.RealizedGain)
+	s.Len(report.Realized, 1)
+	s.Equal([]string{"Lightning Bolt", "Collector Ouphe"}, report.Untracked)
+	s.Equal([]string{"Llanowar Elves"}, report.Unpriced)
+
+	report, err = s.client.GetProfitLoss(ctx, &collections.ProfitLossQuery{Currency: "eur"})
+	s.Require().NoError(err)
+	s.Equal("eur", report.Currency)
+	s.Equal([]collections.CardProfitLoss{
+		{CollectionID: trades.ID, ScryfallID: "ouphe", Name: "Collector Ouphe", Finish: "foil", Count: 1, UnitCost: 5, CostBasis: 5, MarketValue: 4, Gain: -1, GainPercent: percent(-20)},
+	}, report.Cards)
+	s.Equal(-1.0, report.UnrealizedGain)
+	s.Empty(report.Realized)
+
+	// A cost is left out whole when part of it can't be converted.
+	mixed := s.createCollection(ctx, "Mixed")
+	s.Require().NoError(s.client.AddCardToCollection(ctx, mixed.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 2}))
+	for _, req := range []*cards.AcquisitionRequest{
+		{Count: 1, Price: 1, Currency: "usd"},
+		{Count: 1, Price: 1, Currency: "eur"},
+	} {
+		_, err = s.client.AddAcquisition(ctx, mixed.ID, "ring", req, nil)
+		s.Require().NoError(err)
+	}
+	report, err = s.client.GetCollectionProfitLoss(ctx, mixed.ID, nil)
+	s.Require().NoError(err)
+	s.Empty(report.Cards)
+	s.Zero(report.CostBasis)
+	s.Equal([]string{"Sol Ring"}, report.Untracked)
+	s.Require().NoError(s.client.DeleteCardFromCollection(ctx, mixed.ID, "ring", nil))
+	report, err = s.client.GetCollectionProfitLoss(ctx, mixed.ID, nil)
+	s.Require().NoError(err)
+	s.Empty(report.Realized)
+}
+
+func (s *ContractTestSuite) TestProfitLossInvalid() {
+	ctx := s.register(42)
+	col := s.createCollection(ctx, "Binder")
+
+	_, err := s.client.GetCollectionProfitLoss(ctx, col.ID, &collections.ProfitLossQuery{Currency: "tix"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetProfitLoss(ctx, &collections.ProfitLossQuery{Currency: "gbp"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetCollectionProfitLoss(ctx, "not-an-id", nil)
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetCollectionProfitLoss(s.register(43), col.ID, nil)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+}
+
+func (s *ContractTestSuite) TestUserSettings() {
+	ctx := s.register(42)
+
+	settings, err := s.client.GetUserSettings(ctx)
+	s.Require().NoError(err)
+	s.Equal(auth.UserSettings{Currency: "usd"}, *settings)
+
+	currency := "RUB"
+	settings, err = s.client.UpdateUserSettings(ctx, &auth.UpdateSettingsRequest{Currency: &currency})
+	s.Require().NoError(err)
+	s.Equal(auth.UserSettings{Currency: "rub"}, *settings)
+	settings, err = s.client.GetUserSettings(ctx)
+	s.Require().NoError(err)
+	s.Equal("rub", settings.Currency)
+
+	// Settings are per user.
+	settings, err = s.client.GetUserSettings(s.register(7))
+	s.Require().NoError(err)
+	s.Equal("usd", settings.Currency)
+
+	tix := "tix"
+	for _, req := range []*auth.UpdateSettingsRequest{{}, {Currency: &tix}} {
+		_, err = s.client.UpdateUserSettings(ctx, req)
+		s.ErrorIs(err, collectorclient.ErrBadRequest)
+	}
+
+	// An empty currency goes back to the default.
+	currency = ""
+	settings, err = s.client.UpdateUserSettings(ctx, &auth.UpdateSettingsRequest{Currency: &currency})
+	s.Require().NoError(err)
+	s.Equal("usd", settings.Currency)
+}
+
+func (s *ContractTestSuite) TestCurrencyConversion() {
+	ctx := s.register(42)
+	_, err := s.client.GetExchangeRates(ctx)
+	s.ErrorIs(err, collectorclient.ErrNotFound)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	s.setRates("usd", yesterday.AddDate(0, 0, -1), map[string]float64{"eur": 1, "rub": 1})
+	s.setRates("usd", yesterday, map[string]float64{"eur": 0.5, "rub": 90})
+	rates, err := s.client.GetExchangeRates(ctx)
+	s.Require().NoError(err)
+	s.Equal("usd", rates.Base)
+	s.Equal(yesterday, rates.Date.UTC())
+	s.Equal(map[string]float64{"usd": 1, "eur": 0.5, "rub": 90}, rates.Rates)
+
+	binder := s.createCollection(ctx, "Binder")
+	for _, req := range []*cards.AddCardRequest{
+		{ScryfallID: "ring", Name: "Sol Ring", Count: 2},
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1, Finish: "foil"},
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1},
+	} {
+		s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, req))
+	}
+	s.setPrices("ring", today, catalog.Prices{USD: 2})
+	s.setPrices("ouphe", today, catalog.Prices{USDFoil: 10, EURFoil: 4})
+	s.setPrices("bolt", today, catalog.Prices{EUR: 1})
+
+	// Rubles are converted from dollar prices; market currencies are not converted.
+	value, err := s.client.GetCollectionValue(ctx, binder.ID, &collections.CollectionValueQuery{Currency: "rub"})
+	s.Require().NoError(err)
+	s.Equal("rub", value.Currency)
+	s.Equal(1260.0, value.Total)
+	s.Equal([]string{"Lightning Bolt"}, value.Unpriced)
+	s.Require().Len(value.Cards, 2)
+	s.Equal([]any{"ouphe", 900.0}, []any{value.Cards[0].ScryfallID, value.Cards[0].UnitPrice})
+	s.Equal([]any{"ring", 180.0, 360.0}, []any{value.Cards[1].ScryfallID, value.Cards[1].UnitPrice, value.Cards[1].Value})
+	s.Require().NotNil(value.RatesDate)
+	s.Equal(yesterday, value.RatesDate.UTC())
+	value, err = s.client.GetCollectionValue(ctx, binder.ID, &collections.CollectionValueQuery{Currency: "eur"})
+	s.Require().NoError(err)
+	s.Equal(5.0, value.Total)
+	s.Nil(value.RatesDate)
+
+	// The preferred currency is the default of every report.
+	currency := "rub"
+	_, err = s.client.UpdateUserSettings(ctx, &auth.UpdateSettingsRequest{Currency: &currency})
+	s.Require().NoError(err)
+	value, err = s.client.GetCollectionValue(ctx, binder.ID, nil)
+	s.Require().NoError(err)
+	s.Equal("rub", value.Currency)
+	s.Equal(1260.0, value.Total)
+
+	history, err := s.client.GetValueHistory(ctx, nil)
+	s.Require().NoError(err)
+	s.Equal("rub", history.Currency)
+	s.Equal(1260.0, history.Points[len(history.Points)-1].Value)
+	s.Require().NotNil(history.RatesDate)
+	s.Equal(yesterday, history.RatesDate.UTC())
+
+	// Costs in every purchase currency are converted into the one of the report.
+	_, err = s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Count: 2, Price: 1.5, Currency: "usd"}, nil)
+	s.Require().NoError(err)
+	_, err = s.client.AddAcquisition(ctx, binder.ID, "ouphe", &cards.AcquisitionRequest{Count: 1, Price: 5, Currency: "eur"}, &cards.CardEntryQuery{Finish: "foil"})
+	s.Require().NoError(err)
+	_, err = s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Count: 1, Price: 900, Currency: "rub", Status: cards.AcquisitionOrdered}, nil)
+	s.Require().NoError(err)
+	report, err := s.client.GetCollectionProfitLoss(ctx, binder.ID, nil)
+	s.Require().NoError(err)
+	s.Equal("rub", report.Currency)
+	s.Equal([]float64{1170, 1260, 90, 900}, []float64{report.CostBasis, report.MarketValue, report.UnrealizedGain, report.OrderedCost})
+	s.Equal([]string{"Lightning Bolt"}, report.Untracked)
+	s.Require().NotNil(report.RatesDate)
+	s.Equal(yesterday, report.RatesDate.UTC())
+	report, err = s.client.GetProfitLoss(ctx, &collections.ProfitLossQuery{Currency: "usd"})
+	s.Require().NoError(err)
+	s.Equal([]float64{13, 14, 10}, []float64{report.CostBasis, report.MarketValue, report.OrderedCost})
+
+	// Max prices are kept in their own currency and listed in the preferred one.
+	bolt, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Quantity: 4, MaxPrice: 90})
+	s.Require().NoError(err)
+	s.Equal([]any{90.0, "rub"}, []any{bolt.MaxPrice, bolt.Currency})
+	s.Nil(bolt.RatesDate)
+	elves, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "elves", Name: "Llanowar Elves", Quantity: 1, MaxPrice: 2, Currency: "EUR"})
+	s.Require().NoError(err)
+	s.Equal([]any{2.0, "eur"}, []any{elves.MaxPrice, elves.Currency})
+	lands, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "lands", Name: "Forest", Quantity: 1, Currency: "usd"})
+	s.Require().NoError(err)
+	s.Empty(lands.Currency, "no max price, no currency")
+
+	list, err := s.client.ListWishlist(ctx)
+	s.Require().NoError(err)
+	maxPrices := make(map[string][]any)
+	for _, e := range list {
+		maxPrices[e.ID] = []any{e.MaxPrice, e.Currency, e.RatesDate != nil}
+	}
+	s.Equal(map[string][]any{
+		bolt.ID:  {90.0, "rub", false},
+		elves.ID: {360.0, "rub", true},
+		lands.ID: {0.0, "", false},
+	}, maxPrices)
+	missing, err := s.client.ListMissingCards(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(missing, 3)
+
+	usd := "usd"
+	updated, err := s.client.UpdateWishlistEntry(ctx, bolt.ID, &wishlist.UpdateEntryRequest{Currency: &usd})
+	s.Require().NoError(err)
+	s.Equal([]any{90.0, "usd"}, []any{updated.MaxPrice, updated.Currency})
+	list, err = s.client.ListWishlist(ctx)
+	s.Require().NoError(err)
+	i := slices.IndexFunc(list, func(e wishlist.Entry) bool { return e.ID == bolt.ID })
+	s.Equal(8100.0, list[i].MaxPrice)
+}
+
+func (s *ContractTestSuite) TestCurrencyConversionInvalid() {
+	ctx := s.register(42)
+	binder := s.createCollection(ctx, "Binder")
+
+	_, err := s.client.GetCollectionValue(ctx, binder.ID, &collections.CollectionValueQuery{Currency: "gbp"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.GetProfitLoss(ctx, &collections.ProfitLossQuery{Currency: "tix"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+	_, err = s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "bolt", Quantity: 1, MaxPrice: 1, Currency: "tix"})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	entry, err := s.client.AddWishlistEntry(ctx, &wishlist.AddEntryRequest{ScryfallID: "bolt", Quantity: 1})
+	s.Require().NoError(err)
+	gbp := "gbp"
+	_, err = s.client.UpdateWishlistEntry(ctx, entry.ID, &wishlist.UpdateEntryRequest{Currency: &gbp})
+	s.ErrorIs(err, collectorclient.ErrBadRequest)
+
+	// Without exchange rates nothing is converted: rubles have no prices and
+	// costs in other currencies are left out.
+	s.Require().NoError(s.client.AddCardToCollection(ctx, binder.ID, &cards.AddCardRequest{ScryfallID: "ring", Name: "Sol Ring", Count: 1}))
+	s.setPrices("ring", time.Now(), catalog.Prices{USD: 2})
+	_, err = s.client.AddAcquisition(ctx, binder.ID, "ring", &cards.AcquisitionRequest{Count: 1, Price: 1, Currency: "eur"}, nil)
+	s.Require().NoError(err)
+	value, err := s.client.GetCollectionValue(ctx, binder.ID, &collections.CollectionValueQuery{Currency: "rub"})
+	s.Require().NoError(err)
+	s.Equal([]string{"Sol Ring"}, value.Unpriced)
+	s.Nil(value.RatesDate)
+	report, err := s.client.GetCollectionProfitLoss(ctx, binder.ID, nil)
+	s.Require().NoError(err)
+	s.Equal([]string{"Sol Ring"}, report.Untracked)
+	s.Nil(report.RatesDate)
+}
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+)
+
+func InitCatalog(cfg *config.Config) *catalog.Catalog {
+	cards, err := catalog.LoadScryfallBulk(cfg.Catalog.ScryfallBulkPath)
+	if err != nil {
+		panic(err)
+	}
+	banlists, err := catalog.LoadBanlists(cfg.Catalog.BanlistsPath)
+	if err != nil {
+		panic(err)
+	}
+	cards.SetBanlists(banlists)
+
+	return cards
+}
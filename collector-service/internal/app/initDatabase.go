@@ -3,14 +3,13 @@ package app
 import (
 	"context"
 
-	"github.com/spf13/viper"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-func InitDataBase(config *viper.Viper) *mongo.Client {
-	connString := config.GetString("database.conn_string")
-	client, err := mongo.Connect(options.Client().ApplyURI(connString))
+func InitDataBase(cfg *config.Config) *mongo.Client {
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.Database.ConnString))
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type pricesService interface {
+	ImportPrices(quotes []*catalog.PriceQuote) (int, *models.ResponseErr)
+	ImportExchangeRates(rates *catalog.ExchangeRates) *models.ResponseErr
+}
+
+// ratesTimeout bounds the download of the exchange rates.
+const ratesTimeout = time.Minute
+
+// priceImporter stores the prices of the local Scryfall and MTGJSON files as daily
+// snapshots, along with the exchange rates of the day.
+type priceImporter struct {
+	prices       pricesService
+	scryfallPath string
+	cfg          config.PricesConfig
+	client       *http.Client
+	log          logger.Logger
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+func newPriceImporter(prices pricesService, scryfallPath string, cfg config.PricesConfig, log logger.Logger) *priceImporter {
+	return &priceImporter{
+		prices:       prices,
+		scryfallPath: scryfallPath,
+		cfg:          cfg,
+		client:       &http.Client{Timeout: ratesTimeout},
+		log:          log.With(logger.String("worker", "prices")),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Run imports the prices right away and then every ImportInterval until Stop is called.
+// The files are read anew every time, so they can be refreshed while the service runs.
+func (p *priceImporter) Run() {
+	defer close(p.done)
+
+	if p.scryfallPath == "" && p.cfg.MTGJSONPricesPath == "" {
+		p.log.Warn("No price files configured, collections can't be valued")
+		if p.cfg.ExchangeRatesPath == "" && p.cfg.ExchangeRatesURL == "" {
+			return
+		}
+	}
+	if p.cfg.ImportInterval <= 0 {
+		p.log.Warn("Price import is disabled")
+		return
+	}
+
+	p.log.Info("Price import started", logger.Duration("interval", p.cfg.ImportInterval))
+	ticker := time.NewTicker(p.cfg.ImportInterval)
+	defer ticker.Stop()
+
+	for {
+		p.importPrices()
+		p.importRates()
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops Run and waits for the import in progress to finish.
+func (p *priceImporter) Stop() {
+	close(p.stop)
+	<-p.done
+}
+
+func (p *priceImporter) importPrices() {
+	for _, source := range []struct {
+		name string
+		load func() ([]*catalog.PriceQuote, error)
+	}{
+		{"scryfall", func() ([]*catalog.PriceQuote, error) { return catalog.LoadScryfallPrices(p.scryfallPath) }},
+		{"mtgjson", func() ([]*catalog.PriceQuote, error) {
+			return catalog.LoadMTGJSONPrices(p.cfg.MTGJSONPricesPath, p.cfg.MTGJSONIdentifiersPath)
+		}},
+	} {
+		quotes, err := source.load()
+		if err != nil {
+			p.log.Error("Failed to read prices", logger.String("source", source.name), logger.Error(err))
+			continue
+		}
+		if _, respErr := p.prices.ImportPrices(quotes); respErr != nil {
+			p.log.Error("Failed to import prices", logger.String("source", source.name), logger.Error(respErr))
+		}
+	}
+}
+
+func (p *priceImporter) importRates() {
+	var (
+		rates *catalog.ExchangeRates
+		err   error
+	)
+	if p.cfg.ExchangeRatesPath != "" {
+		rates, err = catalog.LoadExchangeRates(p.cfg.ExchangeRatesPath)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), ratesTimeout)
+		rates, err = catalog.FetchExchangeRates(ctx, p.client, p.cfg.ExchangeRatesURL)
+		cancel()
+	}
+	if err != nil {
+		p.log.Error("Failed to read exchange rates", logger.Error(err))
+		return
+	}
+	if rates == nil {
+		return
+	}
+	if respErr := p.prices.ImportExchangeRates(rates); respErr != nil {
+		p.log.Error("Failed to import exchange rates", logger.Error(respErr))
+	}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/controllers"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/middleware"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/services"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// Repository is the storage the HTTP API is built on.
+// It is implemented by repositories.Repository and repositories.MemoryRepository.
+type Repository interface {
+	services.AuthRepositorer
+	services.CollectionsRepositorer
+	services.CardsRepositorer
+	services.SnapshotsRepositorer
+	services.WishlistRepositorer
+	services.DecksRepositorer
+	services.AllocationsRepositorer
+	services.LocationsRepositorer
+	services.PricesRepositorer
+	services.UsersRepositorer
+}
+
+// NewRouter wires services and controllers on top of rep and registers all routes.
+func NewRouter(cfg *config.Config, log logger.Logger, rep Repository, cards *catalog.Catalog) *gin.Engine {
+	// Init services
+	servAuth := services.NewAuthService(rep, cfg.JWT.Secret, cfg.JWT.TokenTTL, log)
+	servCollections := services.NewCollectionsService(rep, cards, log)
+	servCards := services.NewCardsService(rep, cards, log)
+	servSnapshots := services.NewSnapshotsService(rep, log)
+	servWishlist := services.NewWishlistService(rep, cards, log)
+	servDecks := services.NewDecksService(rep, cards, log)
+	servAllocations := services.NewAllocationsService(rep, log)
+	servLocations := services.NewLocationsService(rep, log)
+	servPrices := services.NewPricesService(rep, cards, log)
+	servUsers := services.NewUsersService(rep, log)
+
+	// Init controllers
+	ctrlAuth := controllers.NewAuthController(servAuth, log)
+	ctrlCollections := controllers.NewCollectionsController(servCollections, log)
+	ctrlCards := controllers.NewCardsController(servCards, log)
+	ctrlSnapshots := controllers.NewSnapshotsController(servSnapshots, log)
+	ctrlWishlist := controllers.NewWishlistController(servWishlist, log)
+	ctrlDecks := controllers.NewDecksController(servDecks, log)
+	ctrlAllocations := controllers.NewAllocationsController(servAllocations, log)
+	ctrlLocations := controllers.NewLocationsController(servLocations, log)
+	ctrlPrices := controllers.NewPricesController(servPrices, log)
+	ctrlUsers := controllers.NewUsersController(servUsers, log)
+
+	router := gin.Default()
+	router.Use(gin.Recovery())
+
+	// Setup Swagger
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Middleware
+	mid := middleware.NewJWTMiddleware(cfg.JWT.Secret, log)
+	authMiddleware := mid.Authorization()
+
+	// Public routes
+	public := router.Group("/")
+	{
+		public.POST("/register", ctrlAuth.Register)
+		public.GET("/user/telegram/:telegram_id", ctrlAuth.Who)
+		public.POST("/login", ctrlAuth.Login)
+	}
+
+	// Protected routes
+	authorized := router.Group("/", authMiddleware)
+	{
+		authorized.GET("/collections", ctrlCollections.GetCollections)
+		authorized.POST("/collections", ctrlCollections.CreateCollection)
+		authorized.GET("/collections/:id", ctrlCollections.GetCollection)
+		authorized.PATCH("/collections/:id", ctrlCollections.UpdateCollection)
+		authorized.DELETE("/collections/:id", ctrlCollections.DeleteCollection)
+		authorized.GET("/collections/name/:name", ctrlCollections.GetCollectionByName)
+		authorized.GET("/collections/trash", ctrlCollections.ListTrash)
+		authorized.POST("/collections/:id/restore", ctrlCollections.RestoreCollection)
+		authorized.POST("/collections/:id/merge", ctrlCollections.MergeCollections)
+		authorized.POST("/collections/:id/duplicate", ctrlCollections.DuplicateCollection)
+		authorized.POST("/collections/:id/split", ctrlCollections.SplitCollection)
+
+		authorized.GET("/collections/:id/cards", ctrlCards.ListCardsInCollection)
+		authorized.POST("/collections/:id/cards", ctrlCards.AddCardToCollection)
+		authorized.POST("/collections/:id/cards:batch", ctrlCards.BatchCards)
+		authorized.GET("/collections/:id/cards/:card_id", ctrlCards.GetCardInCollection)
+		authorized.PATCH("/collections/:id/cards/:card_id", ctrlCards.UpdateCardInCollection)
+		authorized.DELETE("/collections/:id/cards/:card_id", ctrlCards.DeleteCardFromCollection)
+		authorized.POST("/collections/:id/cards/:card_id/transfer", ctrlCards.TransferCard)
+		authorized.POST("/collections/:id/cards/:card_id/acquisitions", ctrlCards.AddAcquisition)
+		authorized.PATCH("/collections/:id/cards/:card_id/acquisitions/:acquisition_id", ctrlCards.UpdateAcquisition)
+		authorized.DELETE("/collections/:id/cards/:card_id/acquisitions/:acquisition_id", ctrlCards.DeleteAcquisition)
+		authorized.GET("/collections/:id/history", ctrlCards.ListCardHistory)
+		authorized.POST("/collections/:id/history/:entry_id/undo", ctrlCards.UndoCardChange)
+
+		authorized.GET("/collections/:id/snapshots", ctrlSnapshots.ListSnapshots)
+		authorized.POST("/collections/:id/snapshots", ctrlSnapshots.CreateSnapshot)
+		authorized.GET("/collections/:id/snapshots/:snapshot_id", ctrlSnapshots.GetSnapshot)
+		authorized.DELETE("/collections/:id/snapshots/:snapshot_id", ctrlSnapshots.DeleteSnapshot)
+		authorized.GET("/collections/:id/snapshots/:snapshot_id/diff", ctrlSnapshots.DiffSnapshot)
+		authorized.GET("/collections/:id/validation", ctrlDecks.ValidateDeck)
+		authorized.GET("/collections/:id/allocations", ctrlAllocations.ListAllocations)
+		authorized.POST("/collections/:id/allocations", ctrlAllocations.ReserveCard)
+		authorized.DELETE("/collections/:id/allocations/:allocation_id", ctrlAllocations.ReleaseCard)
+		authorized.POST("/collections/:id/relocate", ctrlLocations.RelocateCards)
+		authorized.GET("/collections/:id/value", ctrlPrices.CollectionValue)
+		authorized.GET("/collections/:id/value/history", ctrlPrices.CollectionValueHistory)
+		authorized.GET("/collections/:id/profit-loss", ctrlPrices.CollectionProfitLoss)
+
+		authorized.GET("/cards/owned", ctrlCards.FindOwnedCards)
+		authorized.POST("/decks/buildability", ctrlDecks.CheckBuildability)
+		authorized.GET("/value/history", ctrlPrices.ValueHistory)
+		authorized.GET("/profit-loss", ctrlPrices.ProfitLoss)
+		authorized.GET("/exchange-rates", ctrlPrices.ExchangeRates)
+		authorized.GET("/allocations/conflicts", ctrlAllocations.ListConflicts)
+
+		authorized.GET("/wishlist", ctrlWishlist.ListWishlist)
+		authorized.POST("/wishlist", ctrlWishlist.AddWishlistEntry)
+		authorized.GET("/wishlist/missing", ctrlWishlist.ListMissingCards)
+		authorized.PATCH("/wishlist/:entry_id", ctrlWishlist.UpdateWishlistEntry)
+		authorized.DELETE("/wishlist/:entry_id", ctrlWishlist.DeleteWishlistEntry)
+
+		authorized.GET("/user/settings", ctrlUsers.GetSettings)
+		authorized.PATCH("/user/settings", ctrlUsers.UpdateSettings)
+
+		authorized.GET("/locations", ctrlLocations.ListLocations)
+		authorized.POST("/locations", ctrlLocations.CreateLocation)
+		authorized.DELETE("/locations/:location_id", ctrlLocations.DeleteLocation)
+		authorized.GET("/locations/:location_id/cards", ctrlLocations.ListLocationCards)
+	}
+
+	return router
+}
@@ -0,0 +1,397 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/repositories"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/services"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RouterTestSuite sends raw HTTP requests through NewRouter, so it checks the
+// route table, the JWT middleware and the JSON bodies the controllers produce.
+//
+// go test github.com/ShenokZlob/collector-ouphe/collector-service/internal/app -run Router
+type RouterTestSuite struct {
+	suite.Suite
+	cfg    *config.Config
+	repo   *repositories.MemoryRepository
+	router *gin.Engine
+	userID string
+	token  string
+	colID  string
+}
+
+func TestRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	suite.Run(t, new(RouterTestSuite))
+}
+
+func (s *RouterTestSuite) SetupTest() {
+	s.cfg = &config.Config{
+		JWT: config.JWTConfig{Secret: "router-secret", TokenTTL: time.Hour},
+	}
+	s.repo = repositories.NewMemoryRepository()
+	cards := catalog.New(
+		&catalog.Card{ScryfallID: "bolt-m10", OracleID: "bolt", Name: "Lightning Bolt", Set: "m10", SetName: "Magic 2010", CollectorNumber: "146", Rarity: "common", Lang: "en"},
+		&catalog.Card{ScryfallID: "bolt-lea", OracleID: "bolt", Name: "Lightning Bolt", Set: "lea", SetName: "Limited Edition Alpha", CollectorNumber: "161", Rarity: "common", Lang: "en"},
+		&catalog.Card{ScryfallID: "ouphe", OracleID: "ouphe", Name: "Collector Ouphe", Set: "mh1", CollectorNumber: "158", Rarity: "rare", Lang: "en"},
+	)
+	s.router = NewRouter(s.cfg, logger.SilentLogger{}, s.repo, cards)
+
+	s.userID, s.token = s.newUser(42)
+	s.colID = s.newCollection(s.userID, "Bulk")
+	owner, err := bson.ObjectIDFromHex(s.userID)
+	s.Require().NoError(err)
+	seed := &models.CardChange{UserID: owner, Source: models.ChangeSourceImport}
+	for _, card := range []*models.Card{
+		{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 2},
+		{ScryfallID: "bolt-lea", Name: "Lightning Bolt", Count: 1},
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1},
+	} {
+		s.Require().Nil(s.repo.AddCardToCollection(seed, s.colID, card))
+	}
+}
+
+func (s *RouterTestSuite) newUser(telegramID int64) (string, string) {
+	user, respErr := s.repo.CreateUser(&models.User{TelegramID: telegramID, FirstName: "Ivan"})
+	s.Require().Nil(respErr)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(s.cfg.JWT.Secret))
+	s.Require().NoError(err)
+	return user.ID, token
+}
+
+func (s *RouterTestSuite) newCollection(userID, name string) string {
+	owner, err := bson.ObjectIDFromHex(userID)
+	s.Require().NoError(err)
+	col, respErr := s.repo.CreateCollection(&models.Collection{UserID: owner, Name: name})
+	s.Require().Nil(respErr)
+	return col.ID
+}
+
+func (s *RouterTestSuite) do(method, path, token string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		s.Require().NoError(json.NewEncoder(&payload).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *RouterTestSuite) getEntry(scryfallID string) cards.CardEntry {
+	w := s.do(http.MethodGet, "/collections/"+s.colID+"/cards/"+scryfallID, s.token, nil)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var entry cards.CardEntry
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &entry))
+	return entry
+}
+
+func (s *RouterTestSuite) TestRoutes() {
+	want := map[string]bool{
+		"POST /register":                                                      true,
+		"GET /user/telegram/:telegram_id":                                     true,
+		"POST /login":                                                         true,
+		"GET /collections":                                                    true,
+		"GET /collections/:id":                                                true,
+		"POST /collections":                                                   true,
+		"PATCH /collections/:id":                                              true,
+		"DELETE /collections/:id":                                             true,
+		"POST /collections/:id/merge":                                         true,
+		"POST /collections/:id/duplicate":                                     true,
+		"POST /collections/:id/split":                                         true,
+		"GET /collections/name/:name":                                         true,
+		"GET /collections/trash":                                              true,
+		"POST /collections/:id/restore":                                       true,
+		"GET /collections/:id/cards":                                          true,
+		"POST /collections/:id/cards":                                         true,
+		"POST /collections/:id/cards:batch":                                   true,
+		"GET /collections/:id/cards/:card_id":                                 true,
+		"PATCH /collections/:id/cards/:card_id":                               true,
+		"DELETE /collections/:id/cards/:card_id":                              true,
+		"POST /collections/:id/cards/:card_id/transfer":                       true,
+		"POST /collections/:id/cards/:card_id/acquisitions":                   true,
+		"PATCH /collections/:id/cards/:card_id/acquisitions/:acquisition_id":  true,
+		"DELETE /collections/:id/cards/:card_id/acquisitions/:acquisition_id": true,
+		"GET /collections/:id/history":                                        true,
+		"POST /collections/:id/history/:entry_id/undo":                        true,
+		"GET /collections/:id/snapshots":                                      true,
+		"POST /collections/:id/snapshots":                                     true,
+		"GET /collections/:id/snapshots/:snapshot_id":                         true,
+		"DELETE /collections/:id/snapshots/:snapshot_id":                      true,
+		"GET /collections/:id/snapshots/:snapshot_id/diff":                    true,
+		"GET /collections/:id/validation":                                     true,
+		"GET /collections/:id/allocations":                                    true,
+		"POST /collections/:id/allocations":                                   true,
+		"DELETE /collections/:id/allocations/:allocation_id":                  true,
+		"POST /collections/:id/relocate":                                      true,
+		"GET /collections/:id/value":                                          true,
+		"GET /collections/:id/value/history":                                  true,
+		"GET /collections/:id/profit-loss":                                    true,
+		"GET /allocations/conflicts":                                          true,
+		"GET /cards/owned":                                                    true,
+		"POST /decks/buildability":                                            true,
+		"GET /value/history":                                                  true,
+		"GET /profit-loss":                                                    true,
+		"GET /exchange-rates":                                                 true,
+		"GET /wishlist":                                                       true,
+		"POST /wishlist":                                                      true,
+		"GET /wishlist/missing":                                               true,
+		"PATCH /wishlist/:entry_id":                                           true,
+		"DELETE /wishlist/:entry_id":                                          true,
+		"GET /user/settings":                                                  true,
+		"PATCH /user/settings":                                                true,
+		"GET /locations":                                                      true,
+		"POST /locations":                                                     true,
+		"DELETE /locations/:location_id":                                      true,
+		"GET /locations/:location_id/cards":                                   true,
+		"GET /swagger/*any":                                                   true,
+	}
+
+	got := make(map[string]bool)
+	for _, r := range s.router.Routes() {
+		got[r.Method+" "+r.Path] = true
+	}
+	s.Equal(want, got)
+}
+
+func (s *RouterTestSuite) TestCardRoutesRequireAuth() {
+	for _, method := range []string{http.MethodGet, http.MethodPatch, http.MethodDelete} {
+		w := s.do(method, "/collections/"+s.colID+"/cards/bolt-m10", "", nil)
+		s.Equal(http.StatusUnauthorized, w.Code, method)
+	}
+}
+
+func (s *RouterTestSuite) TestGetCard() {
+	entry := s.getEntry("bolt-m10")
+	s.Equal("bolt-m10", entry.ScryfallID)
+	s.Equal("Lightning Bolt", entry.Name)
+	s.Equal(2, entry.Count)
+
+	s.Require().NotNil(entry.Catalog)
+	s.Equal("bolt", entry.Catalog.OracleID)
+	s.Equal("m10", entry.Catalog.Set)
+	s.Equal("146", entry.Catalog.CollectorNumber)
+
+	s.Require().Len(entry.Variants, 1)
+	s.Equal(cards.CardVariant{
+		ScryfallID:      "bolt-lea",
+		Name:            "Lightning Bolt",
+		Set:             "lea",
+		CollectorNumber: "161",
+		Count:           1,
+	}, entry.Variants[0])
+}
+
+func (s *RouterTestSuite) TestGetCardWithoutVariants() {
+	w := s.do(http.MethodGet, "/collections/"+s.colID+"/cards/ouphe", s.token, nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"variants":[]`)
+}
+
+func (s *RouterTestSuite) TestGetCardNotFound() {
+	w := s.do(http.MethodGet, "/collections/"+s.colID+"/cards/missing", s.token, nil)
+	s.Equal(http.StatusNotFound, w.Code)
+	s.Contains(w.Body.String(), "Card not found")
+
+	w = s.do(http.MethodGet, "/collections/not-an-id/cards/bolt-m10", s.token, nil)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *RouterTestSuite) TestCardsOfOtherUsers() {
+	_, bobToken := s.newUser(7)
+	path := "/collections/" + s.colID + "/cards/bolt-m10"
+
+	s.Equal(http.StatusNotFound, s.do(http.MethodGet, path, bobToken, nil).Code)
+	s.Equal(http.StatusNotFound, s.do(http.MethodPatch, path, bobToken, map[string]any{"count": 0}).Code)
+	s.Equal(http.StatusNotFound, s.do(http.MethodDelete, path, bobToken, nil).Code)
+	s.Equal(http.StatusNotFound, s.do(http.MethodGet, "/collections/"+s.colID+"/cards", bobToken, nil).Code)
+
+	s.Equal(2, s.getEntry("bolt-m10").Count)
+}
+
+func (s *RouterTestSuite) TestPatchCard() {
+	path := "/collections/" + s.colID + "/cards/bolt-m10"
+
+	w := s.do(http.MethodPatch, path, s.token, map[string]any{"notes": "Binder page 4", "condition": "LP"})
+	s.Require().Equal(http.StatusNoContent, w.Code, w.Body.String())
+
+	entry := s.getEntry("bolt-m10")
+	s.Equal(2, entry.Count, "count is kept when not sent")
+	s.Equal("Binder page 4", entry.Notes)
+	s.Equal("LP", entry.Condition)
+	s.False(entry.UpdatedAt.IsZero())
+
+	w = s.do(http.MethodPatch, path, s.token, map[string]any{"count": 4, "condition": ""})
+	s.Require().Equal(http.StatusNoContent, w.Code, w.Body.String())
+
+	entry = s.getEntry("bolt-m10")
+	s.Equal(4, entry.Count)
+	s.Equal("Binder page 4", entry.Notes)
+	s.Empty(entry.Condition)
+}
+
+func (s *RouterTestSuite) TestPatchCardInvalid() {
+	path := "/collections/" + s.colID + "/cards/bolt-m10"
+
+	tests := []struct {
+		name string
+		body any
+	}{
+		{"empty", map[string]any{}},
+		{"negative count", map[string]any{"count": -1}},
+		{"unknown condition", map[string]any{"condition": "mint"}},
+		{"not json", "count"},
+	}
+	for _, tt := range tests {
+		w := s.do(http.MethodPatch, path, s.token, tt.body)
+		s.Equal(http.StatusBadRequest, w.Code, tt.name)
+	}
+
+	w := s.do(http.MethodPatch, "/collections/"+s.colID+"/cards/missing", s.token, map[string]any{"count": 1})
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *RouterTestSuite) TestDeleteCard() {
+	path := "/collections/" + s.colID + "/cards/ouphe"
+
+	s.Equal(http.StatusNoContent, s.do(http.MethodDelete, path, s.token, nil).Code)
+	s.Equal(http.StatusNotFound, s.do(http.MethodDelete, path, s.token, nil).Code)
+	s.Equal(http.StatusNotFound, s.do(http.MethodGet, path, s.token, nil).Code)
+}
+
+func (s *RouterTestSuite) TestBatchRouteOnlyMatchesBatch() {
+	body := map[string]any{"operations": []map[string]any{{"op": "add", "scryfall_id": "ouphe", "count": 1}}}
+
+	w := s.do(http.MethodPost, "/collections/"+s.colID+"/cards:purge", s.token, body)
+	s.Equal(http.StatusNotFound, w.Code)
+
+	w = s.do(http.MethodPost, "/collections/"+s.colID+"/cards:batch", s.token, body)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	s.Equal(2, s.getEntry("ouphe").Count)
+}
+
+func (s *RouterTestSuite) TestSplitBySet() {
+	body := map[string]any{"name": "Alpha", "filter": map[string]any{"sets": []string{"LEA"}}}
+	w := s.do(http.MethodPost, "/collections/"+s.colID+"/split", s.token, body)
+	s.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	var created struct{ ID string }
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+
+	alpha, respErr := s.repo.GetCollection(created.ID)
+	s.Require().Nil(respErr)
+	s.Require().Len(alpha.Cards, 1)
+	s.Equal("bolt-lea", alpha.Cards[0].ScryfallID)
+
+	bulk, respErr := s.repo.GetCollection(s.colID)
+	s.Require().Nil(respErr)
+	s.Len(bulk.Cards, 2)
+}
+
+func (s *RouterTestSuite) TestTrashPurge() {
+	s.Require().Equal(http.StatusNoContent, s.do(http.MethodDelete, "/collections/"+s.colID, s.token, nil).Code)
+
+	collections := services.NewCollectionsService(s.repo, catalog.New(), logger.SilentLogger{})
+	purged, respErr := collections.PurgeTrash(time.Hour)
+	s.Require().Nil(respErr)
+	s.Zero(purged, "collections deleted within retention are kept")
+
+	purged, respErr = collections.PurgeTrash(0)
+	s.Require().Nil(respErr)
+	s.Equal(1, purged)
+
+	w := s.do(http.MethodGet, "/collections/trash", s.token, nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.JSONEq(`[]`, w.Body.String())
+	s.Equal(http.StatusNotFound, s.do(http.MethodPost, "/collections/"+s.colID+"/restore", s.token, nil).Code)
+}
+
+func (s *RouterTestSuite) TestHistoryChangeSource() {
+	path := "/collections/" + s.colID + "/cards/ouphe"
+	patch := func(source string) int {
+		req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"count": 3}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+s.token)
+		req.Header.Set(cards.ChangeSourceHeader, source)
+
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	s.Equal(http.StatusBadRequest, patch("telepathy"))
+	s.Equal(1, s.getEntry("ouphe").Count)
+	s.Require().Equal(http.StatusNoContent, patch(cards.ChangeSourceBot))
+
+	w := s.do(http.MethodGet, "/collections/"+s.colID+"/history?scryfall_id=ouphe", s.token, nil)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var history []cards.CardHistoryEntry
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &history))
+	s.Require().Len(history, 2)
+	s.Equal(cards.ChangeSourceBot, history[0].Source)
+	s.Equal(models.HistoryActionUpdate, history[0].Action)
+	s.Equal(2, history[0].Delta)
+	s.Equal(cards.ChangeSourceImport, history[1].Source, "the seeded cards")
+
+	// Undo takes no body when only the entry itself is undone.
+	w = s.do(http.MethodPost, "/collections/"+s.colID+"/history/"+history[0].ID+"/undo", s.token, nil)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	s.Equal(1, s.getEntry("ouphe").Count)
+
+	w = s.do(http.MethodGet, "/collections/"+s.colID+"/history?limit=500", s.token, nil)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *RouterTestSuite) TestReservedCardIsAddedWithItsReservation() {
+	owner, err := bson.ObjectIDFromHex(s.userID)
+	s.Require().NoError(err)
+	deck, respErr := s.repo.CreateCollection(&models.Collection{UserID: owner, Name: "Burn", Kind: models.CollectionKindDeck})
+	s.Require().Nil(respErr)
+	source, err := bson.ObjectIDFromHex(s.colID)
+	s.Require().NoError(err)
+
+	allocation := func() *models.Allocation {
+		return &models.Allocation{UserID: owner, DeckID: deck.ObjectID, SourceID: source, ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 1}
+	}
+	_, respErr = s.repo.CreateAllocation(allocation())
+	s.Require().Nil(respErr)
+
+	// The reservation fails, so the card is not added either.
+	change := &models.CardChange{UserID: owner, Source: models.ChangeSourceAPI}
+	respErr = s.repo.AddReservedCardToCollection(change, deck.ID, &models.Card{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 1}, allocation())
+	s.Require().NotNil(respErr)
+	s.Equal(http.StatusConflict, respErr.Status)
+
+	stored, respErr := s.repo.GetCollection(deck.ID)
+	s.Require().Nil(respErr)
+	s.Empty(stored.Cards)
+	s.Equal(deck.Version, stored.Version)
+	history, respErr := s.repo.ListCardHistory(&models.CardHistoryFilter{CollectionID: deck.ObjectID})
+	s.Require().Nil(respErr)
+	s.Empty(history)
+}
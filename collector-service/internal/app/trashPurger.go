@@ -0,0 +1,64 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/config"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type trashService interface {
+	PurgeTrash(retention time.Duration) (int, *models.ResponseErr)
+}
+
+// trashPurger removes deleted collections for good once their retention is over.
+type trashPurger struct {
+	collections trashService
+	cfg         config.TrashConfig
+	log         logger.Logger
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+func newTrashPurger(collections trashService, cfg config.TrashConfig, log logger.Logger) *trashPurger {
+	return &trashPurger{
+		collections: collections,
+		cfg:         cfg,
+		log:         log.With(logger.String("worker", "trash")),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Run purges the trash right away and then every PurgeInterval until Stop is called.
+func (p *trashPurger) Run() {
+	defer close(p.done)
+
+	if p.cfg.PurgeInterval <= 0 {
+		p.log.Warn("Trash purge is disabled, deleted collections are kept forever")
+		return
+	}
+
+	p.log.Info("Trash purge started", logger.Duration("retention", p.cfg.Retention), logger.Duration("interval", p.cfg.PurgeInterval))
+	ticker := time.NewTicker(p.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, respErr := p.collections.PurgeTrash(p.cfg.Retention); respErr != nil {
+			p.log.Error("Failed to purge trash", logger.Error(respErr))
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops Run and waits for the purge in progress to finish.
+func (p *trashPurger) Stop() {
+	close(p.stop)
+	<-p.done
+}
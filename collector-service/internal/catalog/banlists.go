@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Legalities of a card in a format, as named by Scryfall.
+const (
+	Legal      = "legal"
+	NotLegal   = "not_legal"
+	Banned     = "banned"
+	Restricted = "restricted"
+)
+
+// Banlist names the cards banned and restricted in a format.
+type Banlist struct {
+	Banned     []string `json:"banned"`
+	Restricted []string `json:"restricted"`
+}
+
+// Banlists are banlists by format, read from a local file like
+//
+//	{"modern": {"banned": ["Splinter Twin"]}, "vintage": {"restricted": ["Black Lotus"]}}
+//
+// A card on a list is banned or restricted whatever Scryfall data says: the
+// bulk file only changes when it is downloaded again, and it has no cards
+// the catalog doesn't know.
+type Banlists map[string]*Banlist
+
+// LoadBanlists reads a banlists file. An empty path gives no banlists.
+func LoadBanlists(path string) (Banlists, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open banlists: %w", err)
+	}
+	defer f.Close()
+
+	b, err := ReadBanlists(f)
+	if err != nil {
+		return nil, fmt.Errorf("read banlists %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// ReadBanlists decodes banlists from JSON.
+func ReadBanlists(r io.Reader) (Banlists, error) {
+	var b Banlists
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// status returns the legality of a card named name, or "" if it is on no list.
+func (b Banlists) status(format, name string) string {
+	list := b[format]
+	if list == nil {
+		return ""
+	}
+	for _, banned := range list.Banned {
+		if strings.EqualFold(banned, name) {
+			return Banned
+		}
+	}
+	for _, restricted := range list.Restricted {
+		if strings.EqualFold(restricted, name) {
+			return Restricted
+		}
+	}
+	return ""
+}
+
+// SetBanlists makes the catalog answer Legality from b as well.
+// Call it before the catalog is shared.
+func (c *Catalog) SetBanlists(b Banlists) {
+	c.banlists = b
+}
+
+// Legality tells whether a card may be played in a format: Legal, NotLegal,
+// Banned or Restricted, or "" when neither the banlists nor the catalog know.
+func (c *Catalog) Legality(format, scryfallID, name string) string {
+	if c == nil {
+		return ""
+	}
+	if status := c.banlists.status(format, name); status != "" {
+		return status
+	}
+	if card, ok := c.Card(scryfallID); ok {
+		return card.Legalities[format]
+	}
+	return ""
+}
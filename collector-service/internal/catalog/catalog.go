@@ -0,0 +1,223 @@
+// Package catalog holds reference data about Magic cards loaded from local
+// Scryfall bulk data files, so the service never calls Scryfall at request time.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Card is a single printing as described by Scryfall.
+type Card struct {
+	ScryfallID      string            `json:"id"`
+	OracleID        string            `json:"oracle_id"`
+	Name            string            `json:"name"`
+	Lang            string            `json:"lang"`
+	Set             string            `json:"set"`
+	SetName         string            `json:"set_name"`
+	CollectorNumber string            `json:"collector_number"`
+	Rarity          string            `json:"rarity"`
+	ManaCost        string            `json:"mana_cost"`
+	TypeLine        string            `json:"type_line"`
+	OracleText      string            `json:"oracle_text"`
+	Colors          []string          `json:"colors"`
+	ColorIdentity   []string          `json:"color_identity"`
+	Finishes        []string          `json:"finishes"`
+	ImageURIs       map[string]string `json:"image_uris"`
+	ScryfallURI     string            `json:"scryfall_uri"`
+	CardFaces       []CardFace        `json:"card_faces"`
+	// Legalities maps formats to "legal", "not_legal", "banned" or "restricted".
+	Legalities map[string]string `json:"legalities"`
+	// Prices are the prices of the day the bulk file was made, like "usd": "0.25";
+	// a price Scryfall doesn't know is missing or empty.
+	Prices map[string]string `json:"prices"`
+}
+
+// CardFace is one face of a multi-faced card.
+type CardFace struct {
+	Name       string            `json:"name"`
+	ManaCost   string            `json:"mana_cost"`
+	TypeLine   string            `json:"type_line"`
+	OracleText string            `json:"oracle_text"`
+	ImageURIs  map[string]string `json:"image_uris"`
+}
+
+// ImageURL returns the normal-size image of the card or of its front face.
+func (c *Card) ImageURL() string {
+	if url := c.ImageURIs["normal"]; url != "" {
+		return url
+	}
+	if len(c.CardFaces) > 0 {
+		return c.CardFaces[0].ImageURIs["normal"]
+	}
+	return ""
+}
+
+// PriceUSD returns the price of a nonfoil copy in US dollars, if Scryfall knows it.
+func (c *Card) PriceUSD() (float64, bool) {
+	price, err := strconv.ParseFloat(c.Prices["usd"], 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+// Catalog is an in-memory index of card printings.
+// A nil or empty Catalog knows no cards; it is read-only once built.
+type Catalog struct {
+	byID     map[string]*Card
+	byOracle map[string][]*Card
+	// byName indexes printings by lowercase name and, for multi-faced cards,
+	// by the name of their front face too, as decklists often name them.
+	byName map[string][]*Card
+	// bySet indexes printings by lowercase set code and collector number.
+	bySet    map[string]*Card
+	banlists Banlists
+}
+
+// New builds a catalog from the given printings.
+func New(cards ...*Card) *Catalog {
+	c := &Catalog{
+		byID:     make(map[string]*Card, len(cards)),
+		byOracle: make(map[string][]*Card),
+		byName:   make(map[string][]*Card),
+		bySet:    make(map[string]*Card),
+	}
+	for _, card := range cards {
+		c.add(card)
+	}
+	return c
+}
+
+func (c *Catalog) add(card *Card) {
+	c.byID[card.ScryfallID] = card
+	if card.OracleID != "" {
+		c.byOracle[card.OracleID] = append(c.byOracle[card.OracleID], card)
+	}
+	if card.Name != "" {
+		name := strings.ToLower(card.Name)
+		c.byName[name] = append(c.byName[name], card)
+	}
+	if len(card.CardFaces) > 1 && card.CardFaces[0].Name != "" {
+		front := strings.ToLower(card.CardFaces[0].Name)
+		c.byName[front] = append(c.byName[front], card)
+	}
+	if card.Set != "" && card.CollectorNumber != "" {
+		c.bySet[setKey(card.Set, card.CollectorNumber)] = card
+	}
+}
+
+func setKey(set, collectorNumber string) string {
+	return strings.ToLower(set) + "/" + strings.ToLower(collectorNumber)
+}
+
+// LoadScryfallBulk reads a Scryfall bulk data file ("Default Cards" or "All Cards").
+// An empty path gives an empty catalog.
+func LoadScryfallBulk(path string) (*Catalog, error) {
+	if path == "" {
+		return New(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open scryfall bulk data: %w", err)
+	}
+	defer f.Close()
+
+	c, err := ReadScryfallBulk(f)
+	if err != nil {
+		return nil, fmt.Errorf("read scryfall bulk data %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ReadScryfallBulk decodes a JSON array of Scryfall card objects card by card,
+// so the multi-gigabyte "All Cards" file does not have to fit in memory twice.
+func ReadScryfallBulk(r io.Reader) (*Catalog, error) {
+	c := New()
+	if err := decodeCards(r, c.add); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// decodeCards calls add for every card with an ID of a JSON array of Scryfall card objects.
+func decodeCards(r io.Reader, add func(card *Card)) error {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array of cards")
+	}
+
+	for dec.More() {
+		var card Card
+		if err := dec.Decode(&card); err != nil {
+			return err
+		}
+		if card.ScryfallID == "" {
+			continue
+		}
+		add(&card)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// Len reports how many printings the catalog knows.
+func (c *Catalog) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.byID)
+}
+
+// Card looks a printing up by its Scryfall ID.
+func (c *Catalog) Card(scryfallID string) (*Card, bool) {
+	if c == nil {
+		return nil, false
+	}
+	card, ok := c.byID[scryfallID]
+	return card, ok
+}
+
+// Printings returns every known printing of the card with the given oracle ID.
+func (c *Catalog) Printings(oracleID string) []*Card {
+	if c == nil {
+		return nil
+	}
+	return c.byOracle[oracleID]
+}
+
+// Named returns every known printing of a card by its name, case-insensitively.
+// A multi-faced card can be named by its front face.
+func (c *Catalog) Named(name string) []*Card {
+	if c == nil {
+		return nil
+	}
+	return c.byName[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Printing looks a printing up by its set code and collector number.
+func (c *Catalog) Printing(set, collectorNumber string) (*Card, bool) {
+	if c == nil {
+		return nil, false
+	}
+	card, ok := c.bySet[setKey(set, collectorNumber)]
+	return card, ok
+}
+
+// SameCard reports whether two printings are the same card: same oracle ID if
+// the catalog knows both, otherwise the same name.
+func (c *Catalog) SameCard(scryfallA, nameA, scryfallB, nameB string) bool {
+	a, okA := c.Card(scryfallA)
+	b, okB := c.Card(scryfallB)
+	if okA && okB && a.OracleID != "" {
+		return a.OracleID == b.OracleID
+	}
+	return nameA != "" && strings.EqualFold(nameA, nameB)
+}
@@ -0,0 +1,331 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Currencies of prices: US dollars and euros for paper cards, event tickets for MTGO.
+const (
+	CurrencyUSD = "usd"
+	CurrencyEUR = "eur"
+	CurrencyTix = "tix"
+)
+
+// Finishes of printings, as named by Scryfall.
+const (
+	FinishNonfoil = "nonfoil"
+	FinishFoil    = "foil"
+	FinishEtched  = "etched"
+)
+
+// Prices are the market prices of a printing; a zero price is not known.
+// They are stored as is in the daily price snapshots.
+type Prices struct {
+	USD       float64 `bson:"usd,omitempty" json:"usd,omitempty"`
+	USDFoil   float64 `bson:"usd_foil,omitempty" json:"usd_foil,omitempty"`
+	USDEtched float64 `bson:"usd_etched,omitempty" json:"usd_etched,omitempty"`
+	EUR       float64 `bson:"eur,omitempty" json:"eur,omitempty"`
+	EURFoil   float64 `bson:"eur_foil,omitempty" json:"eur_foil,omitempty"`
+	Tix       float64 `bson:"tix,omitempty" json:"tix,omitempty"`
+}
+
+// IsZero reports whether no price is known.
+func (p Prices) IsZero() bool {
+	return p == Prices{}
+}
+
+// Price returns the price of a copy with a finish in a currency, if it is known.
+// Cardmarket has no etched prices, so etched copies cost as foils in euros;
+// MTGO has no finishes at all.
+func (p Prices) Price(currency, finish string) (float64, bool) {
+	var price float64
+	switch currency {
+	case CurrencyUSD:
+		switch finish {
+		case FinishFoil:
+			price = p.USDFoil
+		case FinishEtched:
+			price = p.USDEtched
+		default:
+			price = p.USD
+		}
+	case CurrencyEUR:
+		if finish == FinishFoil || finish == FinishEtched {
+			price = p.EURFoil
+		} else {
+			price = p.EUR
+		}
+	case CurrencyTix:
+		price = p.Tix
+	}
+	return price, price > 0
+}
+
+// merge fills in the prices p doesn't know from other.
+func (p *Prices) merge(other Prices) {
+	for _, pair := range []struct{ to, from *float64 }{
+		{&p.USD, &other.USD}, {&p.USDFoil, &other.USDFoil}, {&p.USDEtched, &other.USDEtched},
+		{&p.EUR, &other.EUR}, {&p.EURFoil, &other.EURFoil}, {&p.Tix, &other.Tix},
+	} {
+		if *pair.to == 0 {
+			*pair.to = *pair.from
+		}
+	}
+}
+
+// PriceQuote is what a printing cost on a day.
+type PriceQuote struct {
+	ScryfallID string
+	// Date is the day of the prices, at midnight UTC.
+	Date time.Time
+	Prices
+}
+
+// Day truncates a time to its day in UTC, the date of price quotes.
+func Day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// ScryfallPrices parses the prices Scryfall gives for the card.
+func (c *Card) ScryfallPrices() Prices {
+	parse := func(key string) float64 {
+		price, err := strconv.ParseFloat(c.Prices[key], 64)
+		if err != nil || price < 0 {
+			return 0
+		}
+		return price
+	}
+	return Prices{
+		USD:       parse("usd"),
+		USDFoil:   parse("usd_foil"),
+		USDEtched: parse("usd_etched"),
+		EUR:       parse("eur"),
+		EURFoil:   parse("eur_foil"),
+		Tix:       parse("tix"),
+	}
+}
+
+// LoadScryfallPrices reads the prices of a Scryfall bulk data file. Bulk files
+// have the prices of the day they were made, which is taken to be the day the
+// file was last modified. An empty path gives no prices.
+func LoadScryfallPrices(path string) ([]*PriceQuote, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open scryfall bulk data: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat scryfall bulk data: %w", err)
+	}
+	quotes, err := ReadScryfallPrices(f, info.ModTime())
+	if err != nil {
+		return nil, fmt.Errorf("read scryfall bulk data %s: %w", path, err)
+	}
+	return quotes, nil
+}
+
+// ReadScryfallPrices reads the prices of a JSON array of Scryfall card objects
+// as quotes of the day of date. Printings without known prices are skipped.
+func ReadScryfallPrices(r io.Reader, date time.Time) ([]*PriceQuote, error) {
+	day := Day(date)
+	var quotes []*PriceQuote
+	err := decodeCards(r, func(card *Card) {
+		if prices := card.ScryfallPrices(); !prices.IsZero() {
+			quotes = append(quotes, &PriceQuote{ScryfallID: card.ScryfallID, Date: day, Prices: prices})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// LoadMTGJSONPrices reads an MTGJSON AllPrices file. MTGJSON names printings
+// by its own UUIDs, so an AllIdentifiers file is needed to map them to Scryfall
+// IDs. An empty prices path gives no prices.
+func LoadMTGJSONPrices(pricesPath, identifiersPath string) ([]*PriceQuote, error) {
+	if pricesPath == "" {
+		return nil, nil
+	}
+	if identifiersPath == "" {
+		return nil, fmt.Errorf("mtgjson prices need an identifiers file")
+	}
+
+	f, err := os.Open(identifiersPath)
+	if err != nil {
+		return nil, fmt.Errorf("open mtgjson identifiers: %w", err)
+	}
+	defer f.Close()
+
+	scryfallIDs, err := ReadMTGJSONIdentifiers(f)
+	if err != nil {
+		return nil, fmt.Errorf("read mtgjson identifiers %s: %w", identifiersPath, err)
+	}
+
+	p, err := os.Open(pricesPath)
+	if err != nil {
+		return nil, fmt.Errorf("open mtgjson prices: %w", err)
+	}
+	defer p.Close()
+
+	quotes, err := ReadMTGJSONPrices(p, scryfallIDs)
+	if err != nil {
+		return nil, fmt.Errorf("read mtgjson prices %s: %w", pricesPath, err)
+	}
+	return quotes, nil
+}
+
+// ReadMTGJSONIdentifiers maps the MTGJSON UUIDs of an AllIdentifiers file to Scryfall IDs.
+func ReadMTGJSONIdentifiers(r io.Reader) (map[string]string, error) {
+	scryfallIDs := make(map[string]string)
+	err := decodeMTGJSONData(r, func(uuid string, dec *json.Decoder) error {
+		var card struct {
+			Identifiers struct {
+				ScryfallID string `json:"scryfallId"`
+			} `json:"identifiers"`
+		}
+		if err := dec.Decode(&card); err != nil {
+			return err
+		}
+		if card.Identifiers.ScryfallID != "" {
+			scryfallIDs[uuid] = card.Identifiers.ScryfallID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scryfallIDs, nil
+}
+
+// mtgjsonPriceList holds the prices of one vendor by finish and date.
+type mtgjsonPriceList struct {
+	Retail map[string]map[string]float64 `json:"retail"`
+}
+
+// ReadMTGJSONPrices reads an AllPrices file: every day it has becomes a quote.
+// US dollars come from TCGplayer, euros from Cardmarket and tix from
+// Cardhoarder, as on Scryfall. Printings without a Scryfall ID are skipped.
+func ReadMTGJSONPrices(r io.Reader, scryfallIDs map[string]string) ([]*PriceQuote, error) {
+	quotes := make(map[string]map[time.Time]*PriceQuote)
+	var order []*PriceQuote
+	err := decodeMTGJSONData(r, func(uuid string, dec *json.Decoder) error {
+		var formats struct {
+			MTGO  map[string]mtgjsonPriceList `json:"mtgo"`
+			Paper map[string]mtgjsonPriceList `json:"paper"`
+		}
+		if err := dec.Decode(&formats); err != nil {
+			return err
+		}
+		scryfallID, ok := scryfallIDs[uuid]
+		if !ok {
+			return nil
+		}
+		if quotes[scryfallID] == nil {
+			quotes[scryfallID] = make(map[time.Time]*PriceQuote)
+		}
+
+		for _, source := range []struct {
+			list   mtgjsonPriceList
+			finish string
+			set    func(p *Prices, price float64)
+		}{
+			{formats.Paper["tcgplayer"], "normal", func(p *Prices, price float64) { p.USD = price }},
+			{formats.Paper["tcgplayer"], "foil", func(p *Prices, price float64) { p.USDFoil = price }},
+			{formats.Paper["tcgplayer"], "etched", func(p *Prices, price float64) { p.USDEtched = price }},
+			{formats.Paper["cardmarket"], "normal", func(p *Prices, price float64) { p.EUR = price }},
+			{formats.Paper["cardmarket"], "foil", func(p *Prices, price float64) { p.EURFoil = price }},
+			{formats.MTGO["cardhoarder"], "normal", func(p *Prices, price float64) { p.Tix = price }},
+		} {
+			for date, price := range source.list.Retail[source.finish] {
+				day, err := time.Parse(time.DateOnly, date)
+				if err != nil || price <= 0 {
+					continue
+				}
+				var prices Prices
+				source.set(&prices, price)
+
+				quote, ok := quotes[scryfallID][day]
+				if !ok {
+					quote = &PriceQuote{ScryfallID: scryfallID, Date: day}
+					quotes[scryfallID][day] = quote
+					order = append(order, quote)
+				}
+				// MTGJSON has a UUID per face of some cards, all with the same prices.
+				quote.merge(prices)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// decodeMTGJSONData calls decode for every entry of the "data" object of an
+// MTGJSON file, letting it decode the value, so the file is read entry by entry.
+func decodeMTGJSONData(r io.Reader, decode func(key string, dec *json.Decoder) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok != "data" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return fmt.Errorf("expected an MTGJSON UUID, got %v", tok)
+			}
+			if err := decode(key, dec); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v in MTGJSON data, got %v", want, tok)
+	}
+	return nil
+}
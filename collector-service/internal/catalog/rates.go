@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// CurrencyRUB is a currency cards are not sold in; amounts in it are converted
+// from the market currencies at the exchange rates.
+const CurrencyRUB = "rub"
+
+// ExchangeRates is a table of exchange rates on a day: how many units of every
+// currency one unit of Base buys. The base buys one unit of itself.
+type ExchangeRates struct {
+	Base string `bson:"base" json:"base"`
+	// Date is the day of the rates, at midnight UTC.
+	Date  time.Time          `bson:"date" json:"date"`
+	Rates map[string]float64 `bson:"rates" json:"rates"`
+}
+
+// Convert converts an amount from one currency to another, if the table has both.
+func (r *ExchangeRates) Convert(amount float64, from, to string) (float64, bool) {
+	if from == to {
+		return amount, true
+	}
+	if r == nil {
+		return 0, false
+	}
+	fromRate, ok := r.Rates[from]
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := r.Rates[to]
+	if !ok {
+		return 0, false
+	}
+	return amount / fromRate * toRate, true
+}
+
+// LoadExchangeRates reads a table of exchange rates from a local file.
+// An empty path gives no table.
+func LoadExchangeRates(path string) (*ExchangeRates, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open exchange rates: %w", err)
+	}
+	defer f.Close()
+
+	rates, err := ReadExchangeRates(f)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates %s: %w", path, err)
+	}
+	return rates, nil
+}
+
+// FetchExchangeRates downloads a table of exchange rates from url, which must
+// serve the same JSON as a local rates file. An empty url gives no table.
+func FetchExchangeRates(ctx context.Context, client *http.Client, url string) (*ExchangeRates, error) {
+	if url == "" {
+		return nil, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch exchange rates: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch exchange rates %s: unexpected status %s", url, resp.Status)
+	}
+
+	rates, err := ReadExchangeRates(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates %s: %w", url, err)
+	}
+	return rates, nil
+}
+
+// ReadExchangeRates reads a table of exchange rates like
+//
+//	{"base": "usd", "date": "2025-03-01", "rates": {"eur": 0.92, "rub": 88.5}}
+//
+// The date may also be a full RFC 3339 time; currencies are lowercased.
+func ReadExchangeRates(r io.Reader) (*ExchangeRates, error) {
+	var file struct {
+		Base  string             `json:"base"`
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	if file.Base == "" {
+		return nil, errors.New("base currency is missing")
+	}
+	date, err := time.Parse(time.DateOnly, file.Date)
+	if err != nil {
+		if date, err = time.Parse(time.RFC3339, file.Date); err != nil {
+			return nil, fmt.Errorf("invalid date %q", file.Date)
+		}
+	}
+
+	rates := &ExchangeRates{
+		Base:  strings.ToLower(file.Base),
+		Date:  Day(date),
+		Rates: make(map[string]float64, len(file.Rates)+1),
+	}
+	for currency, rate := range file.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("rate of %s must be positive", currency)
+		}
+		rates.Rates[strings.ToLower(currency)] = rate
+	}
+	rates.Rates[rates.Base] = 1
+	return rates, nil
+}
@@ -1,34 +1,213 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 	"github.com/spf13/viper"
 )
 
-func InitConfig() *viper.Viper {
-	filename := fetchConfigFile()
-	return InitConfigByFilename(filename)
+// Config is the typed configuration of collector-service.
+// Every key can be overridden from env: server_http.host -> SERVER_HTTP_HOST.
+// Secrets can also be read from a file: jwt.secret -> JWT_SECRET_FILE.
+type Config struct {
+	ServerHTTP ServerHTTPConfig `mapstructure:"server_http"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	Catalog    CatalogConfig    `mapstructure:"catalog"`
+	Trash      TrashConfig      `mapstructure:"trash"`
+	Prices     PricesConfig     `mapstructure:"prices"`
+	Logger     logger.Config    `mapstructure:"logger"`
+}
+
+type ServerHTTPConfig struct {
+	Host string `mapstructure:"host"`
+}
+
+type DatabaseConfig struct {
+	ConnString string `mapstructure:"conn_string"`
+}
+
+type JWTConfig struct {
+	Secret   string        `mapstructure:"secret"`
+	TokenTTL time.Duration `mapstructure:"token_ttl"`
+}
+
+// CatalogConfig points to local card reference data.
+// An empty path leaves the catalog empty or without banlists.
+type CatalogConfig struct {
+	ScryfallBulkPath string `mapstructure:"scryfall_bulk_path"`
+	BanlistsPath     string `mapstructure:"banlists_path"`
+}
+
+// TrashConfig controls how long deleted collections can be restored.
+// Every purge_interval the collections deleted more than retention ago are removed for good.
+type TrashConfig struct {
+	Retention     time.Duration `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+// PricesConfig controls the import of daily price snapshots. Every import_interval
+// the prices of the Scryfall bulk file of the catalog and of the MTGJSON files are
+// stored; MTGJSON prices need the AllIdentifiers file to find Scryfall IDs.
+// The exchange rates are read from exchange_rates_path or, if it is empty,
+// downloaded from exchange_rates_url at the same interval.
+type PricesConfig struct {
+	MTGJSONPricesPath      string        `mapstructure:"mtgjson_prices_path"`
+	MTGJSONIdentifiersPath string        `mapstructure:"mtgjson_identifiers_path"`
+	ExchangeRatesPath      string        `mapstructure:"exchange_rates_path"`
+	ExchangeRatesURL       string        `mapstructure:"exchange_rates_url"`
+	ImportInterval         time.Duration `mapstructure:"import_interval"`
+}
+
+// ValidationError lists every required key that is missing.
+type ValidationError struct {
+	Missing []string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Missing))
+	for i, key := range e.Missing {
+		fields[i] = fmt.Sprintf("%s (%s)", key, envName(key))
+	}
+	return "config: missing required fields: " + strings.Join(fields, ", ")
+}
+
+var defaults = map[string]any{
+	"server_http.host":                "0.0.0.0:8080",
+	"database.conn_string":            "",
+	"jwt.secret":                      "",
+	"jwt.token_ttl":                   72 * time.Hour,
+	"catalog.scryfall_bulk_path":      "",
+	"catalog.banlists_path":           "",
+	"trash.retention":                 30 * 24 * time.Hour,
+	"trash.purge_interval":            time.Hour,
+	"prices.mtgjson_prices_path":      "",
+	"prices.mtgjson_identifiers_path": "",
+	"prices.exchange_rates_path":      "",
+	"prices.exchange_rates_url":       "",
+	"prices.import_interval":          24 * time.Hour,
+	"logger.production":               false,
+	"logger.level":                    "info",
+	"logger.sensitive_keys":           []string{},
+}
+
+// secretKeys may be provided as <ENV>_FILE pointing to a file with the value.
+var secretKeys = []string{"database.conn_string", "jwt.secret"}
+
+var requiredKeys = []string{"server_http.host", "database.conn_string", "jwt.secret"}
+
+// InitConfig reads the file named by -config or APP_CONFIG (if any),
+// applies defaults and env overrides and validates the result.
+func InitConfig() (*Config, error) {
+	v := viper.New()
+	if filename := fetchConfigFile(); filename != "" {
+		if err := readConfigFile(v, filename); err != nil {
+			return nil, err
+		}
+	}
+	return Load(v)
+}
+
+// Load builds a validated Config from v.
+func Load(v *viper.Viper) (*Config, error) {
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	for _, key := range secretKeys {
+		path := os.Getenv(envName(key) + "_FILE")
+		if path == "" {
+			continue
+		}
+		secret, err := readSecretFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", key, err)
+		}
+		v.Set(key, secret)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	if err := validate(v); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
 }
 
 func InitConfigByFilename(filename string) *viper.Viper {
 	config := viper.New()
+	if err := readConfigFile(config, filename); err != nil {
+		panic("Error reading config file: " + err.Error())
+	}
+
+	return config
+}
+
+// readConfigFile accepts either a bare name looked up in $HOME and the
+// working directory ("config") or a path to a file ("/etc/app/config.toml").
+func readConfigFile(config *viper.Viper, filename string) error {
+	if filepath.Ext(filename) != "" || strings.ContainsRune(filename, os.PathSeparator) {
+		config.SetConfigFile(filename)
+		return config.ReadInConfig()
+	}
+
 	config.SetConfigName(filename)
 	config.AddConfigPath("$HOME")
 	config.AddConfigPath(".")
 
-	if err := config.ReadInConfig(); err != nil {
-		panic("Error reading config file: " + err.Error())
+	return config.ReadInConfig()
+}
+
+func validate(v *viper.Viper) error {
+	var missing []string
+	for _, key := range requiredKeys {
+		if strings.TrimSpace(v.GetString(key)) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return &ValidationError{Missing: missing}
 	}
+	return nil
+}
 
-	return config
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", errors.New("secret file " + path + " is empty")
+	}
+	return secret, nil
+}
+
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
 }
 
+// fetchConfigFile uses its own flag set so unknown flags
+// (for example the ones of `go test`) don't abort the program.
 func fetchConfigFile() string {
 	var filename string
-	flag.StringVar(&filename, "config", "", "for configuration collector server")
-	flag.Parse()
+	fs := flag.NewFlagSet("collector-service", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&filename, "config", "", "for configuration collector server")
+	_ = fs.Parse(os.Args[1:])
 	if filename == "" {
 		filename = os.Getenv("APP_CONFIG")
 	}
@@ -4,8 +4,11 @@ package config
 import (
 	"flag"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -68,3 +71,84 @@ func TestInitConfigByFilename_InvalidFile(t *testing.T) {
 		InitConfigByFilename("non_existent_file.yaml")
 	})
 }
+
+func TestLoad_DefaultsAndEnvOverrides(t *testing.T) {
+	t.Setenv("DATABASE_CONN_STRING", "mongodb://localhost:27017")
+	t.Setenv("JWT_SECRET", "env-secret")
+	t.Setenv("JWT_TOKEN_TTL", "1h")
+
+	cfg, err := Load(viper.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0:8080", cfg.ServerHTTP.Host)
+	assert.Equal(t, "mongodb://localhost:27017", cfg.Database.ConnString)
+	assert.Equal(t, "env-secret", cfg.JWT.Secret)
+	assert.Equal(t, time.Hour, cfg.JWT.TokenTTL)
+	assert.Equal(t, 30*24*time.Hour, cfg.Trash.Retention)
+	assert.Equal(t, time.Hour, cfg.Trash.PurgeInterval)
+	assert.Equal(t, 24*time.Hour, cfg.Prices.ImportInterval)
+	assert.Empty(t, cfg.Prices.ExchangeRatesURL)
+	assert.Equal(t, "info", cfg.Logger.Level)
+}
+
+func TestLoad_FromFile(t *testing.T) {
+	t.Setenv("JWT_SECRET", "env-secret")
+
+	tmpFile, err := os.CreateTemp("", "test_config_*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(`
+[server_http]
+host = "127.0.0.1:9090"
+
+[database]
+conn_string = "mongodb://file:27017"
+
+[trash]
+retention = "168h"
+
+[logger]
+production = true
+`)
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	cfg, err := Load(InitConfigByFilename(tmpFile.Name()))
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:9090", cfg.ServerHTTP.Host)
+	assert.Equal(t, "mongodb://file:27017", cfg.Database.ConnString)
+	assert.Equal(t, 7*24*time.Hour, cfg.Trash.Retention)
+	assert.True(t, cfg.Logger.Production)
+}
+
+func TestLoad_MissingFields(t *testing.T) {
+	t.Setenv("DATABASE_CONN_STRING", "")
+	t.Setenv("JWT_SECRET", "")
+
+	_, err := Load(viper.New())
+	require.Error(t, err)
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, []string{"database.conn_string", "jwt.secret"}, vErr.Missing)
+	assert.Contains(t, err.Error(), "JWT_SECRET")
+	assert.Contains(t, err.Error(), "DATABASE_CONN_STRING")
+}
+
+func TestLoad_SecretFile(t *testing.T) {
+	t.Setenv("DATABASE_CONN_STRING", "mongodb://localhost:27017")
+
+	secretFile := filepath.Join(t.TempDir(), "jwt_secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0o600))
+	t.Setenv("JWT_SECRET_FILE", secretFile)
+
+	cfg, err := Load(viper.New())
+	require.NoError(t, err)
+	assert.Equal(t, "file-secret", cfg.JWT.Secret)
+
+	t.Setenv("JWT_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = Load(viper.New())
+	require.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AllocationsController отвечает за резервирование карт для колод
+// @Tags Allocations
+// @BasePath /
+type AllocationsController struct {
+	allocationsService AllocationsServicer
+	log                logger.Logger
+}
+
+type AllocationsServicer interface {
+	ListAllocations(userId, deckId string) ([]*models.Allocation, *models.ResponseErr)
+	ReserveCard(userId, deckId, sourceId, scryfallId string, count int) (*models.Allocation, *models.ResponseErr)
+	ReleaseCard(userId, deckId, allocationId string) *models.ResponseErr
+	ListConflicts(userId string) ([]*models.AllocationConflict, *models.ResponseErr)
+}
+
+// NewAllocationsController создает контроллер резервов
+func NewAllocationsController(allocationsService AllocationsServicer, log logger.Logger) *AllocationsController {
+	return &AllocationsController{
+		allocationsService: allocationsService,
+		log:                log.With(logger.String("controller", "allocations")),
+	}
+}
+
+// @Summary     Get deck allocations
+// @Description Получить резервы колоды: какие копии из каких коллекций в ней лежат
+// @Tags        Allocations
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Collection ID"
+// @Success     200 {array} collections.Allocation
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/allocations [get]
+func (ac AllocationsController) ListAllocations(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	list, respErr := ac.allocationsService.ListAllocations(userId, ctx.Param("id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]collections.Allocation, 0, len(list))
+	for _, a := range list {
+		out = append(out, toAllocation(a))
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Reserve card for deck
+// @Description Зарезервировать копии карты из коллекции-источника для колоды. Одна печать из одного источника
+// @Description резервируется для колоды один раз
+// @Tags        Allocations
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id    path string                         true "Collection ID"
+// @Param       input body collections.ReserveCardRequest true "Карта, источник и количество копий"
+// @Success     201 {object} collections.Allocation
+// @Failure     400,401,404,409 {object} collections.ErrorResponse
+// @Router      /collections/{id}/allocations [post]
+func (ac AllocationsController) ReserveCard(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req collections.ReserveCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	allocation, respErr := ac.allocationsService.ReserveCard(userId, ctx.Param("id"), req.SourceCollectionID, req.ScryfallID, req.Count)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toAllocation(allocation))
+}
+
+// @Summary     Release card reservation
+// @Description Снять резерв колоды
+// @Tags        Allocations
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id            path string true "Collection ID"
+// @Param       allocation_id path string true "Allocation ID"
+// @Success     204 "No Content"
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/allocations/{allocation_id} [delete]
+func (ac AllocationsController) ReleaseCard(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	respErr = ac.allocationsService.ReleaseCard(userId, ctx.Param("id"), ctx.Param("allocation_id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Get allocation conflicts
+// @Description Получить карты, зарезервированные для колод больше раз, чем их копий в коллекции-источнике,
+// @Description и колоды, в которых они лежат
+// @Tags        Allocations
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {array} collections.AllocationConflict
+// @Failure     401 {object} collections.ErrorResponse
+// @Router      /allocations/conflicts [get]
+func (ac AllocationsController) ListConflicts(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	conflicts, respErr := ac.allocationsService.ListConflicts(userId)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]collections.AllocationConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		conflict := collections.AllocationConflict{
+			SourceCollectionID:   c.Source.ObjectID.Hex(),
+			SourceCollectionName: c.Source.Name,
+			ScryfallID:           c.ScryfallID,
+			Name:                 c.Name,
+			Owned:                c.Owned,
+			Allocated:            c.Allocated(),
+			Over:                 c.Allocated() - c.Owned,
+			Decks:                make([]collections.AllocatedDeck, 0, len(c.Allocations)),
+		}
+		for _, a := range c.Allocations {
+			conflict.Decks = append(conflict.Decks, collections.AllocatedDeck{
+				CollectionID: a.Deck.ObjectID.Hex(),
+				Name:         a.Deck.Name,
+				AllocationID: a.Allocation.ObjectID.Hex(),
+				Count:        a.Count,
+			})
+		}
+		out = append(out, conflict)
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+func toAllocation(a *models.Allocation) collections.Allocation {
+	return collections.Allocation{
+		ID:                 a.ObjectID.Hex(),
+		CollectionID:       a.DeckID.Hex(),
+		SourceCollectionID: a.SourceID.Hex(),
+		ScryfallID:         a.ScryfallID,
+		Name:               a.Name,
+		Count:              a.Count,
+		CreatedAt:          a.CreatedAt,
+	}
+}
@@ -9,8 +9,10 @@ import (
 
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/mocks"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -44,8 +46,7 @@ func (its *UnitTestSuite) SetupTest() {
 func (its *UnitTestSuite) TestRegister() {
 	// Mocking the HTTP context
 	reqModel := &models.User{TelegramID: 123, FirstName: "John", Username: "@john"}
-	resModel := &models.User{ID: "abc", TelegramID: 123, FirstName: "John", Username: "@john"}
-	its.authServiceMock.On("Register", reqModel).Return(resModel, nil)
+	its.authServiceMock.On("Register", reqModel).Return("jwt", nil)
 
 	// Making a request to the Register endpoint
 	body := `{"telegram_id":123,"first_name":"John","username":"@john"}`
@@ -57,23 +58,28 @@ func (its *UnitTestSuite) TestRegister() {
 
 	// Asserting the response
 	its.Equal(http.StatusCreated, w.Code)
-	var resp struct {
-		User UserResponse `json:"user"`
-	}
+	var resp auth.RegisterResponse
 	its.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
-	its.Equal("abc", resp.User.ID)
-	its.Equal(int64(123), resp.User.TelegramID)
-	its.Equal("John", resp.User.FirstName)
-	its.Equal("@john", resp.User.Username)
+	its.Equal("jwt", resp.Token)
 
 	its.authServiceMock.AssertExpectations(its.T())
 }
 
+func (its *UnitTestSuite) TestRegisterInvalidBody() {
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(`{"username":"@john"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	its.router.ServeHTTP(w, req)
+
+	its.Equal(http.StatusBadRequest, w.Code)
+	its.authServiceMock.AssertNotCalled(its.T(), "Register", mock.Anything)
+}
+
 func (its *UnitTestSuite) TestWho() {
 	// Mocking the HTTP context
 	telegramIdString := "123"
-	respModel := &models.User{ID: "abc", TelegramID: 123, FirstName: "John", Username: "@john"}
-	its.authServiceMock.On("Who", telegramIdString).Return(respModel, nil)
+	its.authServiceMock.On("Who", telegramIdString).Return("jwt", nil)
 
 	// Making a request to the Who endpoint
 	req := httptest.NewRequest(http.MethodGet, "/user/telegram/123", nil)
@@ -83,23 +89,33 @@ func (its *UnitTestSuite) TestWho() {
 
 	// Asserting the response
 	its.Equal(http.StatusOK, w.Code)
-	var resp UserResponse
+	var resp auth.CheckUserResponse
 	its.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
-	its.Equal("abc", resp.ID)
-	its.Equal(int64(123), resp.TelegramID)
-	its.Equal("John", resp.FirstName)
-	its.Equal("@john", resp.Username)
+	its.Equal("jwt", resp.Token)
+	its.True(resp.Success)
+
+	its.authServiceMock.AssertExpectations(its.T())
+}
+
+func (its *UnitTestSuite) TestWhoNotFound() {
+	its.authServiceMock.On("Who", "123").Return("", &models.ResponseErr{Status: http.StatusNotFound, Message: "User not found"})
+
+	req := httptest.NewRequest(http.MethodGet, "/user/telegram/123", nil)
+	w := httptest.NewRecorder()
 
+	its.router.ServeHTTP(w, req)
+
+	its.Equal(http.StatusNotFound, w.Code)
 	its.authServiceMock.AssertExpectations(its.T())
 }
 
 func (its *UnitTestSuite) TestLogin() {
 	// Mocking the HTTP context
-	reqModel := &models.User{TelegramID: 123, FirstName: "John", Username: "@john"}
-	its.authServiceMock.On("Login", reqModel).Return(nil)
+	reqModel := &models.User{TelegramID: 123}
+	its.authServiceMock.On("Login", reqModel).Return("jwt", nil)
 
 	// Making a request to the Login endpoint
-	body := `{"telegram_id":123,"first_name":"John","username":"@john"}`
+	body := `{"telegram_id":123}`
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
@@ -108,11 +124,10 @@ func (its *UnitTestSuite) TestLogin() {
 
 	// Asserting the response
 	its.Equal(http.StatusOK, w.Code)
-	var resp struct {
-		Token string `json:"token"`
-	}
+	var resp auth.CheckUserResponse
 	its.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
-	its.NotEmpty(resp.Token)
+	its.Equal("jwt", resp.Token)
+	its.True(resp.Success)
 
 	its.authServiceMock.AssertExpectations(its.T())
 }
@@ -1,25 +1,43 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
+// CardsController отвечает за карты в коллекциях
+// @Tags Cards
+// @BasePath /
 type CardsController struct {
 	cardsService CardsServicer
 	log          logger.Logger
 }
 
 type CardsServicer interface {
-	ListCardsInCollection(collectionId string) ([]*models.Card, *models.ResponseErr)
-	AddCardToCollection(collectionId string, card *models.Card) *models.ResponseErr
-	SetCardCountInCollection(collectionId string, card *models.Card) *models.ResponseErr
-	DeleteCardFromCollection(collectionId string, card *models.Card) *models.ResponseErr
+	ListCardsInCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr)
+	GetCardInCollection(userId, collectionId string, key models.CardKey) (*models.CardEntry, *models.ResponseErr)
+	AddCardToCollection(userId, source, collectionId string, card *models.Card, reserveFrom string, ifVersion *int64) *models.ResponseErr
+	UpdateCardInCollection(userId, source, collectionId string, card *models.CardUpdate, ifVersion *int64) *models.ResponseErr
+	DeleteCardFromCollection(userId, source, collectionId string, card *models.Card, ifVersion *int64) *models.ResponseErr
+	TransferCard(userId, source, fromCollectionId, toCollectionId string, key models.CardKey, count int, copy bool, ifVersion *int64) (*models.CardTransferResult, *models.ResponseErr)
+	ApplyCardBatch(userId, source, collectionId string, atomic bool, ops []*models.CardOperation, ifVersion *int64) (*models.CardBatchResult, *models.ResponseErr)
+	ListCardHistory(userId, collectionId, scryfallId, before string, limit int) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	UndoCardChange(userId, source, collectionId, entryId string, wholeBatch bool) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	FindOwnedCards(userId string, query *models.OwnedCardQuery) ([]*models.OwnedCards, *models.ResponseErr)
+	AddAcquisition(userId, collectionId string, key models.CardKey, acquisition *models.Acquisition, ifVersion *int64) (*models.Acquisition, *models.ResponseErr)
+	UpdateAcquisition(userId, collectionId string, key models.CardKey, acquisitionId string, update *models.AcquisitionUpdate, ifVersion *int64) (*models.Acquisition, *models.ResponseErr)
+	DeleteAcquisition(userId, collectionId string, key models.CardKey, acquisitionId string, ifVersion *int64) *models.ResponseErr
 }
 
+// NewCardsController создает контроллер карт
 func NewCardsController(cardsService CardsServicer, log logger.Logger) *CardsController {
 	return &CardsController{
 		cardsService: cardsService,
@@ -27,26 +45,194 @@ func NewCardsController(cardsService CardsServicer, log logger.Logger) *CardsCon
 	}
 }
 
+// @Summary     List cards in collection
+// @Description Получить список карт в коллекции
+// @Tags        Cards
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id            path   string true  "Collection ID"
+// @Param       If-None-Match header string false "ETag коллекции, которая уже есть у клиента"
+// @Success     200 {array} cards.Card
+// @Success     304 "Not Modified"
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards [get]
 func (cc CardsController) ListCardsInCollection(ctx *gin.Context) {
-	collectionId := ctx.Param("id")
-	cards, respErr := cc.cardsService.ListCardsInCollection(collectionId)
+	userId, respErr := getUserFromCtx(ctx)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
 
-	ctx.JSON(200, cards)
+	collection, respErr := cc.cardsService.ListCardsInCollection(userId, ctx.Param("id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	if notModified(ctx, collection.Version) {
+		return
+	}
+
+	out := make([]cards.Card, 0, len(collection.Cards))
+	for _, c := range collection.Cards {
+		out = append(out, toCard(c))
+	}
+	ctx.JSON(http.StatusOK, out)
 }
 
+// @Summary     Get card in collection
+// @Description Получить карту коллекции с другими печатями этой карты и данными каталога
+// @Tags        Cards
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id            path   string true  "Collection ID"
+// @Param       card_id       path   string true  "Scryfall ID"
+// @Param       zone          query  string false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish        query  string false "Отделка карты, по умолчанию nonfoil"
+// @Param       If-None-Match header string false "ETag коллекции, которая уже есть у клиента"
+// @Success     200 {object} cards.CardEntry
+// @Success     304 "Not Modified"
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id} [get]
+func (cc CardsController) GetCardInCollection(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	entry, respErr := cc.cardsService.GetCardInCollection(userId, ctx.Param("id"), key)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	if notModified(ctx, entry.CollectionVersion) {
+		return
+	}
+
+	out := cards.CardEntry{
+		ScryfallID: entry.Card.ScryfallID,
+		Name:       entry.Card.Name,
+		CardUrl:    entry.Card.CardUrl,
+		Count:      entry.Card.Count,
+		Notes:      entry.Card.Notes,
+		Condition:  entry.Card.Condition,
+		Zone:       entry.Card.Zone,
+		Finish:     entry.Card.Finish,
+		Location:   toLocation(entry.Location),
+		AddedAt:    entry.Card.AddedAt,
+		UpdatedAt:  entry.Card.UpdatedAt,
+		Variants:   make([]cards.CardVariant, 0, len(entry.Variants)),
+		Catalog:    toCatalogCard(entry.Printing),
+	}
+	for _, a := range entry.Card.Acquisitions {
+		out.Acquisitions = append(out.Acquisitions, toAcquisition(a))
+	}
+	for _, v := range entry.Variants {
+		out.Variants = append(out.Variants, toCardVariant(v))
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Find owned cards
+// @Description Найти карту во всех коллекциях пользователя, кроме вишлистов: где лежат копии и сколько их по печатям.
+// @Description Нужен ровно один параметр: scryfall_id ищет эту печать, oracle_id и name — все печати карты
+// @Tags        Cards
+// @Security    BearerAuth
+// @Produce     json
+// @Param       scryfall_id query string false "Scryfall ID печати"
+// @Param       oracle_id   query string false "Oracle ID карты"
+// @Param       name        query string false "Название карты или ее лицевой стороны"
+// @Success     200 {object} cards.OwnedCards
+// @Failure     400,401 {object} collections.ErrorResponse
+// @Router      /cards/owned [get]
+func (cc CardsController) FindOwnedCards(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query cards.OwnedCardsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	found, respErr := cc.cardsService.FindOwnedCards(userId, &models.OwnedCardQuery{
+		ScryfallID: query.ScryfallID,
+		OracleID:   query.OracleID,
+		Name:       query.Name,
+	})
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := cards.OwnedCards{Collections: make([]cards.OwnedCollection, 0, len(found))}
+	for _, owned := range found {
+		col := cards.OwnedCollection{
+			CollectionID:   owned.Collection.ID,
+			CollectionName: owned.Collection.Name,
+			Kind:           owned.Collection.Kind,
+			Variants:       make([]cards.CardVariant, 0, len(owned.Cards)),
+		}
+		for _, entry := range owned.Cards {
+			col.Count += entry.Card.Count
+			col.Variants = append(col.Variants, toCardVariant(entry))
+		}
+		out.Count += col.Count
+		out.Collections = append(out.Collections, col)
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Add card to collection
+// @Description Добавить карту в коллекцию; если она уже есть, количество увеличивается.
+// @Description С reserve_from коллекция должна быть колодой, а добавленные копии резервируются из указанной коллекции
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string               true  "Collection ID"
+// @Param       input           body   cards.AddCardRequest true  "Карта и количество копий"
+// @Param       X-Change-Source header string               false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string               false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     201 "Created"
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards [post]
 func (cc CardsController) AddCardToCollection(ctx *gin.Context) {
-	collectionId := ctx.Param("id")
-	var card models.Card
-	if err := ctx.ShouldBindJSON(&card); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, err)
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req cards.AddCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	respErr := cc.cardsService.AddCardToCollection(collectionId, &card)
+	card := &models.Card{
+		ScryfallID: req.ScryfallID,
+		Name:       req.Name,
+		CardUrl:    req.CardUrl,
+		Count:      req.Count,
+		Zone:       req.Zone,
+		Finish:     req.Finish,
+	}
+	respErr = cc.cardsService.AddCardToCollection(userId, changeSource(ctx), ctx.Param("id"), card, req.ReserveFrom, ifVersion)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
@@ -55,17 +241,55 @@ func (cc CardsController) AddCardToCollection(ctx *gin.Context) {
 	ctx.Status(http.StatusCreated)
 }
 
-func (cc CardsController) SetCardCountInCollection(ctx *gin.Context) {
-	collectionId := ctx.Param("id")
-	scryfallId := ctx.Param("card_id")
-	var card models.Card
-	if err := ctx.ShouldBindJSON(&card); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, err)
+// @Summary     Update card in collection
+// @Description Частично обновить карту в коллекции: количество, заметку, состояние или зону колоды
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                  true  "Collection ID"
+// @Param       card_id         path   string                  true  "Scryfall ID"
+// @Param       zone            query  string                  false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish          query  string                  false "Отделка карты, по умолчанию nonfoil"
+// @Param       input           body   cards.UpdateCardRequest true  "Изменяемые поля"
+// @Param       X-Change-Source header string                  false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string                  false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     204 "No Content"
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id} [patch]
+func (cc CardsController) UpdateCardInCollection(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req cards.UpdateCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	card.ScryfallID = scryfallId
-	respErr := cc.cardsService.SetCardCountInCollection(collectionId, &card)
+	update := &models.CardUpdate{
+		Key:       key,
+		Count:     req.Count,
+		Notes:     req.Notes,
+		Condition: req.Condition,
+		Zone:      req.Zone,
+		Finish:    req.Finish,
+	}
+	respErr = cc.cardsService.UpdateCardInCollection(userId, changeSource(ctx), ctx.Param("id"), update, ifVersion)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
@@ -74,11 +298,403 @@ func (cc CardsController) SetCardCountInCollection(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// @Summary     Delete card from collection
+// @Description Удалить карту из коллекции
+// @Tags        Cards
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id              path   string true  "Collection ID"
+// @Param       card_id         path   string true  "Scryfall ID"
+// @Param       zone            query  string false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish          query  string false "Отделка карты, по умолчанию nonfoil"
+// @Param       X-Change-Source header string false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     204 "No Content"
+// @Failure     400,401,404,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id} [delete]
 func (cc CardsController) DeleteCardFromCollection(ctx *gin.Context) {
-	collectionId := ctx.Param("id")
-	scryfallId := ctx.Param("card_id")
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	card := &models.Card{ScryfallID: key.ScryfallID, Zone: key.Zone, Finish: key.Finish}
+	respErr = cc.cardsService.DeleteCardFromCollection(userId, changeSource(ctx), ctx.Param("id"), card, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Transfer card to another collection
+// @Description Перенести или скопировать копии карты в другую коллекцию пользователя одной транзакцией.
+// @Description Зона сохраняется только при переносе в колоду, в других коллекциях у карт зон нет
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                    true  "Collection ID"
+// @Param       card_id         path   string                    true  "Scryfall ID"
+// @Param       zone            query  string                    false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish          query  string                    false "Отделка карты, по умолчанию nonfoil"
+// @Param       input           body   cards.TransferCardRequest true  "Целевая коллекция, количество и режим"
+// @Param       X-Change-Source header string                    false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string                    false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     200 {object} cards.TransferCardResponse
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id}/transfer [post]
+func (cc CardsController) TransferCard(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req cards.TransferCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	result, respErr := cc.cardsService.TransferCard(userId, changeSource(ctx), ctx.Param("id"), req.ToCollectionID, key, req.Count, req.Mode == cards.TransferModeCopy, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cards.TransferCardResponse{
+		FromCount: result.FromCount,
+		ToCount:   result.ToCount,
+	})
+}
+
+// @Summary     Batch card operations
+// @Description Выполнить пакет операций add, set и remove над картами коллекции одной записью в базу
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                  true  "Collection ID"
+// @Param       input           body   cards.BatchCardsRequest true  "Операции и режим"
+// @Param       X-Change-Source header string                  false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string                  false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     200 {object} cards.BatchCardsResponse
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards:batch [post]
+func (cc CardsController) BatchCards(ctx *gin.Context) {
+	// gin reads ":batch" in "cards:batch" as a path parameter holding the rest of the segment.
+	if ctx.Param("batch") != ":batch" {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, collections.ErrorResponse{Message: "404 page not found", Status: http.StatusNotFound})
+		return
+	}
+
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req cards.BatchCardsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ops := make([]*models.CardOperation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		ops = append(ops, &models.CardOperation{
+			Op: op.Op,
+			Card: &models.Card{
+				ScryfallID: op.ScryfallID,
+				Name:       op.Name,
+				CardUrl:    op.CardUrl,
+				Count:      op.Count,
+				Zone:       op.Zone,
+				Finish:     op.Finish,
+			},
+		})
+	}
+
+	batch, respErr := cc.cardsService.ApplyCardBatch(userId, changeSource(ctx), ctx.Param("id"), req.Mode != cards.BatchModeBestEffort, ops, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := cards.BatchCardsResponse{
+		Committed: batch.Committed,
+		Results:   make([]cards.CardOperationResult, 0, len(batch.Results)),
+	}
+	for i, r := range batch.Results {
+		result := cards.CardOperationResult{
+			Index:      i,
+			Op:         r.Op,
+			ScryfallID: r.ScryfallID,
+			Zone:       r.Zone,
+			Finish:     r.Finish,
+			Status:     http.StatusOK,
+			Count:      r.Count,
+		}
+		if r.Err != nil {
+			result.Status = r.Err.Status
+			result.Error = r.Err.Message
+			out.Failed++
+		} else {
+			out.Applied++
+		}
+		out.Results = append(out.Results, result)
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     List card history
+// @Description Получить историю изменений карт коллекции, от новых записей к старым
+// @Tags        Cards
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id          path  string true  "Collection ID"
+// @Param       scryfall_id query string false "Только изменения этой карты"
+// @Param       before      query string false "ID последней записи предыдущей страницы"
+// @Param       limit       query int    false "Сколько записей вернуть, до 200 (по умолчанию 50)"
+// @Success     200 {array} cards.CardHistoryEntry
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/history [get]
+func (cc CardsController) ListCardHistory(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query cards.CardHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, respErr := cc.cardsService.ListCardHistory(userId, ctx.Param("id"), query.ScryfallID, query.Before, query.Limit)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toHistoryEntries(list))
+}
+
+// @Summary     Undo card change
+// @Description Отменить изменение из истории коллекции или все изменения того же запроса.
+// @Description Отмена записывается в историю как новые записи; одно изменение нельзя отменить дважды
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                  true  "Collection ID"
+// @Param       entry_id        path   string                  true  "History entry ID"
+// @Param       X-Change-Source header string                  false "Источник изменения: api, import, bot или trade"
+// @Param       input           body   cards.UndoChangeRequest false "Отменить весь запрос"
+// @Success     200 {object} cards.UndoChangeResponse
+// @Failure     400,401,404,409 {object} collections.ErrorResponse
+// @Router      /collections/{id}/history/{entry_id}/undo [post]
+func (cc CardsController) UndoCardChange(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	// The body is optional: without it only the entry itself is undone.
+	var req cards.UndoChangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	undo, respErr := cc.cardsService.UndoCardChange(userId, changeSource(ctx), ctx.Param("id"), ctx.Param("entry_id"), req.Batch)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cards.UndoChangeResponse{Entries: toHistoryEntries(undo)})
+}
+
+// @Summary     Add card acquisition
+// @Description Записать покупку копий карты коллекции: дату, количество, цену копии, валюту, продавца и статус.
+// @Description По покупкам считается себестоимость карт в отчете о прибыли и убытках. У карт вишлиста покупок нет
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string                   true  "Collection ID"
+// @Param       card_id  path   string                   true  "Scryfall ID"
+// @Param       zone     query  string                   false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish   query  string                   false "Отделка карты, по умолчанию nonfoil"
+// @Param       input    body   cards.AcquisitionRequest true  "Покупка"
+// @Param       If-Match header string                   false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     201 {object} cards.Acquisition
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id}/acquisitions [post]
+func (cc CardsController) AddAcquisition(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req cards.AcquisitionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	acquisition := &models.Acquisition{
+		Date:     req.Date,
+		Count:    req.Count,
+		Price:    req.Price,
+		Currency: req.Currency,
+		Vendor:   req.Vendor,
+		Status:   req.Status,
+	}
+	acquisition, respErr = cc.cardsService.AddAcquisition(userId, ctx.Param("id"), key, acquisition, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
 
-	respErr := cc.cardsService.DeleteCardFromCollection(collectionId, &models.Card{ScryfallID: scryfallId})
+	ctx.JSON(http.StatusCreated, toAcquisition(acquisition))
+}
+
+// @Summary     Update card acquisition
+// @Description Изменить покупку копий карты, например отметить, что заказанные копии пришли
+// @Tags        Cards
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id             path   string                         true  "Collection ID"
+// @Param       card_id        path   string                         true  "Scryfall ID"
+// @Param       acquisition_id path   string                         true  "Acquisition ID"
+// @Param       zone           query  string                         false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish         query  string                         false "Отделка карты, по умолчанию nonfoil"
+// @Param       input          body   cards.UpdateAcquisitionRequest true  "Поля для изменения"
+// @Param       If-Match       header string                         false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     200 {object} cards.Acquisition
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id}/acquisitions/{acquisition_id} [patch]
+func (cc CardsController) UpdateAcquisition(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req cards.UpdateAcquisitionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	update := &models.AcquisitionUpdate{
+		Date:     req.Date,
+		Count:    req.Count,
+		Price:    req.Price,
+		Currency: req.Currency,
+		Vendor:   req.Vendor,
+		Status:   req.Status,
+	}
+	acquisition, respErr := cc.cardsService.UpdateAcquisition(userId, ctx.Param("id"), key, ctx.Param("acquisition_id"), update, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toAcquisition(acquisition))
+}
+
+// @Summary     Delete card acquisition
+// @Description Удалить покупку копий карты
+// @Tags        Cards
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id             path   string true  "Collection ID"
+// @Param       card_id        path   string true  "Scryfall ID"
+// @Param       acquisition_id path   string true  "Acquisition ID"
+// @Param       zone           query  string false "Зона колоды карты, по умолчанию mainboard"
+// @Param       finish         query  string false "Отделка карты, по умолчанию nonfoil"
+// @Param       If-Match       header string false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     204 "No Content"
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/cards/{card_id}/acquisitions/{acquisition_id} [delete]
+func (cc CardsController) DeleteAcquisition(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	key, respErr := cardKey(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	respErr = cc.cardsService.DeleteAcquisition(userId, ctx.Param("id"), key, ctx.Param("acquisition_id"), ifVersion)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
@@ -86,3 +702,120 @@ func (cc CardsController) DeleteCardFromCollection(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// cardKey reads which card of a collection a request is about: the printing
+// from the path and its deck zone and finish from the query.
+func cardKey(ctx *gin.Context) (models.CardKey, *models.ResponseErr) {
+	var query cards.CardEntryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return models.CardKey{}, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	return models.CardKey{ScryfallID: ctx.Param("card_id"), Zone: query.Zone, Finish: query.Finish}, nil
+}
+
+// changeSource tells where a change of cards comes from; services reject unknown sources.
+func changeSource(ctx *gin.Context) string {
+	return ctx.GetHeader(cards.ChangeSourceHeader)
+}
+
+func toHistoryEntries(list []*models.CardHistoryEntry) []cards.CardHistoryEntry {
+	out := make([]cards.CardHistoryEntry, 0, len(list))
+	for _, e := range list {
+		entry := cards.CardHistoryEntry{
+			ID:           e.ObjectID.Hex(),
+			BatchID:      e.BatchID.Hex(),
+			UserID:       e.UserID.Hex(),
+			CollectionID: e.CollectionID.Hex(),
+			ScryfallID:   e.ScryfallID,
+			Name:         e.Name,
+			Zone:         e.Zone,
+			Finish:       e.Finish,
+			Delta:        e.Delta,
+			Action:       e.Action,
+			Source:       e.Source,
+			CreatedAt:    e.CreatedAt,
+		}
+		if !e.RelatedCollectionID.IsZero() {
+			entry.RelatedCollectionID = e.RelatedCollectionID.Hex()
+		}
+		if !e.UndoOf.IsZero() {
+			entry.UndoOf = e.UndoOf.Hex()
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func toCard(c *models.Card) cards.Card {
+	card := cards.Card{
+		ScryfallID: c.ScryfallID,
+		Name:       c.Name,
+		CardUrl:    c.CardUrl,
+		Count:      c.Count,
+		Notes:      c.Notes,
+		Condition:  c.Condition,
+		Zone:       c.Zone,
+		Finish:     c.Finish,
+		Location:   toLocation(c.Location),
+		AddedAt:    c.AddedAt,
+	}
+	for _, a := range c.Acquisitions {
+		card.Acquisitions = append(card.Acquisitions, toAcquisition(a))
+	}
+	return card
+}
+
+func toAcquisition(a *models.Acquisition) cards.Acquisition {
+	return cards.Acquisition{
+		ID:       a.ID,
+		Date:     a.Date,
+		Count:    a.Count,
+		Price:    a.Price,
+		Currency: a.Currency,
+		Vendor:   a.Vendor,
+		Status:   a.Status,
+	}
+}
+
+func toCardVariant(entry *models.CardEntry) cards.CardVariant {
+	variant := cards.CardVariant{
+		ScryfallID: entry.Card.ScryfallID,
+		Name:       entry.Card.Name,
+		Count:      entry.Card.Count,
+		Condition:  entry.Card.Condition,
+		Zone:       entry.Card.Zone,
+		Finish:     entry.Card.Finish,
+		Location:   toLocation(entry.Location),
+	}
+	if entry.Printing != nil {
+		variant.Set = entry.Printing.Set
+		variant.CollectorNumber = entry.Printing.CollectorNumber
+	}
+	return variant
+}
+
+func toCatalogCard(printing *catalog.Card) *cards.CatalogCard {
+	if printing == nil {
+		return nil
+	}
+
+	return &cards.CatalogCard{
+		OracleID:        printing.OracleID,
+		Set:             printing.Set,
+		SetName:         printing.SetName,
+		CollectorNumber: printing.CollectorNumber,
+		Rarity:          printing.Rarity,
+		Lang:            printing.Lang,
+		ManaCost:        printing.ManaCost,
+		TypeLine:        printing.TypeLine,
+		OracleText:      printing.OracleText,
+		Colors:          printing.Colors,
+		ColorIdentity:   printing.ColorIdentity,
+		Finishes:        printing.Finishes,
+		ImageURL:        printing.ImageURL(),
+		ScryfallURI:     printing.ScryfallURI,
+	}
+}
@@ -20,11 +20,17 @@ type CollectionsController struct {
 }
 
 type CollectionsServicer interface {
-	AllUsersCollections(userId string) ([]*models.UserCollectionRef, *models.ResponseErr)
+	ListCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr)
+	GetCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr)
 	CreateCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr)
-	RenameCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr)
-	DeleteCollection(collection *models.Collection) *models.ResponseErr
+	UpdateCollection(update *models.CollectionUpdate) (*models.Collection, *models.ResponseErr)
+	DeleteCollection(collection *models.Collection, ifVersion *int64) *models.ResponseErr
+	ListTrash(userId string) ([]*models.CollectionSummary, *models.ResponseErr)
+	RestoreCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr)
 	GetCollectionByName(collection *models.Collection) (*models.Collection, *models.ResponseErr)
+	MergeCollections(userId, source, targetId, sourceId string, keepSource bool, ifVersion *int64) (*models.Collection, *models.ResponseErr)
+	DuplicateCollection(userId, collectionId, name string) (*models.Collection, *models.ResponseErr)
+	SplitCollection(userId, source, collectionId, name string, filter *models.CardFilter, ifVersion *int64) (*models.Collection, *models.ResponseErr)
 }
 
 // NewCollectionsController создает контроллер коллекций
@@ -36,7 +42,8 @@ func NewCollectionsController(collectionsService CollectionsServicer, log logger
 }
 
 // @Summary     Get user's collections
-// @Description Получить список коллекций текущего пользователя
+// @Description Получить список коллекций текущего пользователя с метаданными и количеством карт.
+// @Description Закрепленные коллекции идут первыми, затем по sort_order и по дате создания
 // @Tags        Collections
 // @Security    BearerAuth
 // @Produce     json
@@ -53,22 +60,22 @@ func (cc CollectionsController) GetCollections(ctx *gin.Context) {
 		return
 	}
 
-	list, respErr := cc.collectionsService.AllUsersCollections(userId)
+	list, respErr := cc.collectionsService.ListCollections(userId)
 	if respErr != nil {
 		cc.log.Error("Failed to get user's collections", logger.Error(respErr))
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
 
-	var out []collections.Collection
+	out := make([]collections.Collection, 0, len(list))
 	for _, c := range list {
-		out = append(out, collections.Collection{ID: c.ID, Name: c.Name})
+		out = append(out, toCollection(c))
 	}
 	ctx.JSON(http.StatusOK, out)
 }
 
 // @Summary     Create new collection
-// @Description Создать новую коллекцию с указанным именем
+// @Description Создать новую коллекцию с указанным именем и метаданными
 // @Tags        Collections
 // @Security    BearerAuth
 // @Accept      json
@@ -100,37 +107,80 @@ func (cc CollectionsController) CreateCollection(ctx *gin.Context) {
 		return
 	}
 
-	model := &models.Collection{UserID: userObjectId, Name: req.Name}
+	model := &models.Collection{
+		UserID:      userObjectId,
+		Name:        req.Name,
+		Description: req.Description,
+		Kind:        req.Kind,
+		Format:      req.Format,
+		Tags:        req.Tags,
+	}
 	created, respErr := cc.collectionsService.CreateCollection(model)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
 
-	out := collections.Collection{ID: created.ID, Name: created.Name}
-	ctx.JSON(http.StatusCreated, out)
+	ctx.JSON(http.StatusCreated, toCollection(models.Summarize(created)))
 }
 
-// @Summary     Rename collection
-// @Description Переименовать коллекцию по ID
+// @Summary     Get collection
+// @Description Получить коллекцию по ID. Версия коллекции приходит в ETag;
+// @Description с If-None-Match ответ 304 без тела, если коллекция не менялась
 // @Tags        Collections
 // @Security    BearerAuth
-// @Accept      json
 // @Produce     json
-// @Param       id   path string                         true "Collection ID"
-// @Param       input body collections.RenameCollectionRequest true "Новое имя коллекции"
-// @Success     204 {object} collections.Collection
+// @Param       id            path   string true  "Collection ID"
+// @Param       If-None-Match header string false "ETag коллекции, которая уже есть у клиента"
+// @Success     200 {object} collections.Collection
+// @Success     304 "Not Modified"
 // @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id} [get]
+func (cc CollectionsController) GetCollection(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	collection, respErr := cc.collectionsService.GetCollection(userId, ctx.Param("id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	if notModified(ctx, collection.Version) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCollection(models.Summarize(collection)))
+}
+
+// @Summary     Update collection
+// @Description Изменить имя и метаданные коллекции по ID, непереданные поля не меняются
+// @Tags        Collections
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string                              true  "Collection ID"
+// @Param       input    body   collections.UpdateCollectionRequest true  "Изменяемые поля"
+// @Param       If-Match header string                              false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     204 "No Content"
+// @Failure     400,401,404,412 {object} collections.ErrorResponse
 // @Router      /collections/{id} [patch]
-func (cc CollectionsController) RenameCollection(ctx *gin.Context) {
+func (cc CollectionsController) UpdateCollection(ctx *gin.Context) {
 	userId, respErr := getUserFromCtx(ctx)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
 
 	id := ctx.Param("id")
-	var req collections.RenameCollectionRequest
+	var req collections.UpdateCollectionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
 		return
@@ -148,25 +198,44 @@ func (cc CollectionsController) RenameCollection(ctx *gin.Context) {
 		return
 	}
 
-	model := &models.Collection{ID: id, UserID: userObjectId, Name: req.Name}
-	updated, respErr := cc.collectionsService.RenameCollection(model)
+	location, respErr := fromLocation(req.Location)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	update := &models.CollectionUpdate{
+		ID:          id,
+		UserID:      userObjectId,
+		Name:        req.Name,
+		Description: req.Description,
+		Kind:        req.Kind,
+		Format:      req.Format,
+		Tags:        req.Tags,
+		CoverCard:   req.CoverCard,
+		Pinned:      req.Pinned,
+		SortOrder:   req.SortOrder,
+		Location:    location,
+		IfVersion:   ifVersion,
+	}
+	_, respErr = cc.collectionsService.UpdateCollection(update)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
 
-	out := collections.Collection{ID: updated.ID, Name: updated.Name}
-	ctx.JSON(http.StatusNoContent, out)
+	ctx.Status(http.StatusNoContent)
 }
 
 // @Summary     Delete collection
-// @Description Удалить коллекцию по ID
+// @Description Переместить коллекцию в корзину по ID. Ее можно восстановить, пока она не удалена окончательно
 // @Tags        Collections
 // @Security    BearerAuth
 // @Produce     json
-// @Param       id path string true "Collection ID"
+// @Param       id       path   string true  "Collection ID"
+// @Param       If-Match header string false "ETag коллекции, изменения которой ожидает клиент"
 // @Success     204 "No Content"
-// @Failure     401,404 {object} collections.ErrorResponse
+// @Failure     400,401,404,412 {object} collections.ErrorResponse
 // @Router      /collections/{id} [delete]
 func (cc CollectionsController) DeleteCollection(ctx *gin.Context) {
 	userId, respErr := getUserFromCtx(ctx)
@@ -174,6 +243,11 @@ func (cc CollectionsController) DeleteCollection(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
 
 	if userId == "" {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, collections.ErrorResponse{Message: "Invalid user ID"})
@@ -186,7 +260,7 @@ func (cc CollectionsController) DeleteCollection(ctx *gin.Context) {
 	}
 
 	id := ctx.Param("id")
-	respErr = cc.collectionsService.DeleteCollection(&models.Collection{ID: id, UserID: userObjectId})
+	respErr = cc.collectionsService.DeleteCollection(&models.Collection{ID: id, UserID: userObjectId}, ifVersion)
 	if respErr != nil {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
@@ -195,13 +269,68 @@ func (cc CollectionsController) DeleteCollection(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// @Summary     List deleted collections
+// @Description Получить коллекции текущего пользователя в корзине, последние удаленные первыми
+// @Tags        Collections
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {array} collections.DeletedCollection
+// @Failure     401 {object} collections.ErrorResponse
+// @Router      /collections/trash [get]
+func (cc CollectionsController) ListTrash(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	list, respErr := cc.collectionsService.ListTrash(userId)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]collections.DeletedCollection, 0, len(list))
+	for _, c := range list {
+		out = append(out, collections.DeletedCollection{Collection: toCollection(c), DeletedAt: *c.DeletedAt})
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Restore collection
+// @Description Вернуть коллекцию из корзины со всеми картами
+// @Tags        Collections
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Collection ID"
+// @Success     200 {object} collections.Collection
+// @Failure     400,401,404,409 {object} collections.ErrorResponse
+// @Router      /collections/{id}/restore [post]
+func (cc CollectionsController) RestoreCollection(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	restored, respErr := cc.collectionsService.RestoreCollection(userId, ctx.Param("id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCollection(models.Summarize(restored)))
+}
+
 // @Summary     Get collection by name
 // @Description Получить коллекцию по имени
 // @Tags        Collections
 // @Security    BearerAuth
 // @Produce     json
-// @Param       name path string true "Collection name"
+// @Param       name          path   string true  "Collection name"
+// @Param       If-None-Match header string false "ETag коллекции, которая уже есть у клиента"
 // @Success     200 {object} collections.Collection
+// @Success     304 "Not Modified"
 // @Failure     401,404 {object} collections.ErrorResponse
 // @Router      /collections/{name} [get]
 func (cc CollectionsController) GetCollectionByName(ctx *gin.Context) {
@@ -228,9 +357,151 @@ func (cc CollectionsController) GetCollectionByName(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(respErr.Status, respErr)
 		return
 	}
+	if notModified(ctx, collection.Version) {
+		return
+	}
 
-	out := collections.Collection{ID: collection.ID, Name: collection.Name}
-	ctx.JSON(http.StatusOK, out)
+	ctx.JSON(http.StatusOK, toCollection(models.Summarize(collection)))
+}
+
+// @Summary     Merge collections
+// @Description Добавить в коллекцию все карты другой коллекции, суммируя количество одинаковых карт
+// @Tags        Collections
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                             true  "Collection ID"
+// @Param       input           body   collections.MergeCollectionRequest true  "Исходная коллекция"
+// @Param       X-Change-Source header string                             false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string                             false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     200 {object} collections.Collection
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/merge [post]
+func (cc CollectionsController) MergeCollections(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req collections.MergeCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	merged, respErr := cc.collectionsService.MergeCollections(userId, changeSource(ctx), ctx.Param("id"), req.SourceCollectionID, req.KeepSource, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCollection(models.Summarize(merged)))
+}
+
+// @Summary     Duplicate collection
+// @Description Создать копию коллекции со всеми картами под новым именем
+// @Tags        Collections
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id    path string                                 true "Collection ID"
+// @Param       input body collections.DuplicateCollectionRequest true "Имя копии"
+// @Success     201 {object} collections.Collection
+// @Failure     400,401,404,409 {object} collections.ErrorResponse
+// @Router      /collections/{id}/duplicate [post]
+func (cc CollectionsController) DuplicateCollection(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req collections.DuplicateCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	created, respErr := cc.collectionsService.DuplicateCollection(userId, ctx.Param("id"), req.Name)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toCollection(models.Summarize(created)))
+}
+
+// @Summary     Split collection
+// @Description Перенести карты, подходящие под фильтр, в новую коллекцию
+// @Tags        Collections
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                             true  "Collection ID"
+// @Param       input           body   collections.SplitCollectionRequest true  "Имя новой коллекции и фильтр"
+// @Param       X-Change-Source header string                             false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string                             false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     201 {object} collections.Collection
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/split [post]
+func (cc CollectionsController) SplitCollection(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req collections.SplitCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	filter := &models.CardFilter{
+		ScryfallIDs: req.Filter.ScryfallIDs,
+		Name:        req.Filter.Name,
+		Sets:        req.Filter.Sets,
+		Rarities:    req.Filter.Rarities,
+		Conditions:  req.Filter.Conditions,
+	}
+	created, respErr := cc.collectionsService.SplitCollection(userId, changeSource(ctx), ctx.Param("id"), req.Name, filter, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toCollection(models.Summarize(created)))
+}
+
+func toCollection(c *models.CollectionSummary) collections.Collection {
+	return collections.Collection{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Kind:        c.Kind,
+		Format:      c.Format,
+		Tags:        c.Tags,
+		CoverCard:   c.CoverCard,
+		Pinned:      c.Pinned,
+		SortOrder:   c.SortOrder,
+		CardCount:   c.CardCount,
+		UniqueCards: c.UniqueCards,
+		Version:     c.Version,
+		Location:    toLocation(c.Location),
+	}
 }
 
 func getUserFromCtx(ctx *gin.Context) (string, *models.ResponseErr) {
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// DecksController отвечает за проверку колод
+// @Tags Decks
+// @BasePath /
+type DecksController struct {
+	decksService DecksServicer
+	log          logger.Logger
+}
+
+type DecksServicer interface {
+	ValidateDeck(userId, collectionId string) (*models.DeckReport, *models.ResponseErr)
+	CheckBuildability(userId, collectionId, decklist string, anyPrinting bool) (*models.Buildability, *models.ResponseErr)
+}
+
+// NewDecksController создает контроллер колод
+func NewDecksController(decksService DecksServicer, log logger.Logger) *DecksController {
+	return &DecksController{
+		decksService: decksService,
+		log:          log.With(logger.String("controller", "decks")),
+	}
+}
+
+// @Summary     Validate deck
+// @Description Проверить колоду по правилам ее формата: размер колоды и сайдборда, лимит копий, синглтон,
+// @Description командиры и их цветовая идентичность, запрещенные и ограниченные карты.
+// @Description Коллекция должна быть колодой (kind deck) с указанным форматом
+// @Tags        Decks
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Collection ID"
+// @Success     200 {object} collections.DeckValidation
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/validation [get]
+func (dc DecksController) ValidateDeck(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	report, respErr := dc.decksService.ValidateDeck(userId, ctx.Param("id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := collections.DeckValidation{
+		Format:        report.Format,
+		Legal:         report.Legal(),
+		Mainboard:     report.Mainboard,
+		Sideboard:     report.Sideboard,
+		Commanders:    report.Commanders,
+		ColorIdentity: report.ColorIdentity,
+		Problems:      make([]collections.DeckProblem, 0, len(report.Problems)),
+		Unchecked:     report.Unchecked,
+	}
+	for _, p := range report.Problems {
+		out.Problems = append(out.Problems, collections.DeckProblem{Rule: p.Rule, Message: p.Message, Cards: p.Cards})
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Check deck buildability
+// @Description Проверить, можно ли собрать колоду из карт других коллекций пользователя: какие карты есть и в каких
+// @Description коллекциях, каких не хватает и сколько они стоят. Колода — коллекция пользователя или вставленный список
+// @Tags        Decks
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       input body collections.BuildabilityRequest true "Колода и нужна ли точная печать"
+// @Success     200 {object} collections.DeckBuildability
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /decks/buildability [post]
+func (dc DecksController) CheckBuildability(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req collections.BuildabilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	report, respErr := dc.decksService.CheckBuildability(userId, req.CollectionID, req.Decklist, req.AnyPrinting)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := collections.DeckBuildability{
+		AnyPrinting:   report.AnyPrinting,
+		Needed:        report.Needed(),
+		Covered:       report.Covered(),
+		EstimatedCost: report.EstimatedCost,
+		Currency:      "usd",
+		Unpriced:      report.Unpriced,
+		Cards:         make([]collections.BuildabilityCard, 0, len(report.Cards)),
+	}
+	out.Missing = out.Needed - out.Covered
+	out.Buildable = out.Missing == 0
+	for _, c := range report.Cards {
+		card := collections.BuildabilityCard{
+			ScryfallID: c.ScryfallID,
+			Name:       c.Name,
+			Needed:     c.Needed,
+			Covered:    c.Covered(),
+			Missing:    c.Missing(),
+			Price:      c.Price,
+			Sources:    make([]collections.CardSource, 0, len(c.Sources)),
+		}
+		for _, s := range c.Sources {
+			card.Sources = append(card.Sources, collections.CardSource{
+				CollectionID:   s.CollectionID,
+				CollectionName: s.CollectionName,
+				ScryfallID:     s.ScryfallID,
+				Count:          s.Count,
+				Reserved:       s.Reserved,
+				DeckID:         s.DeckID,
+				DeckName:       s.DeckName,
+			})
+		}
+		out.Cards = append(out.Cards, card)
+	}
+	ctx.JSON(http.StatusOK, out)
+}
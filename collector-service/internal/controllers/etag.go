@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// etag is the entity tag of a version of a collection.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// notModified sends the version of a collection as the ETag of the response.
+// If the client already has this version, as told by If-None-Match, it answers
+// 304 and returns true; the caller must not write a body then.
+func notModified(ctx *gin.Context, version int64) bool {
+	tag := etag(version)
+	ctx.Header("ETag", tag)
+
+	for _, candidate := range strings.Split(ctx.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			ctx.AbortWithStatus(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch reads from If-Match the version of a collection a write expects.
+// Without the header, or with "*", any version will do and it returns nil.
+func ifMatch(ctx *gin.Context) (*int64, *models.ResponseErr) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	value, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		value, ok = strings.CutSuffix(value, `"`)
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if !ok || err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "If-Match must be the ETag of a collection",
+		}
+	}
+	return &version, nil
+}
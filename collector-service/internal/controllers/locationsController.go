@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// LocationsController отвечает за места хранения карт: коробки и альбомы
+// @Tags Locations
+// @BasePath /
+type LocationsController struct {
+	locationsService LocationsServicer
+	log              logger.Logger
+}
+
+type LocationsServicer interface {
+	CreateLocation(location *models.StorageLocation) (*models.StorageLocation, *models.ResponseErr)
+	ListLocations(userId string) ([]*models.StorageLocation, *models.ResponseErr)
+	DeleteLocation(userId, locationId string) *models.ResponseErr
+	ListLocationCards(userId, locationId, section string, page int) (*models.StorageLocation, []*models.LocatedCard, *models.ResponseErr)
+	RelocateCards(userId, source, collectionId string, scryfallIds []string, location *models.Location, ifVersion *int64) (int, *models.ResponseErr)
+}
+
+// NewLocationsController создает контроллер мест хранения
+func NewLocationsController(locationsService LocationsServicer, log logger.Logger) *LocationsController {
+	return &LocationsController{
+		locationsService: locationsService,
+		log:              log.With(logger.String("controller", "locations")),
+	}
+}
+
+// @Summary     Get storage locations
+// @Description Получить коробки и альбомы пользователя по имени
+// @Tags        Locations
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {array} locations.StorageLocation
+// @Failure     401 {object} collections.ErrorResponse
+// @Router      /locations [get]
+func (lc LocationsController) ListLocations(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	list, respErr := lc.locationsService.ListLocations(userId)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]locations.StorageLocation, 0, len(list))
+	for _, l := range list {
+		out = append(out, toStorageLocation(l))
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Create storage location
+// @Description Создать коробку или альбом. Имена мест хранения у пользователя не повторяются
+// @Tags        Locations
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       input body locations.CreateLocationRequest true "Имя и вид места хранения"
+// @Success     201 {object} locations.StorageLocation
+// @Failure     400,401,409 {object} collections.ErrorResponse
+// @Router      /locations [post]
+func (lc LocationsController) CreateLocation(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req locations.CreateLocationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	userObjectId, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, collections.ErrorResponse{Message: "Invalid user ID format"})
+		return
+	}
+
+	created, respErr := lc.locationsService.CreateLocation(&models.StorageLocation{
+		UserID:      userObjectId,
+		Name:        req.Name,
+		Kind:        req.Kind,
+		Description: req.Description,
+	})
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toStorageLocation(created))
+}
+
+// @Summary     Delete storage location
+// @Description Удалить коробку или альбом. Место хранения, в котором лежат коллекции или карты, удалить нельзя
+// @Tags        Locations
+// @Security    BearerAuth
+// @Produce     json
+// @Param       location_id path string true "Location ID"
+// @Success     204 "No Content"
+// @Failure     400,401,404,409 {object} collections.ErrorResponse
+// @Router      /locations/{location_id} [delete]
+func (lc LocationsController) DeleteLocation(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	if respErr := lc.locationsService.DeleteLocation(userId, ctx.Param("location_id")); respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Get storage location cards
+// @Description Получить карты, которые лежат в коробке или альбоме, по разделам, страницам и ячейкам.
+// @Description Карты без своего места лежат там же, где их коллекция
+// @Tags        Locations
+// @Security    BearerAuth
+// @Produce     json
+// @Param       location_id path  string true  "Location ID"
+// @Param       section     query string false "Только карты раздела"
+// @Param       page        query int    false "Только карты страницы альбома"
+// @Success     200 {object} locations.LocationCards
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /locations/{location_id}/cards [get]
+func (lc LocationsController) ListLocationCards(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query locations.LocationCardsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	storage, located, respErr := lc.locationsService.ListLocationCards(userId, ctx.Param("location_id"), query.Section, query.Page)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := locations.LocationCards{
+		Location: toStorageLocation(storage),
+		Cards:    make([]locations.LocatedCard, 0, len(located)),
+	}
+	for _, l := range located {
+		out.Count += l.Card.Count
+		out.Cards = append(out.Cards, locations.LocatedCard{
+			CollectionID:   l.Collection.ID,
+			CollectionName: l.Collection.Name,
+			ScryfallID:     l.Card.ScryfallID,
+			Name:           l.Card.Name,
+			Count:          l.Card.Count,
+			Location:       *toLocation(l.Location),
+			Inherited:      l.Inherited,
+		})
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Relocate collection cards
+// @Description Переложить карты коллекции в место хранения. Без scryfall_ids перекладываются все карты,
+// @Description а без location карты лежат там же, где коллекция
+// @Tags        Locations
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id              path   string                         true  "Collection ID"
+// @Param       input           body   locations.RelocateCardsRequest true  "Карты и новое место"
+// @Param       X-Change-Source header string                         false "Источник изменения: api, import, bot или trade"
+// @Param       If-Match        header string                         false "ETag коллекции, изменения которой ожидает клиент"
+// @Success     200 {object} locations.RelocateCardsResponse
+// @Failure     400,401,404,409,412 {object} collections.ErrorResponse
+// @Router      /collections/{id}/relocate [post]
+func (lc LocationsController) RelocateCards(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	ifVersion, respErr := ifMatch(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req locations.RelocateCardsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+	location, respErr := fromLocation(req.Location)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+	if location != nil && location.StorageID.IsZero() {
+		location = nil
+	}
+
+	relocated, respErr := lc.locationsService.RelocateCards(userId, changeSource(ctx), ctx.Param("id"), req.ScryfallIDs, location, ifVersion)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, locations.RelocateCardsResponse{Relocated: relocated})
+}
+
+func toStorageLocation(l *models.StorageLocation) locations.StorageLocation {
+	return locations.StorageLocation{
+		ID:          l.ID,
+		Name:        l.Name,
+		Kind:        l.Kind,
+		Description: l.Description,
+		CreatedAt:   l.CreatedAt,
+	}
+}
+
+func toLocation(l *models.Location) *locations.Location {
+	if l == nil {
+		return nil
+	}
+
+	return &locations.Location{
+		LocationID:   l.StorageID.Hex(),
+		LocationName: l.StorageName,
+		Section:      l.Section,
+		Page:         l.Page,
+		Slot:         l.Slot,
+	}
+}
+
+// fromLocation parses a location of a request; an empty location_id gives a
+// location with a zero storage ID, which takes the card or collection out of it.
+func fromLocation(l *locations.Location) (*models.Location, *models.ResponseErr) {
+	if l == nil {
+		return nil, nil
+	}
+
+	location := &models.Location{
+		Section: l.Section,
+		Page:    l.Page,
+		Slot:    l.Slot,
+	}
+	if l.LocationID == "" {
+		return location, nil
+	}
+	storageId, err := bson.ObjectIDFromHex(l.LocationID)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid location ID format",
+		}
+	}
+	location.StorageID = storageId
+	return location, nil
+}
@@ -0,0 +1,372 @@
+package controllers
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// PricesController отвечает за оценку коллекций по ценам карт
+// @Tags Prices
+// @BasePath /
+type PricesController struct {
+	pricesService PricesServicer
+	log           logger.Logger
+}
+
+type PricesServicer interface {
+	CollectionValue(userId, collectionId, currency string, top int) (*models.CollectionValue, *models.ResponseErr)
+	CollectionValueHistory(userId, collectionId string, query *models.ValueHistoryQuery) (*models.ValueHistory, *models.ResponseErr)
+	ValueHistory(userId string, query *models.ValueHistoryQuery) (*models.ValueHistory, *models.ResponseErr)
+	CollectionProfitLoss(userId, collectionId, currency string) (*models.ProfitLoss, *models.ResponseErr)
+	ProfitLoss(userId, currency string) (*models.ProfitLoss, *models.ResponseErr)
+	ExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr)
+}
+
+// NewPricesController создает контроллер цен
+func NewPricesController(pricesService PricesServicer, log logger.Logger) *PricesController {
+	return &PricesController{
+		pricesService: pricesService,
+		log:           log.With(logger.String("controller", "prices")),
+	}
+}
+
+// @Summary     Get collection value
+// @Description Оценить коллекцию по последним известным ценам. Цена копии зависит от ее отделки:
+// @Description обычной, foil или etched. Цены обновляются раз в день из файлов Scryfall и MTGJSON
+// @Tags        Prices
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id       path  string true  "Collection ID"
+// @Param       currency query string false "Валюта: usd, eur, tix или rub"
+// @Param       top      query int    false "Сколько самых дорогих карт вернуть"
+// @Success     200 {object} collections.CollectionValue
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/value [get]
+func (pc PricesController) CollectionValue(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query collections.CollectionValueQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	value, respErr := pc.pricesService.CollectionValue(userId, ctx.Param("id"), query.Currency, query.Top)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := collections.CollectionValue{
+		CollectionID:  value.Collection.ID,
+		Currency:      value.Currency,
+		Total:         value.Total,
+		PricedCount:   value.PricedCount,
+		UnpricedCount: value.UnpricedCount,
+		Cards:         make([]collections.CardValue, 0, len(value.Cards)),
+	}
+	if !value.PricedAt.IsZero() {
+		out.PricedAt = &value.PricedAt
+	}
+	for _, cv := range value.Cards {
+		out.Cards = append(out.Cards, collections.CardValue{
+			ScryfallID: cv.Card.ScryfallID,
+			Name:       cv.Card.Name,
+			Finish:     cv.Card.Finish,
+			Count:      cv.Card.Count,
+			UnitPrice:  cv.UnitPrice,
+			Value:      cv.Value,
+			PricedAt:   cv.PricedAt,
+		})
+	}
+	for _, card := range value.Unpriced {
+		out.Unpriced = append(out.Unpriced, cmp.Or(card.Name, card.ScryfallID))
+	}
+	out.RatesDate = value.RatesDate
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Get collection value history
+// @Description История стоимости коллекции по дням, неделям или месяцам с изменениями в процентах
+// @Description и картами, стоимость которых изменилась больше всего
+// @Tags        Prices
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id       path  string true  "Collection ID"
+// @Param       currency query string false "Валюта: usd, eur, tix или rub"
+// @Param       interval query string false "Интервал точек: day, week или month"
+// @Param       from     query string false "Первый день, например 2025-03-01"
+// @Param       to       query string false "Последний день, по умолчанию сегодня"
+// @Param       movers   query int    false "Сколько карт с самым большим изменением вернуть"
+// @Success     200 {object} collections.ValueHistory
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/value/history [get]
+func (pc PricesController) CollectionValueHistory(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query collections.ValueHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	history, respErr := pc.pricesService.CollectionValueHistory(userId, ctx.Param("id"), toValueHistoryQuery(query))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toValueHistory(history))
+}
+
+// @Summary     Get value history
+// @Description История стоимости всех коллекций пользователя, кроме коллекций типа wishlist.
+// @Description Копии, зарезервированные для колод, считаются один раз
+// @Tags        Prices
+// @Security    BearerAuth
+// @Produce     json
+// @Param       currency query string false "Валюта: usd, eur, tix или rub"
+// @Param       interval query string false "Интервал точек: day, week или month"
+// @Param       from     query string false "Первый день, например 2025-03-01"
+// @Param       to       query string false "Последний день, по умолчанию сегодня"
+// @Param       movers   query int    false "Сколько карт с самым большим изменением вернуть"
+// @Success     200 {object} collections.ValueHistory
+// @Failure     400,401 {object} collections.ErrorResponse
+// @Router      /value/history [get]
+func (pc PricesController) ValueHistory(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query collections.ValueHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	history, respErr := pc.pricesService.ValueHistory(userId, toValueHistoryQuery(query))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toValueHistory(history))
+}
+
+// @Summary     Get collection profit and loss
+// @Description Сравнить себестоимость полученных копий карт коллекции с их стоимостью по последним известным ценам
+// @Description и посчитать прибыль от копий, удаленных из коллекции или отданных в обмен
+// @Tags        Prices
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id       path  string true  "Collection ID"
+// @Param       currency query string false "Валюта: usd, eur или rub"
+// @Success     200 {object} collections.ProfitLoss
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/profit-loss [get]
+func (pc PricesController) CollectionProfitLoss(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query collections.ProfitLossQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	report, respErr := pc.pricesService.CollectionProfitLoss(userId, ctx.Param("id"), query.Currency)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProfitLoss(report))
+}
+
+// @Summary     Get profit and loss
+// @Description Отчет о прибыли и убытках по всем коллекциям пользователя, кроме вишлистов, с итогами каждой коллекции.
+// @Description Копии, зарезервированные для колод, считаются один раз
+// @Tags        Prices
+// @Security    BearerAuth
+// @Produce     json
+// @Param       currency query string false "Валюта: usd, eur или rub"
+// @Success     200 {object} collections.ProfitLoss
+// @Failure     400,401 {object} collections.ErrorResponse
+// @Router      /profit-loss [get]
+func (pc PricesController) ProfitLoss(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var query collections.ProfitLossQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	report, respErr := pc.pricesService.ProfitLoss(userId, query.Currency)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProfitLoss(report))
+}
+
+// @Summary     Get exchange rates
+// @Description Последние курсы валют, по которым пересчитываются цены и себестоимость
+// @Tags        Prices
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} collections.ExchangeRates
+// @Failure     401,404 {object} collections.ErrorResponse
+// @Router      /exchange-rates [get]
+func (pc PricesController) ExchangeRates(ctx *gin.Context) {
+	rates, respErr := pc.pricesService.ExchangeRates()
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, collections.ExchangeRates{
+		Base:  rates.Base,
+		Date:  rates.Date,
+		Rates: rates.Rates,
+	})
+}
+
+func toValueHistoryQuery(query collections.ValueHistoryQuery) *models.ValueHistoryQuery {
+	return &models.ValueHistoryQuery{
+		Currency: query.Currency,
+		Interval: query.Interval,
+		From:     query.From,
+		To:       query.To,
+		Movers:   query.Movers,
+	}
+}
+
+func toValueHistory(history *models.ValueHistory) collections.ValueHistory {
+	out := collections.ValueHistory{
+		Currency:      history.Currency,
+		Interval:      history.Interval,
+		From:          history.From,
+		To:            history.To,
+		Points:        make([]collections.ValuePoint, 0, len(history.Points)),
+		Change:        history.Change,
+		ChangePercent: history.ChangePercent,
+		Movers:        make([]collections.CardMove, 0, len(history.Movers)),
+		RatesDate:     history.RatesDate,
+	}
+	if history.Collection != nil {
+		out.CollectionID = history.Collection.ID
+	}
+	for _, p := range history.Points {
+		out.Points = append(out.Points, collections.ValuePoint{
+			Date:          p.Date,
+			Value:         p.Value,
+			PricedCount:   p.PricedCount,
+			Change:        p.Change,
+			ChangePercent: p.ChangePercent,
+		})
+	}
+	for _, m := range history.Movers {
+		out.Movers = append(out.Movers, collections.CardMove{
+			ScryfallID:    m.Card.ScryfallID,
+			Name:          m.Card.Name,
+			Finish:        m.Card.Finish,
+			Count:         m.Card.Count,
+			StartPrice:    m.StartPrice,
+			EndPrice:      m.EndPrice,
+			Change:        m.Change,
+			ChangePercent: m.ChangePercent,
+		})
+	}
+	return out
+}
+
+func toProfitLoss(report *models.ProfitLoss) collections.ProfitLoss {
+	out := collections.ProfitLoss{
+		Currency:              report.Currency,
+		CostBasis:             report.CostBasis,
+		MarketValue:           report.MarketValue,
+		UnrealizedGain:        report.UnrealizedGain,
+		UnrealizedGainPercent: report.UnrealizedGainPercent,
+		RealizedGain:          report.RealizedGain,
+		OrderedCost:           report.OrderedCost,
+		Cards:                 make([]collections.CardProfitLoss, 0, len(report.Cards)),
+		Realized:              make([]collections.RealizedGain, 0, len(report.Realized)),
+		RatesDate:             report.RatesDate,
+	}
+	if report.Collection != nil {
+		out.CollectionID = report.Collection.ID
+	}
+	for _, c := range report.Collections {
+		out.Collections = append(out.Collections, collections.CollectionProfitLoss{
+			CollectionID:          c.Collection.ID,
+			Name:                  c.Collection.Name,
+			CostBasis:             c.CostBasis,
+			MarketValue:           c.MarketValue,
+			UnrealizedGain:        c.UnrealizedGain,
+			UnrealizedGainPercent: c.UnrealizedGainPercent,
+			RealizedGain:          c.RealizedGain,
+			OrderedCost:           c.OrderedCost,
+		})
+	}
+	for _, c := range report.Cards {
+		out.Cards = append(out.Cards, collections.CardProfitLoss{
+			CollectionID: c.Collection.ID,
+			ScryfallID:   c.Card.ScryfallID,
+			Name:         c.Card.Name,
+			Finish:       c.Card.Finish,
+			Count:        c.Count,
+			UnitCost:     c.UnitCost,
+			CostBasis:    c.CostBasis,
+			MarketValue:  c.MarketValue,
+			Gain:         c.Gain,
+			GainPercent:  c.GainPercent,
+		})
+	}
+	for _, r := range report.Realized {
+		out.Realized = append(out.Realized, collections.RealizedGain{
+			CollectionID: r.Entry.CollectionID.Hex(),
+			ScryfallID:   r.Entry.ScryfallID,
+			Name:         r.Entry.Name,
+			Finish:       r.Entry.Finish,
+			Count:        r.Count,
+			Date:         r.Entry.CreatedAt,
+			Source:       r.Entry.Source,
+			CostBasis:    r.CostBasis,
+			Proceeds:     r.Proceeds,
+			Gain:         r.Gain,
+			GainPercent:  r.GainPercent,
+		})
+	}
+	for _, card := range report.Untracked {
+		out.Untracked = append(out.Untracked, cmp.Or(card.Name, card.ScryfallID))
+	}
+	for _, card := range report.Unpriced {
+		out.Unpriced = append(out.Unpriced, cmp.Or(card.Name, card.ScryfallID))
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// SnapshotsController отвечает за снимки коллекций
+// @Tags Snapshots
+// @BasePath /
+type SnapshotsController struct {
+	snapshotsService SnapshotsServicer
+	log              logger.Logger
+}
+
+type SnapshotsServicer interface {
+	CreateSnapshot(userId, collectionId, name string) (*models.CollectionSnapshot, *models.ResponseErr)
+	ListSnapshots(userId, collectionId string) ([]*models.CollectionSnapshot, *models.ResponseErr)
+	GetSnapshot(userId, collectionId, snapshotId string) (*models.CollectionSnapshot, *models.ResponseErr)
+	DeleteSnapshot(userId, collectionId, snapshotId string) *models.ResponseErr
+	DiffSnapshot(userId, collectionId, snapshotId, toSnapshotId string) (*models.CollectionDiff, *models.ResponseErr)
+}
+
+// NewSnapshotsController создает контроллер снимков коллекций
+func NewSnapshotsController(snapshotsService SnapshotsServicer, log logger.Logger) *SnapshotsController {
+	return &SnapshotsController{
+		snapshotsService: snapshotsService,
+		log:              log.With(logger.String("controller", "snapshots")),
+	}
+}
+
+// @Summary     Create snapshot
+// @Description Сохранить текущие карты коллекции как именованный снимок
+// @Tags        Snapshots
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id    path string                            true "Collection ID"
+// @Param       input body collections.CreateSnapshotRequest true "Имя снимка"
+// @Success     201 {object} collections.Snapshot
+// @Failure     400,401,404,409 {object} collections.ErrorResponse
+// @Router      /collections/{id}/snapshots [post]
+func (sc SnapshotsController) CreateSnapshot(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req collections.CreateSnapshotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	created, respErr := sc.snapshotsService.CreateSnapshot(userId, ctx.Param("id"), req.Name)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toSnapshot(created))
+}
+
+// @Summary     List snapshots
+// @Description Получить снимки коллекции без карт, новые первыми
+// @Tags        Snapshots
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Collection ID"
+// @Success     200 {array} collections.Snapshot
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/snapshots [get]
+func (sc SnapshotsController) ListSnapshots(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	list, respErr := sc.snapshotsService.ListSnapshots(userId, ctx.Param("id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]collections.Snapshot, 0, len(list))
+	for _, s := range list {
+		out = append(out, toSnapshot(s))
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Get snapshot
+// @Description Получить снимок коллекции с картами
+// @Tags        Snapshots
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id          path string true "Collection ID"
+// @Param       snapshot_id path string true "Snapshot ID"
+// @Success     200 {object} collections.Snapshot
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/snapshots/{snapshot_id} [get]
+func (sc SnapshotsController) GetSnapshot(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	snapshot, respErr := sc.snapshotsService.GetSnapshot(userId, ctx.Param("id"), ctx.Param("snapshot_id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSnapshot(snapshot))
+}
+
+// @Summary     Delete snapshot
+// @Description Удалить снимок коллекции
+// @Tags        Snapshots
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id          path string true "Collection ID"
+// @Param       snapshot_id path string true "Snapshot ID"
+// @Success     204 "No Content"
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/snapshots/{snapshot_id} [delete]
+func (sc SnapshotsController) DeleteSnapshot(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	respErr = sc.snapshotsService.DeleteSnapshot(userId, ctx.Param("id"), ctx.Param("snapshot_id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Diff snapshot
+// @Description Сравнить снимок с другим снимком той же коллекции или, без to, с текущими картами коллекции
+// @Tags        Snapshots
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id          path  string true  "Collection ID"
+// @Param       snapshot_id path  string true  "Snapshot ID"
+// @Param       to          query string false "ID более позднего снимка"
+// @Success     200 {object} collections.SnapshotDiff
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /collections/{id}/snapshots/{snapshot_id}/diff [get]
+func (sc SnapshotsController) DiffSnapshot(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	from, to := ctx.Param("snapshot_id"), ctx.Query("to")
+	diff, respErr := sc.snapshotsService.DiffSnapshot(userId, ctx.Param("id"), from, to)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, collections.SnapshotDiff{
+		From:    from,
+		To:      to,
+		Added:   toCardDiffs(diff.Added),
+		Removed: toCardDiffs(diff.Removed),
+		Changed: toCardDiffs(diff.Changed),
+	})
+}
+
+func toSnapshot(s *models.CollectionSnapshot) collections.Snapshot {
+	out := collections.Snapshot{
+		ID:           s.ObjectID.Hex(),
+		CollectionID: s.CollectionID.Hex(),
+		Name:         s.Name,
+		CardCount:    s.CardCount,
+		UniqueCards:  s.UniqueCards,
+		CreatedAt:    s.CreatedAt,
+	}
+	for _, c := range s.Cards {
+		out.Cards = append(out.Cards, collections.SnapshotCard{ScryfallID: c.ScryfallID, Name: c.Name, Finish: c.Finish, Count: c.Count})
+	}
+	return out
+}
+
+func toCardDiffs(list []*models.CardDiff) []collections.CardDiff {
+	out := make([]collections.CardDiff, 0, len(list))
+	for _, d := range list {
+		out = append(out, collections.CardDiff{
+			ScryfallID: d.ScryfallID,
+			Name:       d.Name,
+			Finish:     d.Finish,
+			Before:     d.Before,
+			After:      d.After,
+			Delta:      d.After - d.Before,
+		})
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// UsersController отвечает за настройки пользователя
+// @Tags Users
+// @BasePath /
+type UsersController struct {
+	usersService UsersServicer
+	log          logger.Logger
+}
+
+type UsersServicer interface {
+	Settings(userId string) (*models.UserSettings, *models.ResponseErr)
+	UpdateSettings(userId string, update *models.UserSettingsUpdate) (*models.UserSettings, *models.ResponseErr)
+}
+
+// NewUsersController создает контроллер настроек пользователя
+func NewUsersController(usersService UsersServicer, log logger.Logger) *UsersController {
+	return &UsersController{
+		usersService: usersService,
+		log:          log.With(logger.String("controller", "users")),
+	}
+}
+
+// @Summary     Get user settings
+// @Description Получить настройки пользователя: валюту, в которой по умолчанию считаются стоимость и прибыль
+// @Tags        Users
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} auth.UserSettings
+// @Failure     400,401,404 {object} models.ResponseErr
+// @Router      /user/settings [get]
+func (uc UsersController) GetSettings(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	settings, respErr := uc.usersService.Settings(userId)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, auth.UserSettings{Currency: settings.Currency})
+}
+
+// @Summary     Update user settings
+// @Description Изменить настройки пользователя. currency — usd, eur или rub; пустая строка возвращает usd
+// @Tags        Users
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       input body auth.UpdateSettingsRequest true "Изменяемые поля"
+// @Success     200 {object} auth.UserSettings
+// @Failure     400,401,404 {object} models.ResponseErr
+// @Router      /user/settings [patch]
+func (uc UsersController) UpdateSettings(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req auth.UpdateSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, models.ResponseErr{Message: err.Error()})
+		return
+	}
+
+	settings, respErr := uc.usersService.UpdateSettings(userId, &models.UserSettingsUpdate{Currency: req.Currency})
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, auth.UserSettings{Currency: settings.Currency})
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/wishlist"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// WishlistController отвечает за список желаний пользователя
+// @Tags Wishlist
+// @BasePath /
+type WishlistController struct {
+	wishlistService WishlistServicer
+	log             logger.Logger
+}
+
+type WishlistServicer interface {
+	AddWishlistEntry(entry *models.WishlistEntry) (*models.WishlistEntry, *models.ResponseErr)
+	ListWishlist(userId string) ([]*models.WishlistEntry, *models.ResponseErr)
+	UpdateWishlistEntry(update *models.WishlistEntryUpdate) (*models.WishlistEntry, *models.ResponseErr)
+	DeleteWishlistEntry(userId, entryId string) *models.ResponseErr
+	ListMissingCards(userId string) ([]*models.MissingCard, *models.ResponseErr)
+}
+
+// NewWishlistController создает контроллер списка желаний
+func NewWishlistController(wishlistService WishlistServicer, log logger.Logger) *WishlistController {
+	return &WishlistController{
+		wishlistService: wishlistService,
+		log:             log.With(logger.String("controller", "wishlist")),
+	}
+}
+
+// @Summary     Get wishlist
+// @Description Получить список желаний: сначала карты с высоким приоритетом, затем по дате добавления
+// @Tags        Wishlist
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {array} wishlist.Entry
+// @Failure     401 {object} collections.ErrorResponse
+// @Router      /wishlist [get]
+func (wc WishlistController) ListWishlist(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	list, respErr := wc.wishlistService.ListWishlist(userId)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]wishlist.Entry, 0, len(list))
+	for _, e := range list {
+		out = append(out, toWishlistEntry(e))
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Add card to wishlist
+// @Description Добавить карту в список желаний. Одну и ту же карту можно добавить только один раз
+// @Tags        Wishlist
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       input body wishlist.AddEntryRequest true "Карта и сколько ее нужно"
+// @Success     201 {object} wishlist.Entry
+// @Failure     400,401,409 {object} collections.ErrorResponse
+// @Router      /wishlist [post]
+func (wc WishlistController) AddWishlistEntry(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req wishlist.AddEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	userObjectId, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, collections.ErrorResponse{Message: "Invalid user ID format"})
+		return
+	}
+
+	entry := &models.WishlistEntry{
+		UserID:      userObjectId,
+		ScryfallID:  req.ScryfallID,
+		OracleID:    req.OracleID,
+		Name:        req.Name,
+		AnyPrinting: req.AnyPrinting,
+		Quantity:    req.Quantity,
+		MaxPrice:    req.MaxPrice,
+		Currency:    req.Currency,
+		Finish:      req.Finish,
+		Priority:    req.Priority,
+	}
+	created, respErr := wc.wishlistService.AddWishlistEntry(entry)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toWishlistEntry(created))
+}
+
+// @Summary     Get missing cards
+// @Description Получить карты из списка желаний, которых не хватает. Копии считаются по всем коллекциям
+// @Description пользователя, кроме коллекций типа wishlist; отделка копий не учитывается
+// @Tags        Wishlist
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {array} wishlist.MissingEntry
+// @Failure     401 {object} collections.ErrorResponse
+// @Router      /wishlist/missing [get]
+func (wc WishlistController) ListMissingCards(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	missing, respErr := wc.wishlistService.ListMissingCards(userId)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	out := make([]wishlist.MissingEntry, 0, len(missing))
+	for _, m := range missing {
+		out = append(out, wishlist.MissingEntry{
+			Entry:   toWishlistEntry(m.Entry),
+			Owned:   m.Owned,
+			Missing: m.Missing(),
+		})
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// @Summary     Update wishlist entry
+// @Description Изменить количество, цену, ее валюту, отделку или приоритет карты в списке желаний
+// @Tags        Wishlist
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       entry_id path string                      true "Wishlist entry ID"
+// @Param       input    body wishlist.UpdateEntryRequest true "Изменяемые поля"
+// @Success     200 {object} wishlist.Entry
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /wishlist/{entry_id} [patch]
+func (wc WishlistController) UpdateWishlistEntry(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	var req wishlist.UpdateEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, collections.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	userObjectId, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, collections.ErrorResponse{Message: "Invalid user ID format"})
+		return
+	}
+
+	update := &models.WishlistEntryUpdate{
+		ID:       ctx.Param("entry_id"),
+		UserID:   userObjectId,
+		Quantity: req.Quantity,
+		MaxPrice: req.MaxPrice,
+		Currency: req.Currency,
+		Finish:   req.Finish,
+		Priority: req.Priority,
+	}
+	updated, respErr := wc.wishlistService.UpdateWishlistEntry(update)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toWishlistEntry(updated))
+}
+
+// @Summary     Delete wishlist entry
+// @Description Убрать карту из списка желаний
+// @Tags        Wishlist
+// @Security    BearerAuth
+// @Produce     json
+// @Param       entry_id path string true "Wishlist entry ID"
+// @Success     204 "No Content"
+// @Failure     400,401,404 {object} collections.ErrorResponse
+// @Router      /wishlist/{entry_id} [delete]
+func (wc WishlistController) DeleteWishlistEntry(ctx *gin.Context) {
+	userId, respErr := getUserFromCtx(ctx)
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	respErr = wc.wishlistService.DeleteWishlistEntry(userId, ctx.Param("entry_id"))
+	if respErr != nil {
+		ctx.AbortWithStatusJSON(respErr.Status, respErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func toWishlistEntry(e *models.WishlistEntry) wishlist.Entry {
+	out := wishlist.Entry{
+		ID:          e.ObjectID.Hex(),
+		ScryfallID:  e.ScryfallID,
+		OracleID:    e.OracleID,
+		Name:        e.Name,
+		AnyPrinting: e.AnyPrinting,
+		Quantity:    e.Quantity,
+		MaxPrice:    e.MaxPrice,
+		Finish:      e.Finish,
+		Priority:    e.Priority,
+		CreatedAt:   e.CreatedAt,
+	}
+	if e.MaxPrice > 0 {
+		out.Currency = cmp.Or(e.Currency, catalog.CurrencyUSD)
+		out.RatesDate = e.RatesDate
+	}
+	return out
+}
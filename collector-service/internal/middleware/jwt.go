@@ -10,16 +10,19 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Needs initialization logger
+// JWTMiddleware validates Bearer tokens. A nil logger disables logging.
 type JWTMiddleware struct {
 	secret string
 	logger logger.Logger
 }
 
-func NewJWTMiddleware(secret string, logger logger.Logger) *JWTMiddleware {
+func NewJWTMiddleware(secret string, log logger.Logger) *JWTMiddleware {
+	if log == nil {
+		log = logger.SilentLogger{}
+	}
 	return &JWTMiddleware{
 		secret: secret,
-		logger: logger,
+		logger: log,
 	}
 }
 
@@ -58,7 +61,7 @@ func (m *JWTMiddleware) Authorization() gin.HandlerFunc {
 
 		// TODO: Check if the token is valid
 		if exp, ok := claims["exp"].(float64); !ok || int64(exp) < time.Now().Unix() {
-			m.logger.Warn("JWT token has expired", logger.Int("expiration", int(exp)), logger.String("current_time", time.Now().String()))
+			m.logger.Warn("JWT token has expired", logger.Int64("expiration", int64(exp)), logger.String("current_time", time.Now().String()))
 			ctx.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+)
+
+// Statuses of acquisitions.
+const (
+	AcquisitionOrdered  = "ordered"
+	AcquisitionReceived = "received"
+)
+
+var AcquisitionStatuses = []string{AcquisitionOrdered, AcquisitionReceived}
+
+// PurchaseCurrencies are the currencies cards can be bought and costed in,
+// and the currencies users can prefer.
+var PurchaseCurrencies = []string{catalog.CurrencyUSD, catalog.CurrencyEUR, catalog.CurrencyRUB}
+
+// MaxVendorLen limits the vendor of an acquisition.
+const MaxVendorLen = 100
+
+// Acquisition records a purchase of copies of a card.
+type Acquisition struct {
+	ID   string    `bson:"id" json:"id"`
+	Date time.Time `bson:"date" json:"date"`
+	// Count is the number of copies bought and Price what a copy cost.
+	Count    int     `bson:"count" json:"count"`
+	Price    float64 `bson:"price" json:"price"`
+	Currency string  `bson:"currency" json:"currency"`
+	Vendor   string  `bson:"vendor,omitempty" json:"vendor,omitempty"`
+	// Status is ordered until the copies arrive; only received copies have a cost.
+	Status string `bson:"status" json:"status"`
+}
+
+// AcquisitionUpdate is a partial update of an acquisition; nil fields are left as is.
+type AcquisitionUpdate struct {
+	Date     *time.Time
+	Count    *int
+	Price    *float64
+	Currency *string
+	Vendor   *string
+	Status   *string
+}
+
+// CostBasis is what the copies of a card cost, by currency, at the average
+// price paid for a received copy. Copies beyond the received ones have no
+// known cost, so copies is the number of copies the cost covers.
+func (c *Card) CostBasis() (copies int, cost map[string]float64) {
+	return c.costOf(c.Count)
+}
+
+// RemovedCost is the cost of the copies that leave a card whose count goes
+// down to count. Copies without a known cost are taken to leave first.
+func (c *Card) RemovedCost(count int) (copies int, cost map[string]float64) {
+	held, heldCost := c.CostBasis()
+	kept, keptCost := c.costOf(count)
+	if held == kept {
+		return 0, nil
+	}
+	cost = make(map[string]float64, len(heldCost))
+	for currency, amount := range heldCost {
+		cost[currency] = amount - keptCost[currency]
+	}
+	return held - kept, cost
+}
+
+// costOf is the cost of count copies of a card at the average price of a received copy.
+func (c *Card) costOf(count int) (int, map[string]float64) {
+	received := 0
+	paid := make(map[string]float64)
+	for _, a := range c.Acquisitions {
+		if a.Status == AcquisitionReceived {
+			received += a.Count
+			paid[a.Currency] += a.Price * float64(a.Count)
+		}
+	}
+
+	copies := min(max(count, 0), received)
+	if copies == 0 {
+		return 0, nil
+	}
+	cost := make(map[string]float64, len(paid))
+	for currency, amount := range paid {
+		cost[currency] = amount * float64(copies) / float64(received)
+	}
+	return copies, cost
+}
+
+// OrderedCost is what the copies of a card that are ordered but not received yet cost, by currency.
+func (c *Card) OrderedCost() map[string]float64 {
+	var cost map[string]float64
+	for _, a := range c.Acquisitions {
+		if a.Status == AcquisitionOrdered {
+			if cost == nil {
+				cost = make(map[string]float64)
+			}
+			cost[a.Currency] += a.Price * float64(a.Count)
+		}
+	}
+	return cost
+}
+
+// CardProfitLoss compares what the copies of a card with a known cost were
+// bought for with what they are worth now.
+type CardProfitLoss struct {
+	Collection *Collection
+	Card       *Card
+	// Count is the number of copies with a known cost.
+	Count       int
+	UnitCost    float64
+	CostBasis   float64
+	MarketValue float64
+	Gain        float64
+	// GainPercent is nil when the copies cost nothing.
+	GainPercent *float64
+}
+
+// RealizedGain is the gain on copies with a known cost that left the
+// collections of a user, valued at the market price of the day they left.
+type RealizedGain struct {
+	Entry     *CardHistoryEntry
+	Count     int
+	CostBasis float64
+	// Proceeds, Gain and GainPercent are nil when the price of the day is not known.
+	Proceeds    *float64
+	Gain        *float64
+	GainPercent *float64
+}
+
+// CollectionProfitLoss sums the profit and loss of the cards of a collection.
+type CollectionProfitLoss struct {
+	Collection            *Collection
+	CostBasis             float64
+	MarketValue           float64
+	UnrealizedGain        float64
+	UnrealizedGainPercent *float64
+	RealizedGain          float64
+	// OrderedCost is what the copies that are not received yet cost.
+	OrderedCost float64
+}
+
+// ProfitLoss is the profit and loss report of a collection, or of all
+// collections of a user when Collection is nil.
+type ProfitLoss struct {
+	CollectionProfitLoss
+	Currency string
+	// Collections break the report of a user down by collection.
+	Collections []*CollectionProfitLoss
+	// Cards are the cards with a known cost and price, the biggest gains first.
+	Cards []*CardProfitLoss
+	// Realized are the gains on copies that left, the newest first.
+	Realized []*RealizedGain
+	// Untracked are the cards without a known cost in the currency of the
+	// report, Unpriced the cards with a cost but without a known price.
+	Untracked []*Card
+	Unpriced  []*Card
+	// RatesDate is the day of the exchange rates amounts were converted at, nil if they were not.
+	RatesDate *time.Time
+}
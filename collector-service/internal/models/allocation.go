@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Allocation reserves copies of a printing in a source collection, usually a
+// binder, for a deck: the copies physically sit in the deck. A user has at
+// most one allocation per deck, source and printing.
+type Allocation struct {
+	ID         string        `bson:"-" json:"id"`
+	ObjectID   bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID     bson.ObjectID `bson:"user_id" json:"user_id"`
+	DeckID     bson.ObjectID `bson:"deck_id" json:"deck_id"`
+	SourceID   bson.ObjectID `bson:"source_id" json:"source_id"`
+	ScryfallID string        `bson:"scryfall_id" json:"scryfall_id"`
+	Name       string        `bson:"name,omitempty" json:"name,omitempty"`
+	Count      int           `bson:"count" json:"count"`
+	CreatedAt  time.Time     `bson:"created_at" json:"created_at"`
+}
+
+func (a *Allocation) PrepareForResponse() {
+	a.ID = a.ObjectID.Hex()
+}
+
+// AllocationConflict is a printing of a source collection reserved for more
+// copies than the collection holds.
+type AllocationConflict struct {
+	Source     *Collection
+	ScryfallID string
+	Name       string
+	Owned      int
+	// Allocations are the reservations of the printing, oldest first.
+	Allocations []*DeckAllocation
+}
+
+// Allocated is the number of copies reserved for decks.
+func (c *AllocationConflict) Allocated() int {
+	allocated := 0
+	for _, a := range c.Allocations {
+		allocated += a.Count
+	}
+	return allocated
+}
+
+// DeckAllocation is the part of a reservation that counts: a deck can't
+// hold reserved copies it doesn't have.
+type DeckAllocation struct {
+	Allocation *Allocation
+	Deck       *Collection
+	Count      int
+}
@@ -1,19 +1,109 @@
 package models
 
 import (
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type Collection struct {
-	ID        string        `bson:"-" json:"id"`
-	ObjectID  bson.ObjectID `bson:"_id,omitempty" json:"-"`
-	UserID    bson.ObjectID `bson:"user_id" json:"user_id"`
-	Name      string        `bson:"name" json:"name"`
-	Cards     []*Card       `bson:"cards,omitempty" json:"cards,omitempty"`
-	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
+	ID          string        `bson:"-" json:"id"`
+	ObjectID    bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID      bson.ObjectID `bson:"user_id" json:"user_id"`
+	Name        string        `bson:"name" json:"name"`
+	Description string        `bson:"description,omitempty" json:"description,omitempty"`
+	Kind        string        `bson:"kind,omitempty" json:"kind,omitempty"`
+	Format      string        `bson:"format,omitempty" json:"format,omitempty"`
+	Tags        []string      `bson:"tags,omitempty" json:"tags,omitempty"`
+	CoverCard   string        `bson:"cover_card,omitempty" json:"cover_card,omitempty"`
+	Pinned      bool          `bson:"pinned,omitempty" json:"pinned,omitempty"`
+	SortOrder   int           `bson:"sort_order,omitempty" json:"sort_order,omitempty"`
+	Cards       []*Card       `bson:"cards,omitempty" json:"cards,omitempty"`
+	// Location is where the cards without a location of their own lie.
+	Location  *Location `bson:"location,omitempty" json:"location,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	// DeletedAt is set while the collection is in the trash.
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// Version grows by one with every change of the collection or its cards.
+	Version int64 `bson:"version" json:"version"`
+}
+
+// CheckVersion fails with 412 unless ifVersion is nil or is the version of the collection.
+func (c *Collection) CheckVersion(ifVersion *int64) *ResponseErr {
+	if ifVersion != nil && *ifVersion != c.Version {
+		return VersionMismatch()
+	}
+	return nil
+}
+
+// VersionMismatch is the error of a change that expected another version of the collection.
+func VersionMismatch() *ResponseErr {
+	return &ResponseErr{
+		Status:  http.StatusPreconditionFailed,
+		Message: "Collection version does not match",
+	}
+}
+
+// CollectionKinds are the accepted collection kinds.
+var CollectionKinds = []string{"binder", CollectionKindDeck, "cube", "trade", CollectionKindWishlist}
+
+// CollectionKindDeck collections can have a format and keep their cards in deck zones.
+const CollectionKindDeck = "deck"
+
+// CollectionKindWishlist collections hold cards a user wants rather than owns.
+const CollectionKindWishlist = "wishlist"
+
+// Limits for collection metadata.
+const (
+	MaxCollectionTags   = 20
+	MaxCollectionTagLen = 32
+)
+
+// CollectionSummary is a collection without its cards, with card totals.
+type CollectionSummary struct {
+	Collection `bson:",inline"`
+	// CardCount is the number of copies of all cards.
+	CardCount   int `bson:"card_count"`
+	UniqueCards int `bson:"unique_cards"`
+}
+
+// Summarize counts the cards of a loaded collection.
+func Summarize(c *Collection) *CollectionSummary {
+	summary := &CollectionSummary{Collection: *c, UniqueCards: len(c.Cards)}
+	summary.Cards = nil
+	for _, card := range c.Cards {
+		summary.CardCount += card.Count
+	}
+	return summary
+}
+
+// CollectionUpdate is a partial update of a collection; nil fields are left as is.
+type CollectionUpdate struct {
+	ID          string
+	UserID      bson.ObjectID
+	Name        *string
+	Description *string
+	Kind        *string
+	Format      *string
+	Tags        *[]string
+	CoverCard   *string
+	Pinned      *bool
+	SortOrder   *int
+	// Location with a zero StorageID takes the collection out of its location.
+	Location *Location
+	// IfVersion, when set, is the version the collection must have.
+	IfVersion *int64
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (u *CollectionUpdate) IsEmpty() bool {
+	return u.Name == nil && u.Description == nil && u.Kind == nil && u.Format == nil && u.Tags == nil &&
+		u.CoverCard == nil && u.Pinned == nil && u.SortOrder == nil && u.Location == nil
 }
 
 type Card struct {
@@ -21,9 +111,191 @@ type Card struct {
 	Name       string    `bson:"name" json:"name"`
 	CardUrl    string    `bson:"card_url" json:"card_url"`
 	Count      int       `bson:"count" json:"count"`
+	Notes      string    `bson:"notes,omitempty" json:"notes,omitempty"`
+	Condition  string    `bson:"condition,omitempty" json:"condition,omitempty"`
+	Zone       string    `bson:"zone,omitempty" json:"zone,omitempty"`
 	AddedAt    time.Time `bson:"added_at" json:"added_at"`
+	UpdatedAt  time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	Location   *Location `bson:"location,omitempty" json:"location,omitempty"`
+	// Finish is foil or etched; a card without a finish is nonfoil.
+	Finish string `bson:"finish,omitempty" json:"finish,omitempty"`
+	// Acquisitions are the purchases of the copies of the card, the oldest first.
+	Acquisitions []*Acquisition `bson:"acquisitions,omitempty" json:"acquisitions,omitempty"`
+}
+
+// CardKey identifies a card of a collection. A printing has one card per deck
+// zone and finish; the mainboard and nonfoil are stored as no zone and finish.
+type CardKey struct {
+	ScryfallID string
+	Zone       string
+	Finish     string
+}
+
+// Key returns the key of the card.
+func (c *Card) Key() CardKey {
+	return CardKey{ScryfallID: c.ScryfallID, Zone: c.Zone, Finish: c.Finish}
+}
+
+// FindCard returns the index of the card with key in cards, or -1.
+func FindCard(cards []*Card, key CardKey) int {
+	return slices.IndexFunc(cards, func(c *Card) bool { return c.Key() == key })
+}
+
+// CardConditions are the accepted card conditions, TCGplayer scale.
+var CardConditions = []string{"NM", "LP", "MP", "HP", "DMG"}
+
+// CardEntry is a card of a collection with what is known about it.
+type CardEntry struct {
+	Card *Card
+	// Printing is nil when the catalog doesn't know the card.
+	Printing *catalog.Card
+	// Variants are other printings of the same card in the collection.
+	Variants []*CardEntry
+	// Location is where the card lies, if known.
+	Location *Location
+	// CollectionVersion is the version of the collection the card was read from.
+	CollectionVersion int64
+}
+
+// OwnedCardQuery looks a card up across the collections of a user by one of
+// a printing, an oracle ID or a name.
+type OwnedCardQuery struct {
+	ScryfallID string
+	OracleID   string
+	Name       string
+}
+
+// OwnedCardMatch picks the cards a look-up finds: printings among ScryfallIDs
+// and, with Name, cards of that name, whole or of the front face.
+type OwnedCardMatch struct {
+	ScryfallIDs []string
+	Name        string
+}
+
+// Matches reports whether card is a copy match picks.
+func (m *OwnedCardMatch) Matches(card *Card) bool {
+	if card.Count <= 0 {
+		return false
+	}
+	if slices.Contains(m.ScryfallIDs, card.ScryfallID) {
+		return true
+	}
+	if m.Name == "" {
+		return false
+	}
+	front, _, _ := strings.Cut(card.Name, " // ")
+	return strings.EqualFold(card.Name, m.Name) || strings.EqualFold(front, m.Name)
+}
+
+// OwnedCards are the copies of a card in a collection, one entry per printing.
+type OwnedCards struct {
+	Collection *Collection
+	Cards      []*CardEntry
+}
+
+// CardUpdate is a partial update of a card in a collection; nil fields are left as is.
+type CardUpdate struct {
+	Key       CardKey
+	Count     *int
+	Notes     *string
+	Condition *string
+	Zone      *string
+	Finish    *string
+}
+
+// Removes reports whether the update takes the card out of the collection:
+// a card can't be kept at zero copies.
+func (u *CardUpdate) Removes() bool {
+	return u.Count != nil && *u.Count == 0
+}
+
+// NewKey returns the key the card has after the update.
+func (u *CardUpdate) NewKey() CardKey {
+	key := u.Key
+	if u.Zone != nil {
+		key.Zone = *u.Zone
+	}
+	if u.Finish != nil {
+		key.Finish = *u.Finish
+	}
+	return key
+}
+
+// Card batch operations.
+const (
+	// CardOpAdd adds copies of a card, creating it if needed.
+	CardOpAdd = "add"
+	// CardOpSet sets the absolute count of a card; 0 removes it.
+	CardOpSet = "set"
+	// CardOpRemove removes copies of a card, or the whole card when Count is 0.
+	CardOpRemove = "remove"
+)
+
+// CardOperation is one step of a card batch.
+type CardOperation struct {
+	Op   string
+	Card *Card
+}
+
+// CardOperationResult is the outcome of one step of a card batch.
+type CardOperationResult struct {
+	Op         string
+	ScryfallID string
+	Zone       string
+	Finish     string
+	// Count is the number of copies left after the step.
+	Count int
+	Err   *ResponseErr
+}
+
+// CardBatchResult is the outcome of a card batch.
+type CardBatchResult struct {
+	// Committed is true when the changes were saved.
+	Committed bool
+	Results   []*CardOperationResult
+}
+
+// CardFilter selects cards of a collection. Every non-empty field must match;
+// a list matches if any of its values does.
+type CardFilter struct {
+	ScryfallIDs []string
+	// Name matches a part of the card name, ignoring case.
+	Name       string
+	Sets       []string
+	Rarities   []string
+	Conditions []string
+}
+
+// IsEmpty reports whether the filter has no criteria.
+func (f *CardFilter) IsEmpty() bool {
+	return len(f.ScryfallIDs) == 0 && f.Name == "" && len(f.Sets) == 0 && len(f.Rarities) == 0 && len(f.Conditions) == 0
 }
 
 func (c *Collection) PrepareForResponse() {
 	c.ID = c.ObjectID.Hex()
 }
+
+// CardTransfer moves or copies copies of a card from one collection to another.
+type CardTransfer struct {
+	FromCollectionID bson.ObjectID
+	ToCollectionID   bson.ObjectID
+	Key              CardKey
+	Count            int
+	// Copy leaves the source collection as is.
+	Copy bool
+}
+
+// TransferKey is the key a card of key takes when it is moved into the
+// collection: only decks keep cards in zones.
+func (c *Collection) TransferKey(key CardKey) CardKey {
+	if c.Kind != CollectionKindDeck {
+		key.Zone = ""
+	}
+	return key
+}
+
+// CardTransferResult holds the counts left in both collections after a transfer.
+type CardTransferResult struct {
+	FromCount int
+	ToCount   int
+}
@@ -0,0 +1,167 @@
+package models
+
+// Deck zones. Cards are stored without a zone while they are in the mainboard.
+const (
+	ZoneMainboard  = "mainboard"
+	ZoneSideboard  = "sideboard"
+	ZoneCommander  = "commander"
+	ZoneCompanion  = "companion"
+	ZoneMaybeboard = "maybeboard"
+)
+
+// DeckZones are the accepted deck zones.
+var DeckZones = []string{ZoneMainboard, ZoneSideboard, ZoneCommander, ZoneCompanion, ZoneMaybeboard}
+
+// DeckZone returns the zone of a card, ZoneMainboard if it has none.
+func (c *Card) DeckZone() string {
+	if c.Zone == "" {
+		return ZoneMainboard
+	}
+	return c.Zone
+}
+
+// DeckFormat holds the deck construction rules of a format.
+type DeckFormat struct {
+	// MinDeck and MaxDeck limit the cards in the mainboard, commanders
+	// included; 0 means no limit.
+	MinDeck int
+	MaxDeck int
+	// MaxSideboard limits the sideboard; a companion is part of it outside
+	// of commander formats.
+	MaxSideboard int
+	// MaxCopies of a card other than basic lands; 1 for singleton formats.
+	MaxCopies int
+	// Commander decks are led by one or two commanders whose color identity
+	// every card must fit.
+	Commander bool
+}
+
+var constructedFormat = &DeckFormat{MinDeck: 60, MaxSideboard: 15, MaxCopies: 4}
+
+// DeckFormats are the formats decks can be checked against, by the names
+// Scryfall uses for legalities.
+var DeckFormats = map[string]*DeckFormat{
+	"standard":  constructedFormat,
+	"pioneer":   constructedFormat,
+	"modern":    constructedFormat,
+	"legacy":    constructedFormat,
+	"vintage":   constructedFormat,
+	"pauper":    constructedFormat,
+	"commander": {MinDeck: 100, MaxDeck: 100, MaxCopies: 1, Commander: true},
+}
+
+// Rules a deck can break.
+const (
+	DeckRuleSize          = "deck_size"
+	DeckRuleSideboard     = "sideboard_size"
+	DeckRuleCommander     = "commander"
+	DeckRuleCompanion     = "companion"
+	DeckRuleCopies        = "copies"
+	DeckRuleRestricted    = "restricted"
+	DeckRuleBanned        = "banned"
+	DeckRuleNotLegal      = "not_legal"
+	DeckRuleColorIdentity = "color_identity"
+)
+
+// DeckReport is the result of checking a deck against the rules of its format.
+type DeckReport struct {
+	Format string
+	// Mainboard counts the commanders too.
+	Mainboard     int
+	Sideboard     int
+	Commanders    []string
+	ColorIdentity []string
+	Problems      []*DeckProblem
+	// Unchecked are the cards the catalog doesn't know, so only their
+	// numbers could be checked.
+	Unchecked []string
+}
+
+// Legal reports whether the deck breaks no rule.
+func (r *DeckReport) Legal() bool {
+	return len(r.Problems) == 0
+}
+
+// DeckProblem is a rule a deck breaks and the cards that break it.
+type DeckProblem struct {
+	Rule    string
+	Message string
+	Cards   []string
+}
+
+// DeckCard is a card a deck needs, from a deck collection or a pasted decklist.
+type DeckCard struct {
+	// ScryfallID is empty when a decklist names a card without its printing.
+	ScryfallID string
+	Name       string
+	Count      int
+}
+
+// Buildability tells how much of a deck the cards a user owns cover.
+type Buildability struct {
+	// AnyPrinting is set when any printing of a card covers it, not only the
+	// printing the deck names.
+	AnyPrinting bool
+	Cards       []*BuildabilityCard
+	// EstimatedCost is what the missing copies cost in US dollars, as far as
+	// their prices are known.
+	EstimatedCost float64
+	// Unpriced are the missing cards without a known price.
+	Unpriced []string
+}
+
+// Needed is the number of copies the deck needs.
+func (b *Buildability) Needed() int {
+	needed := 0
+	for _, card := range b.Cards {
+		needed += card.Needed
+	}
+	return needed
+}
+
+// Covered is the number of copies the user owns.
+func (b *Buildability) Covered() int {
+	covered := 0
+	for _, card := range b.Cards {
+		covered += card.Covered()
+	}
+	return covered
+}
+
+// BuildabilityCard is a card of a deck and the owned copies that cover it.
+type BuildabilityCard struct {
+	ScryfallID string
+	Name       string
+	Needed     int
+	Sources    []*CardSource
+	// Price of a copy in US dollars; 0 when it is unknown.
+	Price float64
+}
+
+// Covered is the number of copies taken from the user's collections.
+func (c *BuildabilityCard) Covered() int {
+	covered := 0
+	for _, source := range c.Sources {
+		covered += source.Count
+	}
+	return covered
+}
+
+// Missing is the number of copies the user doesn't own.
+func (c *BuildabilityCard) Missing() int {
+	return c.Needed - c.Covered()
+}
+
+// CardSource is a number of copies of a printing taken from a collection.
+type CardSource struct {
+	CollectionID   string
+	CollectionName string
+	ScryfallID     string
+	Count          int
+	// Reserved copies are allocated to the deck being built.
+	Reserved bool
+	// DeckID and DeckName name the deck the copies are allocated to and
+	// have to be pulled from.
+	DeckID   string
+	DeckName string
+}
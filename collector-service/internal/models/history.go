@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Sources of card changes: where the request came from.
+const (
+	ChangeSourceAPI    = "api"
+	ChangeSourceImport = "import"
+	ChangeSourceBot    = "bot"
+	ChangeSourceTrade  = "trade"
+)
+
+// ChangeSources are the accepted sources of card changes.
+var ChangeSources = []string{ChangeSourceAPI, ChangeSourceImport, ChangeSourceBot, ChangeSourceTrade}
+
+// Actions that change cards.
+const (
+	HistoryActionAdd      = "add"
+	HistoryActionUpdate   = "update"
+	HistoryActionRemove   = "remove"
+	HistoryActionBatch    = "batch"
+	HistoryActionTransfer = "transfer"
+	HistoryActionMerge    = "merge"
+	HistoryActionSplit    = "split"
+	HistoryActionUndo     = "undo"
+)
+
+// DisposalActions are the actions whose removals take copies away from the
+// user; the other removals move copies between collections or undo an addition.
+var DisposalActions = []string{HistoryActionUpdate, HistoryActionRemove, HistoryActionBatch}
+
+// CardChange tells who changes cards and where the change comes from.
+// Every write of cards records it in the card history.
+type CardChange struct {
+	UserID bson.ObjectID
+	Source string
+	// IfVersion, when set, is the version the changed collection must have.
+	IfVersion *int64
+}
+
+// CardHistoryEntry records one change of the count of a card in a collection.
+// Entries are only ever appended.
+type CardHistoryEntry struct {
+	ObjectID bson.ObjectID `bson:"_id,omitempty"`
+	// BatchID is shared by all entries written by one request.
+	BatchID      bson.ObjectID `bson:"batch_id"`
+	UserID       bson.ObjectID `bson:"user_id"`
+	CollectionID bson.ObjectID `bson:"collection_id"`
+	ScryfallID   string        `bson:"scryfall_id"`
+	Name         string        `bson:"name,omitempty"`
+	Zone         string        `bson:"zone,omitempty"`
+	Finish       string        `bson:"finish,omitempty"`
+	Delta        int           `bson:"delta"`
+	Action       string        `bson:"action"`
+	Source       string        `bson:"source"`
+	// RelatedCollectionID is the other side of a transfer, merge or split.
+	RelatedCollectionID bson.ObjectID `bson:"related_collection_id,omitempty"`
+	// UndoOf is the entry this one reverts.
+	UndoOf bson.ObjectID `bson:"undo_of,omitempty"`
+	// CostCopies are the removed copies with a known cost and Cost what they
+	// cost by currency, see Card.RemovedCost.
+	CostCopies int                `bson:"cost_copies,omitempty"`
+	Cost       map[string]float64 `bson:"cost,omitempty"`
+	// Removed is the card as it was before the entry took it out of the
+	// collection, so that an undo brings back its purchases and location.
+	Removed   *Card     `bson:"removed,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// CardKey returns the key of the card the entry changed.
+func (e *CardHistoryEntry) CardKey() CardKey {
+	return CardKey{ScryfallID: e.ScryfallID, Zone: e.Zone, Finish: e.Finish}
+}
+
+// CardChangesFilter selects the history of a user written since a time, oldest first.
+type CardChangesFilter struct {
+	UserID bson.ObjectID
+	// CollectionID limits the history to one collection.
+	CollectionID bson.ObjectID
+	Since        time.Time
+}
+
+// CardHistoryFilter selects the history of a collection, newest entries first.
+type CardHistoryFilter struct {
+	CollectionID bson.ObjectID
+	// ScryfallID limits the history to one card.
+	ScryfallID string
+	// Before returns only entries older than this one, for paging.
+	Before bson.ObjectID
+	Limit  int
+}
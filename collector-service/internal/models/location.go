@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// StorageLocation is a box or a binder a user physically keeps cards in.
+type StorageLocation struct {
+	ID          string        `bson:"-" json:"id"`
+	ObjectID    bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID      bson.ObjectID `bson:"user_id" json:"user_id"`
+	Name        string        `bson:"name" json:"name"`
+	Kind        string        `bson:"kind" json:"kind"`
+	Description string        `bson:"description,omitempty" json:"description,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at" json:"created_at"`
+}
+
+func (l *StorageLocation) PrepareForResponse() {
+	l.ID = l.ObjectID.Hex()
+}
+
+// Storage location kinds. Binders have pages and slots, boxes only sections.
+const (
+	StorageKindBox    = "box"
+	StorageKindBinder = "binder"
+)
+
+// StorageKinds are the accepted storage location kinds.
+var StorageKinds = []string{StorageKindBox, StorageKindBinder}
+
+// Limits for storage locations.
+const (
+	MaxStorageNameLen     = 100
+	MaxLocationSectionLen = 50
+)
+
+// Location is a place in a storage location: a section of a box or a binder,
+// and a page and a slot of a binder. Zero fields are not known.
+type Location struct {
+	StorageID bson.ObjectID `bson:"storage_id" json:"storage_id"`
+	// StorageName is filled in by the services that load storage locations.
+	StorageName string `bson:"-" json:"-"`
+	Section     string `bson:"section,omitempty" json:"section,omitempty"`
+	Page        int    `bson:"page,omitempty" json:"page,omitempty"`
+	Slot        int    `bson:"slot,omitempty" json:"slot,omitempty"`
+}
+
+// CardLocation is where a card of the collection lies: its own location or,
+// without one, the location of the collection. inherited reports the latter.
+func (c *Collection) CardLocation(card *Card) (location *Location, inherited bool) {
+	if card.Location != nil {
+		return card.Location, false
+	}
+	return c.Location, c.Location != nil
+}
+
+// LocatedCard is a card of a collection lying in a storage location.
+type LocatedCard struct {
+	Collection *Collection
+	Card       *Card
+	Location   *Location
+	// Inherited cards have no location of their own and lie where their collection does.
+	Inherited bool
+}
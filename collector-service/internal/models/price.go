@@ -0,0 +1,128 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+)
+
+// CardPrice is a daily price snapshot: what a printing cost on a day.
+// There is at most one snapshot per printing and day.
+type CardPrice struct {
+	ScryfallID string         `bson:"scryfall_id" json:"scryfall_id"`
+	Date       time.Time      `bson:"date" json:"date"`
+	Prices     catalog.Prices `bson:"prices" json:"prices"`
+}
+
+// Currencies are the currencies collections can be valued in.
+var Currencies = []string{catalog.CurrencyUSD, catalog.CurrencyEUR, catalog.CurrencyTix, catalog.CurrencyRUB}
+
+// MarketCurrency is the currency of the market prices an amount in currency
+// comes from: its own if cards are sold in it, US dollars otherwise.
+func MarketCurrency(currency string) string {
+	switch currency {
+	case catalog.CurrencyUSD, catalog.CurrencyEUR, catalog.CurrencyTix:
+		return currency
+	default:
+		return catalog.CurrencyUSD
+	}
+}
+
+// CardValue is what the copies of a card of a collection are worth.
+type CardValue struct {
+	Card *Card
+	// UnitPrice is the price of a copy with the finish of the card.
+	UnitPrice float64
+	Value     float64
+	// PricedAt is the day of the price.
+	PricedAt time.Time
+}
+
+// CollectionValue is what a collection is worth by the latest known prices.
+type CollectionValue struct {
+	Collection *Collection
+	Currency   string
+	Total      float64
+	// PricedCount and UnpricedCount are the copies with and without a known price.
+	PricedCount   int
+	UnpricedCount int
+	// PricedAt is the day of the newest price used, zero if none is known.
+	PricedAt time.Time
+	// Cards are the priced cards, the most valuable first.
+	Cards []*CardValue
+	// Unpriced are the cards without a known price.
+	Unpriced []*Card
+	// RatesDate is the day of the exchange rates the prices were converted at, nil if they were not.
+	RatesDate *time.Time
+}
+
+// RoundPrice rounds an amount of money to cents.
+func RoundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// Intervals of value history points.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+var Intervals = []string{IntervalDay, IntervalWeek, IntervalMonth}
+
+// ValueHistoryQuery selects the points of a value history. Zero fields take defaults.
+type ValueHistoryQuery struct {
+	Currency string
+	Interval string
+	From, To time.Time
+	// Movers is how many of the biggest movers to return.
+	Movers int
+}
+
+// ValuePoint is the value of the cards at the close of a period.
+type ValuePoint struct {
+	// Date is the first day of the period.
+	Date        time.Time
+	Value       float64
+	PricedCount int
+	// Change and ChangePercent are against the previous point; the percent
+	// is nil when the previous value is zero.
+	Change        float64
+	ChangePercent *float64
+}
+
+// CardMove is how the value of the copies of a card changed over a value history.
+type CardMove struct {
+	Card                 *Card
+	StartPrice, EndPrice float64
+	Change               float64
+	ChangePercent        *float64
+}
+
+// ValueHistory is how the value of the cards changed: every point values the
+// cards held at its close, replayed from the card history, at the prices then.
+type ValueHistory struct {
+	// Collection is nil for the history of all collections of a user.
+	Collection    *Collection
+	Currency      string
+	Interval      string
+	From, To      time.Time
+	Points        []*ValuePoint
+	Change        float64
+	ChangePercent *float64
+	// Movers are the cards whose value changed most from the first point to the last.
+	Movers []*CardMove
+	// RatesDate is the day of the exchange rates the prices were converted at, nil if they were not.
+	RatesDate *time.Time
+}
+
+// PercentChange is the change from one amount to another in percent, rounded
+// to hundredths, or nil if the first amount is zero.
+func PercentChange(from, to float64) *float64 {
+	if from == 0 {
+		return nil
+	}
+	percent := math.Round((to-from)/from*10000) / 100
+	return &percent
+}
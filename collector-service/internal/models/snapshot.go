@@ -0,0 +1,123 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// CollectionSnapshot is a named, frozen copy of the cards of a collection.
+// Only what a diff needs is kept for each card.
+type CollectionSnapshot struct {
+	ID           string          `bson:"-" json:"id"`
+	ObjectID     bson.ObjectID   `bson:"_id,omitempty" json:"-"`
+	CollectionID bson.ObjectID   `bson:"collection_id" json:"collection_id"`
+	UserID       bson.ObjectID   `bson:"user_id" json:"user_id"`
+	Name         string          `bson:"name" json:"name"`
+	CardCount    int             `bson:"card_count" json:"card_count"`
+	UniqueCards  int             `bson:"unique_cards" json:"unique_cards"`
+	Cards        []*SnapshotCard `bson:"cards,omitempty" json:"cards,omitempty"`
+	CreatedAt    time.Time       `bson:"created_at" json:"created_at"`
+}
+
+func (s *CollectionSnapshot) PrepareForResponse() {
+	s.ID = s.ObjectID.Hex()
+}
+
+// SnapshotCard is a card of a snapshot. Snapshots taken before finishes were
+// kept have every copy as nonfoil.
+type SnapshotCard struct {
+	ScryfallID string `bson:"scryfall_id" json:"scryfall_id"`
+	Name       string `bson:"name,omitempty" json:"name,omitempty"`
+	Finish     string `bson:"finish,omitempty" json:"finish,omitempty"`
+	Count      int    `bson:"count" json:"count"`
+}
+
+// snapshotKey tells the cards of a snapshot apart: a printing in one finish.
+type snapshotKey struct {
+	ScryfallID string
+	Finish     string
+}
+
+func (c *SnapshotCard) key() snapshotKey {
+	return snapshotKey{ScryfallID: c.ScryfallID, Finish: c.Finish}
+}
+
+func compareSnapshotKeys(a, b snapshotKey) int {
+	return cmp.Or(cmp.Compare(a.ScryfallID, b.ScryfallID), cmp.Compare(a.Finish, b.Finish))
+}
+
+// SnapshotCards freezes cards for a snapshot, sorted by Scryfall ID and finish.
+// The copies of a printing in one finish in all zones make one card.
+func SnapshotCards(cards []*Card) []*SnapshotCard {
+	out := make([]*SnapshotCard, 0, len(cards))
+	index := make(map[snapshotKey]*SnapshotCard, len(cards))
+	for _, c := range cards {
+		key := snapshotKey{ScryfallID: c.ScryfallID, Finish: c.Finish}
+		if s, ok := index[key]; ok {
+			s.Count += c.Count
+			continue
+		}
+		s := &SnapshotCard{ScryfallID: c.ScryfallID, Name: c.Name, Finish: c.Finish, Count: c.Count}
+		index[key] = s
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b *SnapshotCard) int { return compareSnapshotKeys(a.key(), b.key()) })
+	return out
+}
+
+// CardDiff is how the count of a card in a finish differs between two states of a collection.
+type CardDiff struct {
+	ScryfallID string
+	Name       string
+	Finish     string
+	Before     int
+	After      int
+}
+
+func (d *CardDiff) key() snapshotKey {
+	return snapshotKey{ScryfallID: d.ScryfallID, Finish: d.Finish}
+}
+
+// CollectionDiff lists the cards added, removed and with a changed count,
+// each sorted by Scryfall ID and finish. A card that changed finish is removed
+// in one finish and added in the other.
+type CollectionDiff struct {
+	Added   []*CardDiff
+	Removed []*CardDiff
+	Changed []*CardDiff
+}
+
+// DiffCards compares two states of the cards of a collection.
+func DiffCards(before, after []*SnapshotCard) *CollectionDiff {
+	diff := &CollectionDiff{
+		Added:   make([]*CardDiff, 0),
+		Removed: make([]*CardDiff, 0),
+		Changed: make([]*CardDiff, 0),
+	}
+
+	counts := make(map[snapshotKey]*SnapshotCard, len(before))
+	for _, c := range before {
+		counts[c.key()] = c
+	}
+	for _, c := range after {
+		old, ok := counts[c.key()]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, &CardDiff{ScryfallID: c.ScryfallID, Name: c.Name, Finish: c.Finish, After: c.Count})
+		case old.Count != c.Count:
+			diff.Changed = append(diff.Changed, &CardDiff{ScryfallID: c.ScryfallID, Name: c.Name, Finish: c.Finish, Before: old.Count, After: c.Count})
+		}
+		delete(counts, c.key())
+	}
+	for _, c := range counts {
+		diff.Removed = append(diff.Removed, &CardDiff{ScryfallID: c.ScryfallID, Name: c.Name, Finish: c.Finish, Before: c.Count})
+	}
+
+	for _, list := range [][]*CardDiff{diff.Added, diff.Removed, diff.Changed} {
+		slices.SortFunc(list, func(a, b *CardDiff) int { return compareSnapshotKeys(a.key(), b.key()) })
+	}
+	return diff
+}
@@ -7,12 +7,14 @@ import (
 )
 
 type User struct {
-	ID          string               `bson:"-" json:"id"`
-	ObjectID    bson.ObjectID        `bson:"_id,omitempty" json:"-"`
-	TelegramID  int64                `bson:"telegram_id" json:"telegram_id"`
-	FirstName   string               `bson:"first_name" json:"first_name"`
-	LastName    string               `bson:"last_name,omitempty" json:"last_name,omitempty"`
-	Username    string               `bson:"username,omitempty" json:"username,omitempty"`
+	ID         string        `bson:"-" json:"id"`
+	ObjectID   bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	TelegramID int64         `bson:"telegram_id" json:"telegram_id"`
+	FirstName  string        `bson:"first_name" json:"first_name"`
+	LastName   string        `bson:"last_name,omitempty" json:"last_name,omitempty"`
+	Username   string        `bson:"username,omitempty" json:"username,omitempty"`
+	// Currency is the currency amounts are shown in when a request names none.
+	Currency    string               `bson:"currency,omitempty" json:"currency,omitempty"`
 	Collections []*UserCollectionRef `bson:"collections,omitempty" json:"collections,omitempty"`
 	CreatedAt   time.Time            `bson:"created_at,omitempty" json:"created_at"`
 	UpdatedAt   time.Time            `bson:"updated_at,omitempty" json:"updated_at"`
@@ -33,3 +35,14 @@ func (u *User) PrepareForResponse() {
 		u.Collections[i].ID = u.Collections[i].ObjectID.Hex()
 	}
 }
+
+// UserSettings are the preferences of a user with the defaults filled in.
+type UserSettings struct {
+	Currency string
+}
+
+// UserSettingsUpdate changes the preferences of a user; nil fields are kept.
+type UserSettingsUpdate struct {
+	// Currency is one of PurchaseCurrencies, empty for the default.
+	Currency *string
+}
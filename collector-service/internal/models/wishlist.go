@@ -0,0 +1,91 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// WishlistEntry is a card a user wants to get. Wishlists are kept apart from
+// collections, so wanted cards never count as owned.
+type WishlistEntry struct {
+	ID       string        `bson:"-" json:"id"`
+	ObjectID bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID   bson.ObjectID `bson:"user_id" json:"user_id"`
+	// Key identifies the wanted card; a user has at most one entry per key.
+	Key        string `bson:"key" json:"-"`
+	ScryfallID string `bson:"scryfall_id,omitempty" json:"scryfall_id,omitempty"`
+	OracleID   string `bson:"oracle_id,omitempty" json:"oracle_id,omitempty"`
+	Name       string `bson:"name,omitempty" json:"name,omitempty"`
+	// AnyPrinting entries are satisfied by every printing of the card,
+	// the others only by the printing with ScryfallID.
+	AnyPrinting bool `bson:"any_printing" json:"any_printing"`
+	Quantity    int  `bson:"quantity" json:"quantity"`
+	// MaxPrice is the most the user would pay for a copy, in Currency; 0 means no limit.
+	MaxPrice float64 `bson:"max_price,omitempty" json:"max_price,omitempty"`
+	Currency string  `bson:"currency,omitempty" json:"currency,omitempty"`
+	// RatesDate is the day of the exchange rates MaxPrice was converted at for
+	// a response, nil if it was not.
+	RatesDate *time.Time `bson:"-" json:"-"`
+	// Finish is the preferred finish, empty for any.
+	Finish string `bson:"finish,omitempty" json:"finish,omitempty"`
+	// Priority goes from 1 to 5, the most wanted cards have 5.
+	Priority  int       `bson:"priority" json:"priority"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func (e *WishlistEntry) PrepareForResponse() {
+	e.ID = e.ObjectID.Hex()
+}
+
+// SetKey derives Key from the card the entry wants: the printing, the oracle
+// card or, for cards the catalog doesn't know, the name.
+func (e *WishlistEntry) SetKey() {
+	switch {
+	case !e.AnyPrinting:
+		e.Key = "printing:" + e.ScryfallID
+	case e.OracleID != "":
+		e.Key = "oracle:" + e.OracleID
+	default:
+		e.Key = "name:" + strings.ToLower(e.Name)
+	}
+}
+
+// CardFinishes are the finishes of printings, as named by Scryfall.
+var CardFinishes = []string{"nonfoil", "foil", "etched"}
+
+// Wishlist priorities.
+const (
+	MinWishlistPriority     = 1
+	MaxWishlistPriority     = 5
+	DefaultWishlistPriority = 3
+)
+
+// WishlistEntryUpdate is a partial update of a wishlist entry; nil fields are left as is.
+type WishlistEntryUpdate struct {
+	ID       string
+	UserID   bson.ObjectID
+	Quantity *int
+	MaxPrice *float64
+	Currency *string
+	Finish   *string
+	Priority *int
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (u *WishlistEntryUpdate) IsEmpty() bool {
+	return u.Quantity == nil && u.MaxPrice == nil && u.Currency == nil && u.Finish == nil && u.Priority == nil
+}
+
+// MissingCard is a wishlist entry with the copies the user already owns.
+type MissingCard struct {
+	Entry *WishlistEntry
+	Owned int
+}
+
+// Missing is how many copies are still to get.
+func (m *MissingCard) Missing() int {
+	return max(m.Entry.Quantity-m.Owned, 0)
+}
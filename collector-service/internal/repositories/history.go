@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// historyBatch builds the history entries of one write.
+type historyBatch struct {
+	id     bson.ObjectID
+	change *models.CardChange
+	action string
+	now    time.Time
+}
+
+func newHistoryBatch(change *models.CardChange, action string, now time.Time) *historyBatch {
+	return &historyBatch{id: bson.NewObjectID(), change: change, action: action, now: now}
+}
+
+func (b *historyBatch) entry(collectionId, relatedId bson.ObjectID, card *models.Card, delta int) *models.CardHistoryEntry {
+	return &models.CardHistoryEntry{
+		BatchID:             b.id,
+		UserID:              b.change.UserID,
+		CollectionID:        collectionId,
+		ScryfallID:          card.ScryfallID,
+		Name:                card.Name,
+		Zone:                card.Zone,
+		Finish:              card.Finish,
+		Delta:               delta,
+		Action:              b.action,
+		Source:              b.change.Source,
+		RelatedCollectionID: relatedId,
+		CreatedAt:           b.now,
+	}
+}
+
+// diff records how the counts of cards in a collection went from before to after.
+// Cards whose count didn't change get no entry. Removals record the cost of the
+// removed copies, so gains can be realized after the card is gone, and a card
+// removed entirely is kept whole for an undo.
+func (b *historyBatch) diff(collectionId, relatedId bson.ObjectID, before, after []*models.Card) []*models.CardHistoryEntry {
+	var entries []*models.CardHistoryEntry
+	for _, card := range after {
+		delta := card.Count
+		i := models.FindCard(before, card.Key())
+		if i >= 0 {
+			delta -= before[i].Count
+		}
+		if delta != 0 {
+			entry := b.entry(collectionId, relatedId, card, delta)
+			if delta < 0 {
+				entry.CostCopies, entry.Cost = before[i].RemovedCost(card.Count)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	for _, card := range before {
+		if models.FindCard(after, card.Key()) < 0 && card.Count != 0 {
+			entry := b.entry(collectionId, relatedId, card, -card.Count)
+			entry.CostCopies, entry.Cost = card.RemovedCost(0)
+			removed := *card
+			entry.Removed = &removed
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// updatedKeyFree checks that an update moving a card to another deck zone or
+// finish doesn't clash with a card already there.
+func updatedKeyFree(cards []*models.Card, update *models.CardUpdate) *models.ResponseErr {
+	key := update.NewKey()
+	if key == update.Key || models.FindCard(cards, update.Key) < 0 || models.FindCard(cards, key) < 0 {
+		return nil
+	}
+	return &models.ResponseErr{
+		Status:  http.StatusConflict,
+		Message: "Card with this zone and finish is already in the collection",
+	}
+}
+
+// splitAcquisitions splits the acquisitions of a card when count of its copies
+// move to another collection: the moved copies take their share of every
+// purchase, so both sides keep the average price paid. The moved part of a
+// purchase that is split in two gets a new ID.
+func splitAcquisitions(card *models.Card, count int) (moved, kept []*models.Acquisition) {
+	if count >= card.Count {
+		return card.Acquisitions, nil
+	}
+	bought, taken := 0, 0
+	for _, a := range card.Acquisitions {
+		// Rounding the running total keeps the shares adding up to the
+		// proportion of all purchased copies.
+		bought += a.Count
+		share := (bought*count+card.Count/2)/card.Count - taken
+		taken += share
+		if share > 0 {
+			part := *a
+			part.Count = share
+			if share < a.Count {
+				part.ID = bson.NewObjectID().Hex()
+			}
+			moved = append(moved, &part)
+		}
+		if share < a.Count {
+			rest := *a
+			rest.Count = a.Count - share
+			kept = append(kept, &rest)
+		}
+	}
+	return moved, kept
+}
+
+// undoEntries reverts entries on cards, the cards of the collections the
+// entries belong to, and returns the entries recording the undo.
+// A card is created again as it was if it was removed; nothing is changed when
+// any card doesn't have enough copies left to revert an addition.
+func undoEntries(batch *historyBatch, cards map[bson.ObjectID][]*models.Card, entries []*models.CardHistoryEntry) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	undo := make([]*models.CardHistoryEntry, 0, len(entries))
+	for _, e := range entries {
+		list := cards[e.CollectionID]
+		i := models.FindCard(list, e.CardKey())
+		count := -e.Delta
+		if i >= 0 {
+			count += list[i].Count
+		}
+
+		switch {
+		case count < 0:
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Not enough copies of the card to undo the change",
+			}
+		case count == 0 && i >= 0:
+			list = slices.Delete(list, i, i+1)
+		case i >= 0:
+			card := *list[i]
+			card.Count = count
+			card.UpdatedAt = batch.now
+			if e.Removed != nil {
+				card.Acquisitions = append(slices.Clone(card.Acquisitions), e.Removed.Acquisitions...)
+			}
+			list[i] = &card
+		case e.Removed != nil:
+			card := *e.Removed
+			card.Count = count
+			card.UpdatedAt = batch.now
+			list = append(list, &card)
+		case count > 0:
+			list = append(list, &models.Card{
+				ScryfallID: e.ScryfallID,
+				Name:       e.Name,
+				Count:      count,
+				Zone:       e.Zone,
+				Finish:     e.Finish,
+				AddedAt:    batch.now,
+			})
+		}
+		cards[e.CollectionID] = list
+
+		entry := batch.entry(e.CollectionID, e.RelatedCollectionID, &models.Card{ScryfallID: e.ScryfallID, Name: e.Name, Zone: e.Zone, Finish: e.Finish}, -e.Delta)
+		entry.UndoOf = e.ObjectID
+		undo = append(undo, entry)
+	}
+	return undo, nil
+}
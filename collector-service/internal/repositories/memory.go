@@ -0,0 +1,1572 @@
+package repositories
+
+import (
+	"bytes"
+	"cmp"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryRepository keeps users and collections in memory.
+// It behaves like Repository and is meant for tests and local runs without MongoDB.
+type MemoryRepository struct {
+	mu          sync.RWMutex
+	users       map[bson.ObjectID]*models.User
+	collections map[bson.ObjectID]*models.Collection
+	history     []*models.CardHistoryEntry
+	snapshots   map[bson.ObjectID]*models.CollectionSnapshot
+	wishlist    map[bson.ObjectID]*models.WishlistEntry
+	allocations map[bson.ObjectID]*models.Allocation
+	storages    map[bson.ObjectID]*models.StorageLocation
+	// prices are the price snapshots of every printing, oldest first.
+	prices map[string][]*models.CardPrice
+	// rates are the tables of exchange rates, oldest first.
+	rates []*catalog.ExchangeRates
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users:       make(map[bson.ObjectID]*models.User),
+		collections: make(map[bson.ObjectID]*models.Collection),
+		snapshots:   make(map[bson.ObjectID]*models.CollectionSnapshot),
+		wishlist:    make(map[bson.ObjectID]*models.WishlistEntry),
+		allocations: make(map[bson.ObjectID]*models.Allocation),
+		storages:    make(map[bson.ObjectID]*models.StorageLocation),
+		prices:      make(map[string][]*models.CardPrice),
+	}
+}
+
+func (r *MemoryRepository) CreateUser(user *models.User) (*models.User, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.TelegramID == user.TelegramID {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "User with this Telegram ID already exists",
+			}
+		}
+	}
+
+	user.ObjectID = bson.NewObjectID()
+	r.users[user.ObjectID] = copyUser(user)
+
+	user.PrepareForResponse()
+	return user, nil
+}
+
+func (r *MemoryRepository) FindUserByTelegramID(telegramId int64) (*models.User, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.TelegramID == telegramId {
+			user := copyUser(u)
+			user.PrepareForResponse()
+			return user, nil
+		}
+	}
+
+	return nil, &models.ResponseErr{
+		Status:  http.StatusNotFound,
+		Message: "User not found",
+	}
+}
+
+// GetUser returns the user with the ID.
+func (r *MemoryRepository) GetUser(userId string) (*models.User, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[objectID]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+
+	user := copyUser(u)
+	user.PrepareForResponse()
+	return user, nil
+}
+
+// SetUserCurrency changes the currency the user prefers.
+func (r *MemoryRepository) SetUserCurrency(userId, currency string) (*models.User, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[objectID]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+	u.Currency = currency
+	u.UpdatedAt = time.Now()
+
+	user := copyUser(u)
+	user.PrepareForResponse()
+	return user, nil
+}
+
+func (r *MemoryRepository) UsersCollections(userId string) ([]*models.UserCollectionRef, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[objectID]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+
+	user := copyUser(u)
+	user.PrepareForResponse()
+	return user.Collections, nil
+}
+
+func (r *MemoryRepository) CreateCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection.ObjectID = bson.NewObjectID()
+	r.collections[collection.ObjectID] = copyCollection(collection)
+
+	if u, ok := r.users[collection.UserID]; ok {
+		u.Collections = append(u.Collections, &models.UserCollectionRef{
+			ObjectID: collection.ObjectID,
+			Name:     collection.Name,
+		})
+		u.UpdatedAt = time.Now()
+	}
+
+	collection.PrepareForResponse()
+	return collection, nil
+}
+
+func (r *MemoryRepository) UpdateCollection(update *models.CollectionUpdate) (*models.Collection, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(update.ID)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, ok := r.liveCollection(objectId)
+	if !ok || col.UserID != update.UserID {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+	if respErr := col.CheckVersion(update.IfVersion); respErr != nil {
+		return nil, respErr
+	}
+
+	if update.Name != nil {
+		col.Name = *update.Name
+		if u, ok := r.users[col.UserID]; ok {
+			for _, ref := range u.Collections {
+				if ref.ObjectID == objectId {
+					ref.Name = *update.Name
+				}
+			}
+		}
+	}
+	if update.Description != nil {
+		col.Description = *update.Description
+	}
+	if update.Kind != nil {
+		col.Kind = *update.Kind
+	}
+	if update.Format != nil {
+		col.Format = *update.Format
+	}
+	if update.Tags != nil {
+		col.Tags = slices.Clone(*update.Tags)
+	}
+	if update.CoverCard != nil {
+		col.CoverCard = *update.CoverCard
+	}
+	if update.Pinned != nil {
+		col.Pinned = *update.Pinned
+	}
+	if update.SortOrder != nil {
+		col.SortOrder = *update.SortOrder
+	}
+	if update.Location != nil {
+		col.Location = copyLocation(update.Location)
+	}
+	col.UpdatedAt = time.Now()
+	col.Version++
+
+	updated := copyCollection(col)
+	updated.PrepareForResponse()
+	return updated, nil
+}
+
+func (r *MemoryRepository) ListCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.CollectionSummary, 0)
+	for _, col := range r.collections {
+		if col.UserID == objectID && col.DeletedAt == nil {
+			summary := models.Summarize(copyCollection(col))
+			summary.PrepareForResponse()
+			list = append(list, summary)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.CollectionSummary) int {
+		switch {
+		case a.Pinned != b.Pinned && a.Pinned:
+			return -1
+		case a.Pinned != b.Pinned:
+			return 1
+		case a.SortOrder != b.SortOrder:
+			return cmp.Compare(a.SortOrder, b.SortOrder)
+		default:
+			return bytes.Compare(a.ObjectID[:], b.ObjectID[:])
+		}
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) DeleteCollection(collection *models.Collection, ifVersion *int64) *models.ResponseErr {
+	objectId, err := bson.ObjectIDFromHex(collection.ID)
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, ok := r.liveCollection(objectId)
+	if !ok || col.UserID != collection.UserID {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+	if respErr := col.CheckVersion(ifVersion); respErr != nil {
+		return respErr
+	}
+	now := time.Now()
+	col.DeletedAt = &now
+	col.UpdatedAt = now
+	col.Version++
+
+	if u, ok := r.users[collection.UserID]; ok {
+		refs := u.Collections[:0]
+		for _, ref := range u.Collections {
+			if ref.ObjectID != objectId {
+				refs = append(refs, ref)
+			}
+		}
+		u.Collections = refs
+		u.UpdatedAt = now
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) ListDeletedCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.CollectionSummary, 0)
+	for _, col := range r.collections {
+		if col.UserID == objectID && col.DeletedAt != nil {
+			summary := models.Summarize(copyCollection(col))
+			summary.PrepareForResponse()
+			list = append(list, summary)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.CollectionSummary) int {
+		return b.DeletedAt.Compare(*a.DeletedAt)
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) RestoreCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	userObjectId, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, ok := r.collections[objectId]
+	if !ok || col.UserID != userObjectId || col.DeletedAt == nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found in trash",
+		}
+	}
+	for _, other := range r.collections {
+		if other.UserID == userObjectId && other.Name == col.Name && other.DeletedAt == nil {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Collection with this name already exists",
+			}
+		}
+	}
+
+	now := time.Now()
+	col.DeletedAt = nil
+	col.UpdatedAt = now
+	col.Version++
+	if u, ok := r.users[userObjectId]; ok {
+		u.Collections = append(u.Collections, &models.UserCollectionRef{
+			ObjectID: col.ObjectID,
+			Name:     col.Name,
+		})
+		u.UpdatedAt = now
+	}
+
+	restored := copyCollection(col)
+	restored.PrepareForResponse()
+	return restored, nil
+}
+
+func (r *MemoryRepository) PurgeDeletedCollections(before time.Time) (int, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, col := range r.collections {
+		if col.DeletedAt != nil && col.DeletedAt.Before(before) {
+			delete(r.collections, id)
+			maps.DeleteFunc(r.snapshots, func(_ bson.ObjectID, snapshot *models.CollectionSnapshot) bool {
+				return snapshot.CollectionID == id
+			})
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (r *MemoryRepository) MergeCollections(change *models.CardChange, target, source *models.Collection, keepSource bool) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	to, okTo := r.liveCollection(target.ObjectID)
+	from, okFrom := r.liveCollection(source.ObjectID)
+	if !okTo || !okFrom || to.Version != target.Version || from.Version != source.Version {
+		return &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Collection was changed concurrently, try again",
+		}
+	}
+
+	now := time.Now()
+	to.Cards = copyCollection(target).Cards
+	to.UpdatedAt = now
+	to.Version++
+	batch := newHistoryBatch(change, models.HistoryActionMerge, now)
+	r.appendHistory(batch.diff(target.ObjectID, source.ObjectID, nil, source.Cards)...)
+
+	if !keepSource {
+		from.DeletedAt = &now
+		from.UpdatedAt = now
+		from.Version++
+		if u, ok := r.users[source.UserID]; ok {
+			u.Collections = slices.DeleteFunc(u.Collections, func(ref *models.UserCollectionRef) bool { return ref.ObjectID == source.ObjectID })
+			u.UpdatedAt = now
+		}
+		r.appendHistory(batch.diff(source.ObjectID, target.ObjectID, source.Cards, nil)...)
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) SplitCollection(change *models.CardChange, source, created *models.Collection) (*models.Collection, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, ok := r.liveCollection(source.ObjectID)
+	if !ok || from.Version != source.Version {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Collection was changed concurrently, try again",
+		}
+	}
+
+	now := time.Now()
+	from.Cards = copyCollection(source).Cards
+	from.UpdatedAt = now
+	from.Version++
+
+	created.ObjectID = bson.NewObjectID()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.collections[created.ObjectID] = copyCollection(created)
+	if u, ok := r.users[created.UserID]; ok {
+		u.Collections = append(u.Collections, &models.UserCollectionRef{
+			ObjectID: created.ObjectID,
+			Name:     created.Name,
+		})
+		u.UpdatedAt = now
+	}
+
+	batch := newHistoryBatch(change, models.HistoryActionSplit, now)
+	r.appendHistory(batch.diff(created.ObjectID, source.ObjectID, nil, created.Cards)...)
+	r.appendHistory(batch.diff(source.ObjectID, created.ObjectID, created.Cards, nil)...)
+
+	created.PrepareForResponse()
+	return created, nil
+}
+
+func (r *MemoryRepository) GetCollectionByName(collection *models.Collection) (*models.Collection, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, col := range r.collections {
+		if col.Name == collection.Name && col.UserID == collection.UserID && col.DeletedAt == nil {
+			found := copyCollection(col)
+			found.PrepareForResponse()
+			return found, nil
+		}
+	}
+
+	return nil, &models.ResponseErr{
+		Status:  http.StatusNotFound,
+		Message: "Collection not found",
+	}
+}
+
+func (r *MemoryRepository) GetCollection(collectionId string) (*models.Collection, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	col, ok := r.liveCollection(objectId)
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+
+	found := copyCollection(col)
+	found.PrepareForResponse()
+	return found, nil
+}
+
+func (r *MemoryRepository) AddCardToCollection(change *models.CardChange, collectionId string, card *models.Card) *models.ResponseErr {
+	return r.addCard(change, collectionId, card, nil)
+}
+
+// AddReservedCardToCollection adds a card to a deck together with the reservation
+// of its copies; neither is saved if the other fails.
+func (r *MemoryRepository) AddReservedCardToCollection(change *models.CardChange, collectionId string, card *models.Card, allocation *models.Allocation) *models.ResponseErr {
+	return r.addCard(change, collectionId, card, allocation)
+}
+
+func (r *MemoryRepository) addCard(change *models.CardChange, collectionId string, card *models.Card, allocation *models.Allocation) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, respErr := r.collection(collectionId)
+	if respErr != nil {
+		return respErr
+	}
+
+	now := time.Now()
+	return r.changeCards(col, newHistoryBatch(change, models.HistoryActionAdd, now), func() *models.ResponseErr {
+		if allocation != nil {
+			if respErr := r.storeAllocation(allocation); respErr != nil {
+				return respErr
+			}
+		}
+		col.UpdatedAt = now
+		if i := models.FindCard(col.Cards, card.Key()); i >= 0 {
+			col.Cards[i].Count += card.Count
+			return nil
+		}
+
+		added := *card
+		col.Cards = append(col.Cards, &added)
+		return nil
+	})
+}
+
+func (r *MemoryRepository) UpdateCardInCollection(change *models.CardChange, collectionId string, card *models.CardUpdate) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, respErr := r.collection(collectionId)
+	if respErr != nil {
+		return respErr
+	}
+
+	now := time.Now()
+	return r.changeCards(col, newHistoryBatch(change, models.HistoryActionUpdate, now), func() *models.ResponseErr {
+		if card.Removes() {
+			i := models.FindCard(col.Cards, card.Key)
+			if i < 0 {
+				return &models.ResponseErr{
+					Status:  http.StatusNotFound,
+					Message: "Card not found",
+				}
+			}
+			col.Cards = slices.Delete(col.Cards, i, i+1)
+			col.UpdatedAt = now
+			return nil
+		}
+		if respErr := updatedKeyFree(col.Cards, card); respErr != nil {
+			return respErr
+		}
+		for _, c := range col.Cards {
+			if c.Key() != card.Key {
+				continue
+			}
+			if card.Count != nil {
+				c.Count = *card.Count
+			}
+			if card.Notes != nil {
+				c.Notes = *card.Notes
+			}
+			if card.Condition != nil {
+				c.Condition = *card.Condition
+			}
+			if card.Zone != nil {
+				c.Zone = *card.Zone
+			}
+			if card.Finish != nil {
+				c.Finish = *card.Finish
+			}
+			c.UpdatedAt = now
+			col.UpdatedAt = now
+			return nil
+		}
+
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Card not found",
+		}
+	})
+}
+
+func (r *MemoryRepository) SetCollectionCards(change *models.CardChange, collection *models.Collection) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, ok := r.liveCollection(collection.ObjectID)
+	if !ok || col.Version != collection.Version {
+		return &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Collection was changed concurrently, try again",
+		}
+	}
+
+	now := time.Now()
+	return r.changeCards(col, newHistoryBatch(change, models.HistoryActionBatch, now), func() *models.ResponseErr {
+		col.Cards = copyCollection(collection).Cards
+		col.UpdatedAt = now
+		return nil
+	})
+}
+
+func (r *MemoryRepository) TransferCard(change *models.CardChange, transfer *models.CardTransfer) (*models.CardTransferResult, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, okFrom := r.liveCollection(transfer.FromCollectionID)
+	to, okTo := r.liveCollection(transfer.ToCollectionID)
+	if !okFrom || !okTo {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+
+	if respErr := from.CheckVersion(change.IfVersion); respErr != nil {
+		return nil, respErr
+	}
+
+	i := models.FindCard(from.Cards, transfer.Key)
+	if i < 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Card not found",
+		}
+	}
+	card := from.Cards[i]
+	if card.Count < transfer.Count {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Not enough copies of the card in the collection",
+		}
+	}
+
+	now := time.Now()
+	moved := *card
+	moved.Count = transfer.Count
+	moved.UpdatedAt = now
+	moved.Acquisitions = nil
+	if transfer.Copy {
+		moved.AddedAt = now
+	}
+
+	if !transfer.Copy {
+		moved.Acquisitions, card.Acquisitions = splitAcquisitions(card, transfer.Count)
+		card.Count -= transfer.Count
+		card.UpdatedAt = now
+		if card.Count == 0 {
+			from.Cards = slices.Delete(from.Cards, i, i+1)
+		}
+		from.UpdatedAt = now
+		from.Version++
+	}
+
+	toKey := to.TransferKey(transfer.Key)
+	placed := moved
+	placed.Zone = toKey.Zone
+	result := &models.CardTransferResult{FromCount: card.Count, ToCount: transfer.Count}
+	if j := models.FindCard(to.Cards, toKey); j >= 0 {
+		to.Cards[j].Count += transfer.Count
+		to.Cards[j].Acquisitions = append(to.Cards[j].Acquisitions, moved.Acquisitions...)
+		to.Cards[j].UpdatedAt = now
+		result.ToCount = to.Cards[j].Count
+	} else {
+		to.Cards = append(to.Cards, &placed)
+	}
+	to.UpdatedAt = now
+	to.Version++
+
+	batch := newHistoryBatch(change, models.HistoryActionTransfer, now)
+	r.appendHistory(batch.entry(transfer.ToCollectionID, transfer.FromCollectionID, &placed, transfer.Count))
+	if !transfer.Copy {
+		r.appendHistory(batch.entry(transfer.FromCollectionID, transfer.ToCollectionID, &moved, -transfer.Count))
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) DeleteCardFromCollection(change *models.CardChange, collectionId string, card *models.Card) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	col, respErr := r.collection(collectionId)
+	if respErr != nil {
+		return respErr
+	}
+
+	now := time.Now()
+	return r.changeCards(col, newHistoryBatch(change, models.HistoryActionRemove, now), func() *models.ResponseErr {
+		if i := models.FindCard(col.Cards, card.Key()); i >= 0 {
+			col.Cards = slices.Delete(col.Cards, i, i+1)
+			col.UpdatedAt = now
+			return nil
+		}
+
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Card not found",
+		}
+	})
+}
+
+func (r *MemoryRepository) ListCardHistory(filter *models.CardHistoryFilter) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.CardHistoryEntry, 0)
+	for i := len(r.history) - 1; i >= 0 && len(list) < filter.Limit; i-- {
+		e := r.history[i]
+		if e.CollectionID != filter.CollectionID ||
+			(filter.ScryfallID != "" && e.ScryfallID != filter.ScryfallID) ||
+			(!filter.Before.IsZero() && bytes.Compare(e.ObjectID[:], filter.Before[:]) >= 0) {
+			continue
+		}
+		entry := *e
+		list = append(list, &entry)
+	}
+
+	return list, nil
+}
+
+func (r *MemoryRepository) GetCardHistoryEntry(entryId string) (*models.CardHistoryEntry, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(entryId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid history entry ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.history {
+		if e.ObjectID == objectId {
+			entry := *e
+			return &entry, nil
+		}
+	}
+
+	return nil, &models.ResponseErr{
+		Status:  http.StatusNotFound,
+		Message: "History entry not found",
+	}
+}
+
+func (r *MemoryRepository) CardHistoryBatch(batchId bson.ObjectID) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.CardHistoryEntry, 0)
+	for _, e := range r.history {
+		if e.BatchID == batchId {
+			entry := *e
+			list = append(list, &entry)
+		}
+	}
+
+	return list, nil
+}
+
+func (r *MemoryRepository) UndoCardChanges(change *models.CardChange, entries []*models.CardHistoryEntry) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.history {
+		if !e.UndoOf.IsZero() && slices.ContainsFunc(entries, func(u *models.CardHistoryEntry) bool { return u.ObjectID == e.UndoOf }) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Change was already undone",
+			}
+		}
+	}
+
+	cards := make(map[bson.ObjectID][]*models.Card)
+	for _, e := range entries {
+		col, ok := r.liveCollection(e.CollectionID)
+		if !ok {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Collection not found",
+			}
+		}
+		if _, ok := cards[e.CollectionID]; !ok {
+			cards[e.CollectionID] = copyCollection(col).Cards
+		}
+	}
+
+	now := time.Now()
+	undo, respErr := undoEntries(newHistoryBatch(change, models.HistoryActionUndo, now), cards, entries)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	for id, list := range cards {
+		col := r.collections[id]
+		col.Cards = list
+		col.UpdatedAt = now
+		col.Version++
+	}
+	r.appendHistory(undo...)
+
+	result := make([]*models.CardHistoryEntry, 0, len(undo))
+	for _, e := range undo {
+		entry := *e
+		result = append(result, &entry)
+	}
+	return result, nil
+}
+
+// ListDisposals returns the history entries of a user that took copies with a
+// known cost away from the user and were not undone, oldest first.
+func (r *MemoryRepository) ListCardChanges(filter *models.CardChangesFilter) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.CardHistoryEntry, 0)
+	for _, e := range r.history {
+		if (filter.CollectionID.IsZero() && e.UserID != filter.UserID) ||
+			(!filter.CollectionID.IsZero() && e.CollectionID != filter.CollectionID) ||
+			e.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		entry := *e
+		list = append(list, &entry)
+	}
+
+	return list, nil
+}
+
+func (r *MemoryRepository) ListDisposals(userId string) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	undone := make(map[bson.ObjectID]bool)
+	for _, e := range r.history {
+		if e.UserID == objectID && !e.UndoOf.IsZero() {
+			undone[e.UndoOf] = true
+		}
+	}
+
+	list := make([]*models.CardHistoryEntry, 0)
+	for _, e := range r.history {
+		if e.UserID == objectID && e.CostCopies > 0 && slices.Contains(models.DisposalActions, e.Action) && !undone[e.ObjectID] {
+			entry := *e
+			list = append(list, &entry)
+		}
+	}
+
+	return list, nil
+}
+
+func (r *MemoryRepository) CreateSnapshot(snapshot *models.CollectionSnapshot) (*models.CollectionSnapshot, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.snapshots {
+		if other.CollectionID == snapshot.CollectionID && other.Name == snapshot.Name {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Snapshot with this name already exists",
+			}
+		}
+	}
+
+	stored := copySnapshot(snapshot)
+	stored.ObjectID = bson.NewObjectID()
+	r.snapshots[stored.ObjectID] = stored
+
+	created := copySnapshot(stored)
+	created.PrepareForResponse()
+	return created, nil
+}
+
+func (r *MemoryRepository) ListSnapshots(collectionId bson.ObjectID) ([]*models.CollectionSnapshot, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.CollectionSnapshot, 0)
+	for _, snapshot := range r.snapshots {
+		if snapshot.CollectionID != collectionId {
+			continue
+		}
+		s := *snapshot
+		s.Cards = nil
+		s.PrepareForResponse()
+		list = append(list, &s)
+	}
+	slices.SortFunc(list, func(a, b *models.CollectionSnapshot) int {
+		return -bytes.Compare(a.ObjectID[:], b.ObjectID[:])
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) GetSnapshot(snapshotId string) (*models.CollectionSnapshot, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(snapshotId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid snapshot ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot, ok := r.snapshots[objectId]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Snapshot not found",
+		}
+	}
+
+	found := copySnapshot(snapshot)
+	found.PrepareForResponse()
+	return found, nil
+}
+
+func (r *MemoryRepository) DeleteSnapshot(snapshot *models.CollectionSnapshot) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.snapshots[snapshot.ObjectID]; !ok {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Snapshot not found",
+		}
+	}
+	delete(r.snapshots, snapshot.ObjectID)
+
+	return nil
+}
+
+// ListCollectionsWithCards returns every collection of a user that is not in the trash, with its cards.
+func (r *MemoryRepository) ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.Collection, 0)
+	for _, col := range r.collections {
+		if col.UserID == objectID && col.DeletedAt == nil {
+			c := copyCollection(col)
+			c.PrepareForResponse()
+			list = append(list, c)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.Collection) int {
+		return bytes.Compare(a.ObjectID[:], b.ObjectID[:])
+	})
+
+	return list, nil
+}
+
+// FindOwnedCards returns the collections of a user, except the trash and
+// wishlists, that hold a card match picks, with only the cards it picks.
+func (r *MemoryRepository) FindOwnedCards(userId string, match *models.OwnedCardMatch) ([]*models.Collection, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.Collection, 0)
+	for _, col := range r.collections {
+		if col.UserID != objectID || col.DeletedAt != nil || col.Kind == models.CollectionKindWishlist {
+			continue
+		}
+		c := copyCollection(col)
+		c.Cards = slices.DeleteFunc(c.Cards, func(card *models.Card) bool { return !match.Matches(card) })
+		if len(c.Cards) > 0 {
+			c.PrepareForResponse()
+			list = append(list, c)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.Collection) int {
+		return bytes.Compare(a.ObjectID[:], b.ObjectID[:])
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) CreateWishlistEntry(entry *models.WishlistEntry) (*models.WishlistEntry, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.wishlist {
+		if other.UserID == entry.UserID && other.Key == entry.Key {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Card is already on the wishlist",
+			}
+		}
+	}
+
+	entry.ObjectID = bson.NewObjectID()
+	stored := *entry
+	r.wishlist[stored.ObjectID] = &stored
+
+	entry.PrepareForResponse()
+	return entry, nil
+}
+
+// ListWishlist returns the wishlist of a user by priority, highest first, then oldest first.
+func (r *MemoryRepository) ListWishlist(userId string) ([]*models.WishlistEntry, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.WishlistEntry, 0)
+	for _, entry := range r.wishlist {
+		if entry.UserID == objectID {
+			e := *entry
+			e.PrepareForResponse()
+			list = append(list, &e)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.WishlistEntry) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+		return bytes.Compare(a.ObjectID[:], b.ObjectID[:])
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) GetWishlistEntry(entryId string) (*models.WishlistEntry, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(entryId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid wishlist entry ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.wishlist[objectId]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Wishlist entry not found",
+		}
+	}
+
+	found := *entry
+	found.PrepareForResponse()
+	return &found, nil
+}
+
+func (r *MemoryRepository) UpdateWishlistEntry(update *models.WishlistEntryUpdate) (*models.WishlistEntry, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(update.ID)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid wishlist entry ID format",
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.wishlist[objectId]
+	if !ok || entry.UserID != update.UserID {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Wishlist entry not found",
+		}
+	}
+
+	if update.Quantity != nil {
+		entry.Quantity = *update.Quantity
+	}
+	if update.MaxPrice != nil {
+		entry.MaxPrice = *update.MaxPrice
+	}
+	if update.Currency != nil {
+		entry.Currency = *update.Currency
+	}
+	if update.Finish != nil {
+		entry.Finish = *update.Finish
+	}
+	if update.Priority != nil {
+		entry.Priority = *update.Priority
+	}
+	entry.UpdatedAt = time.Now()
+
+	updated := *entry
+	updated.PrepareForResponse()
+	return &updated, nil
+}
+
+func (r *MemoryRepository) DeleteWishlistEntry(entry *models.WishlistEntry) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.wishlist[entry.ObjectID]; !ok {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Wishlist entry not found",
+		}
+	}
+	delete(r.wishlist, entry.ObjectID)
+
+	return nil
+}
+
+func (r *MemoryRepository) CreateAllocation(allocation *models.Allocation) (*models.Allocation, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if respErr := r.storeAllocation(allocation); respErr != nil {
+		return nil, respErr
+	}
+	allocation.PrepareForResponse()
+	return allocation, nil
+}
+
+// storeAllocation saves a new reservation. The caller must hold the lock.
+func (r *MemoryRepository) storeAllocation(allocation *models.Allocation) *models.ResponseErr {
+	for _, other := range r.allocations {
+		if other.DeckID == allocation.DeckID && other.SourceID == allocation.SourceID && other.ScryfallID == allocation.ScryfallID {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Card is already reserved from this collection",
+			}
+		}
+	}
+
+	allocation.ObjectID = bson.NewObjectID()
+	stored := *allocation
+	r.allocations[stored.ObjectID] = &stored
+	return nil
+}
+
+// ListAllocations returns the reservations of a user, oldest first.
+func (r *MemoryRepository) ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.Allocation, 0)
+	for _, allocation := range r.allocations {
+		if allocation.UserID == objectID {
+			a := *allocation
+			a.PrepareForResponse()
+			list = append(list, &a)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.Allocation) int {
+		return bytes.Compare(a.ObjectID[:], b.ObjectID[:])
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) GetAllocation(allocationId string) (*models.Allocation, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(allocationId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid allocation ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	allocation, ok := r.allocations[objectId]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Allocation not found",
+		}
+	}
+
+	found := *allocation
+	found.PrepareForResponse()
+	return &found, nil
+}
+
+func (r *MemoryRepository) DeleteAllocation(allocation *models.Allocation) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.allocations[allocation.ObjectID]; !ok {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Allocation not found",
+		}
+	}
+	delete(r.allocations, allocation.ObjectID)
+
+	return nil
+}
+
+// changeCards runs write on a collection of the version the change expects,
+// bumps the version and records in the history how write changed the cards.
+// The caller must hold the lock.
+func (r *MemoryRepository) CreateStorageLocation(location *models.StorageLocation) (*models.StorageLocation, *models.ResponseErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.storages {
+		if other.UserID == location.UserID && strings.EqualFold(other.Name, location.Name) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Location already exists",
+			}
+		}
+	}
+
+	location.ObjectID = bson.NewObjectID()
+	stored := *location
+	r.storages[stored.ObjectID] = &stored
+
+	location.PrepareForResponse()
+	return location, nil
+}
+
+// ListStorageLocations returns the storage locations of a user by name.
+func (r *MemoryRepository) ListStorageLocations(userId string) ([]*models.StorageLocation, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.StorageLocation, 0)
+	for _, location := range r.storages {
+		if location.UserID == objectID {
+			l := *location
+			l.PrepareForResponse()
+			list = append(list, &l)
+		}
+	}
+	slices.SortFunc(list, func(a, b *models.StorageLocation) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), bytes.Compare(a.ObjectID[:], b.ObjectID[:]))
+	})
+
+	return list, nil
+}
+
+func (r *MemoryRepository) GetStorageLocation(locationId string) (*models.StorageLocation, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(locationId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid location ID format",
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	location, ok := r.storages[objectId]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Location not found",
+		}
+	}
+
+	found := *location
+	found.PrepareForResponse()
+	return &found, nil
+}
+
+func (r *MemoryRepository) DeleteStorageLocation(location *models.StorageLocation) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.storages[location.ObjectID]; !ok {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Location not found",
+		}
+	}
+	delete(r.storages, location.ObjectID)
+
+	return nil
+}
+
+// SaveCardPrices stores price snapshots, replacing the ones of the same printing and day.
+func (r *MemoryRepository) SaveCardPrices(prices []*models.CardPrice) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, price := range prices {
+		saved := *price
+		list := r.prices[price.ScryfallID]
+		i, found := slices.BinarySearchFunc(list, price.Date, func(p *models.CardPrice, date time.Time) int {
+			return p.Date.Compare(date)
+		})
+		if found {
+			list[i] = &saved
+		} else {
+			r.prices[price.ScryfallID] = slices.Insert(list, i, &saved)
+		}
+	}
+
+	return nil
+}
+
+// LatestCardPrices returns the newest price snapshot of each printing that has one.
+func (r *MemoryRepository) LatestCardPrices(scryfallIds []string) (map[string]*models.CardPrice, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	latest := make(map[string]*models.CardPrice)
+	for _, id := range scryfallIds {
+		if list := r.prices[id]; len(list) > 0 {
+			price := *list[len(list)-1]
+			latest[id] = &price
+		}
+	}
+
+	return latest, nil
+}
+
+// SaveExchangeRates stores a table of exchange rates, replacing the one of the same day.
+func (r *MemoryRepository) SaveExchangeRates(rates *catalog.ExchangeRates) *models.ResponseErr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := *rates
+	saved.Rates = maps.Clone(rates.Rates)
+	i, found := slices.BinarySearchFunc(r.rates, rates.Date, func(e *catalog.ExchangeRates, date time.Time) int {
+		return e.Date.Compare(date)
+	})
+	if found {
+		r.rates[i] = &saved
+	} else {
+		r.rates = slices.Insert(r.rates, i, &saved)
+	}
+
+	return nil
+}
+
+// LatestExchangeRates returns the newest table of exchange rates, nil if there is none.
+func (r *MemoryRepository) LatestExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.rates) == 0 {
+		return nil, nil
+	}
+	rates := *r.rates[len(r.rates)-1]
+	rates.Rates = maps.Clone(rates.Rates)
+	return &rates, nil
+}
+
+// ListCardPrices returns the price snapshots of each printing from the day of
+// from to the day of to, the oldest first.
+func (r *MemoryRepository) ListCardPrices(scryfallIds []string, from, to time.Time) (map[string][]*models.CardPrice, *models.ResponseErr) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := make(map[string][]*models.CardPrice)
+	for _, id := range scryfallIds {
+		for _, p := range r.prices[id] {
+			if p.Date.Before(from) || p.Date.After(to) {
+				continue
+			}
+			price := *p
+			history[id] = append(history[id], &price)
+		}
+	}
+
+	return history, nil
+}
+
+func (r *MemoryRepository) changeCards(col *models.Collection, batch *historyBatch, write func() *models.ResponseErr) *models.ResponseErr {
+	if respErr := col.CheckVersion(batch.change.IfVersion); respErr != nil {
+		return respErr
+	}
+
+	before := copyCollection(col).Cards
+	if respErr := write(); respErr != nil {
+		return respErr
+	}
+	col.Version++
+
+	r.appendHistory(batch.diff(col.ObjectID, bson.ObjectID{}, before, col.Cards)...)
+	return nil
+}
+
+// appendHistory stores history entries. The caller must hold the lock.
+func (r *MemoryRepository) appendHistory(entries ...*models.CardHistoryEntry) {
+	for _, e := range entries {
+		e.ObjectID = bson.NewObjectID()
+		r.history = append(r.history, e)
+	}
+}
+
+// liveCollection returns the stored collection unless it is in the trash.
+// The caller must hold the lock.
+func (r *MemoryRepository) liveCollection(id bson.ObjectID) (*models.Collection, bool) {
+	col, ok := r.collections[id]
+	if !ok || col.DeletedAt != nil {
+		return nil, false
+	}
+	return col, true
+}
+
+// collection returns the stored collection itself. The caller must hold the lock.
+func (r *MemoryRepository) collection(collectionId string) (*models.Collection, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	col, ok := r.liveCollection(objectId)
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+	return col, nil
+}
+
+func copyUser(u *models.User) *models.User {
+	user := *u
+	user.Collections = make([]*models.UserCollectionRef, 0, len(u.Collections))
+	for _, ref := range u.Collections {
+		c := *ref
+		user.Collections = append(user.Collections, &c)
+	}
+	return &user
+}
+
+func copyCollection(c *models.Collection) *models.Collection {
+	col := *c
+	col.Tags = slices.Clone(c.Tags)
+	if c.DeletedAt != nil {
+		deletedAt := *c.DeletedAt
+		col.DeletedAt = &deletedAt
+	}
+	col.Location = copyLocation(c.Location)
+	col.Cards = nil
+	for _, card := range c.Cards {
+		cc := *card
+		cc.Location = copyLocation(card.Location)
+		cc.Acquisitions = copyAcquisitions(card.Acquisitions)
+		col.Cards = append(col.Cards, &cc)
+	}
+	return &col
+}
+
+// copyLocation copies a location; a location without a storage is no location.
+func copyLocation(l *models.Location) *models.Location {
+	if l == nil || l.StorageID.IsZero() {
+		return nil
+	}
+	location := *l
+	location.StorageName = ""
+	return &location
+}
+
+func copyAcquisitions(list []*models.Acquisition) []*models.Acquisition {
+	var out []*models.Acquisition
+	for _, a := range list {
+		acquisition := *a
+		out = append(out, &acquisition)
+	}
+	return out
+}
+
+func copySnapshot(s *models.CollectionSnapshot) *models.CollectionSnapshot {
+	snapshot := *s
+	snapshot.Cards = nil
+	for _, card := range s.Cards {
+		cc := *card
+		snapshot.Cards = append(snapshot.Cards, &cc)
+	}
+	return &snapshot
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
 	"time"
 
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -22,14 +25,89 @@ const (
 	database               = "collector_ouphe_db"
 	users_collection       = "users"
 	collections_collection = "collections"
+	history_collection     = "card_history"
+	snapshots_collection   = "collection_snapshots"
+	wishlist_collection    = "wishlist"
+	allocations_collection = "card_allocations"
+	storages_collection    = "storage_locations"
+	prices_collection      = "card_prices"
+	rates_collection       = "exchange_rates"
 )
 
+// notDeleted matches collections that are not in the trash.
+var notDeleted = bson.E{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: false}}}
+
+// cardKey matches the card of a collection with key. Empty fields of a card
+// are not stored, and null matches a missing field.
+func cardKey(key models.CardKey) bson.D {
+	stored := func(value string) any {
+		if value == "" {
+			return nil
+		}
+		return value
+	}
+	return bson.D{
+		{Key: "scryfall_id", Value: key.ScryfallID},
+		{Key: "zone", Value: stored(key.Zone)},
+		{Key: "finish", Value: stored(key.Finish)},
+	}
+}
+
+// hasCard matches collections with the card with key, for the positional operator.
+func hasCard(key models.CardKey) bson.E {
+	return bson.E{Key: "cards", Value: bson.D{{Key: "$elemMatch", Value: cardKey(key)}}}
+}
+
+// atVersion matches collections of version. Collections stored before they had
+// versions have no version field, which reads as version 0.
+func atVersion(version int64) bson.E {
+	if version == 0 {
+		return bson.E{Key: "version", Value: bson.D{{Key: "$in", Value: bson.A{0, nil}}}}
+	}
+	return bson.E{Key: "version", Value: version}
+}
+
 func NewRepository(client *mongo.Client) *Repository {
 	return &Repository{
 		client: client,
 	}
 }
 
+// indexes are the indexes the queries of the repository rely on, by collection.
+var indexes = map[string][]mongo.IndexModel{
+	// One snapshot per printing and day, which the daily import upserts by.
+	prices_collection: {{
+		Keys:    bson.D{{Key: "scryfall_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	rates_collection: {{
+		Keys:    bson.D{{Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	history_collection: {
+		{Keys: bson.D{{Key: "collection_id", Value: 1}, {Key: "_id", Value: -1}}},
+		// Disposals of a user, for realized gains.
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: 1}}},
+	},
+	// Copies of a printing across collections, for the owned cards look-up.
+	collections_collection: {{
+		Keys: bson.D{{Key: "cards.scryfall_id", Value: 1}},
+	}},
+	allocations_collection: {{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+	}},
+}
+
+// CreateIndexes creates the indexes the repository needs; existing ones are left as they are.
+func (r Repository) CreateIndexes(ctx context.Context) error {
+	for name, list := range indexes {
+		if _, err := r.client.Database(database).Collection(name).Indexes().CreateMany(ctx, list); err != nil {
+			return fmt.Errorf("create indexes of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 func (r Repository) CreateUser(user *models.User) (*models.User, *models.ResponseErr) {
 	collection := r.client.Database(database).Collection(users_collection)
 
@@ -84,6 +162,73 @@ func (r Repository) FindUserByTelegramID(telegramId int64) (*models.User, *model
 	return &user, nil
 }
 
+// GetUser returns the user with the ID.
+func (r Repository) GetUser(userId string) (*models.User, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	collection := r.client.Database(database).Collection(users_collection)
+	filter := bson.D{{Key: "_id", Value: objectID}}
+
+	var user models.User
+	if err := collection.FindOne(context.TODO(), filter).Decode(&user); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "User not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find user error: %v", err),
+		}
+	}
+
+	user.PrepareForResponse()
+	return &user, nil
+}
+
+// SetUserCurrency changes the currency the user prefers.
+func (r Repository) SetUserCurrency(userId, currency string) (*models.User, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	filter := bson.D{{Key: "_id", Value: objectID}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "currency", Value: currency},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var user models.User
+	err = r.client.Database(database).Collection(users_collection).FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "User not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Error updating user: %v", err),
+		}
+	}
+
+	user.PrepareForResponse()
+	return &user, nil
+}
+
 // Search names in Users collection
 func (r Repository) UsersCollections(userId string) ([]*models.UserCollectionRef, *models.ResponseErr) {
 	objectID, err := bson.ObjectIDFromHex(userId)
@@ -152,9 +297,10 @@ func (r Repository) CreateCollection(collection *models.Collection) (*models.Col
 	return collection, nil
 }
 
-func (r Repository) RenameCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr) {
+// UpdateCollection changes the fields set in update of a collection owned by update.UserID.
+func (r Repository) UpdateCollection(update *models.CollectionUpdate) (*models.Collection, *models.ResponseErr) {
 	collectionRef := r.client.Database(database).Collection(collections_collection)
-	objectId, err := bson.ObjectIDFromHex(collection.ID)
+	objectId, err := bson.ObjectIDFromHex(update.ID)
 	if err != nil {
 		return nil, &models.ResponseErr{
 			Status:  http.StatusBadRequest,
@@ -162,15 +308,65 @@ func (r Repository) RenameCollection(collection *models.Collection) (*models.Col
 		}
 	}
 
-	filter := bson.D{{Key: "_id", Value: objectId}}
-	update := bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: collection.Name}}}}
+	set := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if update.Name != nil {
+		set = append(set, bson.E{Key: "name", Value: *update.Name})
+	}
+	if update.Description != nil {
+		set = append(set, bson.E{Key: "description", Value: *update.Description})
+	}
+	if update.Kind != nil {
+		set = append(set, bson.E{Key: "kind", Value: *update.Kind})
+	}
+	if update.Format != nil {
+		set = append(set, bson.E{Key: "format", Value: *update.Format})
+	}
+	if update.Tags != nil {
+		set = append(set, bson.E{Key: "tags", Value: *update.Tags})
+	}
+	if update.CoverCard != nil {
+		set = append(set, bson.E{Key: "cover_card", Value: *update.CoverCard})
+	}
+	if update.Pinned != nil {
+		set = append(set, bson.E{Key: "pinned", Value: *update.Pinned})
+	}
+	if update.SortOrder != nil {
+		set = append(set, bson.E{Key: "sort_order", Value: *update.SortOrder})
+	}
+	unset := bson.D{}
+	if update.Location != nil {
+		if update.Location.StorageID.IsZero() {
+			unset = append(unset, bson.E{Key: "location", Value: ""})
+		} else {
+			set = append(set, bson.E{Key: "location", Value: update.Location})
+		}
+	}
 
+	filter := bson.D{
+		{Key: "_id", Value: objectId},
+		{Key: "user_id", Value: update.UserID},
+		notDeleted,
+	}
+	versioned := filter
+	if update.IfVersion != nil {
+		versioned = append(slices.Clone(filter), atVersion(*update.IfVersion))
+	}
+	changes := bson.D{
+		{Key: "$set", Value: set},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+	if len(unset) > 0 {
+		changes = append(changes, bson.E{Key: "$unset", Value: unset})
+	}
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
 	var updated models.Collection
-	err = collectionRef.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&updated)
+	err = collectionRef.FindOneAndUpdate(context.TODO(), versioned, changes, opts).Decode(&updated)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			if update.IfVersion != nil {
+				return nil, r.versionMismatch(context.TODO(), filter)
+			}
 			return nil, &models.ResponseErr{
 				Status:  http.StatusNotFound,
 				Message: "Collection not found",
@@ -182,27 +378,80 @@ func (r Repository) RenameCollection(collection *models.Collection) (*models.Col
 		}
 	}
 
-	// TODO: Update collection name in user's collections
-	userCollectionRef := r.client.Database(database).Collection(users_collection)
-	userFilter := bson.M{"collections._id": objectId}
-	userUpdate := bson.M{
-		"$set": bson.M{
-			"collections.$.name": collection.Name,
-		},
+	if update.Name != nil {
+		userCollectionRef := r.client.Database(database).Collection(users_collection)
+		userFilter := bson.M{"collections._id": objectId}
+		userUpdate := bson.M{
+			"$set": bson.M{
+				"collections.$.name": *update.Name,
+			},
+		}
+
+		_, err = userCollectionRef.UpdateMany(context.TODO(), userFilter, userUpdate)
+		if err != nil {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Error updating user collections: %v", err),
+			}
+		}
 	}
 
-	_, err = userCollectionRef.UpdateMany(context.TODO(), userFilter, userUpdate)
+	updated.PrepareForResponse()
+	return &updated, nil
+}
+
+// ListCollections returns the collections of a user with card totals but without
+// cards: pinned first, then by sort order, then oldest first.
+func (r Repository) ListCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "user_id", Value: objectID}, notDeleted}}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "card_count", Value: bson.D{{Key: "$sum", Value: "$cards.count"}}},
+			{Key: "unique_cards", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$cards", bson.A{}}}}}}},
+			{Key: "pinned", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$pinned", false}}}},
+			{Key: "sort_order", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$sort_order", 0}}}},
+		}}},
+		{{Key: "$unset", Value: "cards"}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "pinned", Value: -1},
+			{Key: "sort_order", Value: 1},
+			{Key: "_id", Value: 1},
+		}}},
+	}
+
+	cursor, err := r.client.Database(database).Collection(collections_collection).Aggregate(context.TODO(), pipeline)
 	if err != nil {
 		return nil, &models.ResponseErr{
 			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Error updating user collections: %v", err),
+			Message: fmt.Sprintf("List collections error: %v", err),
 		}
 	}
 
-	return &updated, nil
+	var list []*models.CollectionSummary
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("List collections error: %v", err),
+		}
+	}
+	for _, c := range list {
+		c.PrepareForResponse()
+	}
+
+	return list, nil
 }
 
-func (r Repository) DeleteCollection(collection *models.Collection) *models.ResponseErr {
+// DeleteCollection moves a collection to the trash. It stays there, hidden from
+// everything but ListDeletedCollections and RestoreCollection, until it is purged.
+func (r Repository) DeleteCollection(collection *models.Collection, ifVersion *int64) *models.ResponseErr {
 	collectionRef := r.client.Database(database).Collection(collections_collection)
 	objectId, err := bson.ObjectIDFromHex(collection.ID)
 	if err != nil {
@@ -212,31 +461,358 @@ func (r Repository) DeleteCollection(collection *models.Collection) *models.Resp
 		}
 	}
 
-	filter := bson.D{{Key: "_id", Value: objectId}}
-	_, err = collectionRef.DeleteOne(context.TODO(), filter)
+	return r.transaction(func(ctx context.Context) *models.ResponseErr {
+		now := time.Now()
+		filter := bson.D{
+			{Key: "_id", Value: objectId},
+			{Key: "user_id", Value: collection.UserID},
+			notDeleted,
+		}
+		versioned := filter
+		if ifVersion != nil {
+			versioned = append(slices.Clone(filter), atVersion(*ifVersion))
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "deleted_at", Value: now},
+				{Key: "updated_at", Value: now},
+			}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+		}
+		result, err := collectionRef.UpdateOne(ctx, versioned, update)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Delete collection error: %v", err),
+			}
+		}
+		if result.MatchedCount == 0 {
+			if ifVersion != nil {
+				return r.versionMismatch(ctx, filter)
+			}
+			return &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Collection not found",
+			}
+		}
+
+		return r.pullCollectionRef(ctx, collection.UserID, objectId, now)
+	})
+}
+
+// ListDeletedCollections returns the collections of a user in the trash, most recently deleted first.
+func (r Repository) ListDeletedCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: objectID},
+			{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: true}}},
+		}}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "card_count", Value: bson.D{{Key: "$sum", Value: "$cards.count"}}},
+			{Key: "unique_cards", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$cards", bson.A{}}}}}}},
+		}}},
+		{{Key: "$unset", Value: "cards"}},
+		{{Key: "$sort", Value: bson.D{{Key: "deleted_at", Value: -1}}}},
+	}
+
+	cursor, err := r.client.Database(database).Collection(collections_collection).Aggregate(context.TODO(), pipeline)
 	if err != nil {
-		return &models.ResponseErr{
+		return nil, &models.ResponseErr{
 			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Delete collection error: %v", err),
+			Message: fmt.Sprintf("List deleted collections error: %v", err),
 		}
 	}
 
-	// Delete collection from user's collections
-	userCollectionRef := r.client.Database(database).Collection(users_collection)
-	update := bson.D{
-		{Key: "$pull", Value: bson.D{{Key: "collections", Value: bson.D{{Key: "_id", Value: objectId}}}}},
-		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	var list []*models.CollectionSummary
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("List deleted collections error: %v", err),
+		}
+	}
+	for _, c := range list {
+		c.PrepareForResponse()
 	}
-	filterUser := bson.D{{Key: "_id", Value: collection.UserID}}
-	_, err = userCollectionRef.UpdateOne(context.TODO(), filterUser, update)
+
+	return list, nil
+}
+
+// RestoreCollection takes a collection of a user out of the trash.
+// It fails with 409 if the user has created another collection with the same name since.
+func (r Repository) RestoreCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	userObjectId, err := bson.ObjectIDFromHex(userId)
 	if err != nil {
-		return &models.ResponseErr{
-			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Error updating user collections: %v", err),
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
 		}
 	}
 
-	return nil
+	collectionRef := r.client.Database(database).Collection(collections_collection)
+	var restored models.Collection
+	respErr := r.transaction(func(ctx context.Context) *models.ResponseErr {
+		filter := bson.D{
+			{Key: "_id", Value: objectId},
+			{Key: "user_id", Value: userObjectId},
+			{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: true}}},
+		}
+		if err := collectionRef.FindOne(ctx, filter).Decode(&restored); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return &models.ResponseErr{
+					Status:  http.StatusNotFound,
+					Message: "Collection not found in trash",
+				}
+			}
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Find collection error: %v", err),
+			}
+		}
+
+		taken, err := collectionRef.CountDocuments(ctx, bson.D{
+			{Key: "user_id", Value: userObjectId},
+			{Key: "name", Value: restored.Name},
+			notDeleted,
+		})
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Find collection error: %v", err),
+			}
+		}
+		if taken > 0 {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Collection with this name already exists",
+			}
+		}
+
+		now := time.Now()
+		update := bson.D{
+			{Key: "$unset", Value: bson.D{{Key: "deleted_at", Value: ""}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+		}
+		if _, err := collectionRef.UpdateOne(ctx, filter, update); err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Restore collection error: %v", err),
+			}
+		}
+		restored.DeletedAt = nil
+		restored.UpdatedAt = now
+		restored.Version++
+
+		return r.pushCollectionRef(ctx, &restored, now)
+	})
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	restored.PrepareForResponse()
+	return &restored, nil
+}
+
+// PurgeDeletedCollections removes for good the collections deleted before the
+// given time together with their snapshots.
+func (r Repository) PurgeDeletedCollections(before time.Time) (int, *models.ResponseErr) {
+	purged := 0
+	respErr := r.transaction(func(ctx context.Context) *models.ResponseErr {
+		collectionRef := r.client.Database(database).Collection(collections_collection)
+		filter := bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$lt", Value: before}}}}
+
+		cursor, err := collectionRef.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Find collections error: %v", err),
+			}
+		}
+		var expired []models.Collection
+		if err := cursor.All(ctx, &expired); err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Decode collections error: %v", err),
+			}
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		ids := make([]bson.ObjectID, 0, len(expired))
+		for _, col := range expired {
+			ids = append(ids, col.ObjectID)
+		}
+		result, err := collectionRef.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Purge collections error: %v", err),
+			}
+		}
+		purged = int(result.DeletedCount)
+
+		snapshotsFilter := bson.D{{Key: "collection_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+		if _, err := r.client.Database(database).Collection(snapshots_collection).DeleteMany(ctx, snapshotsFilter); err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Purge snapshots error: %v", err),
+			}
+		}
+		return nil
+	})
+	if respErr != nil {
+		return 0, respErr
+	}
+
+	return purged, nil
+}
+
+// MergeCollections saves target with the cards of source already merged into it
+// and moves source to the trash unless keepSource is set, in one transaction. Both collections
+// must be unchanged since they were read, judging by Version.
+func (r Repository) MergeCollections(change *models.CardChange, target, source *models.Collection, keepSource bool) *models.ResponseErr {
+	return r.transaction(func(ctx context.Context) *models.ResponseErr {
+		now := time.Now()
+		collectionRef := r.client.Database(database).Collection(collections_collection)
+
+		filter := bson.D{
+			{Key: "_id", Value: target.ObjectID},
+			atVersion(target.Version),
+			notDeleted,
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "cards", Value: target.Cards},
+				{Key: "updated_at", Value: now},
+			}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+		}
+		result, err := collectionRef.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", err),
+			}
+		}
+		if result.MatchedCount == 0 {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Collection was changed concurrently, try again",
+			}
+		}
+
+		batch := newHistoryBatch(change, models.HistoryActionMerge, now)
+		entries := batch.diff(target.ObjectID, source.ObjectID, nil, source.Cards)
+		if !keepSource {
+			filter := bson.D{
+				{Key: "_id", Value: source.ObjectID},
+				atVersion(source.Version),
+				notDeleted,
+			}
+			update := bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "deleted_at", Value: now},
+					{Key: "updated_at", Value: now},
+				}},
+				{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+			}
+			result, err := collectionRef.UpdateOne(ctx, filter, update)
+			if err != nil {
+				return &models.ResponseErr{
+					Status:  http.StatusInternalServerError,
+					Message: fmt.Sprintf("Delete collection error: %v", err),
+				}
+			}
+			if result.MatchedCount == 0 {
+				return &models.ResponseErr{
+					Status:  http.StatusConflict,
+					Message: "Collection was changed concurrently, try again",
+				}
+			}
+			if respErr := r.pullCollectionRef(ctx, source.UserID, source.ObjectID, now); respErr != nil {
+				return respErr
+			}
+			entries = append(entries, batch.diff(source.ObjectID, target.ObjectID, source.Cards, nil)...)
+		}
+
+		return r.appendHistory(ctx, entries)
+	})
+}
+
+// SplitCollection creates created with the cards split off from source and saves
+// source without them, in one transaction. Source must be unchanged since it was
+// read, judging by Version.
+func (r Repository) SplitCollection(change *models.CardChange, source, created *models.Collection) (*models.Collection, *models.ResponseErr) {
+	respErr := r.transaction(func(ctx context.Context) *models.ResponseErr {
+		now := time.Now()
+		collectionRef := r.client.Database(database).Collection(collections_collection)
+
+		filter := bson.D{
+			{Key: "_id", Value: source.ObjectID},
+			atVersion(source.Version),
+			notDeleted,
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "cards", Value: source.Cards},
+				{Key: "updated_at", Value: now},
+			}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+		}
+		result, err := collectionRef.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", err),
+			}
+		}
+		if result.MatchedCount == 0 {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Collection was changed concurrently, try again",
+			}
+		}
+
+		created.ObjectID = bson.NewObjectID()
+		created.CreatedAt = now
+		created.UpdatedAt = now
+		if _, err := collectionRef.InsertOne(ctx, created); err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Create collection error: %v", err),
+			}
+		}
+		if respErr := r.pushCollectionRef(ctx, created, now); respErr != nil {
+			return respErr
+		}
+
+		batch := newHistoryBatch(change, models.HistoryActionSplit, now)
+		entries := batch.diff(created.ObjectID, source.ObjectID, nil, created.Cards)
+		entries = append(entries, batch.diff(source.ObjectID, created.ObjectID, created.Cards, nil)...)
+		return r.appendHistory(ctx, entries)
+	})
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	created.PrepareForResponse()
+	return created, nil
 }
 
 func (r Repository) GetCollectionByName(collection *models.Collection) (*models.Collection, *models.ResponseErr) {
@@ -244,6 +820,7 @@ func (r Repository) GetCollectionByName(collection *models.Collection) (*models.
 	filter := bson.D{
 		{Key: "name", Value: collection.Name},
 		{Key: "user_id", Value: collection.UserID},
+		notDeleted,
 	}
 
 	var col models.Collection
@@ -275,7 +852,7 @@ func (r Repository) GetCollection(collectionId string) (*models.Collection, *mod
 		}
 	}
 	collection := r.client.Database(database).Collection(collections_collection)
-	filter := bson.D{{Key: "_id", Value: objectId}}
+	filter := bson.D{{Key: "_id", Value: objectId}, notDeleted}
 
 	var col models.Collection
 	err = collection.FindOne(context.TODO(), filter).Decode(&col)
@@ -296,133 +873,1479 @@ func (r Repository) GetCollection(collectionId string) (*models.Collection, *mod
 	return &col, nil
 }
 
-func (r Repository) AddCardToCollection(collectionId string, card *models.Card) *models.ResponseErr {
-	objectId, err := bson.ObjectIDFromHex(collectionId)
+func (r Repository) AddCardToCollection(change *models.CardChange, collectionId string, card *models.Card) *models.ResponseErr {
+	return r.addCard(change, collectionId, card, nil)
+}
+
+// AddReservedCardToCollection adds a card to a deck together with the reservation
+// of its copies, in one transaction.
+func (r Repository) AddReservedCardToCollection(change *models.CardChange, collectionId string, card *models.Card, allocation *models.Allocation) *models.ResponseErr {
+	return r.addCard(change, collectionId, card, allocation)
+}
+
+func (r Repository) addCard(change *models.CardChange, collectionId string, card *models.Card, allocation *models.Allocation) *models.ResponseErr {
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	now := time.Now()
+	return r.changeCards(objectId, newHistoryBatch(change, models.HistoryActionAdd, now), func(ctx context.Context) *models.ResponseErr {
+		if allocation != nil {
+			if respErr := r.insertAllocation(ctx, allocation); respErr != nil {
+				return respErr
+			}
+		}
+		collection := r.client.Database(database).Collection(collections_collection)
+
+		// Try to update the card count first
+		filter := bson.D{{Key: "_id", Value: objectId}, hasCard(card.Key()), notDeleted}
+		update := bson.M{
+			"$inc": bson.M{"cards.$.count": card.Count},
+			"$set": bson.M{"updated_at": now},
+		}
+		result, err := collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Error updating card count: %v", err),
+			}
+		}
+
+		if result.MatchedCount > 0 {
+			return nil
+		}
+
+		// If the card doesn't exist, add it to the collection
+		filter = bson.D{{Key: "_id", Value: objectId}, notDeleted}
+		push := bson.M{
+			"$push": bson.M{"cards": card},
+			"$set":  bson.M{"updated_at": now},
+		}
+		_, err = collection.UpdateOne(ctx, filter, push)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Error adding new card: %v", err),
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r Repository) UpdateCardInCollection(change *models.CardChange, collectionId string, card *models.CardUpdate) *models.ResponseErr {
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	now := time.Now()
+	set := bson.D{
+		{Key: "cards.$.updated_at", Value: now},
+		{Key: "updated_at", Value: now},
+	}
+	if card.Count != nil {
+		set = append(set, bson.E{Key: "cards.$.count", Value: *card.Count})
+	}
+	if card.Notes != nil {
+		set = append(set, bson.E{Key: "cards.$.notes", Value: *card.Notes})
+	}
+	if card.Condition != nil {
+		set = append(set, bson.E{Key: "cards.$.condition", Value: *card.Condition})
+	}
+	if card.Zone != nil {
+		set = append(set, bson.E{Key: "cards.$.zone", Value: *card.Zone})
+	}
+	if card.Finish != nil {
+		set = append(set, bson.E{Key: "cards.$.finish", Value: *card.Finish})
+	}
+
+	return r.changeCards(objectId, newHistoryBatch(change, models.HistoryActionUpdate, now), func(ctx context.Context) *models.ResponseErr {
+		collection := r.client.Database(database).Collection(collections_collection)
+		filter := bson.D{{Key: "_id", Value: objectId}, hasCard(card.Key), notDeleted}
+		guarded := filter
+		update := bson.D{{Key: "$set", Value: set}}
+		if card.Removes() {
+			update = bson.D{
+				{Key: "$pull", Value: bson.D{{Key: "cards", Value: cardKey(card.Key)}}},
+				{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+			}
+		} else if key := card.NewKey(); key != card.Key {
+			// A card moved to another zone must not clash with a card already there.
+			clash := bson.E{Key: "cards", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$elemMatch", Value: cardKey(key)}}}}}
+			guarded = bson.D{{Key: "$and", Value: bson.A{filter, bson.D{clash}}}}
+		}
+
+		result, err := collection.UpdateOne(ctx, guarded, update)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", err),
+			}
+		}
+		if result.MatchedCount == 0 {
+			count, err := collection.CountDocuments(ctx, filter)
+			if err != nil {
+				return &models.ResponseErr{
+					Status:  http.StatusInternalServerError,
+					Message: fmt.Sprintf("Find collection error: %v", err),
+				}
+			}
+			if count > 0 {
+				return &models.ResponseErr{
+					Status:  http.StatusConflict,
+					Message: "Card with this zone and finish is already in the collection",
+				}
+			}
+			return &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Card not found",
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetCollectionCards replaces all cards of a collection in one write. The write
+// only happens if the collection is unchanged since it was read, judging by Version.
+func (r Repository) SetCollectionCards(change *models.CardChange, collection *models.Collection) *models.ResponseErr {
+	if collection.Cards == nil {
+		collection.Cards = []*models.Card{}
+	}
+
+	return r.transaction(func(ctx context.Context) *models.ResponseErr {
+		now := time.Now()
+		before, respErr := r.findCollection(ctx, collection.ObjectID)
+		if respErr != nil {
+			return respErr
+		}
+
+		filter := bson.D{
+			{Key: "_id", Value: collection.ObjectID},
+			atVersion(collection.Version),
+			notDeleted,
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "cards", Value: collection.Cards},
+				{Key: "updated_at", Value: now},
+			}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+		}
+		result, err := r.client.Database(database).Collection(collections_collection).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", err),
+			}
+		}
+		if result.MatchedCount == 0 {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Collection was changed concurrently, try again",
+			}
+		}
+
+		batch := newHistoryBatch(change, models.HistoryActionBatch, now)
+		return r.appendHistory(ctx, batch.diff(collection.ObjectID, bson.ObjectID{}, before.Cards, collection.Cards))
+	})
+}
+
+// TransferCard moves or copies copies of a card between collections and records
+// both sides in the card history, all in one transaction.
+func (r Repository) TransferCard(change *models.CardChange, transfer *models.CardTransfer) (*models.CardTransferResult, *models.ResponseErr) {
+	result := &models.CardTransferResult{}
+	respErr := r.transaction(func(ctx context.Context) *models.ResponseErr {
+		collectionRef := r.client.Database(database).Collection(collections_collection)
+
+		from, respErr := r.findCollection(ctx, transfer.FromCollectionID)
+		if respErr != nil {
+			return respErr
+		}
+		if respErr := from.CheckVersion(change.IfVersion); respErr != nil {
+			return respErr
+		}
+
+		i := models.FindCard(from.Cards, transfer.Key)
+		if i < 0 {
+			return &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Card not found",
+			}
+		}
+		card := from.Cards[i]
+		if card.Count < transfer.Count {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Not enough copies of the card in the collection",
+			}
+		}
+
+		now := time.Now()
+		moved := *card
+		moved.Count = transfer.Count
+		moved.UpdatedAt = now
+		moved.Acquisitions = nil
+		if transfer.Copy {
+			moved.AddedAt = now
+		}
+
+		result.FromCount = card.Count
+		if !transfer.Copy {
+			result.FromCount -= transfer.Count
+			var kept []*models.Acquisition
+			moved.Acquisitions, kept = splitAcquisitions(card, transfer.Count)
+
+			filter := bson.D{{Key: "_id", Value: transfer.FromCollectionID}, hasCard(transfer.Key)}
+			update := bson.D{
+				{Key: "$inc", Value: bson.D{{Key: "cards.$.count", Value: -transfer.Count}, {Key: "version", Value: 1}}},
+				{Key: "$set", Value: bson.D{
+					{Key: "cards.$.acquisitions", Value: kept},
+					{Key: "cards.$.updated_at", Value: now},
+					{Key: "updated_at", Value: now},
+				}},
+			}
+			if result.FromCount == 0 {
+				filter = bson.D{{Key: "_id", Value: transfer.FromCollectionID}}
+				update = bson.D{
+					{Key: "$pull", Value: bson.D{{Key: "cards", Value: cardKey(transfer.Key)}}},
+					{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+					{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+				}
+			}
+			if _, err := collectionRef.UpdateOne(ctx, filter, update); err != nil {
+				return &models.ResponseErr{
+					Status:  http.StatusInternalServerError,
+					Message: fmt.Sprintf("Update collection error: %v", err),
+				}
+			}
+		}
+
+		to, respErr := r.findCollection(ctx, transfer.ToCollectionID)
+		if respErr != nil {
+			return respErr
+		}
+
+		toKey := to.TransferKey(transfer.Key)
+		placed := moved
+		placed.Zone = toKey.Zone
+		filter := bson.D{{Key: "_id", Value: transfer.ToCollectionID}}
+		var update bson.D
+		result.ToCount = transfer.Count
+		if j := models.FindCard(to.Cards, toKey); j >= 0 {
+			result.ToCount += to.Cards[j].Count
+			filter = append(filter, hasCard(toKey))
+			update = bson.D{
+				{Key: "$inc", Value: bson.D{{Key: "cards.$.count", Value: transfer.Count}, {Key: "version", Value: 1}}},
+				{Key: "$set", Value: bson.D{{Key: "cards.$.updated_at", Value: now}, {Key: "updated_at", Value: now}}},
+			}
+			if len(moved.Acquisitions) > 0 {
+				update = append(update, bson.E{Key: "$push", Value: bson.D{{Key: "cards.$.acquisitions", Value: bson.D{{Key: "$each", Value: moved.Acquisitions}}}}})
+			}
+		} else {
+			update = bson.D{
+				{Key: "$push", Value: bson.D{{Key: "cards", Value: &placed}}},
+				{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+				{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+			}
+		}
+		if _, err := collectionRef.UpdateOne(ctx, filter, update); err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", err),
+			}
+		}
+
+		batch := newHistoryBatch(change, models.HistoryActionTransfer, now)
+		entries := []*models.CardHistoryEntry{batch.entry(transfer.ToCollectionID, transfer.FromCollectionID, &placed, transfer.Count)}
+		if !transfer.Copy {
+			entries = append(entries, batch.entry(transfer.FromCollectionID, transfer.ToCollectionID, &moved, -transfer.Count))
+		}
+		return r.appendHistory(ctx, entries)
+	})
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return result, nil
+}
+
+func (r Repository) DeleteCardFromCollection(change *models.CardChange, collectionId string, card *models.Card) *models.ResponseErr {
+	objectId, err := bson.ObjectIDFromHex(collectionId)
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid collection ID format",
+		}
+	}
+
+	now := time.Now()
+	return r.changeCards(objectId, newHistoryBatch(change, models.HistoryActionRemove, now), func(ctx context.Context) *models.ResponseErr {
+		collection := r.client.Database(database).Collection(collections_collection)
+		filter := bson.D{{Key: "_id", Value: objectId}, hasCard(card.Key()), notDeleted}
+		update := bson.D{
+			{Key: "$pull", Value: bson.D{
+				{Key: "cards", Value: cardKey(card.Key())},
+			}},
+			{Key: "$set", Value: bson.D{
+				{Key: "updated_at", Value: now},
+			}},
+		}
+
+		res := collection.FindOneAndUpdate(ctx, filter, update)
+		if res.Err() != nil {
+			if res.Err() == mongo.ErrNoDocuments {
+				return &models.ResponseErr{
+					Status:  http.StatusNotFound,
+					Message: "Card not found",
+				}
+			}
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", res.Err()),
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r Repository) ListCardHistory(filter *models.CardHistoryFilter) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	query := bson.D{{Key: "collection_id", Value: filter.CollectionID}}
+	if filter.ScryfallID != "" {
+		query = append(query, bson.E{Key: "scryfall_id", Value: filter.ScryfallID})
+	}
+	if !filter.Before.IsZero() {
+		query = append(query, bson.E{Key: "_id", Value: bson.D{{Key: "$lt", Value: filter.Before}}})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(filter.Limit))
+
+	cursor, err := r.client.Database(database).Collection(history_collection).Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find card history error: %v", err),
+		}
+	}
+
+	list := make([]*models.CardHistoryEntry, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode card history error: %v", err),
+		}
+	}
+
+	return list, nil
+}
+
+func (r Repository) GetCardHistoryEntry(entryId string) (*models.CardHistoryEntry, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(entryId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid history entry ID format",
+		}
+	}
+
+	var entry models.CardHistoryEntry
+	err = r.client.Database(database).Collection(history_collection).FindOne(context.TODO(), bson.D{{Key: "_id", Value: objectId}}).Decode(&entry)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "History entry not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find card history error: %v", err),
+		}
+	}
+
+	return &entry, nil
+}
+
+func (r Repository) CardHistoryBatch(batchId bson.ObjectID) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.client.Database(database).Collection(history_collection).Find(context.TODO(), bson.D{{Key: "batch_id", Value: batchId}}, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find card history error: %v", err),
+		}
+	}
+
+	list := make([]*models.CardHistoryEntry, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode card history error: %v", err),
+		}
+	}
+
+	return list, nil
+}
+
+// UndoCardChanges reverts history entries and records the undo, in one
+// transaction. Entries can only be undone once.
+func (r Repository) UndoCardChanges(change *models.CardChange, entries []*models.CardHistoryEntry) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	var undo []*models.CardHistoryEntry
+	respErr := r.transaction(func(ctx context.Context) *models.ResponseErr {
+		ids := make([]bson.ObjectID, 0, len(entries))
+		for _, e := range entries {
+			ids = append(ids, e.ObjectID)
+		}
+		undone, err := r.client.Database(database).Collection(history_collection).CountDocuments(ctx, bson.D{{Key: "undo_of", Value: bson.D{{Key: "$in", Value: ids}}}})
+		if err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Find card history error: %v", err),
+			}
+		}
+		if undone > 0 {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Change was already undone",
+			}
+		}
+
+		cards := make(map[bson.ObjectID][]*models.Card)
+		for _, e := range entries {
+			if _, ok := cards[e.CollectionID]; ok {
+				continue
+			}
+			col, respErr := r.findCollection(ctx, e.CollectionID)
+			if respErr != nil {
+				return respErr
+			}
+			cards[e.CollectionID] = col.Cards
+		}
+
+		now := time.Now()
+		var respErr *models.ResponseErr
+		undo, respErr = undoEntries(newHistoryBatch(change, models.HistoryActionUndo, now), cards, entries)
+		if respErr != nil {
+			return respErr
+		}
+
+		for id, list := range cards {
+			if list == nil {
+				list = []*models.Card{}
+			}
+			update := bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "cards", Value: list},
+					{Key: "updated_at", Value: now},
+				}},
+				{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+			}
+			if _, err := r.client.Database(database).Collection(collections_collection).UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, update); err != nil {
+				return &models.ResponseErr{
+					Status:  http.StatusInternalServerError,
+					Message: fmt.Sprintf("Update collection error: %v", err),
+				}
+			}
+		}
+
+		return r.appendHistory(ctx, undo)
+	})
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return undo, nil
+}
+
+// ListCardChanges returns the history entries filter selects, oldest first.
+func (r Repository) ListCardChanges(filter *models.CardChangesFilter) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	query := bson.D{{Key: "user_id", Value: filter.UserID}}
+	if !filter.CollectionID.IsZero() {
+		query = bson.D{{Key: "collection_id", Value: filter.CollectionID}}
+	}
+	// IDs start with their creation second, which lets the index find the range.
+	query = append(query,
+		bson.E{Key: "_id", Value: bson.D{{Key: "$gte", Value: bson.NewObjectIDFromTimestamp(filter.Since.Truncate(time.Second))}}},
+		bson.E{Key: "created_at", Value: bson.D{{Key: "$gte", Value: filter.Since}}},
+	)
+
+	cursor, err := r.client.Database(database).Collection(history_collection).Find(context.TODO(), query, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find card history error: %v", err),
+		}
+	}
+
+	list := make([]*models.CardHistoryEntry, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode card history error: %v", err),
+		}
+	}
+
+	return list, nil
+}
+
+// ListDisposals returns the history entries of a user that took copies with a
+// known cost away from the user and were not undone, oldest first.
+func (r Repository) ListDisposals(userId string) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	historyRef := r.client.Database(database).Collection(history_collection)
+	filter := bson.D{
+		{Key: "user_id", Value: objectID},
+		{Key: "cost_copies", Value: bson.D{{Key: "$gt", Value: 0}}},
+		{Key: "action", Value: bson.D{{Key: "$in", Value: models.DisposalActions}}},
+	}
+	cursor, err := historyRef.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find card history error: %v", err),
+		}
+	}
+	list := make([]*models.CardHistoryEntry, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode card history error: %v", err),
+		}
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	ids := make([]bson.ObjectID, 0, len(list))
+	for _, e := range list {
+		ids = append(ids, e.ObjectID)
+	}
+	cursor, err = historyRef.Find(context.TODO(), bson.D{{Key: "undo_of", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find card history error: %v", err),
+		}
+	}
+	var undo []*models.CardHistoryEntry
+	if err := cursor.All(context.TODO(), &undo); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode card history error: %v", err),
+		}
+	}
+
+	return slices.DeleteFunc(list, func(e *models.CardHistoryEntry) bool {
+		return slices.ContainsFunc(undo, func(u *models.CardHistoryEntry) bool { return u.UndoOf == e.ObjectID })
+	}), nil
+}
+
+func (r Repository) CreateSnapshot(snapshot *models.CollectionSnapshot) (*models.CollectionSnapshot, *models.ResponseErr) {
+	snapshotsRef := r.client.Database(database).Collection(snapshots_collection)
+
+	filter := bson.D{
+		{Key: "collection_id", Value: snapshot.CollectionID},
+		{Key: "name", Value: snapshot.Name},
+	}
+	count, err := snapshotsRef.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find snapshot error: %v", err),
+		}
+	}
+	if count > 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Snapshot with this name already exists",
+		}
+	}
+
+	result, err := snapshotsRef.InsertOne(context.TODO(), snapshot)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Create snapshot error: %v", err),
+		}
+	}
+
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		snapshot.ObjectID = id
+	}
+	snapshot.PrepareForResponse()
+	return snapshot, nil
+}
+
+// ListSnapshots returns the snapshots of a collection without their cards, newest first.
+func (r Repository) ListSnapshots(collectionId bson.ObjectID) ([]*models.CollectionSnapshot, *models.ResponseErr) {
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "cards", Value: 0}}).
+		SetSort(bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.client.Database(database).Collection(snapshots_collection).Find(context.TODO(), bson.D{{Key: "collection_id", Value: collectionId}}, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find snapshots error: %v", err),
+		}
+	}
+
+	list := make([]*models.CollectionSnapshot, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode snapshots error: %v", err),
+		}
+	}
+	for _, snapshot := range list {
+		snapshot.PrepareForResponse()
+	}
+
+	return list, nil
+}
+
+func (r Repository) GetSnapshot(snapshotId string) (*models.CollectionSnapshot, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(snapshotId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid snapshot ID format",
+		}
+	}
+
+	var snapshot models.CollectionSnapshot
+	err = r.client.Database(database).Collection(snapshots_collection).FindOne(context.TODO(), bson.D{{Key: "_id", Value: objectId}}).Decode(&snapshot)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Snapshot not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find snapshot error: %v", err),
+		}
+	}
+
+	snapshot.PrepareForResponse()
+	return &snapshot, nil
+}
+
+func (r Repository) DeleteSnapshot(snapshot *models.CollectionSnapshot) *models.ResponseErr {
+	result, err := r.client.Database(database).Collection(snapshots_collection).DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: snapshot.ObjectID}})
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Delete snapshot error: %v", err),
+		}
+	}
+	if result.DeletedCount == 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Snapshot not found",
+		}
+	}
+
+	return nil
+}
+
+// ListCollectionsWithCards returns every collection of a user that is not in the trash, with its cards.
+func (r Repository) ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	filter := bson.D{{Key: "user_id", Value: objectID}, notDeleted}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.client.Database(database).Collection(collections_collection).Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find collections error: %v", err),
+		}
+	}
+
+	list := make([]*models.Collection, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode collections error: %v", err),
+		}
+	}
+	for _, c := range list {
+		c.PrepareForResponse()
+	}
+
+	return list, nil
+}
+
+// FindOwnedCards returns the collections of a user, except the trash and
+// wishlists, that hold a card match picks, with only the cards it picks.
+func (r Repository) FindOwnedCards(userId string, match *models.OwnedCardMatch) ([]*models.Collection, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	ids := match.ScryfallIDs
+	if ids == nil {
+		ids = []string{}
+	}
+	// A card is picked by printing and, with a name, by name in either
+	// form a query and an aggregation expression take.
+	queryOr := bson.A{bson.D{{Key: "scryfall_id", Value: bson.D{{Key: "$in", Value: ids}}}}}
+	exprOr := bson.A{bson.D{{Key: "$in", Value: bson.A{"$$card.scryfall_id", ids}}}}
+	if match.Name != "" {
+		pattern := "^" + regexp.QuoteMeta(match.Name) + "( // .*)?$"
+		queryOr = append(queryOr, bson.D{{Key: "name", Value: bson.Regex{Pattern: pattern, Options: "i"}}})
+		exprOr = append(exprOr, bson.D{{Key: "$regexMatch", Value: bson.D{
+			{Key: "input", Value: "$$card.name"},
+			{Key: "regex", Value: pattern},
+			{Key: "options", Value: "i"},
+		}}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: objectID},
+			notDeleted,
+			{Key: "kind", Value: bson.D{{Key: "$ne", Value: models.CollectionKindWishlist}}},
+			{Key: "cards", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+				{Key: "count", Value: bson.D{{Key: "$gt", Value: 0}}},
+				{Key: "$or", Value: queryOr},
+			}}}},
+		}}},
+		{{Key: "$set", Value: bson.D{{Key: "cards", Value: bson.D{{Key: "$filter", Value: bson.D{
+			{Key: "input", Value: "$cards"},
+			{Key: "as", Value: "card"},
+			{Key: "cond", Value: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "$gt", Value: bson.A{"$$card.count", 0}}},
+				bson.D{{Key: "$or", Value: exprOr}},
+			}}}},
+		}}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.client.Database(database).Collection(collections_collection).Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find owned cards error: %v", err),
+		}
+	}
+
+	list := make([]*models.Collection, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode collections error: %v", err),
+		}
+	}
+	for _, c := range list {
+		c.PrepareForResponse()
+	}
+
+	return list, nil
+}
+
+func (r Repository) CreateWishlistEntry(entry *models.WishlistEntry) (*models.WishlistEntry, *models.ResponseErr) {
+	wishlistRef := r.client.Database(database).Collection(wishlist_collection)
+
+	filter := bson.D{
+		{Key: "user_id", Value: entry.UserID},
+		{Key: "key", Value: entry.Key},
+	}
+	count, err := wishlistRef.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find wishlist entry error: %v", err),
+		}
+	}
+	if count > 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Card is already on the wishlist",
+		}
+	}
+
+	result, err := wishlistRef.InsertOne(context.TODO(), entry)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Create wishlist entry error: %v", err),
+		}
+	}
+
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		entry.ObjectID = id
+	}
+	entry.PrepareForResponse()
+	return entry, nil
+}
+
+// ListWishlist returns the wishlist of a user by priority, highest first, then oldest first.
+func (r Repository) ListWishlist(userId string) ([]*models.WishlistEntry, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "_id", Value: 1}})
+	cursor, err := r.client.Database(database).Collection(wishlist_collection).Find(context.TODO(), bson.D{{Key: "user_id", Value: objectID}}, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find wishlist error: %v", err),
+		}
+	}
+
+	list := make([]*models.WishlistEntry, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode wishlist error: %v", err),
+		}
+	}
+	for _, entry := range list {
+		entry.PrepareForResponse()
+	}
+
+	return list, nil
+}
+
+func (r Repository) GetWishlistEntry(entryId string) (*models.WishlistEntry, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(entryId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid wishlist entry ID format",
+		}
+	}
+
+	var entry models.WishlistEntry
+	err = r.client.Database(database).Collection(wishlist_collection).FindOne(context.TODO(), bson.D{{Key: "_id", Value: objectId}}).Decode(&entry)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Wishlist entry not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find wishlist entry error: %v", err),
+		}
+	}
+
+	entry.PrepareForResponse()
+	return &entry, nil
+}
+
+func (r Repository) UpdateWishlistEntry(update *models.WishlistEntryUpdate) (*models.WishlistEntry, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(update.ID)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid wishlist entry ID format",
+		}
+	}
+
+	set := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if update.Quantity != nil {
+		set = append(set, bson.E{Key: "quantity", Value: *update.Quantity})
+	}
+	if update.MaxPrice != nil {
+		set = append(set, bson.E{Key: "max_price", Value: *update.MaxPrice})
+	}
+	if update.Currency != nil {
+		set = append(set, bson.E{Key: "currency", Value: *update.Currency})
+	}
+	if update.Finish != nil {
+		set = append(set, bson.E{Key: "finish", Value: *update.Finish})
+	}
+	if update.Priority != nil {
+		set = append(set, bson.E{Key: "priority", Value: *update.Priority})
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: objectId},
+		{Key: "user_id", Value: update.UserID},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated models.WishlistEntry
+	err = r.client.Database(database).Collection(wishlist_collection).FindOneAndUpdate(context.TODO(), filter, bson.D{{Key: "$set", Value: set}}, opts).Decode(&updated)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Wishlist entry not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Error updating wishlist entry: %v", err),
+		}
+	}
+
+	updated.PrepareForResponse()
+	return &updated, nil
+}
+
+func (r Repository) DeleteWishlistEntry(entry *models.WishlistEntry) *models.ResponseErr {
+	result, err := r.client.Database(database).Collection(wishlist_collection).DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: entry.ObjectID}})
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Delete wishlist entry error: %v", err),
+		}
+	}
+	if result.DeletedCount == 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Wishlist entry not found",
+		}
+	}
+
+	return nil
+}
+
+func (r Repository) CreateAllocation(allocation *models.Allocation) (*models.Allocation, *models.ResponseErr) {
+	if respErr := r.insertAllocation(context.TODO(), allocation); respErr != nil {
+		return nil, respErr
+	}
+	allocation.PrepareForResponse()
+	return allocation, nil
+}
+
+// insertAllocation saves a new reservation unless the deck already reserves
+// the printing from the same collection.
+func (r Repository) insertAllocation(ctx context.Context, allocation *models.Allocation) *models.ResponseErr {
+	allocationsRef := r.client.Database(database).Collection(allocations_collection)
+
+	filter := bson.D{
+		{Key: "deck_id", Value: allocation.DeckID},
+		{Key: "source_id", Value: allocation.SourceID},
+		{Key: "scryfall_id", Value: allocation.ScryfallID},
+	}
+	count, err := allocationsRef.CountDocuments(ctx, filter)
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find allocation error: %v", err),
+		}
+	}
+	if count > 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Card is already reserved from this collection",
+		}
+	}
+
+	result, err := allocationsRef.InsertOne(ctx, allocation)
 	if err != nil {
 		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Create allocation error: %v", err),
+		}
+	}
+
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		allocation.ObjectID = id
+	}
+	return nil
+}
+
+// ListAllocations returns the reservations of a user, oldest first.
+func (r Repository) ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
 			Status:  http.StatusBadRequest,
-			Message: "Invalid collection ID format",
+			Message: "Invalid user ID format",
 		}
 	}
 
-	collection := r.client.Database(database).Collection(collections_collection)
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.client.Database(database).Collection(allocations_collection).Find(context.TODO(), bson.D{{Key: "user_id", Value: objectID}}, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find allocations error: %v", err),
+		}
+	}
+
+	list := make([]*models.Allocation, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode allocations error: %v", err),
+		}
+	}
+	for _, allocation := range list {
+		allocation.PrepareForResponse()
+	}
+
+	return list, nil
+}
 
-	// Try to update the card count first
-	filter := bson.M{
-		"_id":               objectId,
-		"cards.scryfall_id": card.ScryfallID,
+func (r Repository) GetAllocation(allocationId string) (*models.Allocation, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(allocationId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid allocation ID format",
+		}
 	}
-	update := bson.M{
-		"$inc": bson.M{"cards.$.count": card.Count},
-		"$set": bson.M{"updated_at": time.Now()},
+
+	var allocation models.Allocation
+	err = r.client.Database(database).Collection(allocations_collection).FindOne(context.TODO(), bson.D{{Key: "_id", Value: objectId}}).Decode(&allocation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Allocation not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find allocation error: %v", err),
+		}
 	}
-	result, err := collection.UpdateOne(context.TODO(), filter, update)
+
+	allocation.PrepareForResponse()
+	return &allocation, nil
+}
+
+func (r Repository) DeleteAllocation(allocation *models.Allocation) *models.ResponseErr {
+	result, err := r.client.Database(database).Collection(allocations_collection).DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: allocation.ObjectID}})
 	if err != nil {
 		return &models.ResponseErr{
 			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Error updating card count: %v", err),
+			Message: fmt.Sprintf("Delete allocation error: %v", err),
+		}
+	}
+	if result.DeletedCount == 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Allocation not found",
 		}
 	}
 
-	if result.MatchedCount > 0 {
-		return nil
+	return nil
+}
+
+// changeCards runs write on a collection of the version the change expects in
+// a transaction, bumps the version and records in the card history how write
+// changed the cards.
+func (r Repository) changeCards(collectionId bson.ObjectID, batch *historyBatch, write func(ctx context.Context) *models.ResponseErr) *models.ResponseErr {
+	return r.transaction(func(ctx context.Context) *models.ResponseErr {
+		before, respErr := r.findCollection(ctx, collectionId)
+		if respErr != nil {
+			return respErr
+		}
+		if respErr := before.CheckVersion(batch.change.IfVersion); respErr != nil {
+			return respErr
+		}
+		if respErr := write(ctx); respErr != nil {
+			return respErr
+		}
+		bump := bson.D{{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}}}
+		if _, err := r.client.Database(database).Collection(collections_collection).UpdateByID(ctx, collectionId, bump); err != nil {
+			return &models.ResponseErr{
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Update collection error: %v", err),
+			}
+		}
+		after, respErr := r.findCollection(ctx, collectionId)
+		if respErr != nil {
+			return respErr
+		}
+
+		return r.appendHistory(ctx, batch.diff(collectionId, bson.ObjectID{}, before.Cards, after.Cards))
+	})
+}
+
+// findCollection reads a collection that is not in the trash.
+func (r Repository) findCollection(ctx context.Context, id bson.ObjectID) (*models.Collection, *models.ResponseErr) {
+	var col models.Collection
+	err := r.client.Database(database).Collection(collections_collection).FindOne(ctx, bson.D{{Key: "_id", Value: id}, notDeleted}).Decode(&col)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Collection not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find collection error: %v", err),
+		}
+	}
+	return &col, nil
+}
+
+// versionMismatch tells why a write of a collection filtered by version matched
+// nothing: the collection matching filter has another version or is missing.
+func (r Repository) versionMismatch(ctx context.Context, filter bson.D) *models.ResponseErr {
+	count, err := r.client.Database(database).Collection(collections_collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find collection error: %v", err),
+		}
 	}
+	if count == 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+	return models.VersionMismatch()
+}
 
-	// If the card doesn't exist, add it to the collection
-	filter = bson.M{"_id": objectId}
-	push := bson.M{
-		"$push": bson.M{"cards": card},
-		"$set":  bson.M{"updated_at": time.Now()},
+// transaction runs fn in a MongoDB transaction, which needs a replica set.
+// The transaction is aborted when fn returns an error.
+func (r Repository) transaction(fn func(ctx context.Context) *models.ResponseErr) *models.ResponseErr {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Start session error: %v", err),
+		}
 	}
-	_, err = collection.UpdateOne(context.TODO(), filter, push)
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(ctx context.Context) (any, error) {
+		if respErr := fn(ctx); respErr != nil {
+			return nil, respErr
+		}
+		return nil, nil
+	})
 	if err != nil {
+		var respErr *models.ResponseErr
+		if errors.As(err, &respErr) {
+			return respErr
+		}
 		return &models.ResponseErr{
 			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Error adding new card: %v", err),
+			Message: fmt.Sprintf("Transaction error: %v", err),
 		}
 	}
 
 	return nil
 }
 
-func (r Repository) SetCardCountInCollection(collectionId string, card *models.Card) *models.ResponseErr {
-	objectId, err := bson.ObjectIDFromHex(collectionId)
-	if err != nil {
+func (r Repository) pushCollectionRef(ctx context.Context, collection *models.Collection, now time.Time) *models.ResponseErr {
+	filter := bson.D{{Key: "_id", Value: collection.UserID}}
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "collections", Value: models.UserCollectionRef{
+			ObjectID: collection.ObjectID,
+			Name:     collection.Name,
+		}}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+	}
+	if _, err := r.client.Database(database).Collection(users_collection).UpdateOne(ctx, filter, update); err != nil {
 		return &models.ResponseErr{
-			Status:  http.StatusBadRequest,
-			Message: "Invalid collection ID format",
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Error updating user collections: %v", err),
 		}
 	}
+	return nil
+}
+
+func (r Repository) pullCollectionRef(ctx context.Context, userId, collectionId bson.ObjectID, now time.Time) *models.ResponseErr {
+	filter := bson.D{{Key: "_id", Value: userId}}
+	update := bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "collections", Value: bson.D{{Key: "_id", Value: collectionId}}}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+	}
+	if _, err := r.client.Database(database).Collection(users_collection).UpdateOne(ctx, filter, update); err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Error updating user collections: %v", err),
+		}
+	}
+	return nil
+}
+
+func (r Repository) appendHistory(ctx context.Context, entries []*models.CardHistoryEntry) *models.ResponseErr {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	docs := make([]any, 0, len(entries))
+	for _, e := range entries {
+		e.ObjectID = bson.NewObjectID()
+		docs = append(docs, e)
+	}
+	if _, err := r.client.Database(database).Collection(history_collection).InsertMany(ctx, docs); err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Write card history error: %v", err),
+		}
+	}
+	return nil
+}
+
+func (r Repository) CreateStorageLocation(location *models.StorageLocation) (*models.StorageLocation, *models.ResponseErr) {
+	storagesRef := r.client.Database(database).Collection(storages_collection)
 
-	collection := r.client.Database(database).Collection(collections_collection)
 	filter := bson.D{
-		{Key: "_id", Value: objectId},
-		{Key: "cards.scryfall_id", Value: card.ScryfallID},
+		{Key: "user_id", Value: location.UserID},
+		{Key: "name", Value: location.Name},
 	}
-	update := bson.D{
-		{Key: "$set", Value: bson.D{
-			{Key: "cards.$.count", Value: card.Count},
-			{Key: "updated_at", Value: time.Now()},
-		}},
+	opts := options.Count().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	count, err := storagesRef.CountDocuments(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find location error: %v", err),
+		}
+	}
+	if count > 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Location already exists",
+		}
 	}
-	//opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	// var updatedColection models.Collection
-	res := collection.FindOneAndUpdate(context.TODO(), filter, update)
-	if res.Err() != nil {
-		if res.Err() == mongo.ErrNoDocuments {
-			return &models.ResponseErr{
-				Status:  http.StatusNotFound,
-				Message: "Collection not found",
-			}
+	result, err := storagesRef.InsertOne(context.TODO(), location)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Create location error: %v", err),
 		}
-		return &models.ResponseErr{
+	}
+
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		location.ObjectID = id
+	}
+	location.PrepareForResponse()
+	return location, nil
+}
+
+// ListStorageLocations returns the storage locations of a user by name.
+func (r Repository) ListStorageLocations(userId string) ([]*models.StorageLocation, *models.ResponseErr) {
+	objectID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.client.Database(database).Collection(storages_collection).Find(context.TODO(), bson.D{{Key: "user_id", Value: objectID}}, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
 			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Update collection error: %v", err),
+			Message: fmt.Sprintf("Find locations error: %v", err),
 		}
 	}
 
-	return nil
+	list := make([]*models.StorageLocation, 0)
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode locations error: %v", err),
+		}
+	}
+	for _, location := range list {
+		location.PrepareForResponse()
+	}
+
+	return list, nil
 }
 
-func (r Repository) DeleteCardFromCollection(collectionId string, card *models.Card) *models.ResponseErr {
-	objectId, err := bson.ObjectIDFromHex(collectionId)
+func (r Repository) GetStorageLocation(locationId string) (*models.StorageLocation, *models.ResponseErr) {
+	objectId, err := bson.ObjectIDFromHex(locationId)
 	if err != nil {
-		return &models.ResponseErr{
+		return nil, &models.ResponseErr{
 			Status:  http.StatusBadRequest,
-			Message: "Invalid collection ID format",
+			Message: "Invalid location ID format",
 		}
 	}
 
-	collection := r.client.Database(database).Collection(collections_collection)
-	filter := bson.D{
-		{Key: "_id", Value: objectId},
-		{Key: "cards.scryfall_id", Value: card.ScryfallID},
+	var location models.StorageLocation
+	err = r.client.Database(database).Collection(storages_collection).FindOne(context.TODO(), bson.D{{Key: "_id", Value: objectId}}).Decode(&location)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusNotFound,
+				Message: "Location not found",
+			}
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find location error: %v", err),
+		}
 	}
-	update := bson.D{
-		{Key: "$pull", Value: bson.D{
-			{Key: "cards", Value: bson.D{
-				{Key: "scryfall_id", Value: card.ScryfallID},
-			}},
-		}},
-		{Key: "$set", Value: bson.D{
-			{Key: "updated_at", Value: time.Now()},
-		}},
+
+	location.PrepareForResponse()
+	return &location, nil
+}
+
+func (r Repository) DeleteStorageLocation(location *models.StorageLocation) *models.ResponseErr {
+	result, err := r.client.Database(database).Collection(storages_collection).DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: location.ObjectID}})
+	if err != nil {
+		return &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Delete location error: %v", err),
+		}
+	}
+	if result.DeletedCount == 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Location not found",
+		}
 	}
 
-	res := collection.FindOneAndUpdate(context.TODO(), filter, update)
-	if res.Err() != nil {
-		if res.Err() == mongo.ErrNoDocuments {
+	return nil
+}
+
+// savePricesChunk limits the price snapshots written by one bulk write.
+const savePricesChunk = 1000
+
+// SaveCardPrices stores price snapshots, replacing the ones of the same printing and day.
+func (r Repository) SaveCardPrices(prices []*models.CardPrice) *models.ResponseErr {
+	collection := r.client.Database(database).Collection(prices_collection)
+	for chunk := range slices.Chunk(prices, savePricesChunk) {
+		writes := make([]mongo.WriteModel, 0, len(chunk))
+		for _, price := range chunk {
+			filter := bson.D{{Key: "scryfall_id", Value: price.ScryfallID}, {Key: "date", Value: price.Date}}
+			writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(price).SetUpsert(true))
+		}
+		if _, err := collection.BulkWrite(context.TODO(), writes, options.BulkWrite().SetOrdered(false)); err != nil {
 			return &models.ResponseErr{
-				Status:  http.StatusNotFound,
-				Message: "Collection not found",
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Save prices error: %v", err),
 			}
 		}
+	}
+
+	return nil
+}
+
+// LatestCardPrices returns the newest price snapshot of each printing that has one.
+func (r Repository) LatestCardPrices(scryfallIds []string) (map[string]*models.CardPrice, *models.ResponseErr) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "scryfall_id", Value: bson.D{{Key: "$in", Value: scryfallIds}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "scryfall_id", Value: 1}, {Key: "date", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$scryfall_id"},
+			{Key: "price", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$price"}}}},
+	}
+	cursor, err := r.client.Database(database).Collection(prices_collection).Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find prices error: %v", err),
+		}
+	}
+
+	var list []*models.CardPrice
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode prices error: %v", err),
+		}
+	}
+	latest := make(map[string]*models.CardPrice, len(list))
+	for _, price := range list {
+		latest[price.ScryfallID] = price
+	}
+
+	return latest, nil
+}
+
+// SaveExchangeRates stores a table of exchange rates, replacing the one of the same day.
+func (r Repository) SaveExchangeRates(rates *catalog.ExchangeRates) *models.ResponseErr {
+	filter := bson.D{{Key: "date", Value: rates.Date}}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.client.Database(database).Collection(rates_collection).ReplaceOne(context.TODO(), filter, rates, opts); err != nil {
 		return &models.ResponseErr{
 			Status:  http.StatusInternalServerError,
-			Message: fmt.Sprintf("Update collection error: %v", err),
+			Message: fmt.Sprintf("Save exchange rates error: %v", err),
 		}
 	}
 
 	return nil
 }
+
+// LatestExchangeRates returns the newest table of exchange rates, nil if there is none.
+func (r Repository) LatestExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}})
+
+	var rates catalog.ExchangeRates
+	if err := r.client.Database(database).Collection(rates_collection).FindOne(context.TODO(), bson.D{}, opts).Decode(&rates); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find exchange rates error: %v", err),
+		}
+	}
+
+	return &rates, nil
+}
+
+// ListCardPrices returns the price snapshots of each printing from the day of
+// from to the day of to, the oldest first.
+func (r Repository) ListCardPrices(scryfallIds []string, from, to time.Time) (map[string][]*models.CardPrice, *models.ResponseErr) {
+	filter := bson.D{
+		{Key: "scryfall_id", Value: bson.D{{Key: "$in", Value: scryfallIds}}},
+		{Key: "date", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lte", Value: to}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "scryfall_id", Value: 1}, {Key: "date", Value: 1}})
+	cursor, err := r.client.Database(database).Collection(prices_collection).Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Find prices error: %v", err),
+		}
+	}
+
+	var list []*models.CardPrice
+	if err := cursor.All(context.TODO(), &list); err != nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Decode prices error: %v", err),
+		}
+	}
+	history := make(map[string][]*models.CardPrice)
+	for _, price := range list {
+		history[price.ScryfallID] = append(history[price.ScryfallID], price)
+	}
+
+	return history, nil
+}
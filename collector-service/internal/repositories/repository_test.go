@@ -0,0 +1,352 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RepositoryTestSuite runs the MongoDB repository against a replica set in
+// Docker, which transactions need. It is skipped where Docker is not available.
+//
+// go test github.com/ShenokZlob/collector-ouphe/collector-service/internal/repositories -run Repository
+type RepositoryTestSuite struct {
+	suite.Suite
+	container *mongodb.MongoDBContainer
+	client    *mongo.Client
+	repo      *Repository
+	user      *models.User
+}
+
+func TestRepository(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	suite.Run(t, new(RepositoryTestSuite))
+}
+
+func (s *RepositoryTestSuite) SetupSuite() {
+	ctx := context.Background()
+	container, err := mongodb.Run(ctx, "mongo:7", mongodb.WithReplicaSet("rs0"))
+	s.Require().NoError(err)
+	s.container = container
+
+	uri, err := container.ConnectionString(ctx)
+	s.Require().NoError(err)
+	s.client, err = mongo.Connect(options.Client().ApplyURI(uri))
+	s.Require().NoError(err)
+	s.repo = NewRepository(s.client)
+}
+
+func (s *RepositoryTestSuite) TearDownSuite() {
+	if s.client != nil {
+		s.NoError(s.client.Disconnect(context.Background()))
+	}
+	s.NoError(testcontainers.TerminateContainer(s.container))
+}
+
+func (s *RepositoryTestSuite) SetupTest() {
+	s.Require().NoError(s.client.Database(database).Drop(context.Background()))
+	s.Require().NoError(s.repo.CreateIndexes(context.Background()))
+
+	user, respErr := s.repo.CreateUser(&models.User{TelegramID: 42, FirstName: "Ivan"})
+	s.Require().Nil(respErr)
+	s.user = user
+}
+
+// change is a change of cards by the user of the test.
+func (s *RepositoryTestSuite) change(ifVersion *int64) *models.CardChange {
+	return &models.CardChange{UserID: s.user.ObjectID, Source: models.ChangeSourceAPI, IfVersion: ifVersion}
+}
+
+// legacyCollection stores a collection the way it was stored before
+// collections had versions: without the version field.
+func (s *RepositoryTestSuite) legacyCollection(name string, cards ...*models.Card) *models.Collection {
+	now := time.Now()
+	col, respErr := s.repo.CreateCollection(&models.Collection{UserID: s.user.ObjectID, Name: name, Cards: cards, CreatedAt: now, UpdatedAt: now})
+	s.Require().Nil(respErr)
+
+	unset := bson.D{{Key: "$unset", Value: bson.D{{Key: "version", Value: ""}}}}
+	_, err := s.client.Database(database).Collection(collections_collection).UpdateByID(context.Background(), col.ObjectID, unset)
+	s.Require().NoError(err)
+
+	stored, respErr := s.repo.GetCollection(col.ID)
+	s.Require().Nil(respErr)
+	s.Require().Zero(stored.Version)
+	return stored
+}
+
+// version is the stored version of a collection, nil if it has none.
+func (s *RepositoryTestSuite) version(col *models.Collection) any {
+	var doc bson.M
+	err := s.client.Database(database).Collection(collections_collection).FindOne(context.Background(), bson.D{{Key: "_id", Value: col.ObjectID}}).Decode(&doc)
+	s.Require().NoError(err)
+	return doc["version"]
+}
+
+func (s *RepositoryTestSuite) TestLegacyCollectionsWithoutVersion() {
+	zero := int64(0)
+
+	col := s.legacyCollection("Binder", &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2})
+	name := "Old binder"
+	updated, respErr := s.repo.UpdateCollection(&models.CollectionUpdate{ID: col.ID, UserID: s.user.ObjectID, Name: &name, IfVersion: &zero})
+	s.Require().Nil(respErr, "If-Match 0 matches a collection without a version")
+	s.Equal(int64(1), updated.Version)
+
+	col = s.legacyCollection("Bulk", &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2})
+	col.Cards[0].Count = 3
+	s.Require().Nil(s.repo.SetCollectionCards(s.change(nil), col))
+	s.Equal(int64(1), s.version(col))
+
+	target := s.legacyCollection("Target", &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1})
+	source := s.legacyCollection("Source", &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1})
+	target.Cards = append(target.Cards, source.Cards...)
+	s.Require().Nil(s.repo.MergeCollections(s.change(nil), target, source, false))
+	s.Equal(int64(1), s.version(target))
+	_, respErr = s.repo.GetCollection(source.ID)
+	s.Require().NotNil(respErr)
+
+	source = s.legacyCollection("Whole", &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}, &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1})
+	split := &models.Collection{UserID: s.user.ObjectID, Name: "Part", Cards: source.Cards[1:]}
+	source.Cards = source.Cards[:1]
+	_, respErr = s.repo.SplitCollection(s.change(nil), source, split)
+	s.Require().Nil(respErr)
+	s.Equal(int64(1), s.version(source))
+
+	col = s.legacyCollection("Trash")
+	s.Require().Nil(s.repo.DeleteCollection(col, &zero))
+
+	// A collection that has a version still needs the right one.
+	col, respErr = s.repo.GetCollection(target.ID)
+	s.Require().Nil(respErr)
+	s.Equal(int64(1), col.Version)
+	_, respErr = s.repo.UpdateCollection(&models.CollectionUpdate{ID: col.ID, UserID: s.user.ObjectID, Name: &name, IfVersion: &zero})
+	s.Require().NotNil(respErr)
+	s.Equal(models.VersionMismatch().Status, respErr.Status)
+	col.Version = 0
+	respErr = s.repo.SetCollectionCards(s.change(nil), col)
+	s.Require().NotNil(respErr, "a stale version is a concurrent change")
+}
+
+func (s *RepositoryTestSuite) TestCardPricesAreUniquePerDay() {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	s.Require().Nil(s.repo.SaveCardPrices([]*models.CardPrice{{ScryfallID: "bolt", Date: day, Prices: catalog.Prices{USD: 1}}}))
+	s.Require().Nil(s.repo.SaveCardPrices([]*models.CardPrice{{ScryfallID: "bolt", Date: day, Prices: catalog.Prices{USD: 2}}}))
+
+	prices := s.client.Database(database).Collection(prices_collection)
+	count, err := prices.CountDocuments(context.Background(), bson.D{})
+	s.Require().NoError(err)
+	s.Equal(int64(1), count, "a snapshot of the same day is replaced")
+
+	_, err = prices.InsertOne(context.Background(), &models.CardPrice{ScryfallID: "bolt", Date: day})
+	s.True(mongo.IsDuplicateKeyError(err), "the index keeps one snapshot per printing and day")
+}
+
+// collection stores a new collection with cards.
+func (s *RepositoryTestSuite) collection(name string, cards ...*models.Card) *models.Collection {
+	now := time.Now()
+	col, respErr := s.repo.CreateCollection(&models.Collection{UserID: s.user.ObjectID, Name: name, Cards: cards, CreatedAt: now, UpdatedAt: now})
+	s.Require().Nil(respErr)
+	stored, respErr := s.repo.GetCollection(col.ID)
+	s.Require().Nil(respErr)
+	return stored
+}
+
+// history is the card history of a collection, newest first.
+func (s *RepositoryTestSuite) history(col *models.Collection) []*models.CardHistoryEntry {
+	list, respErr := s.repo.ListCardHistory(&models.CardHistoryFilter{CollectionID: col.ObjectID, Limit: 50})
+	s.Require().Nil(respErr)
+	return list
+}
+
+func (s *RepositoryTestSuite) TestVersionGuards() {
+	col := s.collection("Binder")
+	s.Require().Nil(s.repo.AddCardToCollection(s.change(nil), col.ID, &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 1}))
+	s.Equal(int64(1), s.version(col))
+
+	stale := int64(0)
+	respErr := s.repo.AddCardToCollection(s.change(&stale), col.ID, &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1})
+	s.Require().NotNil(respErr)
+	s.Equal(models.VersionMismatch().Status, respErr.Status)
+	s.Equal(int64(1), s.version(col), "a refused change writes nothing")
+	s.Len(s.history(col), 1)
+
+	current := int64(1)
+	s.Require().Nil(s.repo.AddCardToCollection(s.change(&current), col.ID, &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1}))
+	s.Equal(int64(2), s.version(col))
+
+	// A collection read before another change can't overwrite it.
+	col.Cards = nil
+	respErr = s.repo.SetCollectionCards(s.change(nil), col)
+	s.Require().NotNil(respErr)
+	s.Equal(http.StatusConflict, respErr.Status)
+	stored, respErr := s.repo.GetCollection(col.ID)
+	s.Require().Nil(respErr)
+	s.Len(stored.Cards, 2)
+
+	name := "Renamed"
+	_, respErr = s.repo.UpdateCollection(&models.CollectionUpdate{ID: col.ID, UserID: s.user.ObjectID, Name: &name, IfVersion: &current})
+	s.Require().NotNil(respErr)
+	s.Equal(models.VersionMismatch().Status, respErr.Status)
+	current = 2
+	updated, respErr := s.repo.UpdateCollection(&models.CollectionUpdate{ID: col.ID, UserID: s.user.ObjectID, Name: &name, IfVersion: &current})
+	s.Require().Nil(respErr)
+	s.Equal(int64(3), updated.Version)
+}
+
+func (s *RepositoryTestSuite) TestTransferCard() {
+	bought := &models.Acquisition{ID: "a1", Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Count: 3, Price: 1, Currency: "usd", Status: models.AcquisitionReceived}
+	binder := s.collection("Binder", &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 3, Acquisitions: []*models.Acquisition{bought}})
+	deck := s.collection("Deck")
+	key := models.CardKey{ScryfallID: "bolt"}
+
+	result, respErr := s.repo.TransferCard(s.change(nil), &models.CardTransfer{FromCollectionID: binder.ObjectID, ToCollectionID: deck.ObjectID, Key: key, Count: 1})
+	s.Require().Nil(respErr)
+	s.Equal(&models.CardTransferResult{FromCount: 2, ToCount: 1}, result)
+
+	from, respErr := s.repo.GetCollection(binder.ID)
+	s.Require().Nil(respErr)
+	to, respErr := s.repo.GetCollection(deck.ID)
+	s.Require().Nil(respErr)
+	s.Equal([]int64{1, 1}, []int64{from.Version, to.Version})
+	s.Require().Len(from.Cards, 1)
+	s.Require().Len(to.Cards, 1)
+	s.Equal(2, from.Cards[0].Count)
+	s.Require().Len(from.Cards[0].Acquisitions, 1)
+	s.Equal([]any{"a1", 2}, []any{from.Cards[0].Acquisitions[0].ID, from.Cards[0].Acquisitions[0].Count})
+	s.Require().Len(to.Cards[0].Acquisitions, 1)
+	s.Equal(1, to.Cards[0].Acquisitions[0].Count, "the moved copy takes its share of the purchase")
+	s.Equal(-1, s.history(binder)[0].Delta)
+	s.Equal(1, s.history(deck)[0].Delta)
+
+	_, respErr = s.repo.TransferCard(s.change(nil), &models.CardTransfer{FromCollectionID: binder.ObjectID, ToCollectionID: deck.ObjectID, Key: key, Count: 3})
+	s.Require().NotNil(respErr)
+	s.Equal(http.StatusConflict, respErr.Status)
+	stale := int64(0)
+	_, respErr = s.repo.TransferCard(s.change(&stale), &models.CardTransfer{FromCollectionID: binder.ObjectID, ToCollectionID: deck.ObjectID, Key: key, Count: 1})
+	s.Require().NotNil(respErr)
+	s.Equal(models.VersionMismatch().Status, respErr.Status)
+
+	result, respErr = s.repo.TransferCard(s.change(nil), &models.CardTransfer{FromCollectionID: binder.ObjectID, ToCollectionID: deck.ObjectID, Key: key, Count: 2})
+	s.Require().Nil(respErr)
+	s.Equal(&models.CardTransferResult{FromCount: 0, ToCount: 3}, result)
+	from, respErr = s.repo.GetCollection(binder.ID)
+	s.Require().Nil(respErr)
+	s.Empty(from.Cards, "the card leaves with its last copy")
+	to, respErr = s.repo.GetCollection(deck.ID)
+	s.Require().Nil(respErr)
+	s.Equal(3, to.Cards[0].Count)
+	copies, _ := to.Cards[0].CostBasis()
+	s.Equal(3, copies)
+
+	result, respErr = s.repo.TransferCard(s.change(nil), &models.CardTransfer{FromCollectionID: deck.ObjectID, ToCollectionID: binder.ObjectID, Key: key, Count: 1, Copy: true})
+	s.Require().Nil(respErr)
+	s.Equal(&models.CardTransferResult{FromCount: 3, ToCount: 1}, result)
+	from, respErr = s.repo.GetCollection(binder.ID)
+	s.Require().Nil(respErr)
+	s.Empty(from.Cards[0].Acquisitions, "a copy was not bought")
+}
+
+func (s *RepositoryTestSuite) TestTransferCardZone() {
+	deck := s.collection("Elves", &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 2, Zone: models.ZoneSideboard})
+	kind := models.CollectionKindDeck
+	_, respErr := s.repo.UpdateCollection(&models.CollectionUpdate{ID: deck.ID, UserID: s.user.ObjectID, Kind: &kind})
+	s.Require().Nil(respErr)
+	binder := s.collection("Binder", &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1})
+
+	result, respErr := s.repo.TransferCard(s.change(nil), &models.CardTransfer{
+		FromCollectionID: deck.ObjectID, ToCollectionID: binder.ObjectID, Key: models.CardKey{ScryfallID: "elf", Zone: models.ZoneSideboard}, Count: 1,
+	})
+	s.Require().Nil(respErr)
+	s.Equal(&models.CardTransferResult{FromCount: 1, ToCount: 2}, result, "the copy joins the card without a zone")
+	stored, respErr := s.repo.GetCollection(binder.ID)
+	s.Require().Nil(respErr)
+	s.Require().Len(stored.Cards, 1)
+	s.Empty(stored.Cards[0].Zone)
+	s.Empty(s.history(binder)[0].Zone)
+	s.Equal(models.ZoneSideboard, s.history(deck)[0].Zone)
+}
+
+func (s *RepositoryTestSuite) TestUpdateCardToZero() {
+	col := s.collection("Binder", &models.Card{ScryfallID: "bolt", Name: "Lightning Bolt", Count: 2})
+	zero := 0
+	s.Require().Nil(s.repo.UpdateCardInCollection(s.change(nil), col.ID, &models.CardUpdate{Key: models.CardKey{ScryfallID: "bolt"}, Count: &zero}))
+
+	stored, respErr := s.repo.GetCollection(col.ID)
+	s.Require().Nil(respErr)
+	s.Empty(stored.Cards, "no copies left means no card")
+	entry := s.history(col)[0]
+	s.Equal(-2, entry.Delta)
+	s.Require().NotNil(entry.Removed)
+	s.Equal("Lightning Bolt", entry.Removed.Name)
+
+	respErr = s.repo.UpdateCardInCollection(s.change(nil), col.ID, &models.CardUpdate{Key: models.CardKey{ScryfallID: "bolt"}, Count: &zero})
+	s.Require().NotNil(respErr)
+	s.Equal(http.StatusNotFound, respErr.Status)
+}
+
+func (s *RepositoryTestSuite) TestCardPrices() {
+	first := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 1)
+	s.Require().Nil(s.repo.SaveCardPrices([]*models.CardPrice{
+		{ScryfallID: "bolt", Date: first, Prices: catalog.Prices{USD: 1}},
+		{ScryfallID: "bolt", Date: second, Prices: catalog.Prices{USD: 2}},
+		{ScryfallID: "elf", Date: first, Prices: catalog.Prices{USD: 0.5}},
+	}))
+
+	latest, respErr := s.repo.LatestCardPrices([]string{"bolt", "elf", "ring"})
+	s.Require().Nil(respErr)
+	s.Require().Len(latest, 2)
+	s.Equal(2.0, latest["bolt"].Prices.USD)
+	s.Equal(0.5, latest["elf"].Prices.USD)
+
+	history, respErr := s.repo.ListCardPrices([]string{"bolt"}, first, second)
+	s.Require().Nil(respErr)
+	s.Require().Len(history["bolt"], 2)
+	s.Equal([]float64{1, 2}, []float64{history["bolt"][0].Prices.USD, history["bolt"][1].Prices.USD})
+	history, respErr = s.repo.ListCardPrices([]string{"bolt"}, second, second)
+	s.Require().Nil(respErr)
+	s.Len(history["bolt"], 1)
+}
+
+func (s *RepositoryTestSuite) TestFindOwnedCards() {
+	binder := s.collection("Binder",
+		&models.Card{ScryfallID: "bolt-lea", Name: "Lightning Bolt", Count: 2},
+		&models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 1},
+		&models.Card{ScryfallID: "delver", Name: "Delver of Secrets // Insectile Aberration", Count: 1},
+		&models.Card{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 0},
+	)
+	s.collection("Other", &models.Card{ScryfallID: "elf", Name: "Llanowar Elves", Count: 4})
+	wishlist := s.collection("Wants", &models.Card{ScryfallID: "bolt-m10", Name: "Lightning Bolt", Count: 1})
+	kind := models.CollectionKindWishlist
+	_, respErr := s.repo.UpdateCollection(&models.CollectionUpdate{ID: wishlist.ID, UserID: s.user.ObjectID, Kind: &kind})
+	s.Require().Nil(respErr)
+
+	found, respErr := s.repo.FindOwnedCards(s.user.ID, &models.OwnedCardMatch{ScryfallIDs: []string{"bolt-lea", "bolt-m10"}})
+	s.Require().Nil(respErr)
+	s.Require().Len(found, 1, "wishlists and copies at zero are left out")
+	s.Equal(binder.ID, found[0].ID)
+	s.Require().Len(found[0].Cards, 1, "only the matching cards are returned")
+	s.Equal("bolt-lea", found[0].Cards[0].ScryfallID)
+
+	found, respErr = s.repo.FindOwnedCards(s.user.ID, &models.OwnedCardMatch{Name: "delver of secrets"})
+	s.Require().Nil(respErr)
+	s.Require().Len(found, 1)
+	s.Equal("delver", found[0].Cards[0].ScryfallID, "a name matches the front face")
+
+	found, respErr = s.repo.FindOwnedCards(s.user.ID, &models.OwnedCardMatch{Name: "Llanowar Elves (Alpha)"})
+	s.Require().Nil(respErr)
+	s.Empty(found)
+
+	found, respErr = s.repo.FindOwnedCards(s.user.ID, &models.OwnedCardMatch{ScryfallIDs: []string{"elf"}})
+	s.Require().Nil(respErr)
+	s.Len(found, 2)
+}
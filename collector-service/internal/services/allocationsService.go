@@ -0,0 +1,243 @@
+package services
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type AllocationsService struct {
+	allocationsRepository AllocationsRepositorer
+	log                   logger.Logger
+}
+
+type AllocationsRepositorer interface {
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+	ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr)
+	CreateAllocation(allocation *models.Allocation) (*models.Allocation, *models.ResponseErr)
+	ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr)
+	GetAllocation(allocationId string) (*models.Allocation, *models.ResponseErr)
+	DeleteAllocation(allocation *models.Allocation) *models.ResponseErr
+}
+
+func NewAllocationsService(allocationsRepository AllocationsRepositorer, log logger.Logger) *AllocationsService {
+	return &AllocationsService{
+		allocationsRepository: allocationsRepository,
+		log:                   log.With(logger.String("service", "allocations")),
+	}
+}
+
+// ListAllocations returns the reservations of a deck, oldest first.
+func (as AllocationsService) ListAllocations(userId, deckId string) ([]*models.Allocation, *models.ResponseErr) {
+	deck, respErr := ownedCollection(as.allocationsRepository, userId, deckId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if deck.Kind != models.CollectionKindDeck {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Collection is not a deck",
+		}
+	}
+
+	list, respErr := as.allocationsRepository.ListAllocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	return slices.DeleteFunc(list, func(a *models.Allocation) bool { return a.DeckID != deck.ObjectID }), nil
+}
+
+// ReserveCard reserves count copies of a printing of a source collection for a
+// deck that has the card. A source may be reserved for more copies than it
+// holds; ListConflicts reports such cards.
+func (as AllocationsService) ReserveCard(userId, deckId, sourceId, scryfallId string, count int) (*models.Allocation, *models.ResponseErr) {
+	if count < 1 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "count must be positive",
+		}
+	}
+
+	deck, respErr := ownedCollection(as.allocationsRepository, userId, deckId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	source, respErr := ownedCollection(as.allocationsRepository, userId, sourceId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	allocation, respErr := newAllocation(deck, source, scryfallId, count)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	owned := cardCount(deck, scryfallId)
+	if owned == 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Card not found",
+		}
+	}
+	list, respErr := as.allocationsRepository.ListAllocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	reserved := 0
+	for _, a := range list {
+		if a.DeckID == deck.ObjectID && a.ScryfallID == scryfallId {
+			reserved += a.Count
+		}
+	}
+	if reserved+count > owned {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Deck has fewer copies of the card than reserved",
+		}
+	}
+
+	created, respErr := as.allocationsRepository.CreateAllocation(allocation)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	as.log.Info("Card reserved", logger.String("user_id", userId), logger.String("deck_id", deckId), logger.String("source_id", sourceId))
+	return created, nil
+}
+
+// ReleaseCard deletes a reservation of a deck.
+func (as AllocationsService) ReleaseCard(userId, deckId, allocationId string) *models.ResponseErr {
+	allocation, respErr := as.allocationsRepository.GetAllocation(allocationId)
+	if respErr != nil {
+		return respErr
+	}
+
+	if allocation.UserID.Hex() != userId || allocation.DeckID.Hex() != deckId {
+		return &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Allocation not found",
+		}
+	}
+
+	return as.allocationsRepository.DeleteAllocation(allocation)
+}
+
+// ListConflicts returns the printings of the user's collections reserved for
+// more copies than the collections hold, in the order they were first reserved.
+func (as AllocationsService) ListConflicts(userId string) ([]*models.AllocationConflict, *models.ResponseErr) {
+	collections, respErr := as.allocationsRepository.ListCollectionsWithCards(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	list, respErr := as.allocationsRepository.ListAllocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	var all []*models.AllocationConflict
+	for _, da := range deckAllocations(collections, list) {
+		a := da.Allocation
+		i := slices.IndexFunc(all, func(c *models.AllocationConflict) bool {
+			return c.Source.ObjectID == a.SourceID && c.ScryfallID == a.ScryfallID
+		})
+		if i < 0 {
+			source := findCollection(collections, a.SourceID.Hex())
+			all = append(all, &models.AllocationConflict{
+				Source:     source,
+				ScryfallID: a.ScryfallID,
+				Name:       a.Name,
+				Owned:      cardCount(source, a.ScryfallID),
+			})
+			i = len(all) - 1
+		}
+		all[i].Allocations = append(all[i].Allocations, da)
+	}
+
+	conflicts := make([]*models.AllocationConflict, 0)
+	for _, c := range all {
+		if c.Allocated() > c.Owned {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts, nil
+}
+
+// newAllocation checks that copies of a printing of source can be reserved for deck.
+func newAllocation(deck, source *models.Collection, scryfallId string, count int) (*models.Allocation, *models.ResponseErr) {
+	var message string
+	switch {
+	case deck.Kind != models.CollectionKindDeck:
+		message = "Collection is not a deck"
+	case source.ObjectID == deck.ObjectID:
+		message = "Cards can't be reserved from the deck itself"
+	case source.Kind == models.CollectionKindDeck || source.Kind == models.CollectionKindWishlist:
+		message = "Cards can't be reserved from a deck or a wishlist"
+	}
+	if message != "" {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: message,
+		}
+	}
+
+	i := slices.IndexFunc(source.Cards, func(c *models.Card) bool { return c.ScryfallID == scryfallId })
+	if i < 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Card not found in source collection",
+		}
+	}
+
+	return &models.Allocation{
+		UserID:     deck.UserID,
+		DeckID:     deck.ObjectID,
+		SourceID:   source.ObjectID,
+		ScryfallID: scryfallId,
+		Name:       source.Cards[i].Name,
+		Count:      count,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// deckAllocations returns the reservations between live collections as far as
+// they count: older reservations go first, and a deck can't hold more reserved
+// copies of a printing than it has.
+func deckAllocations(collections []*models.Collection, allocations []*models.Allocation) []*models.DeckAllocation {
+	held := make(map[string]int)
+	var out []*models.DeckAllocation
+	for _, a := range allocations {
+		deck := findCollection(collections, a.DeckID.Hex())
+		if deck == nil || findCollection(collections, a.SourceID.Hex()) == nil {
+			continue
+		}
+		key := a.DeckID.Hex() + "/" + a.ScryfallID
+		count := min(a.Count, cardCount(deck, a.ScryfallID)-held[key])
+		if count <= 0 {
+			continue
+		}
+		held[key] += count
+		out = append(out, &models.DeckAllocation{Allocation: a, Deck: deck, Count: count})
+	}
+	return out
+}
+
+func findCollection(collections []*models.Collection, id string) *models.Collection {
+	i := slices.IndexFunc(collections, func(c *models.Collection) bool { return c.ObjectID.Hex() == id })
+	if i < 0 {
+		return nil
+	}
+	return collections[i]
+}
+
+// cardCount is the number of copies of a printing in a collection, in all zones.
+func cardCount(collection *models.Collection, scryfallId string) int {
+	count := 0
+	for _, card := range collection.Cards {
+		if card.ScryfallID == scryfallId {
+			count += card.Count
+		}
+	}
+	return count
+}
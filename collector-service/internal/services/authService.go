@@ -3,7 +3,6 @@ package services
 import (
 	"fmt"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
@@ -14,6 +13,8 @@ import (
 
 type AuthService struct {
 	authRepository AuthRepositorer
+	jwtSecret      string
+	tokenTTL       time.Duration
 	log            logger.Logger
 }
 
@@ -22,9 +23,11 @@ type AuthRepositorer interface {
 	FindUserByTelegramID(telegramId int64) (*models.User, *models.ResponseErr)
 }
 
-func NewAuthService(authRepository AuthRepositorer, log logger.Logger) *AuthService {
+func NewAuthService(authRepository AuthRepositorer, jwtSecret string, tokenTTL time.Duration, log logger.Logger) *AuthService {
 	return &AuthService{
 		authRepository: authRepository,
+		jwtSecret:      jwtSecret,
+		tokenTTL:       tokenTTL,
 		log:            log.With(logger.String("service", "auth")),
 	}
 }
@@ -45,7 +48,7 @@ func (as AuthService) Register(user *models.User) (string, *models.ResponseErr)
 		return "", respErr
 	}
 
-	token, err := generateToken(createdUser)
+	token, err := as.generateToken(createdUser)
 	if err != nil {
 		as.log.Error("failed to generate token", logger.Error(err))
 		return "", &models.ResponseErr{
@@ -74,7 +77,7 @@ func (as AuthService) Who(telegramId string) (string, *models.ResponseErr) {
 		return "", respErr
 	}
 
-	token, err := generateToken(user)
+	token, err := as.generateToken(user)
 	if err != nil {
 		as.log.Error("failed to generate token", logger.Error(err))
 		return "", &models.ResponseErr{
@@ -119,7 +122,7 @@ func (as AuthService) Login(user *models.User) (string, *models.ResponseErr) {
 		}
 	}
 
-	token, err := generateToken(existingUser)
+	token, err := as.generateToken(existingUser)
 	if err != nil {
 		as.log.Error("failed to generate token", logger.Error(err))
 		return "", &models.ResponseErr{
@@ -153,14 +156,13 @@ func convertTelegramID(telegramId string) (int64, *models.ResponseErr) {
 	return telegramIdInt64, nil
 }
 
-func generateToken(user *models.User) (string, error) {
+func (as AuthService) generateToken(user *models.User) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(72 * time.Hour).Unix(),
+		"exp":     time.Now().Add(as.tokenTTL).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secret := os.Getenv("JWT_SECRET")
 
-	return token.SignedString([]byte(secret))
+	return token.SignedString([]byte(as.jwtSecret))
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/mocks"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
@@ -22,7 +23,7 @@ func TestUnitTestSuite(t *testing.T) {
 
 func (uts *UnitTestSuite) SetupTest() {
 	authRepMock := mocks.MockAuthRepositorer{}
-	authService := NewAuthService(&authRepMock, logger.SilentLogger{})
+	authService := NewAuthService(&authRepMock, "test-secret", time.Hour, logger.SilentLogger{})
 
 	uts.authService = authService
 	uts.authRepMock = &authRepMock
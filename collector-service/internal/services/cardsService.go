@@ -2,62 +2,752 @@ package services
 
 import (
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type CardsService struct {
 	cardsRepository CardsRepositorer
+	catalog         *catalog.Catalog
 	log             logger.Logger
 }
 
 type CardsRepositorer interface {
 	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
-	AddCardToCollection(collectionId string, card *models.Card) *models.ResponseErr
-	SetCardCountInCollection(collectionId string, card *models.Card) *models.ResponseErr
-	DeleteCardFromCollection(collectionId string, card *models.Card) *models.ResponseErr
+	AddCardToCollection(change *models.CardChange, collectionId string, card *models.Card) *models.ResponseErr
+	AddReservedCardToCollection(change *models.CardChange, collectionId string, card *models.Card, allocation *models.Allocation) *models.ResponseErr
+	UpdateCardInCollection(change *models.CardChange, collectionId string, card *models.CardUpdate) *models.ResponseErr
+	DeleteCardFromCollection(change *models.CardChange, collectionId string, card *models.Card) *models.ResponseErr
+	SetCollectionCards(change *models.CardChange, collection *models.Collection) *models.ResponseErr
+	TransferCard(change *models.CardChange, transfer *models.CardTransfer) (*models.CardTransferResult, *models.ResponseErr)
+	ListCardHistory(filter *models.CardHistoryFilter) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	GetCardHistoryEntry(entryId string) (*models.CardHistoryEntry, *models.ResponseErr)
+	CardHistoryBatch(batchId bson.ObjectID) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	UndoCardChanges(change *models.CardChange, entries []*models.CardHistoryEntry) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr)
+	ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr)
+	FindOwnedCards(userId string, match *models.OwnedCardMatch) ([]*models.Collection, *models.ResponseErr)
+	ListStorageLocations(userId string) ([]*models.StorageLocation, *models.ResponseErr)
 }
 
-func NewCardsService(cardsRepository CardsRepositorer, log logger.Logger) *CardsService {
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+func NewCardsService(cardsRepository CardsRepositorer, catalog *catalog.Catalog, log logger.Logger) *CardsService {
 	return &CardsService{
 		cardsRepository: cardsRepository,
+		catalog:         catalog,
 		log:             log.With(logger.String("service", "cards")),
 	}
 }
 
-// ListCardsInCollection retrieves all cards in a collection by its ID.
-func (cs CardsService) ListCardsInCollection(collectionId string) ([]*models.Card, *models.ResponseErr) {
-	collection, err := cs.cardsRepository.GetCollection(collectionId)
-	if err != nil {
-		return nil, err
+// ListCardsInCollection retrieves a collection by its ID with all its cards,
+// so the cards come with the version they were read at.
+func (cs CardsService) ListCardsInCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	if collection.Cards == nil {
+		collection.Cards = []*models.Card{}
+	}
+
+	return collection, nil
+}
+
+// GetCardInCollection returns a card of a collection together with the other
+// printings of the same card in that collection and their catalog data.
+func (cs CardsService) GetCardInCollection(userId, collectionId string, key models.CardKey) (*models.CardEntry, *models.ResponseErr) {
+	key, respErr := checkCardKey(key)
+	if respErr != nil {
+		return nil, respErr
+	}
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
 	}
 
-	if collection == nil {
+	i := models.FindCard(collection.Cards, key)
+	if i < 0 {
 		return nil, &models.ResponseErr{
 			Status:  http.StatusNotFound,
-			Message: "Collection not found",
+			Message: "Card not found",
 		}
 	}
+	card := collection.Cards[i]
 
-	if collection.Cards == nil {
-		collection.Cards = []*models.Card{}
+	entry := cs.entry(card)
+	entry.Location, _ = collection.CardLocation(card)
+	entry.CollectionVersion = collection.Version
+	for _, other := range collection.Cards {
+		if other.Key() != card.Key() && cs.catalog.SameCard(card.ScryfallID, card.Name, other.ScryfallID, other.Name) {
+			variant := cs.entry(other)
+			variant.Location, _ = collection.CardLocation(other)
+			entry.Variants = append(entry.Variants, variant)
+		}
 	}
 
-	return collection.Cards, nil
+	return entry, nil
 }
 
-// AddCardToCollection adds a card to a collection by its ID.
-func (cs CardsService) AddCardToCollection(collectionId string, card *models.Card) *models.ResponseErr {
-	return cs.cardsRepository.AddCardToCollection(collectionId, card)
+// FindOwnedCards looks a card up in all collections of the user except those of
+// the wishlist kind and tells where each printing lies. A Scryfall ID finds
+// that printing only, an oracle ID or a name finds every printing of the card.
+// A name matches the front face of a multi-faced card too.
+func (cs CardsService) FindOwnedCards(userId string, query *models.OwnedCardQuery) ([]*models.OwnedCards, *models.ResponseErr) {
+	query.Name = strings.TrimSpace(query.Name)
+	set := 0
+	for _, v := range []string{query.ScryfallID, query.OracleID, query.Name} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Exactly one of scryfall_id, oracle_id or name is required",
+		}
+	}
+
+	collections, respErr := cs.cardsRepository.FindOwnedCards(userId, cs.ownedMatch(query))
+	if respErr != nil {
+		return nil, respErr
+	}
+	storages, respErr := cs.cardsRepository.ListStorageLocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	found := make([]*models.OwnedCards, 0, len(collections))
+	for _, collection := range collections {
+		owned := &models.OwnedCards{Collection: collection}
+		for _, card := range collection.Cards {
+			entry := cs.entry(card)
+			location, _ := collection.CardLocation(card)
+			entry.Location = namedLocation(location, storages)
+			owned.Cards = append(owned.Cards, entry)
+		}
+		found = append(found, owned)
+	}
+
+	return found, nil
 }
 
-// SetCardCountInCollection updates the count of a card in a collection by its ID.
-func (cs CardsService) SetCardCountInCollection(collectionId string, card *models.Card) *models.ResponseErr {
-	return cs.cardsRepository.SetCardCountInCollection(collectionId, card)
+// ownedMatch resolves a look-up to the printings the catalog knows of the card.
+// Cards missing from the catalog are still found by name.
+func (cs CardsService) ownedMatch(query *models.OwnedCardQuery) *models.OwnedCardMatch {
+	var printings []*catalog.Card
+	switch {
+	case query.ScryfallID != "":
+		return &models.OwnedCardMatch{ScryfallIDs: []string{query.ScryfallID}}
+	case query.OracleID != "":
+		printings = cs.catalog.Printings(query.OracleID)
+	default:
+		printings = cs.catalog.Named(query.Name)
+	}
+	match := &models.OwnedCardMatch{Name: query.Name}
+	for _, printing := range printings {
+		match.ScryfallIDs = append(match.ScryfallIDs, printing.ScryfallID)
+	}
+	return match
 }
 
-// DeleteCardFromCollection removes a card from a collection by its ID.
-func (cs CardsService) DeleteCardFromCollection(collectionId string, card *models.Card) *models.ResponseErr {
-	return cs.cardsRepository.DeleteCardFromCollection(collectionId, card)
+// AddCardToCollection adds a card to a collection by its ID. With reserveFrom
+// the collection is a deck and the added copies are reserved from that
+// collection of the user.
+func (cs CardsService) AddCardToCollection(userId, source, collectionId string, card *models.Card, reserveFrom string, ifVersion *int64) *models.ResponseErr {
+	zone, respErr := deckZone(card.Zone)
+	if respErr != nil {
+		return respErr
+	}
+	card.Zone = zone
+	finish, respErr := cardFinish(card.Finish)
+	if respErr != nil {
+		return respErr
+	}
+	card.Finish = finish
+
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return respErr
+	}
+	change, respErr := cardChange(collection, source, ifVersion)
+	if respErr != nil {
+		return respErr
+	}
+
+	if card.Name == "" {
+		if printing, ok := cs.catalog.Card(card.ScryfallID); ok {
+			card.Name = printing.Name
+		}
+	}
+	if card.AddedAt.IsZero() {
+		card.AddedAt = time.Now()
+	}
+
+	if reserveFrom == "" {
+		return cs.cardsRepository.AddCardToCollection(change, collectionId, card)
+	}
+	allocation, respErr := cs.reservation(userId, collection, reserveFrom, card)
+	if respErr != nil {
+		return respErr
+	}
+	return cs.cardsRepository.AddReservedCardToCollection(change, collectionId, card, allocation)
+}
+
+// reservation checks that the copies of a card added to a deck can be reserved
+// from a collection; the card must already be there.
+func (cs CardsService) reservation(userId string, deck *models.Collection, sourceId string, card *models.Card) (*models.Allocation, *models.ResponseErr) {
+	sourceCollection, respErr := ownedCollection(cs.cardsRepository, userId, sourceId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	allocation, respErr := newAllocation(deck, sourceCollection, card.ScryfallID, card.Count)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	list, respErr := cs.cardsRepository.ListAllocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if slices.ContainsFunc(list, func(a *models.Allocation) bool {
+		return a.DeckID == allocation.DeckID && a.SourceID == allocation.SourceID && a.ScryfallID == allocation.ScryfallID
+	}) {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Card is already reserved from this collection",
+		}
+	}
+
+	return allocation, nil
+}
+
+// UpdateCardInCollection changes the count, notes, condition, deck zone or finish of a card in a collection.
+// A card can't move to a zone that already has a card of the same printing.
+func (cs CardsService) UpdateCardInCollection(userId, source, collectionId string, card *models.CardUpdate, ifVersion *int64) *models.ResponseErr {
+	if card.Count == nil && card.Notes == nil && card.Condition == nil && card.Zone == nil && card.Finish == nil {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Nothing to update",
+		}
+	}
+	if card.Condition != nil && *card.Condition != "" && !slices.Contains(models.CardConditions, *card.Condition) {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid card condition",
+		}
+	}
+	key, respErr := checkCardKey(card.Key)
+	if respErr != nil {
+		return respErr
+	}
+	card.Key = key
+	if card.Zone != nil {
+		zone, respErr := deckZone(*card.Zone)
+		if respErr != nil {
+			return respErr
+		}
+		card.Zone = &zone
+	}
+	if card.Finish != nil {
+		finish, respErr := cardFinish(*card.Finish)
+		if respErr != nil {
+			return respErr
+		}
+		card.Finish = &finish
+	}
+
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return respErr
+	}
+	change, respErr := cardChange(collection, source, ifVersion)
+	if respErr != nil {
+		return respErr
+	}
+
+	return cs.cardsRepository.UpdateCardInCollection(change, collectionId, card)
+}
+
+// DeleteCardFromCollection removes a card from a collection by its ID, deck zone and finish.
+func (cs CardsService) DeleteCardFromCollection(userId, source, collectionId string, card *models.Card, ifVersion *int64) *models.ResponseErr {
+	key, respErr := checkCardKey(card.Key())
+	if respErr != nil {
+		return respErr
+	}
+	card.Zone, card.Finish = key.Zone, key.Finish
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return respErr
+	}
+	change, respErr := cardChange(collection, source, ifVersion)
+	if respErr != nil {
+		return respErr
+	}
+
+	return cs.cardsRepository.DeleteCardFromCollection(change, collectionId, card)
+}
+
+// TransferCard moves count copies of a card from one of the user's collections
+// to another, or copies them if copy is set. The copies keep their deck zone.
+func (cs CardsService) TransferCard(userId, source, fromCollectionId, toCollectionId string, key models.CardKey, count int, copy bool, ifVersion *int64) (*models.CardTransferResult, *models.ResponseErr) {
+	if count < 1 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "count must be positive",
+		}
+	}
+	if fromCollectionId == toCollectionId {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Source and target collections are the same",
+		}
+	}
+	key, respErr := checkCardKey(key)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	from, respErr := ownedCollection(cs.cardsRepository, userId, fromCollectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	to, respErr := ownedCollection(cs.cardsRepository, userId, toCollectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	change, respErr := cardChange(from, source, ifVersion)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return cs.cardsRepository.TransferCard(change, &models.CardTransfer{
+		FromCollectionID: from.ObjectID,
+		ToCollectionID:   to.ObjectID,
+		Key:              key,
+		Count:            count,
+		Copy:             copy,
+	})
+}
+
+// ApplyCardBatch runs card operations in order and saves the result in one write.
+// If atomic, nothing is saved when any operation fails; otherwise failed
+// operations are skipped and the rest is saved.
+func (cs CardsService) ApplyCardBatch(userId, source, collectionId string, atomic bool, ops []*models.CardOperation, ifVersion *int64) (*models.CardBatchResult, *models.ResponseErr) {
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	change, respErr := cardChange(collection, source, ifVersion)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	now := time.Now()
+	batch := &models.CardBatchResult{Results: make([]*models.CardOperationResult, 0, len(ops))}
+	applied, failed := 0, 0
+	for _, op := range ops {
+		result := cs.applyCardOperation(collection, op, now)
+		if result.Err != nil {
+			failed++
+		} else {
+			applied++
+		}
+		batch.Results = append(batch.Results, result)
+	}
+
+	if applied == 0 || (atomic && failed > 0) {
+		return batch, nil
+	}
+	if respErr := cs.cardsRepository.SetCollectionCards(change, collection); respErr != nil {
+		return nil, respErr
+	}
+	batch.Committed = true
+
+	return batch, nil
+}
+
+func (cs CardsService) applyCardOperation(collection *models.Collection, op *models.CardOperation, now time.Time) *models.CardOperationResult {
+	result := &models.CardOperationResult{Op: op.Op, ScryfallID: op.Card.ScryfallID, Zone: op.Card.Zone, Finish: op.Card.Finish}
+	fail := func(status int, message string) *models.CardOperationResult {
+		result.Err = &models.ResponseErr{Status: status, Message: message}
+		return result
+	}
+
+	if op.Card.ScryfallID == "" {
+		return fail(http.StatusBadRequest, "scryfall_id is required")
+	}
+	if op.Card.Count < 0 {
+		return fail(http.StatusBadRequest, "count must not be negative")
+	}
+	zone, respErr := deckZone(op.Card.Zone)
+	if respErr != nil {
+		return fail(respErr.Status, respErr.Message)
+	}
+	finish, respErr := cardFinish(op.Card.Finish)
+	if respErr != nil {
+		return fail(respErr.Status, respErr.Message)
+	}
+	op.Card.Zone, op.Card.Finish = zone, finish
+	result.Zone, result.Finish = zone, finish
+
+	i := models.FindCard(collection.Cards, op.Card.Key())
+	count := 0
+	if i >= 0 {
+		count = collection.Cards[i].Count
+	}
+
+	switch op.Op {
+	case models.CardOpAdd:
+		if op.Card.Count < 1 {
+			return fail(http.StatusBadRequest, "count must be positive")
+		}
+		count += op.Card.Count
+	case models.CardOpSet:
+		count = op.Card.Count
+	case models.CardOpRemove:
+		if i < 0 {
+			return fail(http.StatusNotFound, "Card not found")
+		}
+		if op.Card.Count == 0 {
+			count = 0
+		} else {
+			count = max(count-op.Card.Count, 0)
+		}
+	default:
+		return fail(http.StatusBadRequest, "Unknown operation, use add, set or remove")
+	}
+	result.Count = count
+
+	switch {
+	case count == 0 && i >= 0:
+		collection.Cards = slices.Delete(collection.Cards, i, i+1)
+	case count == 0:
+		// Nothing to remove.
+	case i >= 0:
+		collection.Cards[i].Count = count
+		collection.Cards[i].UpdatedAt = now
+	default:
+		card := *op.Card
+		card.Count = count
+		if card.Name == "" {
+			if printing, ok := cs.catalog.Card(card.ScryfallID); ok {
+				card.Name = printing.Name
+			}
+		}
+		card.AddedAt = now
+		collection.Cards = append(collection.Cards, &card)
+	}
+
+	return result
+}
+
+// ListCardHistory returns the history of the cards of a collection, newest
+// changes first. History can be limited to one card and paged with before,
+// the ID of the last entry of the previous page.
+func (cs CardsService) ListCardHistory(userId, collectionId, scryfallId, before string, limit int) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	filter := &models.CardHistoryFilter{
+		CollectionID: collection.ObjectID,
+		ScryfallID:   scryfallId,
+		Limit:        limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+	filter.Limit = min(filter.Limit, maxHistoryLimit)
+	if before != "" {
+		objectId, err := bson.ObjectIDFromHex(before)
+		if err != nil {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid history entry ID format",
+			}
+		}
+		filter.Before = objectId
+	}
+
+	return cs.cardsRepository.ListCardHistory(filter)
+}
+
+// UndoCardChange reverts a change recorded in the history of a collection, or
+// the whole batch it was written in if wholeBatch is set. A batch can span
+// several collections, as transfers do; they all must still exist.
+func (cs CardsService) UndoCardChange(userId, source, collectionId, entryId string, wholeBatch bool) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	change, respErr := cardChange(collection, source, nil)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	entry, respErr := cs.cardsRepository.GetCardHistoryEntry(entryId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if entry.CollectionID != collection.ObjectID || entry.UserID != collection.UserID {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "History entry not found",
+		}
+	}
+
+	entries := []*models.CardHistoryEntry{entry}
+	if wholeBatch {
+		entries, respErr = cs.cardsRepository.CardHistoryBatch(entry.BatchID)
+		if respErr != nil {
+			return nil, respErr
+		}
+	}
+
+	undo, respErr := cs.cardsRepository.UndoCardChanges(change, entries)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	cs.log.Info("Card changes undone", logger.String("collection_id", collectionId), logger.Int("entries", len(undo)))
+	return undo, nil
+}
+
+// AddAcquisition records a purchase of copies of a card of a collection.
+// The date defaults to now and the status to received.
+func (cs CardsService) AddAcquisition(userId, collectionId string, key models.CardKey, acquisition *models.Acquisition, ifVersion *int64) (*models.Acquisition, *models.ResponseErr) {
+	if acquisition.Date.IsZero() {
+		acquisition.Date = time.Now()
+	}
+	if acquisition.Status == "" {
+		acquisition.Status = models.AcquisitionReceived
+	}
+	acquisition.Currency = strings.ToLower(acquisition.Currency)
+	if respErr := checkAcquisition(acquisition); respErr != nil {
+		return nil, respErr
+	}
+
+	collection, card, change, respErr := cs.acquisitionCard(userId, collectionId, key, ifVersion)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	acquisition.ID = bson.NewObjectID().Hex()
+	card.Acquisitions = append(card.Acquisitions, acquisition)
+	slices.SortStableFunc(card.Acquisitions, func(a, b *models.Acquisition) int { return a.Date.Compare(b.Date) })
+	card.UpdatedAt = time.Now()
+	if respErr := cs.cardsRepository.SetCollectionCards(change, collection); respErr != nil {
+		return nil, respErr
+	}
+
+	return acquisition, nil
+}
+
+// UpdateAcquisition changes a purchase of copies of a card, for one when the ordered copies arrive.
+func (cs CardsService) UpdateAcquisition(userId, collectionId string, key models.CardKey, acquisitionId string, update *models.AcquisitionUpdate, ifVersion *int64) (*models.Acquisition, *models.ResponseErr) {
+	collection, card, change, respErr := cs.acquisitionCard(userId, collectionId, key, ifVersion)
+	if respErr != nil {
+		return nil, respErr
+	}
+	i, respErr := acquisitionIndex(card, acquisitionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	acquisition := *card.Acquisitions[i]
+	if update.Date != nil {
+		acquisition.Date = *update.Date
+	}
+	if update.Count != nil {
+		acquisition.Count = *update.Count
+	}
+	if update.Price != nil {
+		acquisition.Price = *update.Price
+	}
+	if update.Currency != nil {
+		acquisition.Currency = strings.ToLower(*update.Currency)
+	}
+	if update.Vendor != nil {
+		acquisition.Vendor = *update.Vendor
+	}
+	if update.Status != nil {
+		acquisition.Status = *update.Status
+	}
+	if respErr := checkAcquisition(&acquisition); respErr != nil {
+		return nil, respErr
+	}
+
+	card.Acquisitions[i] = &acquisition
+	slices.SortStableFunc(card.Acquisitions, func(a, b *models.Acquisition) int { return a.Date.Compare(b.Date) })
+	card.UpdatedAt = time.Now()
+	if respErr := cs.cardsRepository.SetCollectionCards(change, collection); respErr != nil {
+		return nil, respErr
+	}
+
+	return &acquisition, nil
+}
+
+// DeleteAcquisition removes a purchase of copies of a card.
+func (cs CardsService) DeleteAcquisition(userId, collectionId string, key models.CardKey, acquisitionId string, ifVersion *int64) *models.ResponseErr {
+	collection, card, change, respErr := cs.acquisitionCard(userId, collectionId, key, ifVersion)
+	if respErr != nil {
+		return respErr
+	}
+	i, respErr := acquisitionIndex(card, acquisitionId)
+	if respErr != nil {
+		return respErr
+	}
+
+	card.Acquisitions = slices.Delete(card.Acquisitions, i, i+1)
+	card.UpdatedAt = time.Now()
+	return cs.cardsRepository.SetCollectionCards(change, collection)
+}
+
+// acquisitionCard loads the card whose purchases change. Cards of a wishlist
+// are wanted, not owned, so they have no purchases.
+func (cs CardsService) acquisitionCard(userId, collectionId string, key models.CardKey, ifVersion *int64) (*models.Collection, *models.Card, *models.CardChange, *models.ResponseErr) {
+	key, respErr := checkCardKey(key)
+	if respErr != nil {
+		return nil, nil, nil, respErr
+	}
+	collection, respErr := ownedCollection(cs.cardsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, nil, nil, respErr
+	}
+	if collection.Kind == models.CollectionKindWishlist {
+		return nil, nil, nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Wishlist cards can't have acquisitions",
+		}
+	}
+	change, respErr := cardChange(collection, "", ifVersion)
+	if respErr != nil {
+		return nil, nil, nil, respErr
+	}
+
+	i := models.FindCard(collection.Cards, key)
+	if i < 0 {
+		return nil, nil, nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Card not found",
+		}
+	}
+
+	return collection, collection.Cards[i], change, nil
+}
+
+func acquisitionIndex(card *models.Card, acquisitionId string) (int, *models.ResponseErr) {
+	i := slices.IndexFunc(card.Acquisitions, func(a *models.Acquisition) bool { return a.ID == acquisitionId })
+	if i < 0 {
+		return 0, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Acquisition not found",
+		}
+	}
+	return i, nil
+}
+
+func checkAcquisition(acquisition *models.Acquisition) *models.ResponseErr {
+	var message string
+	switch {
+	case acquisition.Count < 1:
+		message = "count must be positive"
+	case acquisition.Price < 0:
+		message = "price must not be negative"
+	case !slices.Contains(models.PurchaseCurrencies, acquisition.Currency):
+		message = "Currency must be one of " + strings.Join(models.PurchaseCurrencies, ", ")
+	case !slices.Contains(models.AcquisitionStatuses, acquisition.Status):
+		message = "Status must be ordered or received"
+	case len([]rune(acquisition.Vendor)) > models.MaxVendorLen:
+		message = "Vendor is too long"
+	default:
+		return nil
+	}
+	return &models.ResponseErr{
+		Status:  http.StatusBadRequest,
+		Message: message,
+	}
+}
+
+// cardChange tells the repository who changes the cards of a collection, where
+// the change comes from and which version of the collection it expects. An empty
+// source means the API. A collection of another version fails the change with 412.
+func cardChange(collection *models.Collection, source string, ifVersion *int64) (*models.CardChange, *models.ResponseErr) {
+	if source == "" {
+		source = models.ChangeSourceAPI
+	}
+	if !slices.Contains(models.ChangeSources, source) {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Unknown change source",
+		}
+	}
+
+	if respErr := collection.CheckVersion(ifVersion); respErr != nil {
+		return nil, respErr
+	}
+
+	return &models.CardChange{UserID: collection.UserID, Source: source, IfVersion: ifVersion}, nil
+}
+
+// deckZone checks a deck zone; the mainboard is stored as no zone.
+func deckZone(zone string) (string, *models.ResponseErr) {
+	if zone != "" && !slices.Contains(models.DeckZones, zone) {
+		return "", &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Unknown deck zone",
+		}
+	}
+	if zone == models.ZoneMainboard {
+		return "", nil
+	}
+	return zone, nil
+}
+
+// checkCardKey checks the deck zone and finish of a card key.
+func checkCardKey(key models.CardKey) (models.CardKey, *models.ResponseErr) {
+	zone, respErr := deckZone(key.Zone)
+	if respErr != nil {
+		return models.CardKey{}, respErr
+	}
+	finish, respErr := cardFinish(key.Finish)
+	if respErr != nil {
+		return models.CardKey{}, respErr
+	}
+	key.Zone, key.Finish = zone, finish
+	return key, nil
+}
+
+// cardFinish checks the finish of a card; nonfoil cards are stored without one.
+func cardFinish(finish string) (string, *models.ResponseErr) {
+	if finish != "" && !slices.Contains(models.CardFinishes, finish) {
+		return "", &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Finish must be nonfoil, foil or etched",
+		}
+	}
+	if finish == catalog.FinishNonfoil {
+		return "", nil
+	}
+	return finish, nil
+}
+
+func (cs CardsService) entry(card *models.Card) *models.CardEntry {
+	entry := &models.CardEntry{Card: card}
+	if printing, ok := cs.catalog.Card(card.ScryfallID); ok {
+		entry.Printing = printing
+	}
+	return entry
 }
@@ -1,50 +1,395 @@
 package services
 
 import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
 	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 )
 
 type CollectionsService struct {
 	collectionRepository CollectionsRepositorer
+	catalog              *catalog.Catalog
 	log                  logger.Logger
 }
 
 type CollectionsRepositorer interface {
-	UsersCollections(userId string) ([]*models.UserCollectionRef, *models.ResponseErr)
 	CreateCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr)
-	RenameCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr)
-	DeleteCollection(collection *models.Collection) *models.ResponseErr
+	ListCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr)
+	UpdateCollection(update *models.CollectionUpdate) (*models.Collection, *models.ResponseErr)
+	DeleteCollection(collection *models.Collection, ifVersion *int64) *models.ResponseErr
+	ListDeletedCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr)
+	RestoreCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr)
+	PurgeDeletedCollections(before time.Time) (int, *models.ResponseErr)
 	GetCollectionByName(collection *models.Collection) (*models.Collection, *models.ResponseErr)
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+	MergeCollections(change *models.CardChange, target, source *models.Collection, keepSource bool) *models.ResponseErr
+	SplitCollection(change *models.CardChange, source, created *models.Collection) (*models.Collection, *models.ResponseErr)
+	GetStorageLocation(locationId string) (*models.StorageLocation, *models.ResponseErr)
 }
 
-func NewCollectionsService(collectionRepository CollectionsRepositorer, log logger.Logger) *CollectionsService {
+func NewCollectionsService(collectionRepository CollectionsRepositorer, catalog *catalog.Catalog, log logger.Logger) *CollectionsService {
 	return &CollectionsService{
 		collectionRepository: collectionRepository,
+		catalog:              catalog,
 		log:                  log.With(logger.String("service", "collections")),
 	}
 }
 
-func (cs CollectionsService) AllUsersCollections(userId string) ([]*models.UserCollectionRef, *models.ResponseErr) {
-	cs.log.Info("CollectionsService.AllUsersCollections called", logger.String("userId", userId))
+// ListCollections returns the collections of a user with their metadata and card totals.
+func (cs CollectionsService) ListCollections(userId string) ([]*models.CollectionSummary, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.ListCollections called", logger.String("userId", userId))
 
-	return cs.collectionRepository.UsersCollections(userId)
+	return cs.collectionRepository.ListCollections(userId)
 }
 
 // TODO: add logging
 
 func (cs CollectionsService) CreateCollection(collection *models.Collection) (*models.Collection, *models.ResponseErr) {
+	if respErr := validateKind(collection.Kind); respErr != nil {
+		return nil, respErr
+	}
+	if respErr := validateFormat(collection.Format); respErr != nil {
+		return nil, respErr
+	}
+	tags, respErr := normalizeTags(collection.Tags)
+	if respErr != nil {
+		return nil, respErr
+	}
+	collection.Tags = tags
+
 	return cs.collectionRepository.CreateCollection(collection)
 }
 
-func (cs CollectionsService) RenameCollection(collecion *models.Collection) (*models.Collection, *models.ResponseErr) {
-	return cs.collectionRepository.RenameCollection(collecion)
+// UpdateCollection changes the name and metadata of a collection; fields left nil are kept.
+func (cs CollectionsService) UpdateCollection(update *models.CollectionUpdate) (*models.Collection, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.UpdateCollection called", logger.String("userId", update.UserID.Hex()), logger.String("collectionId", update.ID))
+
+	if update.IsEmpty() {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Nothing to update",
+		}
+	}
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Collection name is empty",
+		}
+	}
+	if update.Kind != nil {
+		if respErr := validateKind(*update.Kind); respErr != nil {
+			return nil, respErr
+		}
+	}
+	if update.Format != nil {
+		if respErr := validateFormat(*update.Format); respErr != nil {
+			return nil, respErr
+		}
+	}
+	if update.Tags != nil {
+		tags, respErr := normalizeTags(*update.Tags)
+		if respErr != nil {
+			return nil, respErr
+		}
+		update.Tags = &tags
+	}
+	if update.CoverCard != nil && *update.CoverCard != "" {
+		collection, respErr := ownedCollection(cs.collectionRepository, update.UserID.Hex(), update.ID)
+		if respErr != nil {
+			return nil, respErr
+		}
+		if !slices.ContainsFunc(collection.Cards, func(c *models.Card) bool { return c.ScryfallID == *update.CoverCard }) {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusBadRequest,
+				Message: "Cover card is not in the collection",
+			}
+		}
+	}
+
+	if update.Location != nil && !update.Location.StorageID.IsZero() {
+		if respErr := checkLocation(cs.collectionRepository, update.UserID.Hex(), update.Location); respErr != nil {
+			return nil, respErr
+		}
+	}
+
+	return cs.collectionRepository.UpdateCollection(update)
+}
+
+// GetCollection returns a collection of the user with its cards.
+func (cs CollectionsService) GetCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	return ownedCollection(cs.collectionRepository, userId, collectionId)
 }
 
-func (cs CollectionsService) DeleteCollection(collection *models.Collection) *models.ResponseErr {
-	return cs.collectionRepository.DeleteCollection(collection)
+// DeleteCollection moves a collection to the trash, where it can be restored until it is purged.
+// A set ifVersion must be the version of the collection.
+func (cs CollectionsService) DeleteCollection(collection *models.Collection, ifVersion *int64) *models.ResponseErr {
+	return cs.collectionRepository.DeleteCollection(collection, ifVersion)
+}
+
+// ListTrash returns the deleted collections of a user, most recently deleted first.
+func (cs CollectionsService) ListTrash(userId string) ([]*models.CollectionSummary, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.ListTrash called", logger.String("userId", userId))
+
+	return cs.collectionRepository.ListDeletedCollections(userId)
+}
+
+// RestoreCollection takes a deleted collection out of the trash with all its cards.
+func (cs CollectionsService) RestoreCollection(userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.RestoreCollection called", logger.String("userId", userId), logger.String("collectionId", collectionId))
+
+	return cs.collectionRepository.RestoreCollection(userId, collectionId)
+}
+
+// PurgeTrash removes for good the collections that have been in the trash longer than retention.
+func (cs CollectionsService) PurgeTrash(retention time.Duration) (int, *models.ResponseErr) {
+	purged, respErr := cs.collectionRepository.PurgeDeletedCollections(time.Now().Add(-retention))
+	if respErr != nil {
+		return 0, respErr
+	}
+
+	if purged > 0 {
+		cs.log.Info("Purged deleted collections", logger.Int("count", purged))
+	}
+	return purged, nil
 }
 
 func (cs CollectionsService) GetCollectionByName(collection *models.Collection) (*models.Collection, *models.ResponseErr) {
 	return cs.collectionRepository.GetCollectionByName(collection)
 }
+
+// MergeCollections adds the cards of source to target, summing counts of the
+// same card, and moves source to the trash unless keepSource is set.
+// A set ifVersion must be the version of target.
+func (cs CollectionsService) MergeCollections(userId, changeSource, targetId, sourceId string, keepSource bool, ifVersion *int64) (*models.Collection, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.MergeCollections called", logger.String("userId", userId), logger.String("targetId", targetId), logger.String("sourceId", sourceId))
+
+	if targetId == sourceId {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Cannot merge a collection into itself",
+		}
+	}
+
+	target, respErr := ownedCollection(cs.collectionRepository, userId, targetId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	source, respErr := ownedCollection(cs.collectionRepository, userId, sourceId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	change, respErr := cardChange(target, changeSource, ifVersion)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	now := time.Now()
+	for _, card := range source.Cards {
+		// Purchases move along with the cards; the copies a kept source
+		// leaves in the target were not bought again.
+		acquisitions := card.Acquisitions
+		if keepSource {
+			acquisitions = nil
+		}
+
+		i := models.FindCard(target.Cards, card.Key())
+		if i < 0 {
+			merged := *card
+			merged.Acquisitions = acquisitions
+			target.Cards = append(target.Cards, &merged)
+			continue
+		}
+
+		existing := target.Cards[i]
+		existing.Count += card.Count
+		existing.Acquisitions = append(existing.Acquisitions, acquisitions...)
+		if card.AddedAt.Before(existing.AddedAt) {
+			existing.AddedAt = card.AddedAt
+		}
+		if existing.Notes == "" {
+			existing.Notes = card.Notes
+		}
+		if existing.Condition == "" {
+			existing.Condition = card.Condition
+		}
+		existing.UpdatedAt = now
+	}
+
+	if respErr := cs.collectionRepository.MergeCollections(change, target, source, keepSource); respErr != nil {
+		return nil, respErr
+	}
+
+	return target, nil
+}
+
+// DuplicateCollection creates a copy of a collection with all its cards and
+// metadata under a new name. The copy is neither pinned nor ordered.
+func (cs CollectionsService) DuplicateCollection(userId, collectionId, name string) (*models.Collection, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.DuplicateCollection called", logger.String("userId", userId), logger.String("collectionId", collectionId))
+
+	collection, respErr := ownedCollection(cs.collectionRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if respErr := cs.checkNameFree(collection, name); respErr != nil {
+		return nil, respErr
+	}
+
+	now := time.Now()
+	duplicate := &models.Collection{
+		UserID:      collection.UserID,
+		Name:        name,
+		Description: collection.Description,
+		Kind:        collection.Kind,
+		Format:      collection.Format,
+		Tags:        collection.Tags,
+		CoverCard:   collection.CoverCard,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	// The copies of the duplicate were not bought, so they have no purchases.
+	for _, card := range collection.Cards {
+		copied := *card
+		copied.Acquisitions = nil
+		duplicate.Cards = append(duplicate.Cards, &copied)
+	}
+	return cs.collectionRepository.CreateCollection(duplicate)
+}
+
+// SplitCollection moves the cards matching filter into a new collection.
+// A set ifVersion must be the version of the split collection.
+func (cs CollectionsService) SplitCollection(userId, changeSource, collectionId, name string, filter *models.CardFilter, ifVersion *int64) (*models.Collection, *models.ResponseErr) {
+	cs.log.Info("CollectionsService.SplitCollection called", logger.String("userId", userId), logger.String("collectionId", collectionId))
+
+	if filter.IsEmpty() {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Filter is empty",
+		}
+	}
+
+	source, respErr := ownedCollection(cs.collectionRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if respErr := cs.checkNameFree(source, name); respErr != nil {
+		return nil, respErr
+	}
+	change, respErr := cardChange(source, changeSource, ifVersion)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	created := &models.Collection{UserID: source.UserID, Name: name}
+	kept := make([]*models.Card, 0, len(source.Cards))
+	for _, card := range source.Cards {
+		if cs.matches(card, filter) {
+			created.Cards = append(created.Cards, card)
+		} else {
+			kept = append(kept, card)
+		}
+	}
+	if len(created.Cards) == 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "No cards match the filter",
+		}
+	}
+	source.Cards = kept
+
+	return cs.collectionRepository.SplitCollection(change, source, created)
+}
+
+// checkNameFree makes sure the owner of collection has no collection called name.
+func (cs CollectionsService) checkNameFree(collection *models.Collection, name string) *models.ResponseErr {
+	_, respErr := cs.collectionRepository.GetCollectionByName(&models.Collection{UserID: collection.UserID, Name: name})
+	switch {
+	case respErr == nil:
+		return &models.ResponseErr{
+			Status:  http.StatusConflict,
+			Message: "Collection with this name already exists",
+		}
+	case respErr.Status == http.StatusNotFound:
+		return nil
+	default:
+		return respErr
+	}
+}
+
+func validateKind(kind string) *models.ResponseErr {
+	if kind != "" && !slices.Contains(models.CollectionKinds, kind) {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Unknown collection kind",
+		}
+	}
+	return nil
+}
+
+func validateFormat(format string) *models.ResponseErr {
+	if _, ok := models.DeckFormats[format]; format != "" && !ok {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Unknown deck format",
+		}
+	}
+	return nil
+}
+
+// normalizeTags trims tags and drops duplicates, keeping the order.
+func normalizeTags(tags []string) ([]string, *models.ResponseErr) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len(tag) > models.MaxCollectionTagLen {
+			return nil, &models.ResponseErr{
+				Status:  http.StatusBadRequest,
+				Message: fmt.Sprintf("Tags must be 1 to %d characters long", models.MaxCollectionTagLen),
+			}
+		}
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	if len(out) > models.MaxCollectionTags {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("A collection can have at most %d tags", models.MaxCollectionTags),
+		}
+	}
+	return out, nil
+}
+
+func (cs CollectionsService) matches(card *models.Card, filter *models.CardFilter) bool {
+	if len(filter.ScryfallIDs) > 0 && !slices.Contains(filter.ScryfallIDs, card.ScryfallID) {
+		return false
+	}
+	if filter.Name != "" && !strings.Contains(strings.ToLower(card.Name), strings.ToLower(filter.Name)) {
+		return false
+	}
+	if len(filter.Conditions) > 0 && !slices.Contains(filter.Conditions, card.Condition) {
+		return false
+	}
+	if len(filter.Sets) == 0 && len(filter.Rarities) == 0 {
+		return true
+	}
+
+	// Set and rarity come from the catalog; unknown cards never match them.
+	printing, ok := cs.catalog.Card(card.ScryfallID)
+	if !ok {
+		return false
+	}
+	if len(filter.Sets) > 0 && !slices.ContainsFunc(filter.Sets, func(set string) bool { return strings.EqualFold(set, printing.Set) }) {
+		return false
+	}
+	if len(filter.Rarities) > 0 && !slices.ContainsFunc(filter.Rarities, func(rarity string) bool { return strings.EqualFold(rarity, printing.Rarity) }) {
+		return false
+	}
+	return true
+}
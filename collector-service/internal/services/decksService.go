@@ -0,0 +1,506 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+// basicLands may be played in any number; checked by name when the catalog doesn't know the card.
+var basicLands = []string{
+	"Plains", "Island", "Swamp", "Mountain", "Forest", "Wastes",
+	"Snow-Covered Plains", "Snow-Covered Island", "Snow-Covered Swamp", "Snow-Covered Mountain", "Snow-Covered Forest", "Snow-Covered Wastes",
+}
+
+// colorOrder is the usual WUBRG order of colors.
+var colorOrder = []string{"W", "U", "B", "R", "G"}
+
+type DecksService struct {
+	decksRepository DecksRepositorer
+	catalog         *catalog.Catalog
+	log             logger.Logger
+}
+
+type DecksRepositorer interface {
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+	ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr)
+	ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr)
+}
+
+func NewDecksService(decksRepository DecksRepositorer, catalog *catalog.Catalog, log logger.Logger) *DecksService {
+	return &DecksService{
+		decksRepository: decksRepository,
+		catalog:         catalog,
+		log:             log.With(logger.String("service", "decks")),
+	}
+}
+
+// ValidateDeck checks a deck of the user against the rules of its format.
+// Cards in the maybeboard are not part of the deck.
+func (ds DecksService) ValidateDeck(userId, collectionId string) (*models.DeckReport, *models.ResponseErr) {
+	deck, respErr := ds.userDeck(userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	format, ok := models.DeckFormats[deck.Format]
+	if !ok {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Deck format is not set",
+		}
+	}
+
+	return ds.validate(deck, format), nil
+}
+
+// deckCard is a card of a deck with its copies in every printing and zone.
+type deckCard struct {
+	name       string
+	scryfallID string
+	printing   *catalog.Card
+	count      int
+}
+
+func (ds DecksService) validate(deck *models.Collection, format *models.DeckFormat) *models.DeckReport {
+	report := &models.DeckReport{Format: deck.Format, Problems: make([]*models.DeckProblem, 0)}
+	zones := make(map[string]int)
+	// Cards are the same for deck rules when they have the same name.
+	byName := make(map[string]*deckCard)
+	var list []*deckCard
+	var commanders []*catalog.Card
+	identityKnown := true
+	for _, card := range deck.Cards {
+		zone := card.DeckZone()
+		if zone == models.ZoneMaybeboard {
+			continue
+		}
+		zones[zone] += card.Count
+
+		printing, known := ds.catalog.Card(card.ScryfallID)
+		name := deckCardName(card, printing)
+		if !known && !slices.Contains(report.Unchecked, name) {
+			report.Unchecked = append(report.Unchecked, name)
+		}
+		dc, ok := byName[strings.ToLower(name)]
+		if !ok {
+			dc = &deckCard{name: name, scryfallID: card.ScryfallID}
+			byName[strings.ToLower(name)] = dc
+			list = append(list, dc)
+		}
+		if dc.printing == nil && known {
+			dc.printing, dc.scryfallID = printing, card.ScryfallID
+		}
+		dc.count += card.Count
+
+		if zone == models.ZoneCommander {
+			report.Commanders = append(report.Commanders, name)
+			if known {
+				commanders = append(commanders, printing)
+			} else {
+				identityKnown = false
+			}
+		}
+	}
+
+	report.Mainboard = zones[models.ZoneMainboard] + zones[models.ZoneCommander]
+	report.Sideboard = zones[models.ZoneSideboard]
+	if !format.Commander {
+		report.Sideboard += zones[models.ZoneCompanion]
+	}
+
+	problem := func(rule, message string, cards []string) {
+		report.Problems = append(report.Problems, &models.DeckProblem{Rule: rule, Message: message, Cards: cards})
+	}
+	switch {
+	case format.MinDeck == format.MaxDeck && report.Mainboard != format.MinDeck:
+		problem(models.DeckRuleSize, fmt.Sprintf("Deck has %d cards, %s needs exactly %d", report.Mainboard, deck.Format, format.MinDeck), nil)
+	case report.Mainboard < format.MinDeck:
+		problem(models.DeckRuleSize, fmt.Sprintf("Deck has %d cards, %s needs at least %d", report.Mainboard, deck.Format, format.MinDeck), nil)
+	case format.MaxDeck > 0 && report.Mainboard > format.MaxDeck:
+		problem(models.DeckRuleSize, fmt.Sprintf("Deck has %d cards, %s allows at most %d", report.Mainboard, deck.Format, format.MaxDeck), nil)
+	}
+	switch {
+	case report.Sideboard > 0 && format.MaxSideboard == 0:
+		problem(models.DeckRuleSideboard, fmt.Sprintf("%s decks have no sideboard", deck.Format), nil)
+	case report.Sideboard > format.MaxSideboard:
+		problem(models.DeckRuleSideboard, fmt.Sprintf("Sideboard has %d cards, %s allows at most %d", report.Sideboard, deck.Format, format.MaxSideboard), nil)
+	}
+	switch {
+	case format.Commander && (zones[models.ZoneCommander] < 1 || zones[models.ZoneCommander] > 2):
+		problem(models.DeckRuleCommander, "Deck needs one or two commanders", report.Commanders)
+	case !format.Commander && zones[models.ZoneCommander] > 0:
+		problem(models.DeckRuleCommander, fmt.Sprintf("%s decks have no commander", deck.Format), report.Commanders)
+	}
+	if zones[models.ZoneCompanion] > 1 {
+		problem(models.DeckRuleCompanion, "Deck can have only one companion", nil)
+	}
+
+	var copies, restricted, banned, notLegal []string
+	for _, dc := range list {
+		legality := ds.catalog.Legality(deck.Format, dc.scryfallID, dc.name)
+		switch {
+		case legality == catalog.Banned:
+			banned = append(banned, dc.name)
+		case legality == catalog.NotLegal:
+			notLegal = append(notLegal, dc.name)
+		case legality == catalog.Restricted && dc.count > 1:
+			restricted = append(restricted, dc.name)
+		}
+		if legality != catalog.Restricted && dc.count > format.MaxCopies && !anyNumberAllowed(dc) {
+			copies = append(copies, dc.name)
+		}
+	}
+	if len(copies) > 0 {
+		message := fmt.Sprintf("At most %d copies of a card are allowed", format.MaxCopies)
+		if format.MaxCopies == 1 {
+			message = "Only one copy of a card is allowed"
+		}
+		problem(models.DeckRuleCopies, message, copies)
+	}
+	if len(restricted) > 0 {
+		problem(models.DeckRuleRestricted, "Restricted cards are allowed in one copy", restricted)
+	}
+	if len(banned) > 0 {
+		problem(models.DeckRuleBanned, fmt.Sprintf("Cards are banned in %s", deck.Format), banned)
+	}
+	if len(notLegal) > 0 {
+		problem(models.DeckRuleNotLegal, fmt.Sprintf("Cards are not legal in %s", deck.Format), notLegal)
+	}
+
+	if format.Commander && len(commanders) > 0 && identityKnown {
+		for _, commander := range commanders {
+			for _, color := range commander.ColorIdentity {
+				if !slices.Contains(report.ColorIdentity, color) {
+					report.ColorIdentity = append(report.ColorIdentity, color)
+				}
+			}
+		}
+		slices.SortFunc(report.ColorIdentity, func(a, b string) int {
+			return slices.Index(colorOrder, a) - slices.Index(colorOrder, b)
+		})
+
+		var outside []string
+		for _, dc := range list {
+			if dc.printing == nil || slices.Contains(outside, dc.name) {
+				continue
+			}
+			for _, color := range dc.printing.ColorIdentity {
+				if !slices.Contains(report.ColorIdentity, color) {
+					outside = append(outside, dc.name)
+					break
+				}
+			}
+		}
+		if len(outside) > 0 {
+			problem(models.DeckRuleColorIdentity, "Cards are outside the color identity of the commanders", outside)
+		}
+	}
+
+	return report
+}
+
+// anyNumberAllowed reports whether a deck may have any number of copies of a card:
+// basic lands and cards that say so.
+func anyNumberAllowed(dc *deckCard) bool {
+	if dc.printing == nil {
+		return slices.ContainsFunc(basicLands, func(name string) bool { return strings.EqualFold(name, dc.name) })
+	}
+	return strings.Contains(dc.printing.TypeLine, "Basic") && strings.Contains(dc.printing.TypeLine, "Land") ||
+		strings.Contains(dc.printing.OracleText, "A deck can have any number of cards named")
+}
+
+func deckCardName(card *models.Card, printing *catalog.Card) string {
+	switch {
+	case printing != nil && printing.Name != "":
+		return printing.Name
+	case card.Name != "":
+		return card.Name
+	default:
+		return card.ScryfallID
+	}
+}
+
+// CheckBuildability checks a deck against the cards the user owns in their
+// other collections, except those of the wishlist kind. The deck is one of the
+// user's collections or a pasted decklist; cards in its maybeboard are left out.
+// A copy covers a card if it is the same printing or, with anyPrinting, any
+// printing of the same card. Decklist lines that don't name a printing take any.
+// Copies reserved for other decks are taken last and tell which deck holds them.
+func (ds DecksService) CheckBuildability(userId, collectionId, decklist string, anyPrinting bool) (*models.Buildability, *models.ResponseErr) {
+	if (collectionId == "") == (strings.TrimSpace(decklist) == "") {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Either collection_id or decklist is required",
+		}
+	}
+
+	var needs []*models.DeckCard
+	if collectionId != "" {
+		deck, respErr := ownedCollection(ds.decksRepository, userId, collectionId)
+		if respErr != nil {
+			return nil, respErr
+		}
+		for _, card := range deck.Cards {
+			if card.DeckZone() == models.ZoneMaybeboard || card.Count < 1 {
+				continue
+			}
+			printing, _ := ds.catalog.Card(card.ScryfallID)
+			needs = append(needs, &models.DeckCard{ScryfallID: card.ScryfallID, Name: deckCardName(card, printing), Count: card.Count})
+		}
+	} else {
+		var respErr *models.ResponseErr
+		if needs, respErr = ds.parseDecklist(decklist); respErr != nil {
+			return nil, respErr
+		}
+	}
+
+	collections, respErr := ds.decksRepository.ListCollectionsWithCards(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	allocations, respErr := ds.decksRepository.ListAllocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	reserved := deckAllocations(collections, allocations)
+
+	// owned are the copies not taken by a card of the deck yet. Copies
+	// reserved for the deck go first, copies reserved for other decks last.
+	type owned struct {
+		collection *models.Collection
+		card       *models.Card
+		name       string
+		left       int
+		reserved   bool
+		deck       *models.Collection
+	}
+	var stock []*owned
+	// held and taken count the reserved copies already found in an entry of
+	// the printing in the deck and in the source, so that its other zones and
+	// finishes don't count them again.
+	held := make(map[*models.DeckAllocation]int)
+	taken := make(map[*models.DeckAllocation]int)
+	for _, collection := range collections {
+		if collection.Kind == models.CollectionKindWishlist || collection.ObjectID.Hex() == collectionId {
+			continue
+		}
+		for _, card := range collection.Cards {
+			printing, _ := ds.catalog.Card(card.ScryfallID)
+			name := deckCardName(card, printing)
+			left := card.Count
+			for _, da := range reserved {
+				if da.Allocation.ScryfallID == card.ScryfallID && da.Allocation.DeckID == collection.ObjectID {
+					// Those copies are counted in their source.
+					n := min(da.Count-held[da], left)
+					held[da] += n
+					left -= n
+				}
+			}
+			for _, da := range reserved {
+				if da.Allocation.ScryfallID != card.ScryfallID || da.Allocation.SourceID != collection.ObjectID || left <= 0 {
+					continue
+				}
+				n := min(da.Count-taken[da], left)
+				if n <= 0 {
+					continue
+				}
+				o := &owned{collection: collection, card: card, name: name, left: n}
+				if o.reserved = da.Deck.ObjectID.Hex() == collectionId; !o.reserved {
+					o.deck = da.Deck
+				}
+				taken[da] += n
+				left -= o.left
+				stock = append(stock, o)
+			}
+			if left > 0 {
+				stock = append(stock, &owned{collection: collection, card: card, name: name, left: left})
+			}
+		}
+	}
+	rank := func(o *owned) int {
+		switch {
+		case o.reserved:
+			return 0
+		case o.deck == nil:
+			return 1
+		default:
+			return 2
+		}
+	}
+	slices.SortStableFunc(stock, func(a, b *owned) int { return rank(a) - rank(b) })
+
+	report := &models.Buildability{AnyPrinting: anyPrinting, Cards: make([]*models.BuildabilityCard, 0, len(needs))}
+	for _, need := range needs {
+		card := &models.BuildabilityCard{ScryfallID: need.ScryfallID, Name: need.Name, Needed: need.Count}
+		// The printing the deck names goes first, other printings only fill the rest.
+		for _, exact := range []bool{true, false} {
+			for _, o := range stock {
+				missing := card.Missing()
+				if missing == 0 || o.left == 0 {
+					continue
+				}
+				same := need.ScryfallID != "" && o.card.ScryfallID == need.ScryfallID
+				if exact != same || !exact && need.ScryfallID != "" && !anyPrinting {
+					continue
+				}
+				if !exact && !ds.catalog.SameCard(o.card.ScryfallID, o.name, need.ScryfallID, need.Name) {
+					continue
+				}
+				taken := min(missing, o.left)
+				o.left -= taken
+				source := &models.CardSource{
+					CollectionID:   o.collection.ObjectID.Hex(),
+					CollectionName: o.collection.Name,
+					ScryfallID:     o.card.ScryfallID,
+					Count:          taken,
+					Reserved:       o.reserved,
+				}
+				if o.deck != nil {
+					source.DeckID, source.DeckName = o.deck.ObjectID.Hex(), o.deck.Name
+				}
+				card.Sources = append(card.Sources, source)
+			}
+		}
+
+		if missing := card.Missing(); missing > 0 {
+			if price, ok := ds.cheapestPrice(need, anyPrinting); ok {
+				card.Price = price
+				report.EstimatedCost += price * float64(missing)
+			} else {
+				report.Unpriced = append(report.Unpriced, need.Name)
+			}
+		}
+		report.Cards = append(report.Cards, card)
+	}
+	report.EstimatedCost = math.Round(report.EstimatedCost*100) / 100
+
+	return report, nil
+}
+
+// cheapestPrice returns the lowest known price of a printing that would do for a card of a deck.
+func (ds DecksService) cheapestPrice(need *models.DeckCard, anyPrinting bool) (float64, bool) {
+	var printings []*catalog.Card
+	printing, known := ds.catalog.Card(need.ScryfallID)
+	switch {
+	case known && !anyPrinting:
+		printings = []*catalog.Card{printing}
+	case known && printing.OracleID != "":
+		printings = ds.catalog.Printings(printing.OracleID)
+	case known:
+		printings = []*catalog.Card{printing}
+	case need.ScryfallID == "" || anyPrinting:
+		printings = ds.catalog.Named(need.Name)
+	}
+
+	cheapest, found := 0.0, false
+	for _, p := range printings {
+		if price, ok := p.PriceUSD(); ok && (!found || price < cheapest) {
+			cheapest, found = price, true
+		}
+	}
+	return cheapest, found
+}
+
+// decklistLine is a line of a decklist: "4 Lightning Bolt", "4x Lightning Bolt"
+// or "1 Lightning Bolt (M10) 146" with the set code and collector number of a printing.
+var decklistLine = regexp.MustCompile(`^(?:(\d+)x?\s+)?(.+?)(?:\s+\(([A-Za-z0-9]+)\)(?:\s+(\S+))?)?$`)
+
+// decklistSections are the headers that split a decklist into zones.
+var decklistSections = map[string]string{
+	"deck":       models.ZoneMainboard,
+	"main":       models.ZoneMainboard,
+	"mainboard":  models.ZoneMainboard,
+	"commander":  models.ZoneCommander,
+	"companion":  models.ZoneCompanion,
+	"sideboard":  models.ZoneSideboard,
+	"maybeboard": models.ZoneMaybeboard,
+}
+
+// parseDecklist reads a decklist in the usual text format: a card per line with
+// an optional count, sections like "Sideboard", "SB:" prefixes, and comments
+// starting with "//" or "#". Maybeboard cards are skipped; lines for the same
+// card are added up. Printings are looked up in the catalog by set and number,
+// names of known cards are spelled as the catalog does.
+func (ds DecksService) parseDecklist(decklist string) ([]*models.DeckCard, *models.ResponseErr) {
+	var cards []*models.DeckCard
+	byKey := make(map[string]*models.DeckCard)
+	zone := models.ZoneMainboard
+	for i, line := range strings.Split(decklist, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if section, ok := decklistSections[strings.ToLower(strings.TrimSuffix(line, ":"))]; ok {
+			zone = section
+			continue
+		}
+		if len(line) > 3 && strings.EqualFold(line[:3], "SB:") {
+			line = strings.TrimSpace(line[3:])
+		} else if zone == models.ZoneMaybeboard {
+			continue
+		}
+
+		match := decklistLine.FindStringSubmatch(line)
+		count := 1
+		if match[1] != "" {
+			n, err := strconv.Atoi(match[1])
+			if err != nil || n < 1 {
+				return nil, &models.ResponseErr{
+					Status:  http.StatusBadRequest,
+					Message: fmt.Sprintf("Line %d: count must be positive", i+1),
+				}
+			}
+			count = n
+		}
+
+		card := &models.DeckCard{Name: match[2], Count: count}
+		if printing, ok := ds.catalog.Printing(match[3], match[4]); ok {
+			card.ScryfallID, card.Name = printing.ScryfallID, printing.Name
+		} else if named := ds.catalog.Named(card.Name); len(named) > 0 {
+			card.Name = named[0].Name
+		}
+
+		key := card.ScryfallID
+		if key == "" {
+			key = strings.ToLower(card.Name)
+		}
+		if same, ok := byKey[key]; ok {
+			same.Count += card.Count
+			continue
+		}
+		byKey[key] = card
+		cards = append(cards, card)
+	}
+
+	if len(cards) == 0 {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Decklist has no cards",
+		}
+	}
+	return cards, nil
+}
+
+// userDeck loads a deck of the user; other collections are not decks.
+func (ds DecksService) userDeck(userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	collection, respErr := ownedCollection(ds.decksRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if collection.Kind != models.CollectionKindDeck {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Collection is not a deck",
+		}
+	}
+
+	return collection, nil
+}
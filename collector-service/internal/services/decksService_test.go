@@ -0,0 +1,162 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// go test github.com/ShenokZlob/collector-ouphe/collector-service/internal/services -run DecksTestSuite
+type DecksTestSuite struct {
+	suite.Suite
+	decksService    *DecksService
+	decksRepository *decksRepositoryStub
+}
+
+func TestDecksTestSuite(t *testing.T) {
+	suite.Run(t, &DecksTestSuite{})
+}
+
+func (dts *DecksTestSuite) SetupTest() {
+	legal := map[string]string{"modern": catalog.Legal, "vintage": catalog.Legal, "commander": catalog.Legal}
+	cards := catalog.New(
+		&catalog.Card{ScryfallID: "krenko", Name: "Krenko, Mob Boss", TypeLine: "Legendary Creature — Goblin Warrior", ColorIdentity: []string{"R"}, Legalities: legal},
+		&catalog.Card{ScryfallID: "bolt", OracleID: "bolt", Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146", TypeLine: "Instant", ColorIdentity: []string{"R"},
+			Legalities: legal, Prices: map[string]string{"usd": "2.50"}},
+		&catalog.Card{ScryfallID: "bolt-2xm", OracleID: "bolt", Name: "Lightning Bolt", Set: "2xm", CollectorNumber: "117", TypeLine: "Instant", ColorIdentity: []string{"R"},
+			Legalities: legal, Prices: map[string]string{"usd": "1.25"}},
+		&catalog.Card{ScryfallID: "delver", OracleID: "delver", Name: "Delver of Secrets // Insectile Aberration",
+			CardFaces: []catalog.CardFace{{Name: "Delver of Secrets"}, {Name: "Insectile Aberration"}}},
+		&catalog.Card{ScryfallID: "counterspell", Name: "Counterspell", TypeLine: "Instant", ColorIdentity: []string{"U"},
+			Legalities: map[string]string{"modern": catalog.NotLegal, "vintage": catalog.Legal, "commander": catalog.Legal}},
+		&catalog.Card{ScryfallID: "lotus", Name: "Black Lotus", TypeLine: "Artifact", Legalities: map[string]string{"modern": catalog.NotLegal, "vintage": catalog.Restricted, "commander": catalog.Banned}},
+		&catalog.Card{ScryfallID: "rats", Name: "Relentless Rats", TypeLine: "Creature — Rat", ColorIdentity: []string{"B"},
+			OracleText: "A deck can have any number of cards named Relentless Rats.", Legalities: legal},
+		&catalog.Card{ScryfallID: "snow-mountain", Name: "Snow-Covered Mountain", TypeLine: "Basic Snow Land — Mountain", ColorIdentity: []string{"R"}, Legalities: legal},
+	)
+	cards.SetBanlists(catalog.Banlists{"modern": {Banned: []string{"lightning bolt"}}})
+
+	dts.decksRepository = &decksRepositoryStub{}
+	dts.decksService = NewDecksService(dts.decksRepository, cards, logger.SilentLogger{})
+}
+
+// decksRepositoryStub serves the collections of a single user.
+type decksRepositoryStub struct {
+	collections []*models.Collection
+}
+
+func (r *decksRepositoryStub) GetCollection(collectionId string) (*models.Collection, *models.ResponseErr) {
+	for _, c := range r.collections {
+		if c.ObjectID.Hex() == collectionId {
+			return c, nil
+		}
+	}
+	return nil, &models.ResponseErr{Status: http.StatusNotFound, Message: "Collection not found"}
+}
+
+func (r *decksRepositoryStub) ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr) {
+	return r.collections, nil
+}
+
+func (r *decksRepositoryStub) ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr) {
+	return nil, nil
+}
+
+func deckOf(format string, cards ...*models.Card) *models.Collection {
+	return &models.Collection{Kind: models.CollectionKindDeck, Format: format, Cards: cards}
+}
+
+func (dts *DecksTestSuite) TestLegality() {
+	report := dts.decksService.validate(deckOf("modern",
+		&models.Card{ScryfallID: "bolt", Count: 4},
+		&models.Card{ScryfallID: "counterspell", Count: 4},
+		&models.Card{ScryfallID: "rats", Count: 30},
+		&models.Card{ScryfallID: "snow-mountain", Count: 22},
+	), models.DeckFormats["modern"])
+
+	dts.False(report.Legal())
+	dts.Empty(report.Unchecked)
+	dts.Equal([]*models.DeckProblem{
+		{Rule: models.DeckRuleBanned, Message: "Cards are banned in modern", Cards: []string{"Lightning Bolt"}},
+		{Rule: models.DeckRuleNotLegal, Message: "Cards are not legal in modern", Cards: []string{"Counterspell"}},
+	}, report.Problems, "the banlist wins over the catalog, rats and basic lands are not limited")
+}
+
+func (dts *DecksTestSuite) TestRestricted() {
+	format := models.DeckFormats["vintage"]
+	report := dts.decksService.validate(deckOf("vintage",
+		&models.Card{ScryfallID: "lotus", Count: 1},
+		&models.Card{ScryfallID: "snow-mountain", Count: 59},
+	), format)
+	dts.True(report.Legal())
+
+	report = dts.decksService.validate(deckOf("vintage",
+		&models.Card{ScryfallID: "lotus", Count: 2},
+		&models.Card{ScryfallID: "snow-mountain", Count: 58},
+	), format)
+	dts.Equal([]*models.DeckProblem{
+		{Rule: models.DeckRuleRestricted, Message: "Restricted cards are allowed in one copy", Cards: []string{"Black Lotus"}},
+	}, report.Problems)
+}
+
+func (dts *DecksTestSuite) TestColorIdentity() {
+	report := dts.decksService.validate(deckOf("commander",
+		&models.Card{ScryfallID: "krenko", Count: 1, Zone: models.ZoneCommander},
+		&models.Card{ScryfallID: "bolt", Count: 1},
+		&models.Card{ScryfallID: "counterspell", Count: 1},
+		&models.Card{ScryfallID: "rats", Count: 30},
+		&models.Card{ScryfallID: "snow-mountain", Count: 67},
+	), models.DeckFormats["commander"])
+
+	dts.Equal([]string{"Krenko, Mob Boss"}, report.Commanders)
+	dts.Equal([]string{"R"}, report.ColorIdentity)
+	dts.Equal(100, report.Mainboard)
+	dts.Equal([]*models.DeckProblem{
+		{Rule: models.DeckRuleColorIdentity, Message: "Cards are outside the color identity of the commanders", Cards: []string{"Counterspell", "Relentless Rats"}},
+	}, report.Problems)
+}
+
+func (dts *DecksTestSuite) TestUnknownCommander() {
+	report := dts.decksService.validate(deckOf("commander",
+		&models.Card{ScryfallID: "unknown", Name: "Somebody", Count: 1, Zone: models.ZoneCommander},
+		&models.Card{ScryfallID: "counterspell", Count: 1},
+		&models.Card{ScryfallID: "snow-mountain", Count: 98},
+	), models.DeckFormats["commander"])
+
+	dts.True(report.Legal(), "color identity is not checked without the commander")
+	dts.Empty(report.ColorIdentity)
+	dts.Equal([]string{"Somebody"}, report.Unchecked)
+}
+
+func (dts *DecksTestSuite) TestBuildability() {
+	userId := bson.NewObjectID()
+	binder := &models.Collection{ObjectID: bson.NewObjectID(), UserID: userId, Name: "Binder", Cards: []*models.Card{
+		{ScryfallID: "bolt-2xm", Count: 1},
+		{ScryfallID: "delver", Count: 1},
+	}}
+	dts.decksRepository.collections = []*models.Collection{binder}
+	decklist := "4 Lightning Bolt (M10) 146\n1 delver of secrets\n2 Somebody"
+
+	report, respErr := dts.decksService.CheckBuildability(userId.Hex(), "", decklist, false)
+	dts.Require().Nil(respErr)
+	dts.Equal([]*models.BuildabilityCard{
+		{ScryfallID: "bolt", Name: "Lightning Bolt", Needed: 4, Price: 2.5},
+		{Name: "Delver of Secrets // Insectile Aberration", Needed: 1, Sources: []*models.CardSource{
+			{CollectionID: binder.ObjectID.Hex(), CollectionName: "Binder", ScryfallID: "delver", Count: 1},
+		}},
+		{Name: "Somebody", Needed: 2},
+	}, report.Cards, "the printing is found by set and number, the card by the name of its front face")
+	dts.Equal(10.0, report.EstimatedCost)
+	dts.Equal([]string{"Somebody"}, report.Unpriced)
+
+	report, respErr = dts.decksService.CheckBuildability(userId.Hex(), "", decklist, true)
+	dts.Require().Nil(respErr)
+	dts.Equal(1, report.Cards[0].Covered())
+	dts.Equal(1.25, report.Cards[0].Price, "the cheapest printing")
+	dts.Equal(3.75, report.EstimatedCost)
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type LocationsService struct {
+	locationsRepository LocationsRepositorer
+	log                 logger.Logger
+}
+
+type LocationsRepositorer interface {
+	CreateStorageLocation(location *models.StorageLocation) (*models.StorageLocation, *models.ResponseErr)
+	ListStorageLocations(userId string) ([]*models.StorageLocation, *models.ResponseErr)
+	GetStorageLocation(locationId string) (*models.StorageLocation, *models.ResponseErr)
+	DeleteStorageLocation(location *models.StorageLocation) *models.ResponseErr
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+	ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr)
+	SetCollectionCards(change *models.CardChange, collection *models.Collection) *models.ResponseErr
+}
+
+func NewLocationsService(locationsRepository LocationsRepositorer, log logger.Logger) *LocationsService {
+	return &LocationsService{
+		locationsRepository: locationsRepository,
+		log:                 log.With(logger.String("service", "locations")),
+	}
+}
+
+// CreateLocation creates a box or a binder of a user; names are unique per user.
+func (ls LocationsService) CreateLocation(location *models.StorageLocation) (*models.StorageLocation, *models.ResponseErr) {
+	location.Name = strings.TrimSpace(location.Name)
+	var message string
+	switch {
+	case location.Name == "":
+		message = "Location name is empty"
+	case len(location.Name) > models.MaxStorageNameLen:
+		message = "Location name is too long"
+	case !slices.Contains(models.StorageKinds, location.Kind):
+		message = "Location kind must be one of box, binder"
+	}
+	if message != "" {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: message,
+		}
+	}
+	location.CreatedAt = time.Now()
+
+	created, respErr := ls.locationsRepository.CreateStorageLocation(location)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	ls.log.Info("Location created", logger.String("user_id", location.UserID.Hex()), logger.String("location_id", created.ID))
+	return created, nil
+}
+
+// ListLocations returns the boxes and binders of a user by name.
+func (ls LocationsService) ListLocations(userId string) ([]*models.StorageLocation, *models.ResponseErr) {
+	return ls.locationsRepository.ListStorageLocations(userId)
+}
+
+// DeleteLocation deletes a box or a binder no collection or card lies in.
+func (ls LocationsService) DeleteLocation(userId, locationId string) *models.ResponseErr {
+	storage, respErr := ownedStorageLocation(ls.locationsRepository, userId, locationId)
+	if respErr != nil {
+		return respErr
+	}
+
+	collections, respErr := ls.locationsRepository.ListCollectionsWithCards(userId)
+	if respErr != nil {
+		return respErr
+	}
+	for _, collection := range collections {
+		if inStorage(collection.Location, storage) || slices.ContainsFunc(collection.Cards, func(c *models.Card) bool { return inStorage(c.Location, storage) }) {
+			return &models.ResponseErr{
+				Status:  http.StatusConflict,
+				Message: "Location is not empty",
+			}
+		}
+	}
+
+	return ls.locationsRepository.DeleteStorageLocation(storage)
+}
+
+// ListLocationCards returns what lies in a box or a binder, optionally in one
+// section or on one page only, ordered by section, page and slot. Cards of
+// wishlists are not physical and never lie anywhere.
+func (ls LocationsService) ListLocationCards(userId, locationId, section string, page int) (*models.StorageLocation, []*models.LocatedCard, *models.ResponseErr) {
+	storage, respErr := ownedStorageLocation(ls.locationsRepository, userId, locationId)
+	if respErr != nil {
+		return nil, nil, respErr
+	}
+	collections, respErr := ls.locationsRepository.ListCollectionsWithCards(userId)
+	if respErr != nil {
+		return nil, nil, respErr
+	}
+
+	located := make([]*models.LocatedCard, 0)
+	for _, collection := range collections {
+		if collection.Kind == models.CollectionKindWishlist {
+			continue
+		}
+		for _, card := range collection.Cards {
+			location, inherited := collection.CardLocation(card)
+			if card.Count < 1 || !inStorage(location, storage) ||
+				section != "" && !strings.EqualFold(location.Section, section) || page > 0 && location.Page != page {
+				continue
+			}
+			located = append(located, &models.LocatedCard{
+				Collection: collection,
+				Card:       card,
+				Location:   namedLocation(location, []*models.StorageLocation{storage}),
+				Inherited:  inherited,
+			})
+		}
+	}
+	slices.SortStableFunc(located, func(a, b *models.LocatedCard) int {
+		return cmp.Or(
+			cmp.Compare(a.Location.Section, b.Location.Section),
+			cmp.Compare(a.Location.Page, b.Location.Page),
+			cmp.Compare(a.Location.Slot, b.Location.Slot),
+		)
+	})
+
+	return storage, located, nil
+}
+
+// RelocateCards puts cards of a collection, all of them without scryfallIds,
+// into a location. A nil location takes the cards out of their own locations,
+// so they lie where the collection does. It returns how many cards it moved.
+func (ls LocationsService) RelocateCards(userId, source, collectionId string, scryfallIds []string, location *models.Location, ifVersion *int64) (int, *models.ResponseErr) {
+	collection, respErr := ownedCollection(ls.locationsRepository, userId, collectionId)
+	if respErr != nil {
+		return 0, respErr
+	}
+	change, respErr := cardChange(collection, source, ifVersion)
+	if respErr != nil {
+		return 0, respErr
+	}
+	if location != nil {
+		if respErr := checkLocation(ls.locationsRepository, userId, location); respErr != nil {
+			return 0, respErr
+		}
+	}
+
+	moved := collection.Cards
+	if len(scryfallIds) > 0 {
+		moved = nil
+		for _, id := range scryfallIds {
+			// Every zone of a printing lies in the same place.
+			found := false
+			for _, card := range collection.Cards {
+				if card.ScryfallID != id {
+					continue
+				}
+				found = true
+				if !slices.Contains(moved, card) {
+					moved = append(moved, card)
+				}
+			}
+			if !found {
+				return 0, &models.ResponseErr{
+					Status:  http.StatusNotFound,
+					Message: "Card not found",
+				}
+			}
+		}
+	}
+	if len(moved) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	for _, card := range moved {
+		card.Location = location
+		card.UpdatedAt = now
+	}
+	if respErr := ls.locationsRepository.SetCollectionCards(change, collection); respErr != nil {
+		return 0, respErr
+	}
+
+	ls.log.Info("Cards relocated", logger.String("user_id", userId), logger.String("collection_id", collectionId))
+	return len(moved), nil
+}
+
+// checkLocation checks that a location of a card or a collection is in a box
+// or a binder of the user and fits it: boxes have no pages or slots.
+func checkLocation(repository storageLocationGetter, userId string, location *models.Location) *models.ResponseErr {
+	var message string
+	switch {
+	case len(location.Section) > models.MaxLocationSectionLen:
+		message = "Location section is too long"
+	case location.Page < 0 || location.Slot < 0:
+		message = "Location page and slot can't be negative"
+	case location.Slot > 0 && location.Page == 0:
+		message = "Location slot needs a page"
+	}
+	if message != "" {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: message,
+		}
+	}
+
+	storage, respErr := ownedStorageLocation(repository, userId, location.StorageID.Hex())
+	if respErr != nil {
+		return respErr
+	}
+	if storage.Kind == models.StorageKindBox && location.Page > 0 {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Boxes have no pages or slots",
+		}
+	}
+
+	return nil
+}
+
+// inStorage reports whether a location is in the storage location.
+func inStorage(location *models.Location, storage *models.StorageLocation) bool {
+	return location != nil && location.StorageID == storage.ObjectID
+}
+
+// namedLocation copies a location with the name of its storage location, if the user has it.
+func namedLocation(location *models.Location, storages []*models.StorageLocation) *models.Location {
+	if location == nil {
+		return nil
+	}
+	named := *location
+	if i := slices.IndexFunc(storages, func(s *models.StorageLocation) bool { return s.ObjectID == location.StorageID }); i >= 0 {
+		named.StorageName = storages[i].Name
+	}
+	return &named
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+)
+
+// Everything a user keeps is hidden from other users: the helpers below load it
+// and answer "not found" to anyone but its owner, as if it did not exist.
+
+type collectionGetter interface {
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+}
+
+// ownedCollection loads a collection and hides it from everyone but its owner.
+func ownedCollection(repository collectionGetter, userId, collectionId string) (*models.Collection, *models.ResponseErr) {
+	collection, respErr := repository.GetCollection(collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	if collection == nil || collection.UserID.Hex() != userId {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Collection not found",
+		}
+	}
+
+	return collection, nil
+}
+
+type storageLocationGetter interface {
+	GetStorageLocation(locationId string) (*models.StorageLocation, *models.ResponseErr)
+}
+
+// ownedStorageLocation loads a storage location and hides it from everyone but its owner.
+func ownedStorageLocation(repository storageLocationGetter, userId, locationId string) (*models.StorageLocation, *models.ResponseErr) {
+	storage, respErr := repository.GetStorageLocation(locationId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	if storage.UserID.Hex() != userId {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Location not found",
+		}
+	}
+
+	return storage, nil
+}
+
+type wishlistEntryGetter interface {
+	GetWishlistEntry(entryId string) (*models.WishlistEntry, *models.ResponseErr)
+}
+
+// ownedWishlistEntry loads a wishlist entry and hides it from everyone but its owner.
+func ownedWishlistEntry(repository wishlistEntryGetter, userId, entryId string) (*models.WishlistEntry, *models.ResponseErr) {
+	entry, respErr := repository.GetWishlistEntry(entryId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	if entry.UserID.Hex() != userId {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Wishlist entry not found",
+		}
+	}
+
+	return entry, nil
+}
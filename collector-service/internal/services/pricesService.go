@@ -0,0 +1,871 @@
+package services
+
+import (
+	"cmp"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type PricesService struct {
+	pricesRepository PricesRepositorer
+	catalog          *catalog.Catalog
+	log              logger.Logger
+}
+
+type PricesRepositorer interface {
+	SaveCardPrices(prices []*models.CardPrice) *models.ResponseErr
+	LatestCardPrices(scryfallIds []string) (map[string]*models.CardPrice, *models.ResponseErr)
+	ListCardPrices(scryfallIds []string, from, to time.Time) (map[string][]*models.CardPrice, *models.ResponseErr)
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+	ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr)
+	ListAllocations(userId string) ([]*models.Allocation, *models.ResponseErr)
+	ListDisposals(userId string) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	ListCardChanges(filter *models.CardChangesFilter) ([]*models.CardHistoryEntry, *models.ResponseErr)
+	GetUser(userId string) (*models.User, *models.ResponseErr)
+	SaveExchangeRates(rates *catalog.ExchangeRates) *models.ResponseErr
+	LatestExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr)
+}
+
+// Limits of value histories.
+const (
+	defaultMovers  = 5
+	maxValuePoints = 366
+	// priceStaleAfter is how long a printing keeps its last known price.
+	priceStaleAfter = 30 * 24 * time.Hour
+)
+
+func NewPricesService(pricesRepository PricesRepositorer, catalog *catalog.Catalog, log logger.Logger) *PricesService {
+	return &PricesService{
+		pricesRepository: pricesRepository,
+		catalog:          catalog,
+		log:              log.With(logger.String("service", "prices")),
+	}
+}
+
+// ImportPrices stores price quotes as daily snapshots, replacing the snapshots
+// of the same printings and days. It returns how many snapshots were stored.
+func (ps PricesService) ImportPrices(quotes []*catalog.PriceQuote) (int, *models.ResponseErr) {
+	prices := make([]*models.CardPrice, 0, len(quotes))
+	for _, quote := range quotes {
+		if quote.ScryfallID == "" || quote.Prices.IsZero() {
+			continue
+		}
+		prices = append(prices, &models.CardPrice{
+			ScryfallID: quote.ScryfallID,
+			Date:       catalog.Day(quote.Date),
+			Prices:     quote.Prices,
+		})
+	}
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	if respErr := ps.pricesRepository.SaveCardPrices(prices); respErr != nil {
+		return 0, respErr
+	}
+
+	ps.log.Info("Prices imported", logger.Int("snapshots", len(prices)))
+	return len(prices), nil
+}
+
+// ImportExchangeRates stores a table of exchange rates, replacing the table of the same day.
+func (ps PricesService) ImportExchangeRates(rates *catalog.ExchangeRates) *models.ResponseErr {
+	if respErr := ps.pricesRepository.SaveExchangeRates(rates); respErr != nil {
+		return respErr
+	}
+
+	ps.log.Info("Exchange rates imported", logger.String("date", rates.Date.Format(time.DateOnly)), logger.Int("currencies", len(rates.Rates)))
+	return nil
+}
+
+// ExchangeRates returns the latest table of exchange rates.
+func (ps PricesService) ExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr) {
+	rates, respErr := ps.pricesRepository.LatestExchangeRates()
+	if respErr != nil {
+		return nil, respErr
+	}
+	if rates == nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Exchange rates not found",
+		}
+	}
+	return rates, nil
+}
+
+// CollectionValue values the cards of a collection of the user by the latest
+// snapshot of every printing, taking the finish of every card into account.
+// The currency defaults to the one the user prefers, then to US dollars; rubles
+// are converted from dollar prices at the latest exchange rates. Top limits the
+// listed cards to the most valuable ones, the totals always cover the whole collection.
+func (ps PricesService) CollectionValue(userId, collectionId, currency string, top int) (*models.CollectionValue, *models.ResponseErr) {
+	currency, respErr := priceCurrency(currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+	conv, respErr := newConverter(ps.pricesRepository, userId, currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	collection, respErr := ownedCollection(ps.pricesRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	var scryfallIds []string
+	for _, card := range collection.Cards {
+		if card.Count > 0 && !slices.Contains(scryfallIds, card.ScryfallID) {
+			scryfallIds = append(scryfallIds, card.ScryfallID)
+		}
+	}
+	latest, respErr := ps.pricesRepository.LatestCardPrices(scryfallIds)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	value := &models.CollectionValue{
+		Collection: collection,
+		Currency:   conv.currency,
+	}
+	for _, card := range collection.Cards {
+		if card.Count < 1 {
+			continue
+		}
+		snapshot, ok := latest[card.ScryfallID]
+		var unitPrice float64
+		if ok {
+			unitPrice, ok = conv.price(snapshot.Prices, ps.pricedFinish(card))
+		}
+		if !ok {
+			value.UnpricedCount += card.Count
+			value.Unpriced = append(value.Unpriced, card)
+			continue
+		}
+
+		cardValue := &models.CardValue{
+			Card:      card,
+			UnitPrice: unitPrice,
+			Value:     models.RoundPrice(unitPrice * float64(card.Count)),
+			PricedAt:  snapshot.Date,
+		}
+		value.Cards = append(value.Cards, cardValue)
+		value.Total += cardValue.Value
+		value.PricedCount += card.Count
+		if snapshot.Date.After(value.PricedAt) {
+			value.PricedAt = snapshot.Date
+		}
+	}
+	value.Total = models.RoundPrice(value.Total)
+	value.RatesDate = conv.ratesDate()
+
+	slices.SortStableFunc(value.Cards, func(a, b *models.CardValue) int {
+		return cmp.Or(
+			cmp.Compare(b.Value, a.Value),
+			cmp.Compare(a.Card.Name, b.Card.Name),
+			cmp.Compare(a.Card.ScryfallID, b.Card.ScryfallID),
+		)
+	})
+	if top > 0 && len(value.Cards) > top {
+		value.Cards = value.Cards[:top]
+	}
+
+	return value, nil
+}
+
+// CollectionValueHistory tells how the value of the cards of a collection of the
+// user changed, by day, week or month. Every point values the cards the
+// collection held then, as replayed from the card history.
+func (ps PricesService) CollectionValueHistory(userId, collectionId string, query *models.ValueHistoryQuery) (*models.ValueHistory, *models.ResponseErr) {
+	if respErr := valueHistoryQuery(query); respErr != nil {
+		return nil, respErr
+	}
+	collection, respErr := ownedCollection(ps.pricesRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	changes, respErr := ps.cardChanges([]*models.Collection{collection}, query)
+	if respErr != nil {
+		return nil, respErr
+	}
+	history, respErr := ps.valueHistory(userId, collection.Cards, changes, query)
+	if respErr != nil {
+		return nil, respErr
+	}
+	history.Collection = collection
+	return history, nil
+}
+
+// ValueHistory is CollectionValueHistory for all collections of the user except
+// those of the wishlist kind. Copies reserved for a deck are counted once, in
+// the collection they were reserved from.
+func (ps PricesService) ValueHistory(userId string, query *models.ValueHistoryQuery) (*models.ValueHistory, *models.ResponseErr) {
+	if respErr := valueHistoryQuery(query); respErr != nil {
+		return nil, respErr
+	}
+	collections, respErr := ps.ownedCollections(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	var cards []*models.Card
+	for _, collection := range collections {
+		cards = append(cards, collection.Cards...)
+	}
+	changes, respErr := ps.cardChanges(collections, query)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return ps.valueHistory(userId, cards, changes, query)
+}
+
+// cardChanges lists the history of collections from the first period of query on.
+// Collections in the trash or purged are left out, as are their changes.
+func (ps PricesService) cardChanges(collections []*models.Collection, query *models.ValueHistoryQuery) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	if len(collections) == 0 {
+		return nil, nil
+	}
+	filter := &models.CardChangesFilter{
+		UserID: collections[0].UserID,
+		Since:  periodStart(query.From, query.Interval),
+	}
+	if len(collections) == 1 {
+		filter.CollectionID = collections[0].ObjectID
+	}
+	changes, respErr := ps.pricesRepository.ListCardChanges(filter)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	held := make(map[bson.ObjectID]bool, len(collections))
+	for _, collection := range collections {
+		held[collection.ObjectID] = true
+	}
+	return slices.DeleteFunc(changes, func(e *models.CardHistoryEntry) bool { return !held[e.CollectionID] }), nil
+}
+
+// ownedCollections lists the collections of the user except those of the
+// wishlist kind, without the copies of decks reserved from other collections.
+func (ps PricesService) ownedCollections(userId string) ([]*models.Collection, *models.ResponseErr) {
+	collections, respErr := ps.pricesRepository.ListCollectionsWithCards(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	allocations, respErr := ps.pricesRepository.ListAllocations(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	reserved := make(map[string]int)
+	for _, da := range deckAllocations(collections, allocations) {
+		reserved[da.Deck.ID+"/"+da.Allocation.ScryfallID] += da.Count
+	}
+	owned := make([]*models.Collection, 0, len(collections))
+	for _, collection := range collections {
+		if collection.Kind == models.CollectionKindWishlist {
+			continue
+		}
+		cards := make([]*models.Card, 0, len(collection.Cards))
+		for _, card := range collection.Cards {
+			// The reserved copies of a printing are taken from its entries in turn.
+			c := *card
+			key := collection.ID + "/" + card.ScryfallID
+			taken := min(reserved[key], c.Count)
+			c.Count -= taken
+			reserved[key] -= taken
+			cards = append(cards, &c)
+		}
+		collection.Cards = cards
+		owned = append(owned, collection)
+	}
+
+	return owned, nil
+}
+
+// valueHistory values the cards held at the close of every period of the query
+// by the latest prices known then. The cards held are the cards held now with
+// the changes, oldest first, made after the close taken back; cards held
+// before the history was kept are taken as held all along. A price older than
+// priceStaleAfter is not used. Prices are converted at the latest exchange
+// rates for every point.
+func (ps PricesService) valueHistory(userId string, cards []*models.Card, changes []*models.CardHistoryEntry, query *models.ValueHistoryQuery) (*models.ValueHistory, *models.ResponseErr) {
+	conv, respErr := newConverter(ps.pricesRepository, userId, query.Currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	type holding struct {
+		card   *models.Card
+		finish string
+		// count is the number of copies held at the current point.
+		count  int
+		prices []*models.CardPrice
+		next   int
+		// start and price are the unit prices at the first point and now.
+		start, price     float64
+		startOK, priceOK bool
+	}
+
+	// Copies of a printing with the same finish are one holding.
+	var holdings []*holding
+	index := make(map[string]*holding)
+	var scryfallIds []string
+	holdingOf := func(card *models.Card) *holding {
+		finish := ps.pricedFinish(card)
+		if h, ok := index[card.ScryfallID+"/"+finish]; ok {
+			return h
+		}
+		owned := *card
+		owned.Count = 0
+		h := &holding{card: &owned, finish: finish}
+		index[card.ScryfallID+"/"+finish] = h
+		holdings = append(holdings, h)
+		if !slices.Contains(scryfallIds, card.ScryfallID) {
+			scryfallIds = append(scryfallIds, card.ScryfallID)
+		}
+		return h
+	}
+	for _, card := range cards {
+		if card.Count > 0 {
+			holdingOf(card).count += card.Count
+		}
+	}
+	// Take every change back, to replay them point by point.
+	changed := make([]*holding, len(changes))
+	for i, e := range changes {
+		changed[i] = holdingOf(&models.Card{ScryfallID: e.ScryfallID, Name: e.Name, Finish: e.Finish})
+		changed[i].count -= e.Delta
+	}
+	next := 0
+
+	prices, respErr := ps.pricesRepository.ListCardPrices(scryfallIds, query.From.Add(-priceStaleAfter), query.To)
+	if respErr != nil {
+		return nil, respErr
+	}
+	for _, h := range holdings {
+		h.prices = prices[h.card.ScryfallID]
+	}
+
+	history := &models.ValueHistory{
+		Currency: conv.currency,
+		Interval: query.Interval,
+		From:     query.From,
+		To:       query.To,
+	}
+	for start := periodStart(query.From, query.Interval); !start.After(query.To); start = nextPeriod(start, query.Interval) {
+		end := nextPeriod(start, query.Interval).AddDate(0, 0, -1)
+		if end.After(query.To) {
+			end = query.To
+		}
+
+		closing := end.AddDate(0, 0, 1)
+		for ; next < len(changes) && changes[next].CreatedAt.Before(closing); next++ {
+			changed[next].count += changes[next].Delta
+		}
+
+		point := &models.ValuePoint{Date: start}
+		for _, h := range holdings {
+			// Changes before the history was kept can make a count negative.
+			h.card.Count = max(h.count, 0)
+			for h.next < len(h.prices) && !h.prices[h.next].Date.After(end) {
+				h.next++
+			}
+			h.price, h.priceOK = 0, false
+			if h.next > 0 {
+				if latest := h.prices[h.next-1]; end.Sub(latest.Date) <= priceStaleAfter {
+					h.price, h.priceOK = conv.price(latest.Prices, h.finish)
+				}
+			}
+			if len(history.Points) == 0 {
+				h.start, h.startOK = h.price, h.priceOK
+			}
+			if h.priceOK {
+				point.Value += models.RoundPrice(h.price * float64(h.card.Count))
+				point.PricedCount += h.card.Count
+			}
+		}
+		point.Value = models.RoundPrice(point.Value)
+		if len(history.Points) > 0 {
+			previous := history.Points[len(history.Points)-1]
+			point.Change = models.RoundPrice(point.Value - previous.Value)
+			point.ChangePercent = models.PercentChange(previous.Value, point.Value)
+		}
+		history.Points = append(history.Points, point)
+	}
+
+	first, last := history.Points[0], history.Points[len(history.Points)-1]
+	history.Change = models.RoundPrice(last.Value - first.Value)
+	history.ChangePercent = models.PercentChange(first.Value, last.Value)
+
+	for _, h := range holdings {
+		if h.card.Count == 0 || !h.startOK || !h.priceOK || h.start == h.price {
+			continue
+		}
+		history.Movers = append(history.Movers, &models.CardMove{
+			Card:          h.card,
+			StartPrice:    h.start,
+			EndPrice:      h.price,
+			Change:        models.RoundPrice((h.price - h.start) * float64(h.card.Count)),
+			ChangePercent: models.PercentChange(h.start, h.price),
+		})
+	}
+	slices.SortStableFunc(history.Movers, func(a, b *models.CardMove) int {
+		return cmp.Or(
+			cmp.Compare(math.Abs(b.Change), math.Abs(a.Change)),
+			cmp.Compare(a.Card.Name, b.Card.Name),
+			cmp.Compare(a.Card.ScryfallID, b.Card.ScryfallID),
+		)
+	})
+	if len(history.Movers) > query.Movers {
+		history.Movers = history.Movers[:query.Movers]
+	}
+	history.RatesDate = conv.ratesDate()
+
+	return history, nil
+}
+
+// CollectionProfitLoss compares what the received copies of the cards of a
+// collection of the user cost with what they are worth by the latest prices,
+// and sums the gains realized on copies that left the collection. The currency
+// defaults to the one the user prefers, then to US dollars; purchases in other
+// currencies are converted at the latest exchange rates, or left out without them.
+func (ps PricesService) CollectionProfitLoss(userId, collectionId, currency string) (*models.ProfitLoss, *models.ResponseErr) {
+	currency, respErr := purchaseCurrency(currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+	conv, respErr := newConverter(ps.pricesRepository, userId, currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+	collection, respErr := ownedCollection(ps.pricesRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	disposals, respErr := ps.pricesRepository.ListDisposals(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	disposals = slices.DeleteFunc(disposals, func(e *models.CardHistoryEntry) bool { return e.CollectionID != collection.ObjectID })
+
+	report, respErr := ps.profitLoss([]*models.Collection{collection}, disposals, conv)
+	if respErr != nil {
+		return nil, respErr
+	}
+	report.CollectionProfitLoss = *report.Collections[0]
+	report.Collections = nil
+	return report, nil
+}
+
+// ProfitLoss is CollectionProfitLoss for all collections of the user except
+// those of the wishlist kind, broken down by collection. Copies reserved for a
+// deck are counted once, in the collection they were reserved from. Gains
+// realized in collections that are gone count in the totals only.
+func (ps PricesService) ProfitLoss(userId, currency string) (*models.ProfitLoss, *models.ResponseErr) {
+	currency, respErr := purchaseCurrency(currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+	conv, respErr := newConverter(ps.pricesRepository, userId, currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+	collections, respErr := ps.ownedCollections(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	disposals, respErr := ps.pricesRepository.ListDisposals(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return ps.profitLoss(collections, disposals, conv)
+}
+
+// profitLoss builds the report of the collections, with a subtotal for every
+// collection in Collections, sorted by name.
+func (ps PricesService) profitLoss(collections []*models.Collection, disposals []*models.CardHistoryEntry, conv *converter) (*models.ProfitLoss, *models.ResponseErr) {
+	var scryfallIds []string
+	for _, collection := range collections {
+		for _, card := range collection.Cards {
+			if card.Count > 0 && !slices.Contains(scryfallIds, card.ScryfallID) {
+				scryfallIds = append(scryfallIds, card.ScryfallID)
+			}
+		}
+	}
+	latest, respErr := ps.pricesRepository.LatestCardPrices(scryfallIds)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	report := &models.ProfitLoss{Currency: conv.currency}
+	subtotals := make(map[string]*models.CollectionProfitLoss, len(collections))
+	for _, collection := range collections {
+		subtotal := &models.CollectionProfitLoss{Collection: collection}
+		subtotals[collection.ObjectID.Hex()] = subtotal
+		report.Collections = append(report.Collections, subtotal)
+
+		for _, card := range collection.Cards {
+			if card.Count < 1 {
+				continue
+			}
+			if orderedCost, ok := conv.cost(card.OrderedCost()); ok {
+				subtotal.OrderedCost += orderedCost
+			}
+
+			copies, cost := card.CostBasis()
+			costBasis, ok := conv.cost(cost)
+			if !ok {
+				report.Untracked = append(report.Untracked, card)
+				continue
+			}
+			snapshot, ok := latest[card.ScryfallID]
+			var unitPrice float64
+			if ok {
+				unitPrice, ok = conv.price(snapshot.Prices, ps.pricedFinish(card))
+			}
+			if !ok {
+				report.Unpriced = append(report.Unpriced, card)
+				continue
+			}
+
+			cardReport := &models.CardProfitLoss{
+				Collection:  collection,
+				Card:        card,
+				Count:       copies,
+				UnitCost:    models.RoundPrice(costBasis / float64(copies)),
+				CostBasis:   models.RoundPrice(costBasis),
+				MarketValue: models.RoundPrice(unitPrice * float64(copies)),
+			}
+			cardReport.Gain = models.RoundPrice(cardReport.MarketValue - cardReport.CostBasis)
+			cardReport.GainPercent = models.PercentChange(cardReport.CostBasis, cardReport.MarketValue)
+			report.Cards = append(report.Cards, cardReport)
+			subtotal.CostBasis += cardReport.CostBasis
+			subtotal.MarketValue += cardReport.MarketValue
+		}
+	}
+
+	realized, respErr := ps.realizedGains(disposals, conv)
+	if respErr != nil {
+		return nil, respErr
+	}
+	report.Realized = realized
+	for _, gain := range realized {
+		if gain.Gain == nil {
+			continue
+		}
+		report.RealizedGain += *gain.Gain
+		if subtotal, ok := subtotals[gain.Entry.CollectionID.Hex()]; ok {
+			subtotal.RealizedGain += *gain.Gain
+		}
+	}
+
+	for _, subtotal := range report.Collections {
+		subtotal.CostBasis = models.RoundPrice(subtotal.CostBasis)
+		subtotal.MarketValue = models.RoundPrice(subtotal.MarketValue)
+		subtotal.UnrealizedGain = models.RoundPrice(subtotal.MarketValue - subtotal.CostBasis)
+		subtotal.UnrealizedGainPercent = models.PercentChange(subtotal.CostBasis, subtotal.MarketValue)
+		subtotal.RealizedGain = models.RoundPrice(subtotal.RealizedGain)
+		subtotal.OrderedCost = models.RoundPrice(subtotal.OrderedCost)
+		report.CostBasis += subtotal.CostBasis
+		report.MarketValue += subtotal.MarketValue
+		report.OrderedCost += subtotal.OrderedCost
+	}
+	report.CostBasis = models.RoundPrice(report.CostBasis)
+	report.MarketValue = models.RoundPrice(report.MarketValue)
+	report.UnrealizedGain = models.RoundPrice(report.MarketValue - report.CostBasis)
+	report.UnrealizedGainPercent = models.PercentChange(report.CostBasis, report.MarketValue)
+	report.RealizedGain = models.RoundPrice(report.RealizedGain)
+	report.OrderedCost = models.RoundPrice(report.OrderedCost)
+	report.RatesDate = conv.ratesDate()
+
+	slices.SortStableFunc(report.Collections, func(a, b *models.CollectionProfitLoss) int {
+		return cmp.Or(cmp.Compare(a.Collection.Name, b.Collection.Name), cmp.Compare(a.Collection.ID, b.Collection.ID))
+	})
+	slices.SortStableFunc(report.Cards, func(a, b *models.CardProfitLoss) int {
+		return cmp.Or(
+			cmp.Compare(math.Abs(b.Gain), math.Abs(a.Gain)),
+			cmp.Compare(a.Card.Name, b.Card.Name),
+			cmp.Compare(a.Card.ScryfallID, b.Card.ScryfallID),
+		)
+	})
+
+	return report, nil
+}
+
+// realizedGains values the copies that left by the price of the day they left,
+// the newest first. A price older than priceStaleAfter is not used.
+func (ps PricesService) realizedGains(disposals []*models.CardHistoryEntry, conv *converter) ([]*models.RealizedGain, *models.ResponseErr) {
+	var scryfallIds []string
+	var from, to time.Time
+	for _, e := range disposals {
+		if _, ok := conv.cost(e.Cost); !ok {
+			continue
+		}
+		if !slices.Contains(scryfallIds, e.ScryfallID) {
+			scryfallIds = append(scryfallIds, e.ScryfallID)
+		}
+		day := catalog.Day(e.CreatedAt)
+		if from.IsZero() || day.Before(from) {
+			from = day
+		}
+		if day.After(to) {
+			to = day
+		}
+	}
+	if len(scryfallIds) == 0 {
+		return nil, nil
+	}
+	prices, respErr := ps.pricesRepository.ListCardPrices(scryfallIds, from.Add(-priceStaleAfter), to)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	var realized []*models.RealizedGain
+	for i := len(disposals) - 1; i >= 0; i-- {
+		e := disposals[i]
+		cost, ok := conv.cost(e.Cost)
+		if !ok {
+			continue
+		}
+		gain := &models.RealizedGain{Entry: e, Count: e.CostCopies, CostBasis: models.RoundPrice(cost)}
+		realized = append(realized, gain)
+
+		day := catalog.Day(e.CreatedAt)
+		history := prices[e.ScryfallID]
+		j := slices.IndexFunc(history, func(p *models.CardPrice) bool { return p.Date.After(day) })
+		if j < 0 {
+			j = len(history)
+		}
+		if j == 0 || day.Sub(history[j-1].Date) > priceStaleAfter {
+			continue
+		}
+		unitPrice, ok := conv.price(history[j-1].Prices, ps.pricedFinish(&models.Card{ScryfallID: e.ScryfallID, Finish: e.Finish}))
+		if !ok {
+			continue
+		}
+		proceeds := models.RoundPrice(unitPrice * float64(e.CostCopies))
+		value := models.RoundPrice(proceeds - gain.CostBasis)
+		gain.Proceeds = &proceeds
+		gain.Gain = &value
+		gain.GainPercent = models.PercentChange(gain.CostBasis, proceeds)
+	}
+
+	return realized, nil
+}
+
+// valueHistoryQuery checks a value history query and fills in the defaults:
+// daily points and the last 30 days, 12 weeks or 12 months.
+func valueHistoryQuery(query *models.ValueHistoryQuery) *models.ResponseErr {
+	currency, respErr := priceCurrency(query.Currency)
+	if respErr != nil {
+		return respErr
+	}
+	query.Currency = currency
+
+	query.Interval = strings.ToLower(query.Interval)
+	if query.Interval == "" {
+		query.Interval = models.IntervalDay
+	}
+	if !slices.Contains(models.Intervals, query.Interval) {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Interval must be one of " + strings.Join(models.Intervals, ", "),
+		}
+	}
+
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	query.To = catalog.Day(query.To)
+	if query.From.IsZero() {
+		switch query.Interval {
+		case models.IntervalDay:
+			query.From = query.To.AddDate(0, 0, -29)
+		case models.IntervalWeek:
+			query.From = query.To.AddDate(0, 0, -7*11)
+		case models.IntervalMonth:
+			query.From = query.To.AddDate(0, -11, 0)
+		}
+	}
+	query.From = catalog.Day(query.From)
+
+	var message string
+	points := 0
+	for start := periodStart(query.From, query.Interval); !start.After(query.To) && points <= maxValuePoints; start = nextPeriod(start, query.Interval) {
+		points++
+	}
+	switch {
+	case query.From.After(query.To):
+		message = "From must not be after to"
+	case points > maxValuePoints:
+		message = "Too many points, use a longer interval or a shorter period"
+	case query.Movers < 0:
+		message = "Movers must not be negative"
+	}
+	if message != "" {
+		return &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: message,
+		}
+	}
+	if query.Movers == 0 {
+		query.Movers = defaultMovers
+	}
+	return nil
+}
+
+// periodStart is the first day of the period of a day: the day itself, the
+// Monday of its week or the first day of its month.
+func periodStart(day time.Time, interval string) time.Time {
+	switch interval {
+	case models.IntervalWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case models.IntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextPeriod is the first day of the period after the one starting on start.
+func nextPeriod(start time.Time, interval string) time.Time {
+	switch interval {
+	case models.IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case models.IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// priceCurrency checks the currency of a valuation; empty is left for newConverter to default.
+func priceCurrency(currency string) (string, *models.ResponseErr) {
+	currency = strings.ToLower(currency)
+	if currency == "" {
+		return "", nil
+	}
+	if !slices.Contains(models.Currencies, currency) {
+		return "", &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Currency must be one of " + strings.Join(models.Currencies, ", "),
+		}
+	}
+	return currency, nil
+}
+
+// purchaseCurrency checks the currency of a cost report; empty is left for newConverter to default.
+func purchaseCurrency(currency string) (string, *models.ResponseErr) {
+	currency = strings.ToLower(currency)
+	if currency == "" {
+		return "", nil
+	}
+	if !slices.Contains(models.PurchaseCurrencies, currency) {
+		return "", &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Currency must be one of " + strings.Join(models.PurchaseCurrencies, ", "),
+		}
+	}
+	return currency, nil
+}
+
+// currencyRepositorer is what a converter needs of a repository.
+type currencyRepositorer interface {
+	GetUser(userId string) (*models.User, *models.ResponseErr)
+	LatestExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr)
+}
+
+// converter converts amounts into the currency of a report at the latest
+// exchange rates and remembers whether it had to.
+type converter struct {
+	currency string
+	rates    *catalog.ExchangeRates
+	used     bool
+}
+
+// newConverter converts into currency, which defaults to the currency the user
+// prefers and then to US dollars.
+func newConverter(repository currencyRepositorer, userId, currency string) (*converter, *models.ResponseErr) {
+	if currency == "" {
+		user, respErr := repository.GetUser(userId)
+		if respErr != nil {
+			return nil, respErr
+		}
+		currency = cmp.Or(user.Currency, catalog.CurrencyUSD)
+	}
+	rates, respErr := repository.LatestExchangeRates()
+	if respErr != nil {
+		return nil, respErr
+	}
+	return &converter{currency: currency, rates: rates}, nil
+}
+
+// convert converts an amount in from, rounded to cents when it had to.
+func (c *converter) convert(amount float64, from string) (float64, bool) {
+	if from == c.currency {
+		return amount, true
+	}
+	converted, ok := c.rates.Convert(amount, from, c.currency)
+	if !ok {
+		return 0, false
+	}
+	c.used = true
+	return models.RoundPrice(converted), true
+}
+
+// price is the market price of a finish, taken in the currency cards are sold
+// in closest to the one of the converter.
+func (c *converter) price(prices catalog.Prices, finish string) (float64, bool) {
+	market := models.MarketCurrency(c.currency)
+	price, ok := prices.Price(market, finish)
+	if !ok {
+		return 0, false
+	}
+	return c.convert(price, market)
+}
+
+// cost sums a cost by currency. It is false when there is no cost or any of
+// its currencies can't be converted, rather than leave part of the cost out.
+func (c *converter) cost(cost map[string]float64) (float64, bool) {
+	var total float64
+	for currency, amount := range cost {
+		converted, ok := c.convert(amount, currency)
+		if !ok {
+			return 0, false
+		}
+		total += converted
+	}
+	return total, len(cost) > 0
+}
+
+// ratesDate is the day of the exchange rates, nil if no amount was converted.
+func (c *converter) ratesDate() *time.Time {
+	if !c.used {
+		return nil
+	}
+	date := c.rates.Date
+	return &date
+}
+
+// pricedFinish is the finish a card is priced by. A card without a finish is
+// nonfoil, unless the catalog knows its printing was only made in one other finish.
+func (ps PricesService) pricedFinish(card *models.Card) string {
+	if card.Finish != "" {
+		return card.Finish
+	}
+	if printing, ok := ps.catalog.Card(card.ScryfallID); ok && len(printing.Finishes) == 1 {
+		return printing.Finishes[0]
+	}
+	return catalog.FinishNonfoil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// go test github.com/ShenokZlob/collector-ouphe/collector-service/internal/services -run PricesTestSuite
+type PricesTestSuite struct {
+	suite.Suite
+	pricesService    *PricesService
+	pricesRepository *pricesRepositoryStub
+	monday           time.Time
+}
+
+func TestPricesTestSuite(t *testing.T) {
+	suite.Run(t, &PricesTestSuite{})
+}
+
+func (pts *PricesTestSuite) SetupTest() {
+	pts.monday = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	pts.pricesRepository = &pricesRepositoryStub{
+		collection: &models.Collection{ObjectID: bson.NewObjectID(), UserID: bson.NewObjectID()},
+		prices: map[string][]*models.CardPrice{
+			"ring":  {{ScryfallID: "ring", Date: pts.monday, Prices: catalog.Prices{USD: 1}}},
+			"ouphe": {{ScryfallID: "ouphe", Date: pts.monday, Prices: catalog.Prices{USD: 10}}},
+		},
+	}
+	pts.pricesService = NewPricesService(pts.pricesRepository, catalog.New(), logger.SilentLogger{})
+}
+
+// pricesRepositoryStub serves one collection with its history and prices;
+// the methods the tests don't reach are left to the nil interface.
+type pricesRepositoryStub struct {
+	PricesRepositorer
+	collection *models.Collection
+	changes    []*models.CardHistoryEntry
+	prices     map[string][]*models.CardPrice
+}
+
+func (r *pricesRepositoryStub) GetCollection(collectionId string) (*models.Collection, *models.ResponseErr) {
+	if r.collection.ObjectID.Hex() != collectionId {
+		return nil, &models.ResponseErr{Status: http.StatusNotFound, Message: "Collection not found"}
+	}
+	return r.collection, nil
+}
+
+func (r *pricesRepositoryStub) ListCardChanges(filter *models.CardChangesFilter) ([]*models.CardHistoryEntry, *models.ResponseErr) {
+	var list []*models.CardHistoryEntry
+	for _, e := range r.changes {
+		if e.CollectionID == filter.CollectionID && !e.CreatedAt.Before(filter.Since) {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+func (r *pricesRepositoryStub) ListCardPrices(scryfallIds []string, from, to time.Time) (map[string][]*models.CardPrice, *models.ResponseErr) {
+	return r.prices, nil
+}
+
+func (r *pricesRepositoryStub) LatestExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr) {
+	return &catalog.ExchangeRates{}, nil
+}
+
+// change records that delta copies of a card were added to the collection at noon of day.
+func (pts *PricesTestSuite) change(day int, scryfallId string, delta int) {
+	pts.pricesRepository.changes = append(pts.pricesRepository.changes, &models.CardHistoryEntry{
+		CollectionID: pts.pricesRepository.collection.ObjectID,
+		ScryfallID:   scryfallId,
+		Delta:        delta,
+		CreatedAt:    pts.monday.AddDate(0, 0, day).Add(12 * time.Hour),
+	})
+}
+
+func (pts *PricesTestSuite) history() *models.ValueHistory {
+	collection := pts.pricesRepository.collection
+	history, respErr := pts.pricesService.CollectionValueHistory(collection.UserID.Hex(), collection.ObjectID.Hex(), &models.ValueHistoryQuery{
+		Currency: "usd", From: pts.monday, To: pts.monday.AddDate(0, 0, 3),
+	})
+	pts.Require().Nil(respErr)
+	return history
+}
+
+func (pts *PricesTestSuite) TestValueHistoryReplaysChanges() {
+	// Two rings held all along, an ouphe bought on Tuesday and a ring sold on Wednesday.
+	pts.pricesRepository.collection.Cards = []*models.Card{
+		{ScryfallID: "ring", Name: "Sol Ring", Count: 1},
+		{ScryfallID: "ouphe", Name: "Collector Ouphe", Count: 1},
+	}
+	pts.change(1, "ouphe", 1)
+	pts.change(2, "ring", -1)
+
+	var values []float64
+	var counts []int
+	for _, point := range pts.history().Points {
+		values = append(values, point.Value)
+		counts = append(counts, point.PricedCount)
+	}
+	pts.Equal([]float64{2, 12, 11, 11}, values)
+	pts.Equal([]int{2, 3, 2, 2}, counts)
+}
+
+func (pts *PricesTestSuite) TestValueHistoryOfSoldCards() {
+	// A card sold since the first point was held then, though the collection lacks it now.
+	pts.pricesRepository.collection.Cards = []*models.Card{}
+	pts.change(2, "ouphe", -1)
+
+	history := pts.history()
+	pts.Equal(10.0, history.Points[0].Value)
+	pts.Equal(10.0, history.Points[1].Value)
+	pts.Equal(0.0, history.Points[2].Value)
+	pts.Empty(history.Movers, "cards no longer held don't move")
+}
+
+func (pts *PricesTestSuite) TestValueHistoryBeforeHistory() {
+	// Changes older than the first point are not replayed, and copies held
+	// before the history was kept are taken as held all along.
+	pts.pricesRepository.collection.Cards = []*models.Card{{ScryfallID: "ring", Name: "Sol Ring", Count: 1}}
+	pts.change(-5, "ring", 3)
+	pts.change(1, "ring", -2)
+
+	var values []float64
+	for _, point := range pts.history().Points {
+		values = append(values, point.Value)
+	}
+	pts.Equal([]float64{3, 1, 1, 1}, values)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// maxSnapshotNameLen limits the names of snapshots.
+const maxSnapshotNameLen = 100
+
+type SnapshotsService struct {
+	snapshotsRepository SnapshotsRepositorer
+	log                 logger.Logger
+}
+
+type SnapshotsRepositorer interface {
+	GetCollection(collectionId string) (*models.Collection, *models.ResponseErr)
+	CreateSnapshot(snapshot *models.CollectionSnapshot) (*models.CollectionSnapshot, *models.ResponseErr)
+	ListSnapshots(collectionId bson.ObjectID) ([]*models.CollectionSnapshot, *models.ResponseErr)
+	GetSnapshot(snapshotId string) (*models.CollectionSnapshot, *models.ResponseErr)
+	DeleteSnapshot(snapshot *models.CollectionSnapshot) *models.ResponseErr
+}
+
+func NewSnapshotsService(snapshotsRepository SnapshotsRepositorer, log logger.Logger) *SnapshotsService {
+	return &SnapshotsService{
+		snapshotsRepository: snapshotsRepository,
+		log:                 log.With(logger.String("service", "snapshots")),
+	}
+}
+
+// CreateSnapshot freezes the current cards of a collection under a name
+// that is unique within the collection.
+func (ss SnapshotsService) CreateSnapshot(userId, collectionId, name string) (*models.CollectionSnapshot, *models.ResponseErr) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxSnapshotNameLen {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Snapshot name must be 1 to 100 characters long",
+		}
+	}
+
+	collection, respErr := ownedCollection(ss.snapshotsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	summary := models.Summarize(collection)
+	snapshot := &models.CollectionSnapshot{
+		CollectionID: collection.ObjectID,
+		UserID:       collection.UserID,
+		Name:         name,
+		CardCount:    summary.CardCount,
+		UniqueCards:  summary.UniqueCards,
+		Cards:        models.SnapshotCards(collection.Cards),
+		CreatedAt:    time.Now(),
+	}
+	created, respErr := ss.snapshotsRepository.CreateSnapshot(snapshot)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	ss.log.Info("Snapshot created", logger.String("collection_id", collectionId), logger.String("snapshot_id", created.ID))
+	return created, nil
+}
+
+// ListSnapshots returns the snapshots of a collection without their cards, newest first.
+func (ss SnapshotsService) ListSnapshots(userId, collectionId string) ([]*models.CollectionSnapshot, *models.ResponseErr) {
+	collection, respErr := ownedCollection(ss.snapshotsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return ss.snapshotsRepository.ListSnapshots(collection.ObjectID)
+}
+
+// GetSnapshot returns a snapshot of a collection with its cards.
+func (ss SnapshotsService) GetSnapshot(userId, collectionId, snapshotId string) (*models.CollectionSnapshot, *models.ResponseErr) {
+	collection, respErr := ownedCollection(ss.snapshotsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return ss.collectionSnapshot(collection, snapshotId)
+}
+
+func (ss SnapshotsService) DeleteSnapshot(userId, collectionId, snapshotId string) *models.ResponseErr {
+	collection, respErr := ownedCollection(ss.snapshotsRepository, userId, collectionId)
+	if respErr != nil {
+		return respErr
+	}
+	snapshot, respErr := ss.collectionSnapshot(collection, snapshotId)
+	if respErr != nil {
+		return respErr
+	}
+
+	return ss.snapshotsRepository.DeleteSnapshot(snapshot)
+}
+
+// DiffSnapshot compares a snapshot with a later one, or with the collection
+// as it is now when toSnapshotId is empty.
+func (ss SnapshotsService) DiffSnapshot(userId, collectionId, snapshotId, toSnapshotId string) (*models.CollectionDiff, *models.ResponseErr) {
+	collection, respErr := ownedCollection(ss.snapshotsRepository, userId, collectionId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	from, respErr := ss.collectionSnapshot(collection, snapshotId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	after := models.SnapshotCards(collection.Cards)
+	if toSnapshotId != "" {
+		to, respErr := ss.collectionSnapshot(collection, toSnapshotId)
+		if respErr != nil {
+			return nil, respErr
+		}
+		after = to.Cards
+	}
+
+	return models.DiffCards(from.Cards, after), nil
+}
+
+// collectionSnapshot loads a snapshot and hides it unless it was taken of collection.
+func (ss SnapshotsService) collectionSnapshot(collection *models.Collection, snapshotId string) (*models.CollectionSnapshot, *models.ResponseErr) {
+	snapshot, respErr := ss.snapshotsRepository.GetSnapshot(snapshotId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	if snapshot.CollectionID != collection.ObjectID {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusNotFound,
+			Message: "Snapshot not found",
+		}
+	}
+
+	return snapshot, nil
+}
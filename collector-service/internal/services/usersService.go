@@ -0,0 +1,60 @@
+package services
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type UsersService struct {
+	usersRepository UsersRepositorer
+	log             logger.Logger
+}
+
+type UsersRepositorer interface {
+	GetUser(userId string) (*models.User, *models.ResponseErr)
+	SetUserCurrency(userId, currency string) (*models.User, *models.ResponseErr)
+}
+
+func NewUsersService(usersRepository UsersRepositorer, log logger.Logger) *UsersService {
+	return &UsersService{
+		usersRepository: usersRepository,
+		log:             log.With(logger.String("service", "users")),
+	}
+}
+
+// Settings returns the settings of a user with the defaults filled in.
+func (us UsersService) Settings(userId string) (*models.UserSettings, *models.ResponseErr) {
+	user, respErr := us.usersRepository.GetUser(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	return &models.UserSettings{Currency: cmp.Or(user.Currency, catalog.CurrencyUSD)}, nil
+}
+
+// UpdateSettings changes the settings of a user; fields left nil are kept.
+// An empty currency goes back to the default.
+func (us UsersService) UpdateSettings(userId string, update *models.UserSettingsUpdate) (*models.UserSettings, *models.ResponseErr) {
+	if update.Currency == nil {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Nothing to update",
+		}
+	}
+	currency, respErr := purchaseCurrency(*update.Currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	user, respErr := us.usersRepository.SetUserCurrency(userId, currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	us.log.Info("User settings updated", logger.String("user_id", userId), logger.String("currency", user.Currency))
+	return &models.UserSettings{Currency: cmp.Or(user.Currency, catalog.CurrencyUSD)}, nil
+}
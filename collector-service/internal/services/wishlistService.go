@@ -0,0 +1,277 @@
+package services
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/catalog"
+	"github.com/ShenokZlob/collector-ouphe/collector-service/internal/models"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+)
+
+type WishlistService struct {
+	wishlistRepository WishlistRepositorer
+	catalog            *catalog.Catalog
+	log                logger.Logger
+}
+
+type WishlistRepositorer interface {
+	ListCollectionsWithCards(userId string) ([]*models.Collection, *models.ResponseErr)
+	CreateWishlistEntry(entry *models.WishlistEntry) (*models.WishlistEntry, *models.ResponseErr)
+	ListWishlist(userId string) ([]*models.WishlistEntry, *models.ResponseErr)
+	GetWishlistEntry(entryId string) (*models.WishlistEntry, *models.ResponseErr)
+	UpdateWishlistEntry(update *models.WishlistEntryUpdate) (*models.WishlistEntry, *models.ResponseErr)
+	DeleteWishlistEntry(entry *models.WishlistEntry) *models.ResponseErr
+	GetUser(userId string) (*models.User, *models.ResponseErr)
+	LatestExchangeRates() (*catalog.ExchangeRates, *models.ResponseErr)
+}
+
+func NewWishlistService(wishlistRepository WishlistRepositorer, catalog *catalog.Catalog, log logger.Logger) *WishlistService {
+	return &WishlistService{
+		wishlistRepository: wishlistRepository,
+		catalog:            catalog,
+		log:                log.With(logger.String("service", "wishlist")),
+	}
+}
+
+// AddWishlistEntry puts a card on the wishlist of entry.UserID. A card wanted
+// in any printing is identified by its oracle ID; cards the catalog doesn't
+// know are matched by name, so they need one. The max price is in the currency
+// the user prefers unless the entry names one.
+func (ws WishlistService) AddWishlistEntry(entry *models.WishlistEntry) (*models.WishlistEntry, *models.ResponseErr) {
+	if entry.ScryfallID == "" && entry.OracleID == "" {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "scryfall_id or oracle_id is required",
+		}
+	}
+	if entry.Priority == 0 {
+		entry.Priority = models.DefaultWishlistPriority
+	}
+	if respErr := validateWish(entry.Quantity, entry.MaxPrice, entry.Finish, entry.Priority); respErr != nil {
+		return nil, respErr
+	}
+	currency, respErr := purchaseCurrency(entry.Currency)
+	if respErr != nil {
+		return nil, respErr
+	}
+	entry.Currency = currency
+	if entry.Currency == "" && entry.MaxPrice > 0 {
+		if entry.Currency, respErr = ws.userCurrency(entry.UserID.Hex()); respErr != nil {
+			return nil, respErr
+		}
+	}
+
+	if entry.OracleID != "" {
+		entry.AnyPrinting = true
+	}
+	if printing, ok := ws.catalog.Card(entry.ScryfallID); ok {
+		if entry.OracleID == "" {
+			entry.OracleID = printing.OracleID
+		}
+		if entry.Name == "" {
+			entry.Name = printing.Name
+		}
+	}
+	if printings := ws.catalog.Printings(entry.OracleID); len(printings) > 0 && entry.Name == "" {
+		entry.Name = printings[0].Name
+	}
+	if entry.AnyPrinting && entry.OracleID == "" && strings.TrimSpace(entry.Name) == "" {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Card is not in the catalog, name is required",
+		}
+	}
+	if entry.AnyPrinting && entry.OracleID != "" {
+		// The printing only helped to find the card.
+		entry.ScryfallID = ""
+	}
+
+	entry.SetKey()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = entry.CreatedAt
+	created, respErr := ws.wishlistRepository.CreateWishlistEntry(entry)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	ws.log.Info("Wishlist entry added", logger.String("user_id", entry.UserID.Hex()), logger.String("entry_id", created.ID))
+	return created, nil
+}
+
+// ListWishlist returns the wishlist of a user, most wanted cards first, with the
+// max prices in the currency the user prefers where the exchange rates allow.
+func (ws WishlistService) ListWishlist(userId string) ([]*models.WishlistEntry, *models.ResponseErr) {
+	entries, respErr := ws.wishlistRepository.ListWishlist(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if respErr := ws.convertMaxPrices(userId, entries); respErr != nil {
+		return nil, respErr
+	}
+	return entries, nil
+}
+
+// UpdateWishlistEntry changes what a user wants of a card; fields left nil are kept.
+func (ws WishlistService) UpdateWishlistEntry(update *models.WishlistEntryUpdate) (*models.WishlistEntry, *models.ResponseErr) {
+	if update.IsEmpty() {
+		return nil, &models.ResponseErr{
+			Status:  http.StatusBadRequest,
+			Message: "Nothing to update",
+		}
+	}
+	quantity, maxPrice, finish, priority := 1, 0.0, "", models.DefaultWishlistPriority
+	if update.Quantity != nil {
+		quantity = *update.Quantity
+	}
+	if update.MaxPrice != nil {
+		maxPrice = *update.MaxPrice
+	}
+	if update.Finish != nil {
+		finish = *update.Finish
+	}
+	if update.Priority != nil {
+		priority = *update.Priority
+	}
+	if respErr := validateWish(quantity, maxPrice, finish, priority); respErr != nil {
+		return nil, respErr
+	}
+	if update.Currency != nil {
+		currency, respErr := purchaseCurrency(*update.Currency)
+		if respErr != nil {
+			return nil, respErr
+		}
+		update.Currency = &currency
+	}
+
+	entry, respErr := ownedWishlistEntry(ws.wishlistRepository, update.UserID.Hex(), update.ID)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if update.Currency == nil && entry.Currency == "" && maxPrice > 0 {
+		currency, respErr := ws.userCurrency(update.UserID.Hex())
+		if respErr != nil {
+			return nil, respErr
+		}
+		update.Currency = &currency
+	}
+
+	return ws.wishlistRepository.UpdateWishlistEntry(update)
+}
+
+func (ws WishlistService) DeleteWishlistEntry(userId, entryId string) *models.ResponseErr {
+	entry, respErr := ownedWishlistEntry(ws.wishlistRepository, userId, entryId)
+	if respErr != nil {
+		return respErr
+	}
+
+	return ws.wishlistRepository.DeleteWishlistEntry(entry)
+}
+
+// ListMissingCards returns the wishlist entries the user doesn't own enough
+// copies of yet, counting copies across all their collections except those of
+// the wishlist kind. Every entry counts all owned copies that fit it. Finishes
+// are not compared: the finish of an entry is only a preference.
+func (ws WishlistService) ListMissingCards(userId string) ([]*models.MissingCard, *models.ResponseErr) {
+	entries, respErr := ws.wishlistRepository.ListWishlist(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	collections, respErr := ws.wishlistRepository.ListCollectionsWithCards(userId)
+	if respErr != nil {
+		return nil, respErr
+	}
+	collections = slices.DeleteFunc(collections, func(c *models.Collection) bool { return c.Kind == models.CollectionKindWishlist })
+	if respErr := ws.convertMaxPrices(userId, entries); respErr != nil {
+		return nil, respErr
+	}
+
+	missing := make([]*models.MissingCard, 0)
+	for _, entry := range entries {
+		m := &models.MissingCard{Entry: entry}
+		for _, collection := range collections {
+			for _, card := range collection.Cards {
+				if ws.satisfies(card, entry) {
+					m.Owned += card.Count
+				}
+			}
+		}
+		if m.Missing() > 0 {
+			missing = append(missing, m)
+		}
+	}
+
+	return missing, nil
+}
+
+// satisfies reports whether an owned card is a copy of the card a wishlist entry wants.
+func (ws WishlistService) satisfies(card *models.Card, entry *models.WishlistEntry) bool {
+	if !entry.AnyPrinting {
+		return card.ScryfallID == entry.ScryfallID
+	}
+	if printing, ok := ws.catalog.Card(card.ScryfallID); ok && printing.OracleID != "" && entry.OracleID != "" {
+		return printing.OracleID == entry.OracleID
+	}
+	return entry.Name != "" && strings.EqualFold(card.Name, entry.Name)
+}
+
+// convertMaxPrices converts the max prices of entries into the currency the user
+// prefers. A max price that can't be converted is left in its own currency.
+func (ws WishlistService) convertMaxPrices(userId string, entries []*models.WishlistEntry) *models.ResponseErr {
+	if !slices.ContainsFunc(entries, func(e *models.WishlistEntry) bool { return e.MaxPrice > 0 }) {
+		return nil
+	}
+	conv, respErr := newConverter(ws.wishlistRepository, userId, "")
+	if respErr != nil {
+		return respErr
+	}
+
+	for _, entry := range entries {
+		if entry.MaxPrice <= 0 {
+			continue
+		}
+		from := cmp.Or(entry.Currency, catalog.CurrencyUSD)
+		conv.used = false
+		maxPrice, ok := conv.convert(entry.MaxPrice, from)
+		if !ok {
+			entry.Currency = from
+			continue
+		}
+		entry.MaxPrice = maxPrice
+		entry.Currency = conv.currency
+		entry.RatesDate = conv.ratesDate()
+	}
+	return nil
+}
+
+// userCurrency is the currency the user prefers, US dollars if they have not chosen one.
+func (ws WishlistService) userCurrency(userId string) (string, *models.ResponseErr) {
+	user, respErr := ws.wishlistRepository.GetUser(userId)
+	if respErr != nil {
+		return "", respErr
+	}
+	return cmp.Or(user.Currency, catalog.CurrencyUSD), nil
+}
+
+func validateWish(quantity int, maxPrice float64, finish string, priority int) *models.ResponseErr {
+	var message string
+	switch {
+	case quantity < 1:
+		message = "Quantity must be positive"
+	case maxPrice < 0:
+		message = "Max price must not be negative"
+	case finish != "" && !slices.Contains(models.CardFinishes, finish):
+		message = "Finish must be nonfoil, foil or etched"
+	case priority < models.MinWishlistPriority || priority > models.MaxWishlistPriority:
+		message = "Priority must be from 1 to 5"
+	default:
+		return nil
+	}
+	return &models.ResponseErr{
+		Status:  http.StatusBadRequest,
+		Message: message,
+	}
+}
@@ -1,16 +1,57 @@
 package authctx
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 type ctxKeyJWT string
 
-const jwtTokenKey ctxKeyJWT = "jwtToken"
+const (
+	jwtTokenKey   ctxKeyJWT = "jwtToken"
+	telegramIDKey ctxKeyJWT = "telegramID"
+)
+
+// tokenHolder lets a refreshed token be seen by every later call made with the same context.
+type tokenHolder struct {
+	mu    sync.RWMutex
+	token string
+}
 
 func WithJWT(ctx context.Context, token string) context.Context {
-	return context.WithValue(ctx, jwtTokenKey, token)
+	return context.WithValue(ctx, jwtTokenKey, &tokenHolder{token: token})
 }
 
 func GetJWT(ctx context.Context) (string, bool) {
-	token, ok := ctx.Value(jwtTokenKey).(string)
-	return token, ok
+	holder, ok := ctx.Value(jwtTokenKey).(*tokenHolder)
+	if !ok {
+		return "", false
+	}
+	holder.mu.RLock()
+	defer holder.mu.RUnlock()
+	return holder.token, true
+}
+
+// SetJWT replaces the token stored by WithJWT.
+// It returns false if ctx carries no token.
+func SetJWT(ctx context.Context, token string) bool {
+	holder, ok := ctx.Value(jwtTokenKey).(*tokenHolder)
+	if !ok {
+		return false
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	holder.token = token
+	return true
+}
+
+// WithTelegramID stores the Telegram user the request is made for,
+// so an expired token can be renewed on their behalf.
+func WithTelegramID(ctx context.Context, telegramID int64) context.Context {
+	return context.WithValue(ctx, telegramIDKey, telegramID)
+}
+
+func GetTelegramID(ctx context.Context) (int64, bool) {
+	telegramID, ok := ctx.Value(telegramIDKey).(int64)
+	return telegramID, ok
 }
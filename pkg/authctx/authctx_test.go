@@ -0,0 +1,33 @@
+package authctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWT(t *testing.T) {
+	_, ok := GetJWT(context.Background())
+	require.False(t, ok)
+	require.False(t, SetJWT(context.Background(), "new"))
+
+	ctx := WithJWT(context.Background(), "old")
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	require.True(t, SetJWT(child, "new"))
+
+	token, ok := GetJWT(ctx)
+	require.True(t, ok)
+	require.Equal(t, "new", token)
+}
+
+func TestTelegramID(t *testing.T) {
+	_, ok := GetTelegramID(context.Background())
+	require.False(t, ok)
+
+	id, ok := GetTelegramID(WithTelegramID(context.Background(), 42))
+	require.True(t, ok)
+	require.Equal(t, int64(42), id)
+}
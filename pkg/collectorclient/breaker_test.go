@@ -0,0 +1,57 @@
+package collectorclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.allow())
+	b.record(true)
+	require.NoError(t, b.allow())
+	b.record(true)
+
+	// Open: fail fast until the cooldown passes.
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// Half-open: one probe, the rest still fail fast.
+	now = now.Add(time.Minute)
+	require.NoError(t, b.allow())
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// A failed probe opens the breaker again.
+	b.record(true)
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	require.NoError(t, b.allow())
+	b.record(false)
+	require.NoError(t, b.allow())
+	require.NoError(t, b.allow())
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker(0, time.Minute)
+	require.Nil(t, b)
+
+	b.record(true)
+	require.NoError(t, b.allow())
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := retryPolicy{maxAttempts: 5, baseDelay: 10 * time.Millisecond, maxDelay: 50 * time.Millisecond}
+
+	for n := 1; n <= 10; n++ {
+		d := p.backoff(n)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.LessOrEqual(t, d, 50*time.Millisecond)
+	}
+	require.LessOrEqual(t, p.backoff(1), 10*time.Millisecond)
+}
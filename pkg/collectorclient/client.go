@@ -4,23 +4,92 @@ import (
 	"context"
 
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/wishlist"
 )
 
 type CollectorClient interface {
 	CollectorClientAuth
 	CollectorClientCollections
+	CollectorClientCards
+	CollectorClientWishlist
+	CollectorClientAllocations
+	CollectorClientLocations
 }
 
 type CollectorClientAuth interface {
-	RegisterUser(reqData *auth.RegisterRequest) (*auth.RegisterResponse, error)
-	CheckUser(reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error)
+	RegisterUser(ctx context.Context, reqData *auth.RegisterRequest) (*auth.RegisterResponse, error)
+	CheckUser(ctx context.Context, reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error)
+	GetUserSettings(ctx context.Context) (*auth.UserSettings, error)
+	UpdateUserSettings(ctx context.Context, req *auth.UpdateSettingsRequest) (*auth.UserSettings, error)
 }
 
 type CollectorClientCollections interface {
 	GetUserCollections(ctx context.Context) ([]collections.Collection, error)
 	CreateCollection(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error)
 	RenameCollection(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error
+	UpdateCollection(ctx context.Context, collectionID string, req *collections.UpdateCollectionRequest) error
 	DeleteCollection(ctx context.Context, collectionID string) error
+	ListTrash(ctx context.Context) ([]collections.DeletedCollection, error)
+	RestoreCollection(ctx context.Context, collectionID string) (*collections.Collection, error)
+	GetCollection(ctx context.Context, collectionID string) (*collections.Collection, error)
 	GetUsersCollectionByName(ctx context.Context, name string) (*collections.Collection, error)
+	MergeCollections(ctx context.Context, collectionID string, req *collections.MergeCollectionRequest) (*collections.Collection, error)
+	DuplicateCollection(ctx context.Context, collectionID string, req *collections.DuplicateCollectionRequest) (*collections.Collection, error)
+	SplitCollection(ctx context.Context, collectionID string, req *collections.SplitCollectionRequest) (*collections.Collection, error)
+	CreateSnapshot(ctx context.Context, collectionID string, req *collections.CreateSnapshotRequest) (*collections.Snapshot, error)
+	ListSnapshots(ctx context.Context, collectionID string) ([]collections.Snapshot, error)
+	GetSnapshot(ctx context.Context, collectionID, snapshotID string) (*collections.Snapshot, error)
+	DeleteSnapshot(ctx context.Context, collectionID, snapshotID string) error
+	DiffSnapshot(ctx context.Context, collectionID, snapshotID, toSnapshotID string) (*collections.SnapshotDiff, error)
+	ValidateDeck(ctx context.Context, collectionID string) (*collections.DeckValidation, error)
+	CheckBuildability(ctx context.Context, req *collections.BuildabilityRequest) (*collections.DeckBuildability, error)
+	GetCollectionValue(ctx context.Context, collectionID string, query *collections.CollectionValueQuery) (*collections.CollectionValue, error)
+	GetCollectionValueHistory(ctx context.Context, collectionID string, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error)
+	GetValueHistory(ctx context.Context, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error)
+	GetCollectionProfitLoss(ctx context.Context, collectionID string, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error)
+	GetProfitLoss(ctx context.Context, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error)
+	GetExchangeRates(ctx context.Context) (*collections.ExchangeRates, error)
+}
+
+type CollectorClientCards interface {
+	ListCardsInCollection(ctx context.Context, collectionID string) ([]cards.Card, error)
+	GetCardInCollection(ctx context.Context, collectionID, scryfallID string, entry *cards.CardEntryQuery) (*cards.CardEntry, error)
+	AddCardToCollection(ctx context.Context, collectionID string, req *cards.AddCardRequest) error
+	SetCardCountInCollection(ctx context.Context, collectionID, scryfallID string, req *cards.SetCardCountRequest, entry *cards.CardEntryQuery) error
+	UpdateCardInCollection(ctx context.Context, collectionID, scryfallID string, req *cards.UpdateCardRequest, entry *cards.CardEntryQuery) error
+	DeleteCardFromCollection(ctx context.Context, collectionID, scryfallID string, entry *cards.CardEntryQuery) error
+	TransferCard(ctx context.Context, collectionID, scryfallID string, req *cards.TransferCardRequest, entry *cards.CardEntryQuery) (*cards.TransferCardResponse, error)
+	AddAcquisition(ctx context.Context, collectionID, scryfallID string, req *cards.AcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error)
+	UpdateAcquisition(ctx context.Context, collectionID, scryfallID, acquisitionID string, req *cards.UpdateAcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error)
+	DeleteAcquisition(ctx context.Context, collectionID, scryfallID, acquisitionID string, entry *cards.CardEntryQuery) error
+	BatchCardsInCollection(ctx context.Context, collectionID string, req *cards.BatchCardsRequest) (*cards.BatchCardsResponse, error)
+	ListCardHistory(ctx context.Context, collectionID string, query *cards.CardHistoryQuery) ([]cards.CardHistoryEntry, error)
+	UndoCardChange(ctx context.Context, collectionID, entryID string, req *cards.UndoChangeRequest) (*cards.UndoChangeResponse, error)
+	FindOwnedCards(ctx context.Context, query *cards.OwnedCardsQuery) (*cards.OwnedCards, error)
+}
+
+type CollectorClientWishlist interface {
+	ListWishlist(ctx context.Context) ([]wishlist.Entry, error)
+	AddWishlistEntry(ctx context.Context, req *wishlist.AddEntryRequest) (*wishlist.Entry, error)
+	UpdateWishlistEntry(ctx context.Context, entryID string, req *wishlist.UpdateEntryRequest) (*wishlist.Entry, error)
+	DeleteWishlistEntry(ctx context.Context, entryID string) error
+	ListMissingCards(ctx context.Context) ([]wishlist.MissingEntry, error)
+}
+
+type CollectorClientAllocations interface {
+	ListAllocations(ctx context.Context, collectionID string) ([]collections.Allocation, error)
+	ReserveCard(ctx context.Context, collectionID string, req *collections.ReserveCardRequest) (*collections.Allocation, error)
+	ReleaseCard(ctx context.Context, collectionID, allocationID string) error
+	ListAllocationConflicts(ctx context.Context) ([]collections.AllocationConflict, error)
+}
+
+type CollectorClientLocations interface {
+	ListLocations(ctx context.Context) ([]locations.StorageLocation, error)
+	CreateLocation(ctx context.Context, req *locations.CreateLocationRequest) (*locations.StorageLocation, error)
+	DeleteLocation(ctx context.Context, locationID string) error
+	ListLocationCards(ctx context.Context, locationID string, query *locations.LocationCardsQuery) (*locations.LocationCards, error)
+	RelocateCards(ctx context.Context, collectionID string, req *locations.RelocateCardsRequest) (*locations.RelocateCardsResponse, error)
 }
@@ -0,0 +1,88 @@
+package collectorclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors returned by CollectorClient methods.
+// Use errors.Is to check them; errors.As with *APIError gives the details.
+var (
+	ErrMissingToken       = errors.New("authorization token is missing")
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrNotModified        = errors.New("not modified")
+	ErrServer             = errors.New("collector service error")
+	ErrUnexpectedStatus   = errors.New("unexpected status code")
+)
+
+// APIError is returned when collector-service answers with an unexpected status.
+type APIError struct {
+	// Op is the client method that failed, e.g. "GetUserCollections".
+	Op         string
+	StatusCode int
+	// Message is the message sent by the server, if any.
+	Message string
+	kind    error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: %v (status %d)", e.Op, e.kind, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %v (status %d): %s", e.Op, e.kind, e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+func newAPIError(op string, statusCode int, message string) *APIError {
+	return &APIError{
+		Op:         op,
+		StatusCode: statusCode,
+		Message:    message,
+		kind:       errorKind(statusCode),
+	}
+}
+
+func errorKind(statusCode int) error {
+	switch {
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return ErrBadRequest
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusConflict:
+		return ErrConflict
+	case statusCode == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case statusCode == http.StatusNotModified:
+		return ErrNotModified
+	case statusCode >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return ErrUnexpectedStatus
+	}
+}
+
+// transportError wraps failures to reach collector-service at all.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
@@ -4,314 +4,1527 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
 	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/wishlist"
 	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
 )
 
+// maxErrorBody limits how much of an error response is read.
+const maxErrorBody = 64 << 10
+
 type HTTPCollectorClient struct {
 	URL        string
 	Log        logger.Logger
 	ClientHTTP *http.Client
+
+	timeout time.Duration
+	retry   retryPolicy
+	breaker *circuitBreaker
+	tokens  TokenSource
+	// changeSource is sent with every request, see cards.ChangeSourceHeader.
+	changeSource string
 }
 
-func NewHTTPCollectorClient(url string, log logger.Logger) *HTTPCollectorClient {
-	return &HTTPCollectorClient{
+// NewHTTPCollectorClient creates a client with a 10s per-attempt timeout,
+// 3 attempts for idempotent requests and a breaker opening after 5 failures.
+// Use options to change the defaults.
+func NewHTTPCollectorClient(url string, log logger.Logger, opts ...Option) *HTTPCollectorClient {
+	c := &HTTPCollectorClient{
 		URL:        url,
 		Log:        log,
-		ClientHTTP: http.DefaultClient,
+		ClientHTTP: &http.Client{},
+		timeout:    defaultTimeout,
+		retry: retryPolicy{
+			maxAttempts: defaultRetryAttempts,
+			baseDelay:   defaultRetryBaseDelay,
+			maxDelay:    defaultRetryMaxDelay,
+		},
+		breaker: newCircuitBreaker(defaultBreakerThreshold, defaultBreakerCooldown),
+		tokens:  ContextTokenSource{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // CheckUser checks if the user exists in the collector service
-func (c *HTTPCollectorClient) CheckUser(reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error) {
-	c.Log.Info("Checking user in collector service", logger.String("method", "HTTPCollectorClient.CheckUser"), logger.Int("telegram_id", int(reqData.TelegramID)))
+func (c *HTTPCollectorClient) CheckUser(ctx context.Context, reqData *auth.CheckUserRequest) (*auth.CheckUserResponse, error) {
+	c.Log.Info("Checking user in collector service", logger.String("method", "HTTPCollectorClient.CheckUser"), logger.Int64("telegram_id", reqData.TelegramID))
 
-	body, err := json.Marshal(reqData)
+	var respData auth.CheckUserResponse
+	err := c.do(ctx, apiRequest{
+		op:         "CheckUser",
+		idempotent: true,
+		method:     http.MethodPost,
+		path:       "/login",
+		body:       reqData,
+		status:     http.StatusOK,
+		out:        &respData,
+	})
 	if err != nil {
-		c.Log.Error("Failed to marshal request data", logger.Error(err))
 		return nil, err
 	}
 
-	resp, err := http.Post(c.URL+"/login", "application/json", bytes.NewBuffer(body))
+	return &respData, nil
+}
+
+// RegisterUser reg the user in collection service
+func (c *HTTPCollectorClient) RegisterUser(ctx context.Context, reqdata *auth.RegisterRequest) (*auth.RegisterResponse, error) {
+	c.Log.Info("Registering user in collector service", logger.String("method", "HTTPCollectorClient.RegisterUser"), logger.Int64("telegram_id", reqdata.TelegramID))
+
+	var respData auth.RegisterResponse
+	err := c.do(ctx, apiRequest{
+		op:     "RegisterUser",
+		method: http.MethodPost,
+		path:   "/register",
+		body:   reqdata,
+		status: http.StatusCreated,
+		out:    &respData,
+	})
 	if err != nil {
-		c.Log.Error("Failed to send request to collector service", logger.Error(err))
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		c.Log.Error("Failed to check user in collector service", logger.Int("status_code", resp.StatusCode))
-		return nil, fmt.Errorf("failed to check user in collector service, status code: %d", resp.StatusCode)
+	return &respData, nil
+}
+
+// GetUserSettings returns the settings of the user, such as the currency amounts are shown in.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetUserSettings(ctx context.Context) (*auth.UserSettings, error) {
+	c.Log.Info("Get user settings", logger.String("method", "HTTPCollectorClient.GetUserSettings"))
+
+	var settings auth.UserSettings
+	err := c.do(ctx, apiRequest{
+		op:         "GetUserSettings",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/user/settings",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &settings,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var respData auth.CheckUserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
-		c.Log.Error("Failed to decode response data", logger.Error(err))
+	return &settings, nil
+}
+
+// UpdateUserSettings changes the settings of the user; fields left nil are kept.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) UpdateUserSettings(ctx context.Context, req *auth.UpdateSettingsRequest) (*auth.UserSettings, error) {
+	c.Log.Info("Update user settings", logger.String("method", "HTTPCollectorClient.UpdateUserSettings"))
+
+	var settings auth.UserSettings
+	err := c.do(ctx, apiRequest{
+		op:         "UpdateUserSettings",
+		idempotent: true,
+		method:     http.MethodPatch,
+		path:       "/user/settings",
+		auth:       true,
+		body:       req,
+		status:     http.StatusOK,
+		out:        &settings,
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &respData, nil
+	return &settings, nil
 }
 
-// RegisterUser reg the user in collection service
-func (c *HTTPCollectorClient) RegisterUser(reqdata *auth.RegisterRequest) (*auth.RegisterResponse, error) {
-	c.Log.Info("Registering user in collector service", logger.String("method", "HTTPCollectorClient.RegisterUser"), logger.Int("telegram_id", int(reqdata.TelegramID)))
+// GetCollections gets list of collections for user
+// Need JWT token for this opperation
+// Authorization: Bearer TOKEN
+func (c *HTTPCollectorClient) GetUserCollections(ctx context.Context) ([]collections.Collection, error) {
+	c.Log.Info("Get user's list of collections", logger.String("method", "HTTPCollectorClient.GetUserCollections"))
 
-	body, err := json.Marshal(reqdata)
+	var list []collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:         "GetUserCollections",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/collections",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
 	if err != nil {
-		c.Log.Error("Failed to marshal request data", logger.Error(err))
 		return nil, err
 	}
 
-	resp, err := http.Post(c.URL+"/register", "application/json", bytes.NewBuffer(body))
+	return list, nil
+}
+
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) CreateCollection(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error) {
+	c.Log.Info("Create collection", logger.String("method", "HTTPCollectorClient.CreateCollection"))
+
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:     "CreateCollection",
+		method: http.MethodPost,
+		path:   "/collections",
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &collection,
+	})
 	if err != nil {
-		c.Log.Error("Failed to send request to collector service", logger.Error(err))
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		c.Log.Error("Failed to register user in collector service", logger.Int("status_code", resp.StatusCode))
-		return nil, fmt.Errorf("failed to register user in collector service, status code: %d", resp.StatusCode)
+	return &collection, nil
+}
+
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) RenameCollection(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error {
+	c.Log.Info("Rename collection", logger.String("collection_id", collectionID), logger.String("method", "HTTPCollectorClient.RenameCollection"))
+
+	return c.do(ctx, apiRequest{
+		op:         "RenameCollection",
+		idempotent: true,
+		method:     http.MethodPatch,
+		path:       "/collections/" + url.PathEscape(collectionID),
+		auth:       true,
+		body:       req,
+		status:     http.StatusNoContent,
+	})
+}
+
+// UpdateCollection changes the fields of req that are set; the rest are kept.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) UpdateCollection(ctx context.Context, collectionID string, req *collections.UpdateCollectionRequest) error {
+	c.Log.Info("Update collection", logger.String("collection_id", collectionID), logger.String("method", "HTTPCollectorClient.UpdateCollection"))
+
+	return c.do(ctx, apiRequest{
+		op:         "UpdateCollection",
+		idempotent: true,
+		method:     http.MethodPatch,
+		path:       "/collections/" + url.PathEscape(collectionID),
+		auth:       true,
+		body:       req,
+		status:     http.StatusNoContent,
+	})
+}
+
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DeleteCollection(ctx context.Context, collectionID string) error {
+	c.Log.Info("Delete collection", logger.String("collection_id", collectionID), logger.String("method", "HTTPCollectorClient.DeleteCollection"))
+
+	return c.do(ctx, apiRequest{
+		op:         "DeleteCollection",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       "/collections/" + url.PathEscape(collectionID),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// GetCollection returns a collection of the user by ID.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetCollection(ctx context.Context, collectionID string) (*collections.Collection, error) {
+	c.Log.Info("Get user's collection", logger.String("method", "HTTPCollectorClient.GetCollection"), logger.String("collection_id", collectionID))
+
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:         "GetCollection",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/collections/" + url.PathEscape(collectionID),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &collection,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var respData auth.RegisterResponse
-	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
-		c.Log.Error("Failed to decode response data", logger.Error(err))
+	return &collection, nil
+}
+
+func (c *HTTPCollectorClient) GetUsersCollectionByName(ctx context.Context, collectionName string) (*collections.Collection, error) {
+	c.Log.Info("Get user's collection by name", logger.String("method", "HTTPCollectorClient.GetUsersCollectionByName"), logger.String("collection_name", collectionName))
+
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:         "GetUsersCollectionByName",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/collections/name/" + url.PathEscape(collectionName),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &collection,
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &respData, nil
+	return &collection, nil
 }
 
-// GetCollections gets list of collections for user
+// ListTrash returns the deleted collections that can still be restored, most recently deleted first.
 // Need JWT token for this opperation
-// Authorization: Bearer TOKEN
-func (c *HTTPCollectorClient) GetUserCollections(ctx context.Context) ([]collections.Collection, error) {
-	token, ok := authctx.GetJWT(ctx)
-	if !ok || token == "" {
-		c.Log.Error("Authorization token is missing")
-		return nil, fmt.Errorf("authorization token is missing")
+func (c *HTTPCollectorClient) ListTrash(ctx context.Context) ([]collections.DeletedCollection, error) {
+	c.Log.Info("Get user's deleted collections", logger.String("method", "HTTPCollectorClient.ListTrash"))
+
+	var list []collections.DeletedCollection
+	err := c.do(ctx, apiRequest{
+		op:         "ListTrash",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/collections/trash",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	c.Log.Info("Get user's list of collections", logger.String("method", "HTTPCollectorClient.GetUserCollections"), logger.String("token_auth", token))
+	return list, nil
+}
+
+// RestoreCollection takes a deleted collection out of the trash.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) RestoreCollection(ctx context.Context, collectionID string) (*collections.Collection, error) {
+	c.Log.Info("Restore collection", logger.String("collection_id", collectionID), logger.String("method", "HTTPCollectorClient.RestoreCollection"))
 
-	request, err := http.NewRequest(http.MethodGet, c.URL+"/collections", nil)
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:     "RestoreCollection",
+		method: http.MethodPost,
+		path:   "/collections/" + url.PathEscape(collectionID) + "/restore",
+		auth:   true,
+		status: http.StatusOK,
+		out:    &collection,
+	})
 	if err != nil {
-		c.Log.Error("Failed to create request", logger.Error(err))
 		return nil, err
 	}
 
-	request.Header.Set("Authorization", "Bearer "+token)
-	c.Log.Debug("Req token", logger.String("header", request.Header.Get("Authorization")))
+	return &collection, nil
+}
 
-	resp, err := c.ClientHTTP.Do(request)
+// MergeCollections adds the cards of req.SourceCollectionID to the collection,
+// summing counts of the same card, and moves the source to the trash unless req.KeepSource is set.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) MergeCollections(ctx context.Context, collectionID string, req *collections.MergeCollectionRequest) (*collections.Collection, error) {
+	c.Log.Info("Merge collections", logger.String("method", "HTTPCollectorClient.MergeCollections"), logger.String("collection_id", collectionID), logger.String("source_collection_id", req.SourceCollectionID))
+
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:     "MergeCollections",
+		method: http.MethodPost,
+		path:   "/collections/" + url.PathEscape(collectionID) + "/merge",
+		auth:   true,
+		body:   req,
+		status: http.StatusOK,
+		out:    &collection,
+	})
 	if err != nil {
-		c.Log.Error("Failed to do a request", logger.Error(err))
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var errorRepsonse collections.ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorRepsonse); err != nil {
-			c.Log.Error("Failed to decode error response", logger.Error(err))
-			return nil, fmt.Errorf("failed to decode error response, status code: %d", resp.StatusCode)
-		}
-		c.Log.Error("Failed to get user's collections", logger.String("message", errorRepsonse.Message))
-		return nil, fmt.Errorf("failed to get user's collections, status code: %d", resp.StatusCode)
+	return &collection, nil
+}
+
+// DuplicateCollection creates a copy of the collection with all its cards.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DuplicateCollection(ctx context.Context, collectionID string, req *collections.DuplicateCollectionRequest) (*collections.Collection, error) {
+	c.Log.Info("Duplicate collection", logger.String("method", "HTTPCollectorClient.DuplicateCollection"), logger.String("collection_id", collectionID))
+
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:     "DuplicateCollection",
+		method: http.MethodPost,
+		path:   "/collections/" + url.PathEscape(collectionID) + "/duplicate",
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &collection,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// SplitCollection moves the cards matching req.Filter into a new collection.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) SplitCollection(ctx context.Context, collectionID string, req *collections.SplitCollectionRequest) (*collections.Collection, error) {
+	c.Log.Info("Split collection", logger.String("method", "HTTPCollectorClient.SplitCollection"), logger.String("collection_id", collectionID))
+
+	var collection collections.Collection
+	err := c.do(ctx, apiRequest{
+		op:     "SplitCollection",
+		method: http.MethodPost,
+		path:   "/collections/" + url.PathEscape(collectionID) + "/split",
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &collection,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var collections []collections.Collection
-	err = json.NewDecoder(resp.Body).Decode(&collections)
+	return &collection, nil
+}
+
+// CreateSnapshot saves the current cards of the collection under a name.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) CreateSnapshot(ctx context.Context, collectionID string, req *collections.CreateSnapshotRequest) (*collections.Snapshot, error) {
+	c.Log.Info("Create snapshot", logger.String("method", "HTTPCollectorClient.CreateSnapshot"), logger.String("collection_id", collectionID))
+
+	var snapshot collections.Snapshot
+	err := c.do(ctx, apiRequest{
+		op:     "CreateSnapshot",
+		method: http.MethodPost,
+		path:   snapshotsPath(collectionID),
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &snapshot,
+	})
 	if err != nil {
-		c.Log.Error("Failed to decode a body request", logger.Error(err))
 		return nil, err
 	}
 
-	return collections, nil
+	return &snapshot, nil
 }
 
+// ListSnapshots returns the snapshots of the collection without their cards, newest first.
 // Need JWT token for this opperation
-func (c *HTTPCollectorClient) CreateCollection(ctx context.Context, req *collections.CreateCollectionRequest) (*collections.Collection, error) {
-	token, ok := authctx.GetJWT(ctx)
-	if !ok || token == "" {
-		c.Log.Error("Authorization token is missing")
-		return nil, fmt.Errorf("authorization token is missing")
+func (c *HTTPCollectorClient) ListSnapshots(ctx context.Context, collectionID string) ([]collections.Snapshot, error) {
+	c.Log.Info("List snapshots", logger.String("method", "HTTPCollectorClient.ListSnapshots"), logger.String("collection_id", collectionID))
+
+	var list []collections.Snapshot
+	err := c.do(ctx, apiRequest{
+		op:         "ListSnapshots",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       snapshotsPath(collectionID),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// doesn't look good to logging this information!!!
-	c.Log.Info("Create collection", logger.String("metod", "HTTPCollectorClient.CreateCollection"), logger.String("token_auth", token))
+	return list, nil
+}
+
+// GetSnapshot returns a snapshot of the collection with its cards.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetSnapshot(ctx context.Context, collectionID, snapshotID string) (*collections.Snapshot, error) {
+	c.Log.Info("Get snapshot", logger.String("method", "HTTPCollectorClient.GetSnapshot"), logger.String("collection_id", collectionID), logger.String("snapshot_id", snapshotID))
 
-	body, err := json.Marshal(&req)
+	var snapshot collections.Snapshot
+	err := c.do(ctx, apiRequest{
+		op:         "GetSnapshot",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       snapshotPath(collectionID, snapshotID),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &snapshot,
+	})
 	if err != nil {
-		c.Log.Error("Failed to marshal data", logger.Error(err))
 		return nil, err
 	}
 
-	request, err := http.NewRequest(http.MethodPost, c.URL+"/collections", bytes.NewBuffer(body))
+	return &snapshot, nil
+}
+
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DeleteSnapshot(ctx context.Context, collectionID, snapshotID string) error {
+	c.Log.Info("Delete snapshot", logger.String("method", "HTTPCollectorClient.DeleteSnapshot"), logger.String("collection_id", collectionID), logger.String("snapshot_id", snapshotID))
+
+	return c.do(ctx, apiRequest{
+		op:         "DeleteSnapshot",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       snapshotPath(collectionID, snapshotID),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// DiffSnapshot compares a snapshot with a later one, or with the current
+// cards of the collection when toSnapshotID is empty.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DiffSnapshot(ctx context.Context, collectionID, snapshotID, toSnapshotID string) (*collections.SnapshotDiff, error) {
+	c.Log.Info("Diff snapshot", logger.String("method", "HTTPCollectorClient.DiffSnapshot"), logger.String("collection_id", collectionID), logger.String("snapshot_id", snapshotID))
+
+	path := snapshotPath(collectionID, snapshotID) + "/diff"
+	if toSnapshotID != "" {
+		path += "?" + url.Values{"to": {toSnapshotID}}.Encode()
+	}
+
+	var diff collections.SnapshotDiff
+	err := c.do(ctx, apiRequest{
+		op:         "DiffSnapshot",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       path,
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &diff,
+	})
 	if err != nil {
-		c.Log.Error("Failed to create request", logger.Error(err))
 		return nil, err
 	}
 
-	request.Header.Set("Authorization", "Bearer "+token)
+	return &diff, nil
+}
 
-	resp, err := c.ClientHTTP.Do(request)
+// ValidateDeck checks a deck collection against the rules of its format.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ValidateDeck(ctx context.Context, collectionID string) (*collections.DeckValidation, error) {
+	c.Log.Info("Validate deck", logger.String("method", "HTTPCollectorClient.ValidateDeck"), logger.String("collection_id", collectionID))
+
+	var report collections.DeckValidation
+	err := c.do(ctx, apiRequest{
+		op:         "ValidateDeck",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/collections/" + url.PathEscape(collectionID) + "/validation",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &report,
+	})
 	if err != nil {
-		c.Log.Error("Failed to send request to collector service", logger.Error(err))
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		var errorResponse collections.ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			c.Log.Error("Failed to decode error response", logger.Error(err))
-			return nil, fmt.Errorf("failed to decode error response, status code: %d", resp.StatusCode)
+	return &report, nil
+}
+
+// CheckBuildability checks a deck collection or a decklist against the cards the user owns.
+// It changes nothing, so it is retried like a GET.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) CheckBuildability(ctx context.Context, req *collections.BuildabilityRequest) (*collections.DeckBuildability, error) {
+	c.Log.Info("Check deck buildability", logger.String("method", "HTTPCollectorClient.CheckBuildability"), logger.String("collection_id", req.CollectionID))
+
+	var report collections.DeckBuildability
+	err := c.do(ctx, apiRequest{
+		op:         "CheckBuildability",
+		idempotent: true,
+		method:     http.MethodPost,
+		path:       "/decks/buildability",
+		auth:       true,
+		body:       req,
+		status:     http.StatusOK,
+		out:        &report,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// GetCollectionValue values the collection by the latest known prices,
+// in the currency the user prefers unless query.Currency says otherwise.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetCollectionValue(ctx context.Context, collectionID string, query *collections.CollectionValueQuery) (*collections.CollectionValue, error) {
+	c.Log.Info("Get collection value", logger.String("method", "HTTPCollectorClient.GetCollectionValue"), logger.String("collection_id", collectionID))
+
+	params := url.Values{}
+	if query != nil {
+		if query.Currency != "" {
+			params.Set("currency", query.Currency)
 		}
-		c.Log.Error("Failed to create collection's for user", logger.String("message", errorResponse.Message))
-		return nil, fmt.Errorf("failed to create collection's, status code: %d", resp.StatusCode)
+		if query.Top > 0 {
+			params.Set("top", strconv.Itoa(query.Top))
+		}
+	}
+	path := "/collections/" + url.PathEscape(collectionID) + "/value"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
 	}
 
-	var collection collections.Collection
-	err = json.NewDecoder(resp.Body).Decode(&collection)
+	var value collections.CollectionValue
+	err := c.do(ctx, apiRequest{
+		op:         "GetCollectionValue",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       path,
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &value,
+	})
 	if err != nil {
-		c.Log.Error("Failed to decode a responser body", logger.Error(err))
 		return nil, err
 	}
 
-	return &collection, nil
+	return &value, nil
 }
 
+// GetCollectionValueHistory returns how the value of the collection changed with
+// prices, by day unless query.Interval says otherwise.
 // Need JWT token for this opperation
-func (c *HTTPCollectorClient) RenameCollection(ctx context.Context, collectionID string, req *collections.RenameCollectionRequest) error {
-	token, ok := authctx.GetJWT(ctx)
-	if !ok || token == "" {
-		c.Log.Error("Authorization token is missing")
-		return fmt.Errorf("authorization token is missing")
+func (c *HTTPCollectorClient) GetCollectionValueHistory(ctx context.Context, collectionID string, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error) {
+	c.Log.Info("Get collection value history", logger.String("method", "HTTPCollectorClient.GetCollectionValueHistory"), logger.String("collection_id", collectionID))
+
+	return c.valueHistory(ctx, "GetCollectionValueHistory", "/collections/"+url.PathEscape(collectionID)+"/value/history", query)
+}
+
+// GetValueHistory returns how the value of all collections of the user changed with prices.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetValueHistory(ctx context.Context, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error) {
+	c.Log.Info("Get value history", logger.String("method", "HTTPCollectorClient.GetValueHistory"))
+
+	return c.valueHistory(ctx, "GetValueHistory", "/value/history", query)
+}
+
+func (c *HTTPCollectorClient) valueHistory(ctx context.Context, op, path string, query *collections.ValueHistoryQuery) (*collections.ValueHistory, error) {
+	params := url.Values{}
+	if query != nil {
+		if query.Currency != "" {
+			params.Set("currency", query.Currency)
+		}
+		if query.Interval != "" {
+			params.Set("interval", query.Interval)
+		}
+		if !query.From.IsZero() {
+			params.Set("from", query.From.Format(time.DateOnly))
+		}
+		if !query.To.IsZero() {
+			params.Set("to", query.To.Format(time.DateOnly))
+		}
+		if query.Movers > 0 {
+			params.Set("movers", strconv.Itoa(query.Movers))
+		}
+	}
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var history collections.ValueHistory
+	err := c.do(ctx, apiRequest{
+		op:         op,
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       path,
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &history,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	c.Log.Info("Rename collection", logger.String("collection_id", collectionID), logger.String("method", "HTTPCollectorClient.RenameCollection"), logger.String("token_auth", token))
+	return &history, nil
+}
 
-	body, err := json.Marshal(req)
+// GetCollectionProfitLoss compares what the cards of the collection cost with what they are worth now.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetCollectionProfitLoss(ctx context.Context, collectionID string, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error) {
+	c.Log.Info("Get collection profit and loss", logger.String("method", "HTTPCollectorClient.GetCollectionProfitLoss"), logger.String("collection_id", collectionID))
+
+	return c.profitLoss(ctx, "GetCollectionProfitLoss", "/collections/"+url.PathEscape(collectionID)+"/profit-loss", query)
+}
+
+// GetProfitLoss compares what the cards of all collections of the user cost with what they are worth now.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetProfitLoss(ctx context.Context, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error) {
+	c.Log.Info("Get profit and loss", logger.String("method", "HTTPCollectorClient.GetProfitLoss"))
+
+	return c.profitLoss(ctx, "GetProfitLoss", "/profit-loss", query)
+}
+
+// GetExchangeRates returns the latest exchange rates amounts are converted at.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetExchangeRates(ctx context.Context) (*collections.ExchangeRates, error) {
+	c.Log.Info("Get exchange rates", logger.String("method", "HTTPCollectorClient.GetExchangeRates"))
+
+	var rates collections.ExchangeRates
+	err := c.do(ctx, apiRequest{
+		op:         "GetExchangeRates",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/exchange-rates",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &rates,
+	})
 	if err != nil {
-		c.Log.Error("Failed to marshal request data")
-		return err
+		return nil, err
 	}
 
-	request, err := http.NewRequest(http.MethodPatch, c.URL+"/collections/"+collectionID, bytes.NewBuffer(body))
+	return &rates, nil
+}
+
+func (c *HTTPCollectorClient) profitLoss(ctx context.Context, op, path string, query *collections.ProfitLossQuery) (*collections.ProfitLoss, error) {
+	if query != nil && query.Currency != "" {
+		path += "?" + url.Values{"currency": {query.Currency}}.Encode()
+	}
+
+	var report collections.ProfitLoss
+	err := c.do(ctx, apiRequest{
+		op:         op,
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       path,
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &report,
+	})
 	if err != nil {
-		c.Log.Error("Failed to create request", logger.Error(err))
-		return err
+		return nil, err
 	}
 
-	request.Header.Set("Authorization", "Bearer "+token)
+	return &report, nil
+}
 
-	resp, err := c.ClientHTTP.Do(request)
+// ListCardsInCollection returns all cards of the collection.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListCardsInCollection(ctx context.Context, collectionID string) ([]cards.Card, error) {
+	c.Log.Info("List cards in collection", logger.String("method", "HTTPCollectorClient.ListCardsInCollection"), logger.String("collection_id", collectionID))
+
+	var list []cards.Card
+	err := c.do(ctx, apiRequest{
+		op:         "ListCardsInCollection",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       cardsPath(collectionID),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
 	if err != nil {
-		c.Log.Error("Failed to do a request", logger.Error(err))
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		var errorResponse collections.ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			c.Log.Error("Failed to decode error response", logger.Error(err))
-			return fmt.Errorf("failed to decode error response, status code: %d", resp.StatusCode)
-		}
-		c.Log.Error("Failed to rename collection", logger.String("message", errorResponse.Message))
-		return fmt.Errorf("failed to rename the user's collection, status code: %d", resp.StatusCode)
+	return list, nil
+}
+
+// GetCardInCollection returns a card of the collection with its other printings and catalog data.
+// entry picks the deck zone and finish of the card, nil means the nonfoil mainboard card.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) GetCardInCollection(ctx context.Context, collectionID, scryfallID string, entry *cards.CardEntryQuery) (*cards.CardEntry, error) {
+	c.Log.Info("Get card in collection", logger.String("method", "HTTPCollectorClient.GetCardInCollection"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID))
+
+	var card cards.CardEntry
+	err := c.do(ctx, apiRequest{
+		op:         "GetCardInCollection",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       entryPath(cardPath(collectionID, scryfallID), entry),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &card,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &card, nil
 }
 
+// AddCardToCollection adds copies of a card, increasing the count if it is already there.
 // Need JWT token for this opperation
-func (c *HTTPCollectorClient) DeleteCollection(ctx context.Context, collectionID string) error {
-	token, ok := authctx.GetJWT(ctx)
-	if !ok || token == "" {
-		c.Log.Error("Authorization token is missing")
-		return fmt.Errorf("authorization token is missing")
+func (c *HTTPCollectorClient) AddCardToCollection(ctx context.Context, collectionID string, req *cards.AddCardRequest) error {
+	c.Log.Info("Add card to collection", logger.String("method", "HTTPCollectorClient.AddCardToCollection"), logger.String("collection_id", collectionID), logger.String("scryfall_id", req.ScryfallID))
+
+	return c.do(ctx, apiRequest{
+		op:     "AddCardToCollection",
+		method: http.MethodPost,
+		path:   cardsPath(collectionID),
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+	})
+}
+
+// SetCardCountInCollection sets the absolute count of a card.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) SetCardCountInCollection(ctx context.Context, collectionID, scryfallID string, req *cards.SetCardCountRequest, entry *cards.CardEntryQuery) error {
+	c.Log.Info("Set card count in collection", logger.String("method", "HTTPCollectorClient.SetCardCountInCollection"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID))
+
+	return c.do(ctx, apiRequest{
+		op:         "SetCardCountInCollection",
+		idempotent: true,
+		method:     http.MethodPatch,
+		path:       entryPath(cardPath(collectionID, scryfallID), entry),
+		auth:       true,
+		body:       req,
+		status:     http.StatusNoContent,
+	})
+}
+
+// UpdateCardInCollection changes only the fields set in req.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) UpdateCardInCollection(ctx context.Context, collectionID, scryfallID string, req *cards.UpdateCardRequest, entry *cards.CardEntryQuery) error {
+	c.Log.Info("Update card in collection", logger.String("method", "HTTPCollectorClient.UpdateCardInCollection"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID))
+
+	return c.do(ctx, apiRequest{
+		op:         "UpdateCardInCollection",
+		idempotent: true,
+		method:     http.MethodPatch,
+		path:       entryPath(cardPath(collectionID, scryfallID), entry),
+		auth:       true,
+		body:       req,
+		status:     http.StatusNoContent,
+	})
+}
+
+// DeleteCardFromCollection removes a card with all its copies.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DeleteCardFromCollection(ctx context.Context, collectionID, scryfallID string, entry *cards.CardEntryQuery) error {
+	c.Log.Info("Delete card from collection", logger.String("method", "HTTPCollectorClient.DeleteCardFromCollection"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID))
+
+	return c.do(ctx, apiRequest{
+		op:         "DeleteCardFromCollection",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       entryPath(cardPath(collectionID, scryfallID), entry),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// TransferCard moves copies of a card to another collection of the user,
+// or copies them when req.Mode is cards.TransferModeCopy.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) TransferCard(ctx context.Context, collectionID, scryfallID string, req *cards.TransferCardRequest, entry *cards.CardEntryQuery) (*cards.TransferCardResponse, error) {
+	c.Log.Info("Transfer card", logger.String("method", "HTTPCollectorClient.TransferCard"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID), logger.String("to_collection_id", req.ToCollectionID))
+
+	var resp cards.TransferCardResponse
+	err := c.do(ctx, apiRequest{
+		op:     "TransferCard",
+		method: http.MethodPost,
+		path:   entryPath(cardPath(collectionID, scryfallID)+"/transfer", entry),
+		auth:   true,
+		body:   req,
+		status: http.StatusOK,
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// doesn't look good to logging this information!!!
-	c.Log.Info("Delete collection", logger.String("method", "HTTPCollectorClient.DeleteCollection"), logger.String("token_auth", token))
+	return &resp, nil
+}
+
+// AddAcquisition records a purchase of copies of a card in the collection.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) AddAcquisition(ctx context.Context, collectionID, scryfallID string, req *cards.AcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error) {
+	c.Log.Info("Add acquisition", logger.String("method", "HTTPCollectorClient.AddAcquisition"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID))
 
-	request, err := http.NewRequest(http.MethodDelete, c.URL+"/collections/"+collectionID, nil)
+	var acquisition cards.Acquisition
+	err := c.do(ctx, apiRequest{
+		op:     "AddAcquisition",
+		method: http.MethodPost,
+		path:   entryPath(cardPath(collectionID, scryfallID)+"/acquisitions", entry),
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &acquisition,
+	})
 	if err != nil {
-		c.Log.Error("Failed to prepare a request", logger.Error(err))
-		return err
+		return nil, err
 	}
 
-	request.Header.Set("Authorization", "Bearer "+token)
+	return &acquisition, nil
+}
 
-	resp, err := c.ClientHTTP.Do(request)
+// UpdateAcquisition changes the given fields of an acquisition of a card.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) UpdateAcquisition(ctx context.Context, collectionID, scryfallID, acquisitionID string, req *cards.UpdateAcquisitionRequest, entry *cards.CardEntryQuery) (*cards.Acquisition, error) {
+	c.Log.Info("Update acquisition", logger.String("method", "HTTPCollectorClient.UpdateAcquisition"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID), logger.String("acquisition_id", acquisitionID))
+
+	var acquisition cards.Acquisition
+	err := c.do(ctx, apiRequest{
+		op:     "UpdateAcquisition",
+		method: http.MethodPatch,
+		path:   entryPath(cardPath(collectionID, scryfallID)+"/acquisitions/"+url.PathEscape(acquisitionID), entry),
+		auth:   true,
+		body:   req,
+		status: http.StatusOK,
+		out:    &acquisition,
+	})
 	if err != nil {
-		c.Log.Error("Failed to do a request", logger.Error(err))
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		var errorResponse collections.ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			c.Log.Error("Failed to decode error response", logger.Error(err))
-			return fmt.Errorf("failed to decode error response, status code: %d", resp.StatusCode)
+	return &acquisition, nil
+}
+
+// DeleteAcquisition removes an acquisition of a card.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DeleteAcquisition(ctx context.Context, collectionID, scryfallID, acquisitionID string, entry *cards.CardEntryQuery) error {
+	c.Log.Info("Delete acquisition", logger.String("method", "HTTPCollectorClient.DeleteAcquisition"), logger.String("collection_id", collectionID), logger.String("scryfall_id", scryfallID), logger.String("acquisition_id", acquisitionID))
+
+	return c.do(ctx, apiRequest{
+		op:         "DeleteAcquisition",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       entryPath(cardPath(collectionID, scryfallID)+"/acquisitions/"+url.PathEscape(acquisitionID), entry),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// BatchCardsInCollection runs add, set and remove operations on the cards of a
+// collection in one request. In the atomic mode nothing is saved if any operation
+// fails; check Committed and the per-operation results.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) BatchCardsInCollection(ctx context.Context, collectionID string, req *cards.BatchCardsRequest) (*cards.BatchCardsResponse, error) {
+	c.Log.Info("Batch cards in collection", logger.String("method", "HTTPCollectorClient.BatchCardsInCollection"), logger.String("collection_id", collectionID), logger.Int("operations", len(req.Operations)))
+
+	var resp cards.BatchCardsResponse
+	err := c.do(ctx, apiRequest{
+		op:     "BatchCardsInCollection",
+		method: http.MethodPost,
+		path:   cardsPath(collectionID) + ":batch",
+		auth:   true,
+		body:   req,
+		status: http.StatusOK,
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListCardHistory returns the history of card changes of a collection, newest first.
+// Pass the ID of the last entry as query.Before to get the next page.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListCardHistory(ctx context.Context, collectionID string, query *cards.CardHistoryQuery) ([]cards.CardHistoryEntry, error) {
+	c.Log.Info("List card history", logger.String("method", "HTTPCollectorClient.ListCardHistory"), logger.String("collection_id", collectionID))
+
+	params := url.Values{}
+	if query != nil {
+		if query.ScryfallID != "" {
+			params.Set("scryfall_id", query.ScryfallID)
+		}
+		if query.Before != "" {
+			params.Set("before", query.Before)
+		}
+		if query.Limit > 0 {
+			params.Set("limit", strconv.Itoa(query.Limit))
 		}
-		c.Log.Error("Failed to delete the user's collection", logger.String("message", errorResponse.Message))
-		return fmt.Errorf("failed to delete the user's collection, status code: %d", resp.StatusCode)
+	}
+	path := historyPath(collectionID)
+	if len(params) > 0 {
+		path += "?" + params.Encode()
 	}
 
-	return nil
+	var list []cards.CardHistoryEntry
+	err := c.do(ctx, apiRequest{
+		op:         "ListCardHistory",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       path,
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
 }
 
-func (c *HTTPCollectorClient) GetUsersCollectionByName(ctx context.Context, collectionName string) (*collections.Collection, error) {
-	token, ok := authctx.GetJWT(ctx)
-	if !ok || token == "" {
-		c.Log.Error("Authorization token is missing")
-		return nil, fmt.Errorf("authorization token is missing")
+// FindOwnedCards looks a card up in all collections of the user except
+// wishlists. Set exactly one of query.ScryfallID, query.OracleID or query.Name.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) FindOwnedCards(ctx context.Context, query *cards.OwnedCardsQuery) (*cards.OwnedCards, error) {
+	c.Log.Info("Find owned cards", logger.String("method", "HTTPCollectorClient.FindOwnedCards"))
+
+	params := url.Values{}
+	if query.ScryfallID != "" {
+		params.Set("scryfall_id", query.ScryfallID)
+	}
+	if query.OracleID != "" {
+		params.Set("oracle_id", query.OracleID)
+	}
+	if query.Name != "" {
+		params.Set("name", query.Name)
+	}
+
+	var resp cards.OwnedCards
+	err := c.do(ctx, apiRequest{
+		op:         "FindOwnedCards",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/cards/owned?" + params.Encode(),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UndoCardChange reverts a change from the history of a collection, or every
+// change of the same request when req.Batch is set.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) UndoCardChange(ctx context.Context, collectionID, entryID string, req *cards.UndoChangeRequest) (*cards.UndoChangeResponse, error) {
+	c.Log.Info("Undo card change", logger.String("method", "HTTPCollectorClient.UndoCardChange"), logger.String("collection_id", collectionID), logger.String("entry_id", entryID))
+
+	var resp cards.UndoChangeResponse
+	err := c.do(ctx, apiRequest{
+		op:     "UndoCardChange",
+		method: http.MethodPost,
+		path:   historyPath(collectionID) + "/" + url.PathEscape(entryID) + "/undo",
+		auth:   true,
+		body:   req,
+		status: http.StatusOK,
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	c.Log.Info("Get user's collection by name", logger.String("method", "HTTPCollectorClient.GetUsersCollectionByName"), logger.String("token_auth", token), logger.String("collection_name", collectionName))
+	return &resp, nil
+}
+
+// ListWishlist returns the wishlist of the user, most wanted cards first.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListWishlist(ctx context.Context) ([]wishlist.Entry, error) {
+	c.Log.Info("List wishlist", logger.String("method", "HTTPCollectorClient.ListWishlist"))
 
-	request, err := http.NewRequest(http.MethodGet, c.URL+"/collections/name/"+collectionName, nil)
+	var list []wishlist.Entry
+	err := c.do(ctx, apiRequest{
+		op:         "ListWishlist",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/wishlist",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
 	if err != nil {
-		c.Log.Error("Failed to create request", logger.Error(err))
 		return nil, err
 	}
 
-	request.Header.Set("Authorization", "Bearer "+token)
+	return list, nil
+}
 
-	resp, err := c.ClientHTTP.Do(request)
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) AddWishlistEntry(ctx context.Context, req *wishlist.AddEntryRequest) (*wishlist.Entry, error) {
+	c.Log.Info("Add wishlist entry", logger.String("method", "HTTPCollectorClient.AddWishlistEntry"))
+
+	var entry wishlist.Entry
+	err := c.do(ctx, apiRequest{
+		op:     "AddWishlistEntry",
+		method: http.MethodPost,
+		path:   "/wishlist",
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &entry,
+	})
 	if err != nil {
-		c.Log.Error("Failed to do a request", logger.Error(err))
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse collections.ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			c.Log.Error("Failed to decode error response", logger.Error(err))
-			return nil, fmt.Errorf("failed to decode error response, status code: %d", resp.StatusCode)
+	return &entry, nil
+}
+
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) UpdateWishlistEntry(ctx context.Context, entryID string, req *wishlist.UpdateEntryRequest) (*wishlist.Entry, error) {
+	c.Log.Info("Update wishlist entry", logger.String("method", "HTTPCollectorClient.UpdateWishlistEntry"), logger.String("entry_id", entryID))
+
+	var entry wishlist.Entry
+	err := c.do(ctx, apiRequest{
+		op:         "UpdateWishlistEntry",
+		idempotent: true,
+		method:     http.MethodPatch,
+		path:       wishlistEntryPath(entryID),
+		auth:       true,
+		body:       req,
+		status:     http.StatusOK,
+		out:        &entry,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DeleteWishlistEntry(ctx context.Context, entryID string) error {
+	c.Log.Info("Delete wishlist entry", logger.String("method", "HTTPCollectorClient.DeleteWishlistEntry"), logger.String("entry_id", entryID))
+
+	return c.do(ctx, apiRequest{
+		op:         "DeleteWishlistEntry",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       wishlistEntryPath(entryID),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// ListMissingCards returns the wishlist cards the user doesn't own enough copies of.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListMissingCards(ctx context.Context) ([]wishlist.MissingEntry, error) {
+	c.Log.Info("List missing cards", logger.String("method", "HTTPCollectorClient.ListMissingCards"))
+
+	var list []wishlist.MissingEntry
+	err := c.do(ctx, apiRequest{
+		op:         "ListMissingCards",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/wishlist/missing",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ListAllocations returns the card reservations of a deck.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListAllocations(ctx context.Context, collectionID string) ([]collections.Allocation, error) {
+	c.Log.Info("List allocations", logger.String("method", "HTTPCollectorClient.ListAllocations"), logger.String("collection_id", collectionID))
+
+	var list []collections.Allocation
+	err := c.do(ctx, apiRequest{
+		op:         "ListAllocations",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       allocationsPath(collectionID),
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ReserveCard reserves copies of a card in a source collection for a deck.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ReserveCard(ctx context.Context, collectionID string, req *collections.ReserveCardRequest) (*collections.Allocation, error) {
+	c.Log.Info("Reserve card", logger.String("method", "HTTPCollectorClient.ReserveCard"), logger.String("collection_id", collectionID), logger.String("source_collection_id", req.SourceCollectionID))
+
+	var allocation collections.Allocation
+	err := c.do(ctx, apiRequest{
+		op:     "ReserveCard",
+		method: http.MethodPost,
+		path:   allocationsPath(collectionID),
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &allocation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &allocation, nil
+}
+
+// ReleaseCard deletes a card reservation of a deck.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ReleaseCard(ctx context.Context, collectionID, allocationID string) error {
+	c.Log.Info("Release card", logger.String("method", "HTTPCollectorClient.ReleaseCard"), logger.String("collection_id", collectionID), logger.String("allocation_id", allocationID))
+
+	return c.do(ctx, apiRequest{
+		op:         "ReleaseCard",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       allocationsPath(collectionID) + "/" + url.PathEscape(allocationID),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// ListAllocationConflicts returns the cards reserved for more copies than the user owns.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListAllocationConflicts(ctx context.Context) ([]collections.AllocationConflict, error) {
+	c.Log.Info("List allocation conflicts", logger.String("method", "HTTPCollectorClient.ListAllocationConflicts"))
+
+	var list []collections.AllocationConflict
+	err := c.do(ctx, apiRequest{
+		op:         "ListAllocationConflicts",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/allocations/conflicts",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ListLocations returns the boxes and binders of the user by name.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListLocations(ctx context.Context) ([]locations.StorageLocation, error) {
+	c.Log.Info("List locations", logger.String("method", "HTTPCollectorClient.ListLocations"))
+
+	var list []locations.StorageLocation
+	err := c.do(ctx, apiRequest{
+		op:         "ListLocations",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       "/locations",
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &list,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// CreateLocation creates a box or a binder; location names are unique per user.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) CreateLocation(ctx context.Context, req *locations.CreateLocationRequest) (*locations.StorageLocation, error) {
+	c.Log.Info("Create location", logger.String("method", "HTTPCollectorClient.CreateLocation"), logger.String("name", req.Name))
+
+	var location locations.StorageLocation
+	err := c.do(ctx, apiRequest{
+		op:     "CreateLocation",
+		method: http.MethodPost,
+		path:   "/locations",
+		auth:   true,
+		body:   req,
+		status: http.StatusCreated,
+		out:    &location,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// DeleteLocation deletes a box or a binder no collection or card lies in.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) DeleteLocation(ctx context.Context, locationID string) error {
+	c.Log.Info("Delete location", logger.String("method", "HTTPCollectorClient.DeleteLocation"), logger.String("location_id", locationID))
+
+	return c.do(ctx, apiRequest{
+		op:         "DeleteLocation",
+		idempotent: true,
+		method:     http.MethodDelete,
+		path:       locationPath(locationID),
+		auth:       true,
+		status:     http.StatusNoContent,
+	})
+}
+
+// ListLocationCards returns the cards lying in a box or a binder, optionally
+// in one section or on one page only.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) ListLocationCards(ctx context.Context, locationID string, query *locations.LocationCardsQuery) (*locations.LocationCards, error) {
+	c.Log.Info("List location cards", logger.String("method", "HTTPCollectorClient.ListLocationCards"), logger.String("location_id", locationID))
+
+	params := url.Values{}
+	if query != nil {
+		if query.Section != "" {
+			params.Set("section", query.Section)
 		}
-		c.Log.Error("Failed to get user's collection by name", logger.String("message", errorResponse.Message))
-		return nil, fmt.Errorf("failed to get user's collection by name, status code: %d", resp.StatusCode)
+		if query.Page > 0 {
+			params.Set("page", strconv.Itoa(query.Page))
+		}
+	}
+	path := locationPath(locationID) + "/cards"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
 	}
 
-	var collection collections.Collection
-	err = json.NewDecoder(resp.Body).Decode(&collection)
+	var resp locations.LocationCards
+	err := c.do(ctx, apiRequest{
+		op:         "ListLocationCards",
+		idempotent: true,
+		method:     http.MethodGet,
+		path:       path,
+		auth:       true,
+		status:     http.StatusOK,
+		out:        &resp,
+	})
 	if err != nil {
-		c.Log.Error("Failed to decode a body request", logger.Error(err))
 		return nil, err
 	}
 
-	return &collection, nil
+	return &resp, nil
+}
+
+// RelocateCards puts cards of a collection, all of them without req.ScryfallIDs,
+// into req.Location. Without a location the cards lie where the collection does.
+// Need JWT token for this opperation
+func (c *HTTPCollectorClient) RelocateCards(ctx context.Context, collectionID string, req *locations.RelocateCardsRequest) (*locations.RelocateCardsResponse, error) {
+	c.Log.Info("Relocate cards", logger.String("method", "HTTPCollectorClient.RelocateCards"), logger.String("collection_id", collectionID), logger.Int("cards", len(req.ScryfallIDs)))
+
+	var resp locations.RelocateCardsResponse
+	err := c.do(ctx, apiRequest{
+		op:     "RelocateCards",
+		method: http.MethodPost,
+		path:   "/collections/" + url.PathEscape(collectionID) + "/relocate",
+		auth:   true,
+		body:   req,
+		status: http.StatusOK,
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func locationPath(locationID string) string {
+	return "/locations/" + url.PathEscape(locationID)
+}
+
+func allocationsPath(collectionID string) string {
+	return "/collections/" + url.PathEscape(collectionID) + "/allocations"
+}
+
+func wishlistEntryPath(entryID string) string {
+	return "/wishlist/" + url.PathEscape(entryID)
+}
+
+func historyPath(collectionID string) string {
+	return "/collections/" + url.PathEscape(collectionID) + "/history"
+}
+
+func snapshotsPath(collectionID string) string {
+	return "/collections/" + url.PathEscape(collectionID) + "/snapshots"
+}
+
+func snapshotPath(collectionID, snapshotID string) string {
+	return snapshotsPath(collectionID) + "/" + url.PathEscape(snapshotID)
+}
+
+func cardsPath(collectionID string) string {
+	return "/collections/" + url.PathEscape(collectionID) + "/cards"
+}
+
+func cardPath(collectionID, scryfallID string) string {
+	return cardsPath(collectionID) + "/" + url.PathEscape(scryfallID)
+}
+
+// entryPath points path at one entry of a card in a collection.
+// A nil entry means the nonfoil mainboard entry.
+func entryPath(path string, entry *cards.CardEntryQuery) string {
+	if entry == nil {
+		return path
+	}
+	params := url.Values{}
+	if entry.Zone != "" {
+		params.Set("zone", entry.Zone)
+	}
+	if entry.Finish != "" {
+		params.Set("finish", entry.Finish)
+	}
+	if len(params) == 0 {
+		return path
+	}
+	return path + "?" + params.Encode()
+}
+
+// apiRequest describes one call to collector-service.
+type apiRequest struct {
+	op     string
+	method string
+	path   string
+	// auth attaches the JWT of the token source.
+	auth bool
+	// body is encoded as JSON when not nil.
+	body any
+	// idempotent requests are retried according to the retry policy.
+	idempotent bool
+	// status is the only status code treated as success.
+	status int
+	// out receives the decoded response body when not nil.
+	out any
+}
+
+// do sends req and decodes the response into req.out.
+// A request rejected with 401 is repeated once with a refreshed token.
+func (c *HTTPCollectorClient) do(ctx context.Context, req apiRequest) error {
+	log := c.Log.With(logger.String("op", req.op))
+
+	var payload []byte
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			log.Error("Failed to marshal request data", logger.Error(err))
+			return err
+		}
+		payload = data
+	}
+
+	var token string
+	if req.auth {
+		var err error
+		token, err = c.tokens.Token(ctx)
+		if err != nil {
+			log.Error("Authorization token is missing", logger.Error(err))
+			return err
+		}
+	}
+
+	err := c.send(ctx, req, payload, token, log)
+	if !req.auth || !errors.Is(err, ErrUnauthorized) {
+		return err
+	}
+
+	log.Info("Token rejected by collector service, refreshing")
+	fresh, refreshErr := c.tokens.Refresh(ctx)
+	if refreshErr != nil {
+		if !errors.Is(refreshErr, ErrRefreshUnsupported) {
+			log.Error("Failed to refresh token", logger.Error(refreshErr))
+		}
+		return err
+	}
+	authctx.SetJWT(ctx, fresh)
+
+	return c.send(ctx, req, payload, fresh, log)
+}
+
+// send makes up to the allowed number of attempts of req.
+func (c *HTTPCollectorClient) send(ctx context.Context, req apiRequest, payload []byte, token string, log logger.Logger) error {
+	attempts := 1
+	if req.idempotent {
+		attempts = c.retry.maxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			delay := c.retry.backoff(attempt - 1)
+			log.Warn("Retrying request to collector service", logger.Int("attempt", attempt), logger.Duration("delay", delay), logger.Error(err))
+			if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+				return err
+			}
+		}
+
+		if breakerErr := c.breaker.allow(); breakerErr != nil {
+			log.Warn("Collector service circuit breaker is open")
+			return breakerErr
+		}
+
+		err = c.attempt(ctx, req, payload, token)
+		if err != nil && ctx.Err() != nil {
+			// Cancelled by the caller: says nothing about the service.
+			c.breaker.abort()
+			break
+		}
+		c.breaker.record(serviceFailure(err))
+
+		if err == nil || !retryable(err) {
+			break
+		}
+	}
+
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			log.Error("Collector service returned an error", logger.Int("status_code", apiErr.StatusCode), logger.String("message", apiErr.Message))
+		} else {
+			log.Error("Failed to do a request to collector service", logger.Error(err))
+		}
+	}
+	return err
+}
+
+// attempt sends req once, bounded by the per-attempt timeout.
+func (c *HTTPCollectorClient) attempt(ctx context.Context, req apiRequest, payload []byte, token string) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, req.method, c.URL+req.path, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.changeSource != "" {
+		request.Header.Set(cards.ChangeSourceHeader, c.changeSource)
+	}
+	for name, value := range preconditions(ctx) {
+		request.Header.Set(name, value)
+	}
+
+	resp, err := c.ClientHTTP.Do(request)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != req.status {
+		return newAPIError(req.op, resp.StatusCode, readErrorMessage(resp.Body))
+	}
+
+	if req.out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(req.out); err != nil {
+		return fmt.Errorf("%s: decode response: %w", req.op, err)
+	}
+
+	return nil
+}
+
+// readErrorMessage extracts the message of collections.ErrorResponse.
+// Bodies that are not JSON are returned as plain text.
+func readErrorMessage(r io.Reader) string {
+	data, err := io.ReadAll(io.LimitReader(r, maxErrorBody))
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+
+	var errorResponse collections.ErrorResponse
+	if err := json.Unmarshal(data, &errorResponse); err == nil {
+		return errorResponse.Message
+	}
+	return strings.TrimSpace(string(data))
 }
@@ -0,0 +1,902 @@
+package collectorclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/auth"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/cards"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/collections"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/wishlist"
+	"github.com/ShenokZlob/collector-ouphe/pkg/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+const testToken = "test-token"
+
+// go test github.com/ShenokZlob/collector-ouphe/pkg/collectorclient -run HTTPClientTestSuite
+type HTTPClientTestSuite struct {
+	suite.Suite
+	mux    *http.ServeMux
+	server *httptest.Server
+	client *HTTPCollectorClient
+	ctx    context.Context
+}
+
+func TestHTTPClientTestSuite(t *testing.T) {
+	suite.Run(t, &HTTPClientTestSuite{})
+}
+
+func (s *HTTPClientTestSuite) SetupTest() {
+	s.mux = http.NewServeMux()
+	s.server = httptest.NewServer(s.mux)
+	s.client = NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+	s.ctx = authctx.WithJWT(context.Background(), testToken)
+}
+
+func (s *HTTPClientTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// handle registers a handler that checks the bearer token and decodes the body into in.
+func (s *HTTPClientTestSuite) handle(pattern string, in any, status int, out any) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("Bearer "+testToken, r.Header.Get("Authorization"))
+		if in != nil {
+			s.Require().NoError(json.NewDecoder(r.Body).Decode(in))
+		}
+		writeJSON(w, status, out)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, out any) {
+	if out == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(out)
+}
+
+func (s *HTTPClientTestSuite) TestCheckUser() {
+	var got auth.CheckUserRequest
+	s.mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&got))
+		writeJSON(w, http.StatusOK, auth.CheckUserResponse{Token: "jwt", Success: true})
+	})
+
+	resp, err := s.client.CheckUser(s.ctx, &auth.CheckUserRequest{TelegramID: 42})
+	s.Require().NoError(err)
+	s.Equal(int64(42), got.TelegramID)
+	s.Equal("jwt", resp.Token)
+	s.True(resp.Success)
+}
+
+func (s *HTTPClientTestSuite) TestCheckUserNotFound() {
+	s.mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, collections.ErrorResponse{Message: "User not found"})
+	})
+
+	_, err := s.client.CheckUser(s.ctx, &auth.CheckUserRequest{TelegramID: 42})
+	s.ErrorIs(err, ErrNotFound)
+
+	var apiErr *APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal("CheckUser", apiErr.Op)
+	s.Equal(http.StatusNotFound, apiErr.StatusCode)
+	s.Equal("User not found", apiErr.Message)
+}
+
+func (s *HTTPClientTestSuite) TestRegisterUser() {
+	var got auth.RegisterRequest
+	s.mux.HandleFunc("POST /register", func(w http.ResponseWriter, r *http.Request) {
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&got))
+		writeJSON(w, http.StatusCreated, auth.RegisterResponse{Token: "jwt"})
+	})
+
+	resp, err := s.client.RegisterUser(s.ctx, &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.Require().NoError(err)
+	s.Equal("Ivan", got.FirstName)
+	s.Equal("jwt", resp.Token)
+}
+
+func (s *HTTPClientTestSuite) TestRegisterUserConflict() {
+	s.mux.HandleFunc("POST /register", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusConflict, map[string]any{"Message": "User with this Telegram ID already exists", "Status": 409})
+	})
+
+	_, err := s.client.RegisterUser(s.ctx, &auth.RegisterRequest{TelegramID: 42, FirstName: "Ivan"})
+	s.ErrorIs(err, ErrConflict)
+	s.Contains(err.Error(), "already exists")
+}
+
+func (s *HTTPClientTestSuite) TestGetUserCollections() {
+	want := []collections.Collection{{ID: "1", Name: "Bulk"}, {ID: "2", Name: "Deck"}}
+	s.handle("GET /collections", nil, http.StatusOK, want)
+
+	got, err := s.client.GetUserCollections(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *HTTPClientTestSuite) TestGetUserCollectionsUnauthorized() {
+	// JWTMiddleware aborts without a body.
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := s.client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, ErrUnauthorized)
+}
+
+func (s *HTTPClientTestSuite) TestMissingToken() {
+	_, err := s.client.GetUserCollections(context.Background())
+	s.ErrorIs(err, ErrMissingToken)
+}
+
+// stubTokenSource hands out fresh tokens and counts refreshes.
+type stubTokenSource struct {
+	ContextTokenSource
+	fresh     string
+	err       error
+	refreshes int
+}
+
+func (ts *stubTokenSource) Refresh(ctx context.Context) (string, error) {
+	ts.refreshes++
+	return ts.fresh, ts.err
+}
+
+func (s *HTTPClientTestSuite) TestRefreshTokenOnUnauthorized() {
+	tokens := &stubTokenSource{fresh: "fresh-token"}
+	s.client = NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithTokenSource(tokens))
+
+	var seen []string
+	s.mux.HandleFunc("POST /collections", func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, http.StatusCreated, collections.Collection{ID: "1", Name: "Bulk"})
+	})
+
+	got, err := s.client.CreateCollection(s.ctx, &collections.CreateCollectionRequest{Name: "Bulk"})
+	s.Require().NoError(err)
+	s.Equal("1", got.ID)
+	s.Equal([]string{"Bearer " + testToken, "Bearer fresh-token"}, seen)
+	s.Equal(1, tokens.refreshes)
+
+	token, _ := authctx.GetJWT(s.ctx)
+	s.Equal("fresh-token", token)
+}
+
+func (s *HTTPClientTestSuite) TestRefreshTokenOnlyOnce() {
+	tokens := &stubTokenSource{fresh: "fresh-token"}
+	s.client = NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithTokenSource(tokens))
+
+	var calls atomic.Int32
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := s.client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, ErrUnauthorized)
+	s.Equal(int32(2), calls.Load())
+	s.Equal(1, tokens.refreshes)
+}
+
+func (s *HTTPClientTestSuite) TestRefreshTokenFailed() {
+	tokens := &stubTokenSource{err: errors.New("redis is down")}
+	s.client = NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithTokenSource(tokens))
+
+	var calls atomic.Int32
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := s.client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, ErrUnauthorized)
+	s.Equal(int32(1), calls.Load())
+}
+
+func (s *HTTPClientTestSuite) TestNoRefreshForPublicRequests() {
+	tokens := &stubTokenSource{fresh: "fresh-token"}
+	s.client = NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithTokenSource(tokens))
+
+	s.mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := s.client.CheckUser(s.ctx, &auth.CheckUserRequest{TelegramID: 42})
+	s.ErrorIs(err, ErrUnauthorized)
+	s.Zero(tokens.refreshes)
+}
+
+func (s *HTTPClientTestSuite) TestCreateCollection() {
+	var got collections.CreateCollectionRequest
+	s.handle("POST /collections", &got, http.StatusCreated, collections.Collection{ID: "1", Name: "Bulk"})
+
+	created, err := s.client.CreateCollection(s.ctx, &collections.CreateCollectionRequest{Name: "Bulk"})
+	s.Require().NoError(err)
+	s.Equal("Bulk", got.Name)
+	s.Equal("1", created.ID)
+}
+
+func (s *HTTPClientTestSuite) TestRenameCollection() {
+	var got collections.RenameCollectionRequest
+	s.handle("PATCH /collections/1", &got, http.StatusNoContent, nil)
+
+	err := s.client.RenameCollection(s.ctx, "1", &collections.RenameCollectionRequest{Name: "Trade"})
+	s.Require().NoError(err)
+	s.Equal("Trade", got.Name)
+}
+
+func (s *HTTPClientTestSuite) TestUpdateCollection() {
+	var got map[string]any
+	s.handle("PATCH /collections/1", &got, http.StatusNoContent, nil)
+
+	pinned := true
+	err := s.client.UpdateCollection(s.ctx, "1", &collections.UpdateCollectionRequest{Pinned: &pinned, Tags: &[]string{}})
+	s.Require().NoError(err)
+	s.Equal(map[string]any{"pinned": true, "tags": []any{}}, got, "only the fields that are set are sent")
+}
+
+func (s *HTTPClientTestSuite) TestDeleteCollection() {
+	s.handle("DELETE /collections/1", nil, http.StatusNoContent, nil)
+
+	s.NoError(s.client.DeleteCollection(s.ctx, "1"))
+}
+
+func (s *HTTPClientTestSuite) TestDeleteCollectionServerError() {
+	s.handle("DELETE /collections/1", nil, http.StatusInternalServerError, collections.ErrorResponse{Message: "boom"})
+
+	err := s.client.DeleteCollection(s.ctx, "1")
+	s.ErrorIs(err, ErrServer)
+}
+
+func (s *HTTPClientTestSuite) TestGetUsersCollectionByName() {
+	s.mux.HandleFunc("GET /collections/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("My deck", r.PathValue("name"))
+		writeJSON(w, http.StatusOK, collections.Collection{ID: "1", Name: "My deck"})
+	})
+
+	got, err := s.client.GetUsersCollectionByName(s.ctx, "My deck")
+	s.Require().NoError(err)
+	s.Equal("1", got.ID)
+}
+
+func (s *HTTPClientTestSuite) TestGetCollection() {
+	var ifNoneMatch []string
+	s.mux.HandleFunc("GET /collections/1", func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"3"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writeJSON(w, http.StatusOK, collections.Collection{ID: "1", Name: "Bulk", Version: 3})
+	})
+
+	got, err := s.client.GetCollection(s.ctx, "1")
+	s.Require().NoError(err)
+	s.Equal(&collections.Collection{ID: "1", Name: "Bulk", Version: 3}, got)
+
+	_, err = s.client.GetCollection(IfNoneMatch(s.ctx, got.Version), "1")
+	s.ErrorIs(err, ErrNotModified)
+	s.Equal([]string{"", `"3"`}, ifNoneMatch)
+}
+
+func (s *HTTPClientTestSuite) TestListTrash() {
+	deletedAt := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	s.handle("GET /collections/trash", nil, http.StatusOK, []collections.DeletedCollection{
+		{Collection: collections.Collection{ID: "1", Name: "Bulk", CardCount: 3, UniqueCards: 2}, DeletedAt: deletedAt},
+	})
+
+	list, err := s.client.ListTrash(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Equal("Bulk", list[0].Name)
+	s.Equal(3, list[0].CardCount)
+	s.True(deletedAt.Equal(list[0].DeletedAt))
+}
+
+func (s *HTTPClientTestSuite) TestRestoreCollection() {
+	s.handle("POST /collections/1/restore", nil, http.StatusOK, collections.Collection{ID: "1", Name: "Bulk"})
+
+	col, err := s.client.RestoreCollection(s.ctx, "1")
+	s.Require().NoError(err)
+	s.Equal(&collections.Collection{ID: "1", Name: "Bulk"}, col)
+}
+
+func (s *HTTPClientTestSuite) TestMergeCollections() {
+	var got collections.MergeCollectionRequest
+	s.handle("POST /collections/1/merge", &got, http.StatusOK, collections.Collection{ID: "1", Name: "Bulk"})
+
+	req := &collections.MergeCollectionRequest{SourceCollectionID: "2", KeepSource: true}
+	col, err := s.client.MergeCollections(s.ctx, "1", req)
+	s.Require().NoError(err)
+	s.Equal(*req, got)
+	s.Equal(&collections.Collection{ID: "1", Name: "Bulk"}, col)
+}
+
+func (s *HTTPClientTestSuite) TestDuplicateCollection() {
+	var got collections.DuplicateCollectionRequest
+	s.handle("POST /collections/1/duplicate", &got, http.StatusCreated, collections.Collection{ID: "2", Name: "Copy"})
+
+	col, err := s.client.DuplicateCollection(s.ctx, "1", &collections.DuplicateCollectionRequest{Name: "Copy"})
+	s.Require().NoError(err)
+	s.Equal("Copy", got.Name)
+	s.Equal(&collections.Collection{ID: "2", Name: "Copy"}, col)
+}
+
+func (s *HTTPClientTestSuite) TestSplitCollection() {
+	var got collections.SplitCollectionRequest
+	s.handle("POST /collections/1/split", &got, http.StatusCreated, collections.Collection{ID: "2", Name: "Duskmourn"})
+
+	req := &collections.SplitCollectionRequest{Name: "Duskmourn", Filter: collections.CardFilter{Sets: []string{"dsk"}}}
+	col, err := s.client.SplitCollection(s.ctx, "1", req)
+	s.Require().NoError(err)
+	s.Equal(*req, got)
+	s.Equal("2", col.ID)
+}
+
+func (s *HTTPClientTestSuite) TestCreateSnapshot() {
+	var got collections.CreateSnapshotRequest
+	want := collections.Snapshot{ID: "5", CollectionID: "1", Name: "Before FNM", CardCount: 2, UniqueCards: 1,
+		Cards: []collections.SnapshotCard{{ScryfallID: "abc", Count: 2}}}
+	s.handle("POST /collections/1/snapshots", &got, http.StatusCreated, want)
+
+	snapshot, err := s.client.CreateSnapshot(s.ctx, "1", &collections.CreateSnapshotRequest{Name: "Before FNM"})
+	s.Require().NoError(err)
+	s.Equal("Before FNM", got.Name)
+	s.Equal(&want, snapshot)
+}
+
+func (s *HTTPClientTestSuite) TestSnapshotsNotFound() {
+	s.handle("GET /collections/1/snapshots", nil, http.StatusNotFound, collections.ErrorResponse{Message: "Collection not found"})
+	s.handle("DELETE /collections/1/snapshots/5", nil, http.StatusNotFound, collections.ErrorResponse{Message: "Snapshot not found"})
+
+	_, err := s.client.ListSnapshots(s.ctx, "1")
+	s.ErrorIs(err, ErrNotFound)
+	s.ErrorIs(s.client.DeleteSnapshot(s.ctx, "1", "5"), ErrNotFound)
+}
+
+func (s *HTTPClientTestSuite) TestDiffSnapshot() {
+	var query url.Values
+	s.mux.HandleFunc("GET /collections/1/snapshots/5/diff", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, collections.SnapshotDiff{From: "5", To: "6",
+			Changed: []collections.CardDiff{{ScryfallID: "abc", Before: 2, After: 3, Delta: 1}}})
+	})
+
+	diff, err := s.client.DiffSnapshot(s.ctx, "1", "5", "6")
+	s.Require().NoError(err)
+	s.Equal(url.Values{"to": {"6"}}, query)
+	s.Equal([]collections.CardDiff{{ScryfallID: "abc", Before: 2, After: 3, Delta: 1}}, diff.Changed)
+
+	_, err = s.client.DiffSnapshot(s.ctx, "1", "5", "")
+	s.Require().NoError(err)
+	s.Empty(query)
+}
+
+func (s *HTTPClientTestSuite) TestValidateDeck() {
+	want := collections.DeckValidation{Format: "modern", Mainboard: 58, Problems: []collections.DeckProblem{
+		{Rule: collections.DeckRuleSize, Message: "Deck has 58 cards, modern needs at least 60"},
+	}}
+	s.handle("GET /collections/1/validation", nil, http.StatusOK, want)
+
+	got, err := s.client.ValidateDeck(s.ctx, "1")
+	s.Require().NoError(err)
+	s.Equal(want, *got)
+}
+
+func (s *HTTPClientTestSuite) TestCheckBuildability() {
+	var got collections.BuildabilityRequest
+	want := collections.DeckBuildability{AnyPrinting: true, Needed: 4, Covered: 4, Buildable: true, Currency: "usd", Cards: []collections.BuildabilityCard{
+		{Name: "Lightning Bolt", Needed: 4, Covered: 4, Sources: []collections.CardSource{{CollectionID: "2", CollectionName: "Binder", ScryfallID: "abc", Count: 4}}},
+	}}
+	s.handle("POST /decks/buildability", &got, http.StatusOK, want)
+
+	report, err := s.client.CheckBuildability(s.ctx, &collections.BuildabilityRequest{Decklist: "4 Lightning Bolt", AnyPrinting: true})
+	s.Require().NoError(err)
+	s.Equal(want, *report)
+	s.Equal(collections.BuildabilityRequest{Decklist: "4 Lightning Bolt", AnyPrinting: true}, got)
+}
+
+func (s *HTTPClientTestSuite) TestGetCollectionValue() {
+	var query url.Values
+	pricedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	want := collections.CollectionValue{CollectionID: "1", Currency: "eur", Total: 7, PricedCount: 2, UnpricedCount: 1, PricedAt: &pricedAt,
+		Cards:    []collections.CardValue{{ScryfallID: "abc", Name: "Sol Ring", Finish: "foil", Count: 2, UnitPrice: 3.5, Value: 7, PricedAt: pricedAt}},
+		Unpriced: []string{"Llanowar Elves"},
+	}
+	s.mux.HandleFunc("GET /collections/1/value", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, want)
+	})
+
+	got, err := s.client.GetCollectionValue(s.ctx, "1", &collections.CollectionValueQuery{Currency: "eur", Top: 5})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"currency": {"eur"}, "top": {"5"}}, query)
+	s.Equal(want, *got)
+
+	_, err = s.client.GetCollectionValue(s.ctx, "1", nil)
+	s.Require().NoError(err)
+	s.Empty(query)
+}
+
+func (s *HTTPClientTestSuite) TestGetValueHistory() {
+	var query url.Values
+	day := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	percent := 25.0
+	want := collections.ValueHistory{CollectionID: "1", Currency: "usd", Interval: "week", From: day, To: day.AddDate(0, 0, 7),
+		Points: []collections.ValuePoint{{Date: day, Value: 4, PricedCount: 2}, {Date: day.AddDate(0, 0, 7), Value: 5, PricedCount: 2, Change: 1, ChangePercent: &percent}},
+		Change: 1, ChangePercent: &percent,
+		Movers: []collections.CardMove{{ScryfallID: "abc", Name: "Sol Ring", Count: 2, StartPrice: 2, EndPrice: 2.5, Change: 1, ChangePercent: &percent}},
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, want)
+	}
+	s.mux.HandleFunc("GET /collections/1/value/history", handler)
+	s.mux.HandleFunc("GET /value/history", handler)
+
+	got, err := s.client.GetCollectionValueHistory(s.ctx, "1", &collections.ValueHistoryQuery{Interval: "week", From: day, To: day.AddDate(0, 0, 7), Movers: 3})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"interval": {"week"}, "from": {"2025-03-03"}, "to": {"2025-03-10"}, "movers": {"3"}}, query)
+	s.Equal(want, *got)
+
+	_, err = s.client.GetValueHistory(s.ctx, &collections.ValueHistoryQuery{Currency: "eur"})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"currency": {"eur"}}, query)
+
+	_, err = s.client.GetValueHistory(s.ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(query)
+}
+
+func (s *HTTPClientTestSuite) TestReserveCard() {
+	var got collections.ReserveCardRequest
+	want := collections.Allocation{ID: "9", CollectionID: "1", SourceCollectionID: "2", ScryfallID: "ring", Name: "Sol Ring", Count: 1}
+	s.handle("POST /collections/1/allocations", &got, http.StatusCreated, want)
+
+	allocation, err := s.client.ReserveCard(s.ctx, "1", &collections.ReserveCardRequest{ScryfallID: "ring", SourceCollectionID: "2", Count: 1})
+	s.Require().NoError(err)
+	s.Equal(want, *allocation)
+	s.Equal(collections.ReserveCardRequest{ScryfallID: "ring", SourceCollectionID: "2", Count: 1}, got)
+}
+
+func (s *HTTPClientTestSuite) TestListAllocationConflicts() {
+	want := []collections.AllocationConflict{{SourceCollectionID: "2", ScryfallID: "ring", Owned: 2, Allocated: 3, Over: 1, Decks: []collections.AllocatedDeck{
+		{CollectionID: "1", Name: "Krenko", AllocationID: "9", Count: 1},
+	}}}
+	s.handle("GET /allocations/conflicts", nil, http.StatusOK, want)
+
+	got, err := s.client.ListAllocationConflicts(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *HTTPClientTestSuite) TestCreateLocation() {
+	var got locations.CreateLocationRequest
+	s.handle("POST /locations", &got, http.StatusCreated, locations.StorageLocation{ID: "7", Name: "Red box", Kind: locations.KindBox})
+
+	location, err := s.client.CreateLocation(s.ctx, &locations.CreateLocationRequest{Name: "Red box", Kind: locations.KindBox})
+	s.Require().NoError(err)
+	s.Equal(locations.CreateLocationRequest{Name: "Red box", Kind: locations.KindBox}, got)
+	s.Equal("7", location.ID)
+}
+
+func (s *HTTPClientTestSuite) TestListLocationCards() {
+	var query url.Values
+	want := locations.LocationCards{Location: locations.StorageLocation{ID: "7", Name: "Binder", Kind: locations.KindBinder}, Count: 2, Cards: []locations.LocatedCard{
+		{CollectionID: "1", CollectionName: "Trades", ScryfallID: "abc", Name: "Sol Ring", Count: 2, Location: locations.Location{LocationID: "7", LocationName: "Binder", Page: 3, Slot: 5}},
+	}}
+	s.mux.HandleFunc("GET /locations/7/cards", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, want)
+	})
+
+	got, err := s.client.ListLocationCards(s.ctx, "7", &locations.LocationCardsQuery{Section: "Red", Page: 3})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"section": {"Red"}, "page": {"3"}}, query)
+	s.Equal(want, *got)
+
+	_, err = s.client.ListLocationCards(s.ctx, "7", nil)
+	s.Require().NoError(err)
+	s.Empty(query)
+}
+
+func (s *HTTPClientTestSuite) TestRelocateCards() {
+	var got locations.RelocateCardsRequest
+	s.handle("POST /collections/1/relocate", &got, http.StatusOK, locations.RelocateCardsResponse{Relocated: 1})
+
+	req := &locations.RelocateCardsRequest{ScryfallIDs: []string{"abc"}, Location: &locations.Location{LocationID: "7", Page: 3}}
+	resp, err := s.client.RelocateCards(s.ctx, "1", req)
+	s.Require().NoError(err)
+	s.Equal(*req, got)
+	s.Equal(1, resp.Relocated)
+}
+
+func (s *HTTPClientTestSuite) TestListCardsInCollection() {
+	want := []cards.Card{{ScryfallID: "abc", Name: "Fury Sliver", Count: 2}}
+	s.handle("GET /collections/1/cards", nil, http.StatusOK, want)
+
+	got, err := s.client.ListCardsInCollection(s.ctx, "1")
+	s.Require().NoError(err)
+	s.Require().Len(got, 1)
+	s.Equal("abc", got[0].ScryfallID)
+	s.Equal(2, got[0].Count)
+}
+
+func (s *HTTPClientTestSuite) TestAddCardToCollection() {
+	var got cards.AddCardRequest
+	s.handle("POST /collections/1/cards", &got, http.StatusCreated, nil)
+
+	err := s.client.AddCardToCollection(s.ctx, "1", &cards.AddCardRequest{ScryfallID: "abc", Count: 3})
+	s.Require().NoError(err)
+	s.Equal("abc", got.ScryfallID)
+	s.Equal(3, got.Count)
+}
+
+func (s *HTTPClientTestSuite) TestSetCardCountInCollection() {
+	var got cards.SetCardCountRequest
+	s.handle("PATCH /collections/1/cards/abc", &got, http.StatusNoContent, nil)
+
+	err := s.client.SetCardCountInCollection(s.ctx, "1", "abc", &cards.SetCardCountRequest{Count: 4}, nil)
+	s.Require().NoError(err)
+	s.Equal(4, got.Count)
+}
+
+func (s *HTTPClientTestSuite) TestGetCardInCollection() {
+	want := cards.CardEntry{
+		ScryfallID: "abc",
+		Name:       "Fury Sliver",
+		Count:      2,
+		Condition:  "NM",
+		Variants:   []cards.CardVariant{{ScryfallID: "def", Name: "Fury Sliver", Set: "tsr", Count: 1}},
+		Catalog:    &cards.CatalogCard{Set: "tsp", CollectorNumber: "157"},
+	}
+	s.handle("GET /collections/1/cards/abc", nil, http.StatusOK, want)
+
+	got, err := s.client.GetCardInCollection(s.ctx, "1", "abc", nil)
+	s.Require().NoError(err)
+	s.Equal(want, *got)
+}
+
+func (s *HTTPClientTestSuite) TestUpdateCardInCollection() {
+	var got map[string]any
+	s.handle("PATCH /collections/1/cards/abc", &got, http.StatusNoContent, nil)
+
+	condition := "LP"
+	err := s.client.UpdateCardInCollection(s.ctx, "1", "abc", &cards.UpdateCardRequest{Condition: &condition}, nil)
+	s.Require().NoError(err)
+	s.Equal(map[string]any{"condition": "LP"}, got)
+}
+
+func (s *HTTPClientTestSuite) TestTransferCard() {
+	var got cards.TransferCardRequest
+	s.handle("POST /collections/1/cards/abc/transfer", &got, http.StatusOK, cards.TransferCardResponse{FromCount: 1, ToCount: 3})
+
+	req := &cards.TransferCardRequest{ToCollectionID: "2", Count: 2, Mode: cards.TransferModeCopy}
+	resp, err := s.client.TransferCard(s.ctx, "1", "abc", req, nil)
+	s.Require().NoError(err)
+	s.Equal(*req, got)
+	s.Equal(&cards.TransferCardResponse{FromCount: 1, ToCount: 3}, resp)
+}
+
+func (s *HTTPClientTestSuite) TestBatchCardsInCollection() {
+	var got cards.BatchCardsRequest
+	s.handle("POST /collections/1/cards:batch", &got, http.StatusOK, cards.BatchCardsResponse{
+		Committed: true,
+		Applied:   1,
+		Results:   []cards.CardOperationResult{{Op: cards.CardOpAdd, ScryfallID: "abc", Status: http.StatusOK, Count: 4}},
+	})
+
+	req := &cards.BatchCardsRequest{
+		Mode:       cards.BatchModeBestEffort,
+		Operations: []cards.CardOperation{{Op: cards.CardOpAdd, ScryfallID: "abc", Count: 4}},
+	}
+	resp, err := s.client.BatchCardsInCollection(s.ctx, "1", req)
+	s.Require().NoError(err)
+	s.Equal(*req, got)
+	s.True(resp.Committed)
+	s.Require().Len(resp.Results, 1)
+	s.Equal(4, resp.Results[0].Count)
+}
+
+func (s *HTTPClientTestSuite) TestSetCardCountInCollectionNotFound() {
+	s.handle("PATCH /collections/1/cards/abc", nil, http.StatusNotFound, collections.ErrorResponse{Message: "Card not found"})
+
+	err := s.client.SetCardCountInCollection(s.ctx, "1", "abc", &cards.SetCardCountRequest{Count: 4}, nil)
+	s.ErrorIs(err, ErrNotFound)
+	s.False(errors.Is(err, ErrConflict))
+}
+
+func (s *HTTPClientTestSuite) TestDeleteCardFromCollection() {
+	s.handle("DELETE /collections/1/cards/abc", nil, http.StatusNoContent, nil)
+
+	s.NoError(s.client.DeleteCardFromCollection(s.ctx, "1", "abc", nil))
+}
+
+func (s *HTTPClientTestSuite) TestAcquisitions() {
+	var got cards.AcquisitionRequest
+	want := cards.Acquisition{ID: "5", Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Count: 2, Price: 1.5, Currency: "usd", Status: cards.AcquisitionOrdered}
+	s.handle("POST /collections/1/cards/abc/acquisitions", &got, http.StatusCreated, want)
+
+	req := &cards.AcquisitionRequest{Date: want.Date, Count: 2, Price: 1.5, Currency: "usd", Status: cards.AcquisitionOrdered}
+	acquisition, err := s.client.AddAcquisition(s.ctx, "1", "abc", req, nil)
+	s.Require().NoError(err)
+	s.Equal(*req, got)
+	s.Equal(want, *acquisition)
+
+	var update cards.UpdateAcquisitionRequest
+	want.Status = cards.AcquisitionReceived
+	s.handle("PATCH /collections/1/cards/abc/acquisitions/5", &update, http.StatusOK, want)
+
+	status := cards.AcquisitionReceived
+	acquisition, err = s.client.UpdateAcquisition(s.ctx, "1", "abc", "5", &cards.UpdateAcquisitionRequest{Status: &status}, nil)
+	s.Require().NoError(err)
+	s.Equal(cards.UpdateAcquisitionRequest{Status: &status}, update)
+	s.Equal(want, *acquisition)
+
+	s.handle("DELETE /collections/1/cards/abc/acquisitions/5", nil, http.StatusNoContent, nil)
+	s.NoError(s.client.DeleteAcquisition(s.ctx, "1", "abc", "5", nil))
+}
+
+func (s *HTTPClientTestSuite) TestGetProfitLoss() {
+	var query url.Values
+	percent := 100.0
+	want := collections.ProfitLoss{CollectionID: "1", Currency: "eur", CostBasis: 3, MarketValue: 6, UnrealizedGain: 3, UnrealizedGainPercent: &percent,
+		Cards:    []collections.CardProfitLoss{{CollectionID: "1", ScryfallID: "abc", Name: "Sol Ring", Count: 2, UnitCost: 1.5, CostBasis: 3, MarketValue: 6, Gain: 3, GainPercent: &percent}},
+		Realized: []collections.RealizedGain{},
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, want)
+	}
+	s.mux.HandleFunc("GET /collections/1/profit-loss", handler)
+	s.mux.HandleFunc("GET /profit-loss", handler)
+
+	got, err := s.client.GetCollectionProfitLoss(s.ctx, "1", &collections.ProfitLossQuery{Currency: "eur"})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"currency": {"eur"}}, query)
+	s.Equal(want, *got)
+
+	_, err = s.client.GetProfitLoss(s.ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(query)
+}
+
+func (s *HTTPClientTestSuite) TestGetExchangeRates() {
+	want := collections.ExchangeRates{Base: "usd", Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"usd": 1, "rub": 88.5}}
+	s.handle("GET /exchange-rates", nil, http.StatusOK, want)
+
+	got, err := s.client.GetExchangeRates(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(want, *got)
+}
+
+func (s *HTTPClientTestSuite) TestUserSettings() {
+	s.handle("GET /user/settings", nil, http.StatusOK, auth.UserSettings{Currency: "usd"})
+	settings, err := s.client.GetUserSettings(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(auth.UserSettings{Currency: "usd"}, *settings)
+
+	var got auth.UpdateSettingsRequest
+	s.handle("PATCH /user/settings", &got, http.StatusOK, auth.UserSettings{Currency: "rub"})
+	currency := "rub"
+	settings, err = s.client.UpdateUserSettings(s.ctx, &auth.UpdateSettingsRequest{Currency: &currency})
+	s.Require().NoError(err)
+	s.Equal(auth.UpdateSettingsRequest{Currency: &currency}, got)
+	s.Equal(auth.UserSettings{Currency: "rub"}, *settings)
+}
+
+func (s *HTTPClientTestSuite) TestListCardHistory() {
+	var query url.Values
+	s.mux.HandleFunc("GET /collections/1/history", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, []cards.CardHistoryEntry{{ID: "9", ScryfallID: "abc", Delta: -2, Action: "remove", Source: cards.ChangeSourceBot}})
+	})
+
+	list, err := s.client.ListCardHistory(s.ctx, "1", &cards.CardHistoryQuery{ScryfallID: "abc", Before: "10", Limit: 20})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"scryfall_id": {"abc"}, "before": {"10"}, "limit": {"20"}}, query)
+	s.Equal([]cards.CardHistoryEntry{{ID: "9", ScryfallID: "abc", Delta: -2, Action: "remove", Source: cards.ChangeSourceBot}}, list)
+
+	_, err = s.client.ListCardHistory(s.ctx, "1", nil)
+	s.Require().NoError(err)
+	s.Empty(query)
+}
+
+func (s *HTTPClientTestSuite) TestFindOwnedCards() {
+	var query url.Values
+	s.mux.HandleFunc("GET /cards/owned", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, cards.OwnedCards{Count: 2, Collections: []cards.OwnedCollection{
+			{CollectionID: "1", CollectionName: "Binder", Count: 2, Variants: []cards.CardVariant{{ScryfallID: "abc", Name: "Sol Ring", Count: 2}}},
+		}})
+	})
+
+	found, err := s.client.FindOwnedCards(s.ctx, &cards.OwnedCardsQuery{Name: "Sol Ring"})
+	s.Require().NoError(err)
+	s.Equal(url.Values{"name": {"Sol Ring"}}, query)
+	s.Equal(2, found.Count)
+	s.Require().Len(found.Collections, 1)
+	s.Equal("Binder", found.Collections[0].CollectionName)
+}
+
+func (s *HTTPClientTestSuite) TestUndoCardChange() {
+	var got cards.UndoChangeRequest
+	s.handle("POST /collections/1/history/9/undo", &got, http.StatusOK, cards.UndoChangeResponse{
+		Entries: []cards.CardHistoryEntry{{ID: "10", Delta: 2, Action: "undo", UndoOf: "9"}},
+	})
+
+	resp, err := s.client.UndoCardChange(s.ctx, "1", "9", &cards.UndoChangeRequest{Batch: true})
+	s.Require().NoError(err)
+	s.True(got.Batch)
+	s.Require().Len(resp.Entries, 1)
+	s.Equal("9", resp.Entries[0].UndoOf)
+}
+
+func (s *HTTPClientTestSuite) TestAddWishlistEntry() {
+	var got wishlist.AddEntryRequest
+	s.handle("POST /wishlist", &got, http.StatusCreated, wishlist.Entry{ID: "3", ScryfallID: "abc", Quantity: 4, Priority: 3})
+
+	entry, err := s.client.AddWishlistEntry(s.ctx, &wishlist.AddEntryRequest{ScryfallID: "abc", Quantity: 4})
+	s.Require().NoError(err)
+	s.Equal("abc", got.ScryfallID)
+	s.Equal(4, got.Quantity)
+	s.Equal("3", entry.ID)
+}
+
+func (s *HTTPClientTestSuite) TestListMissingCards() {
+	want := []wishlist.MissingEntry{{Entry: wishlist.Entry{ID: "3", Quantity: 4}, Owned: 1, Missing: 3}}
+	s.handle("GET /wishlist/missing", nil, http.StatusOK, want)
+
+	got, err := s.client.ListMissingCards(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *HTTPClientTestSuite) TestChangeSourceHeader() {
+	var source string
+	s.mux.HandleFunc("DELETE /collections/1/cards/abc", func(w http.ResponseWriter, r *http.Request) {
+		source = r.Header.Get(cards.ChangeSourceHeader)
+		writeJSON(w, http.StatusNoContent, nil)
+	})
+
+	s.Require().NoError(s.client.DeleteCardFromCollection(s.ctx, "1", "abc", nil))
+	s.Empty(source, "no header without the option")
+
+	bot := NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithChangeSource(cards.ChangeSourceBot))
+	s.Require().NoError(bot.DeleteCardFromCollection(s.ctx, "1", "abc", nil))
+	s.Equal(cards.ChangeSourceBot, source)
+}
+
+func (s *HTTPClientTestSuite) TestIfMatchHeader() {
+	var ifMatch string
+	s.mux.HandleFunc("DELETE /collections/1", func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = r.Header.Get("If-Match")
+		if ifMatch != "" && ifMatch != `"4"` {
+			writeJSON(w, http.StatusPreconditionFailed, collections.ErrorResponse{Message: "Collection version does not match"})
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	})
+
+	s.Require().NoError(s.client.DeleteCollection(s.ctx, "1"))
+	s.Empty(ifMatch)
+
+	err := s.client.DeleteCollection(IfMatch(s.ctx, 3), "1")
+	s.ErrorIs(err, ErrPreconditionFailed)
+	s.Equal(`"3"`, ifMatch)
+
+	s.Require().NoError(s.client.DeleteCollection(IfMatch(s.ctx, 4), "1"))
+}
+
+func (s *HTTPClientTestSuite) TestRetryIdempotentRequest() {
+	var calls atomic.Int32
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, nil)
+			return
+		}
+		writeJSON(w, http.StatusOK, []collections.Collection{{ID: "1", Name: "Bulk"}})
+	})
+
+	got, err := s.client.GetUserCollections(s.ctx)
+	s.Require().NoError(err)
+	s.Len(got, 1)
+	s.Equal(int32(3), calls.Load())
+}
+
+func (s *HTTPClientTestSuite) TestRetryGivesUp() {
+	var calls atomic.Int32
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusBadGateway, nil)
+	})
+
+	_, err := s.client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, ErrServer)
+	s.Equal(int32(3), calls.Load())
+}
+
+func (s *HTTPClientTestSuite) TestNoRetryForNonIdempotentRequest() {
+	var calls atomic.Int32
+	s.mux.HandleFunc("POST /collections/1/cards", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusServiceUnavailable, nil)
+	})
+
+	err := s.client.AddCardToCollection(s.ctx, "1", &cards.AddCardRequest{ScryfallID: "abc", Count: 1})
+	s.ErrorIs(err, ErrServer)
+	s.Equal(int32(1), calls.Load())
+}
+
+func (s *HTTPClientTestSuite) TestNoRetryForClientErrors() {
+	var calls atomic.Int32
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusNotFound, nil)
+	})
+
+	_, err := s.client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, ErrNotFound)
+	s.Equal(int32(1), calls.Load())
+}
+
+func (s *HTTPClientTestSuite) TestTimeout() {
+	release := make(chan struct{})
+	defer close(release)
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	client := NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithTimeout(20*time.Millisecond), WithRetry(1, 0, 0))
+
+	start := time.Now()
+	_, err := client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Less(time.Since(start), time.Second)
+}
+
+func (s *HTTPClientTestSuite) TestContextCancelled() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+
+	_, err := s.client.GetUserCollections(ctx)
+	s.ErrorIs(err, context.Canceled)
+}
+
+func (s *HTTPClientTestSuite) TestCircuitBreakerFailsFast() {
+	var calls atomic.Int32
+	s.mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusInternalServerError, nil)
+	})
+	client := NewHTTPCollectorClient(s.server.URL, logger.SilentLogger{}, WithRetry(1, 0, 0), WithCircuitBreaker(2, time.Hour))
+
+	for range 2 {
+		_, err := client.GetUserCollections(s.ctx)
+		s.ErrorIs(err, ErrServer)
+	}
+	_, err := client.GetUserCollections(s.ctx)
+	s.ErrorIs(err, ErrCircuitOpen)
+	s.Equal(int32(2), calls.Load())
+}
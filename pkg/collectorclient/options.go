@@ -0,0 +1,61 @@
+package collectorclient
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultRetryAttempts    = 3
+	defaultRetryBaseDelay   = 100 * time.Millisecond
+	defaultRetryMaxDelay    = 2 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// Option configures HTTPCollectorClient.
+type Option func(*HTTPCollectorClient)
+
+// WithHTTPClient replaces the underlying *http.Client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *HTTPCollectorClient) {
+		c.ClientHTTP = client
+	}
+}
+
+// WithTimeout limits every single attempt of a request. Zero disables the limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HTTPCollectorClient) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry retries idempotent requests up to maxAttempts times in total
+// on network errors, 429, 502, 503 and 504. The delay before each retry
+// is picked at random between zero and baseDelay*2^n, capped by maxDelay.
+// maxAttempts of 1 disables retries.
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(c *HTTPCollectorClient) {
+		c.retry = retryPolicy{maxAttempts: max(maxAttempts, 1), baseDelay: baseDelay, maxDelay: maxDelay}
+	}
+}
+
+// WithCircuitBreaker opens the breaker after failureThreshold consecutive
+// failures (network errors and 5xx). While open, calls fail fast with
+// ErrCircuitOpen; after cooldown a single probe request is let through.
+// failureThreshold of 0 disables the breaker.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(c *HTTPCollectorClient) {
+		c.breaker = newCircuitBreaker(failureThreshold, cooldown)
+	}
+}
+
+// WithChangeSource tells collector-service where the card changes made through
+// the client come from, one of the cards.ChangeSource constants. The service
+// records it in the card history; without it changes count as made through the API.
+func WithChangeSource(source string) Option {
+	return func(c *HTTPCollectorClient) {
+		c.changeSource = source
+	}
+}
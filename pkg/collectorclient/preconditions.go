@@ -0,0 +1,44 @@
+package collectorclient
+
+import (
+	"context"
+	"strconv"
+)
+
+type ctxKeyPrecondition string
+
+const (
+	ifMatchKey     ctxKeyPrecondition = "ifMatch"
+	ifNoneMatchKey ctxKeyPrecondition = "ifNoneMatch"
+)
+
+// IfMatch makes the writes done with ctx conditional on the collection
+// still being of version, as returned in collections.Collection.Version.
+// Writes to a collection changed since then fail with ErrPreconditionFailed.
+func IfMatch(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, ifMatchKey, version)
+}
+
+// IfNoneMatch makes the reads of a collection or its cards done with ctx fail
+// with ErrNotModified while the collection is still of version. It lets a
+// caller poll for changes without downloading the same collection again.
+func IfNoneMatch(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, ifNoneMatchKey, version)
+}
+
+// etag returns the entity tag collector-service gives to a version of a collection.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// preconditions returns the If-Match and If-None-Match headers set on ctx.
+func preconditions(ctx context.Context) map[string]string {
+	headers := make(map[string]string)
+	if version, ok := ctx.Value(ifMatchKey).(int64); ok {
+		headers["If-Match"] = etag(version)
+	}
+	if version, ok := ctx.Value(ifNoneMatchKey).(int64); ok {
+		headers["If-None-Match"] = etag(version)
+	}
+	return headers
+}
@@ -0,0 +1,45 @@
+package collectorclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/authctx"
+)
+
+// ErrRefreshUnsupported is returned by token sources that can't renew tokens.
+var ErrRefreshUnsupported = errors.New("token refresh is not supported")
+
+// TokenSource supplies JWTs for requests that need authorization.
+// When collector-service answers 401 the client calls Refresh once,
+// stores the new token with authctx.SetJWT and repeats the request.
+type TokenSource interface {
+	// Token returns the token to send with a request made with ctx.
+	Token(ctx context.Context) (string, error)
+	// Refresh obtains a new token after the current one was rejected.
+	Refresh(ctx context.Context) (string, error)
+}
+
+// ContextTokenSource reads the token stored with authctx.WithJWT.
+// It can't refresh tokens.
+type ContextTokenSource struct{}
+
+func (ContextTokenSource) Token(ctx context.Context) (string, error) {
+	token, ok := authctx.GetJWT(ctx)
+	if !ok || token == "" {
+		return "", ErrMissingToken
+	}
+	return token, nil
+}
+
+func (ContextTokenSource) Refresh(ctx context.Context) (string, error) {
+	return "", ErrRefreshUnsupported
+}
+
+// WithTokenSource sets where tokens come from and how they are renewed.
+// The default is ContextTokenSource.
+func WithTokenSource(tokens TokenSource) Option {
+	return func(c *HTTPCollectorClient) {
+		c.tokens = tokens
+	}
+}
@@ -31,3 +31,17 @@ type RegisterRequest struct {
 type RegisterResponse struct {
 	Token string `json:"token" example:"eyJhbG..."`
 }
+
+// UserSettings — настройки пользователя
+// @Description currency — валюта отчетов и цен по умолчанию: usd, eur или rub
+// @example { "currency": "rub" }
+type UserSettings struct {
+	Currency string `json:"currency" example:"rub"`
+}
+
+// UpdateSettingsRequest — запрос для изменения настроек пользователя
+// @Description Меняет только переданные поля
+// @example { "currency": "eur" }
+type UpdateSettingsRequest struct {
+	Currency *string `json:"currency,omitempty" example:"eur"`
+}
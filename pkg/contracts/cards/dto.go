@@ -0,0 +1,339 @@
+package cards
+
+import (
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+)
+
+// Card — карта в коллекции
+// @Description Карта в коллекции пользователя с количеством копий. location — свое место карты;
+// @Description без него карта лежит там же, где коллекция. finish — foil или etched, без него карта nonfoil.
+// @Description acquisitions — покупки копий карты, старые первыми
+// @example { "scryfall_id": "0000579f-7b35-4ed3-b44c-db2a538066fe", "name": "Fury Sliver", "count": 2 }
+type Card struct {
+	ScryfallID   string              `json:"scryfall_id" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Name         string              `json:"name" example:"Fury Sliver"`
+	CardUrl      string              `json:"card_url,omitempty" example:"https://scryfall.com/card/tsp/157/fury-sliver"`
+	Count        int                 `json:"count" example:"2"`
+	Notes        string              `json:"notes,omitempty" example:"Signed by the artist"`
+	Condition    string              `json:"condition,omitempty" example:"NM"`
+	Zone         string              `json:"zone,omitempty" example:"sideboard"`
+	Finish       string              `json:"finish,omitempty" example:"foil"`
+	AddedAt      time.Time           `json:"added_at,omitempty"`
+	Location     *locations.Location `json:"location,omitempty"`
+	Acquisitions []Acquisition       `json:"acquisitions,omitempty"`
+}
+
+// CardEntry — полная запись о карте в коллекции
+// @Description Карта в коллекции с другими печатями той же карты и данными каталога.
+// @Description location — где лежит карта: ее место или место коллекции; acquisitions — покупки копий карты
+type CardEntry struct {
+	ScryfallID   string              `json:"scryfall_id" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Name         string              `json:"name" example:"Fury Sliver"`
+	CardUrl      string              `json:"card_url,omitempty" example:"https://scryfall.com/card/tsp/157/fury-sliver"`
+	Count        int                 `json:"count" example:"2"`
+	Notes        string              `json:"notes,omitempty" example:"Signed by the artist"`
+	Condition    string              `json:"condition,omitempty" example:"NM"`
+	Zone         string              `json:"zone,omitempty" example:"sideboard"`
+	Finish       string              `json:"finish,omitempty" example:"foil"`
+	AddedAt      time.Time           `json:"added_at,omitempty"`
+	UpdatedAt    time.Time           `json:"updated_at,omitempty"`
+	Variants     []CardVariant       `json:"variants"`
+	Catalog      *CatalogCard        `json:"catalog,omitempty"`
+	Location     *locations.Location `json:"location,omitempty"`
+	Acquisitions []Acquisition       `json:"acquisitions,omitempty"`
+}
+
+// CardVariant — печать карты в коллекции
+// @Description Печать карты в коллекции; в записи карты — другая печать той же карты (по oracle_id или имени).
+// @Description location — где лежит печать
+type CardVariant struct {
+	ScryfallID      string              `json:"scryfall_id" example:"a2f1ae9a-4b0c-4dce-8a8c-2a3fbd1c8e4c"`
+	Name            string              `json:"name" example:"Fury Sliver"`
+	Set             string              `json:"set,omitempty" example:"tsp"`
+	CollectorNumber string              `json:"collector_number,omitempty" example:"157"`
+	Count           int                 `json:"count" example:"1"`
+	Condition       string              `json:"condition,omitempty" example:"LP"`
+	Zone            string              `json:"zone,omitempty" example:"sideboard"`
+	Finish          string              `json:"finish,omitempty" example:"foil"`
+	Location        *locations.Location `json:"location,omitempty"`
+}
+
+// CardEntryQuery — какая карта коллекции имеется в виду
+// @Description Печать может лежать в коллекции в нескольких зонах колоды и отделках;
+// @Description без zone выбирается карта в mainboard, без finish — nonfoil
+type CardEntryQuery struct {
+	Zone   string `form:"zone" json:"zone,omitempty" example:"sideboard"`
+	Finish string `form:"finish" json:"finish,omitempty" example:"foil"`
+}
+
+// OwnedCardsQuery — параметры поиска карты по коллекциям пользователя
+// @Description Ровно один из параметров: scryfall_id ищет эту печать, oracle_id и name — все печати карты
+type OwnedCardsQuery struct {
+	ScryfallID string `form:"scryfall_id" json:"scryfall_id,omitempty" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	OracleID   string `form:"oracle_id" json:"oracle_id,omitempty" example:"b2b3b1b4-1d7e-4b5f-9e8e-0a5c0f1d5f8a"`
+	Name       string `form:"name" json:"name,omitempty" example:"Fury Sliver"`
+}
+
+// OwnedCards — где лежат копии карты
+// @Description Коллекции пользователя с копиями карты, кроме вишлистов, и места хранения печатей
+type OwnedCards struct {
+	Count       int               `json:"count" example:"3"`
+	Collections []OwnedCollection `json:"collections"`
+}
+
+// OwnedCollection — копии карты в одной коллекции
+// @Description Коллекция с копиями карты по печатям
+type OwnedCollection struct {
+	CollectionID   string        `json:"collection_id" example:"64a9b66b2db8b91234a6e8e4"`
+	CollectionName string        `json:"collection_name" example:"Red binder"`
+	Kind           string        `json:"kind,omitempty" example:"binder"`
+	Count          int           `json:"count" example:"2"`
+	Variants       []CardVariant `json:"variants"`
+}
+
+// CatalogCard — данные о печати карты из каталога Scryfall
+// @Description Справочные данные о печати карты
+type CatalogCard struct {
+	OracleID        string   `json:"oracle_id" example:"b2b3b1b4-1d7e-4b5f-9e8e-0a5c0f1d5f8a"`
+	Set             string   `json:"set" example:"tsp"`
+	SetName         string   `json:"set_name" example:"Time Spiral"`
+	CollectorNumber string   `json:"collector_number" example:"157"`
+	Rarity          string   `json:"rarity" example:"uncommon"`
+	Lang            string   `json:"lang" example:"en"`
+	ManaCost        string   `json:"mana_cost,omitempty" example:"{5}{R}"`
+	TypeLine        string   `json:"type_line" example:"Creature — Sliver"`
+	OracleText      string   `json:"oracle_text,omitempty" example:"All Sliver creatures have double strike."`
+	Colors          []string `json:"colors,omitempty"`
+	ColorIdentity   []string `json:"color_identity,omitempty"`
+	Finishes        []string `json:"finishes,omitempty"`
+	ImageURL        string   `json:"image_url,omitempty" example:"https://cards.scryfall.io/normal/front/0/0/0000579f.jpg"`
+	ScryfallURI     string   `json:"scryfall_uri,omitempty" example:"https://scryfall.com/card/tsp/157/fury-sliver"`
+}
+
+// AddCardRequest — запрос для добавления карты в коллекцию
+// @Description Добавляет карту; если она уже есть в этой зоне колоды с той же отделкой, количество увеличивается.
+// @Description Копии печати в разных зонах или с разной отделкой — разные карты коллекции.
+// @Description reserve_from — коллекция, из которой добавленные в колоду копии резервируются; карта должна в ней быть
+// @example { "scryfall_id": "0000579f-7b35-4ed3-b44c-db2a538066fe", "name": "Fury Sliver", "count": 1 }
+type AddCardRequest struct {
+	ScryfallID  string `json:"scryfall_id" binding:"required" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Name        string `json:"name" example:"Fury Sliver"`
+	CardUrl     string `json:"card_url,omitempty" example:"https://scryfall.com/card/tsp/157/fury-sliver"`
+	Count       int    `json:"count" binding:"required,min=1" example:"1"`
+	Zone        string `json:"zone,omitempty" example:"sideboard"`
+	Finish      string `json:"finish,omitempty" example:"foil"`
+	ReserveFrom string `json:"reserve_from,omitempty" example:"64a9b66b2db8b91234a6e8e4"`
+}
+
+// Статусы покупки
+const (
+	AcquisitionOrdered  = "ordered"
+	AcquisitionReceived = "received"
+)
+
+// Acquisition — покупка копий карты
+// @Description Сколько копий куплено, когда, у кого и по какой цене за копию. status — ordered, пока копии не пришли,
+// @Description и received после; в себестоимость идут только полученные копии, по средней цене копии.
+// @Description Покупки переходят в другую коллекцию вместе с картой, только если она переносится целиком
+// @example { "id": "66f1c2a79b1e8d001c8e4f70", "date": "2025-03-01T00:00:00Z", "count": 2, "price": 1.5, "currency": "usd", "vendor": "Card Kingdom", "status": "received" }
+type Acquisition struct {
+	ID       string    `json:"id" example:"66f1c2a79b1e8d001c8e4f70"`
+	Date     time.Time `json:"date" example:"2025-03-01T00:00:00Z"`
+	Count    int       `json:"count" example:"2"`
+	Price    float64   `json:"price" example:"1.5"`
+	Currency string    `json:"currency" example:"usd"`
+	Vendor   string    `json:"vendor,omitempty" example:"Card Kingdom"`
+	Status   string    `json:"status" example:"received"`
+}
+
+// AcquisitionRequest — запрос для записи покупки
+// @Description price — цена одной копии, currency — usd или eur. date по умолчанию — сейчас, status — received
+// @example { "count": 2, "price": 1.5, "currency": "usd", "vendor": "Card Kingdom", "status": "ordered" }
+type AcquisitionRequest struct {
+	Date     time.Time `json:"date,omitempty" example:"2025-03-01T00:00:00Z"`
+	Count    int       `json:"count" binding:"required,min=1" example:"2"`
+	Price    float64   `json:"price" binding:"min=0" example:"1.5"`
+	Currency string    `json:"currency" binding:"required" example:"usd"`
+	Vendor   string    `json:"vendor,omitempty" binding:"max=100" example:"Card Kingdom"`
+	Status   string    `json:"status,omitempty" binding:"omitempty,oneof=ordered received" example:"ordered"`
+}
+
+// UpdateAcquisitionRequest — частичное обновление покупки
+// @Description Меняет только переданные поля, например status, когда заказанные копии пришли
+// @example { "status": "received" }
+type UpdateAcquisitionRequest struct {
+	Date     *time.Time `json:"date,omitempty" example:"2025-03-01T00:00:00Z"`
+	Count    *int       `json:"count,omitempty" binding:"omitempty,min=1" example:"2"`
+	Price    *float64   `json:"price,omitempty" binding:"omitempty,min=0" example:"1.5"`
+	Currency *string    `json:"currency,omitempty" example:"usd"`
+	Vendor   *string    `json:"vendor,omitempty" binding:"omitempty,max=100" example:"Card Kingdom"`
+	Status   *string    `json:"status,omitempty" binding:"omitempty,oneof=ordered received" example:"received"`
+}
+
+// SetCardCountRequest — запрос для установки количества копий карты
+// @Description Устанавливает абсолютное количество копий карты в коллекции; 0 убирает карту из коллекции
+// @example { "count": 4 }
+type SetCardCountRequest struct {
+	Count int `json:"count" binding:"min=0" example:"4"`
+}
+
+// UpdateCardRequest — частичное обновление карты в коллекции
+// @Description Меняет только переданные поля; пустая строка очищает заметку или состояние и возвращает карту в mainboard.
+// @Description count 0 убирает карту из коллекции, как DELETE; остальные поля тогда не учитываются.
+// @Description Карту нельзя перенести в зону или сменить ей отделку, если там уже есть эта печать с такой отделкой.
+// @Description Состояние — одно из NM, LP, MP, HP, DMG; зона — mainboard, sideboard, commander, companion или maybeboard;
+// @Description отделка — nonfoil, foil или etched
+// @example { "count": 3, "condition": "LP" }
+type UpdateCardRequest struct {
+	Count     *int    `json:"count,omitempty" binding:"omitempty,min=0" example:"3"`
+	Notes     *string `json:"notes,omitempty" binding:"omitempty,max=1000" example:"Signed by the artist"`
+	Condition *string `json:"condition,omitempty" example:"LP"`
+	Zone      *string `json:"zone,omitempty" example:"sideboard"`
+	Finish    *string `json:"finish,omitempty" example:"foil"`
+}
+
+// Зоны колоды. Карта без зоны лежит в mainboard
+const (
+	ZoneMainboard  = "mainboard"
+	ZoneSideboard  = "sideboard"
+	ZoneCommander  = "commander"
+	ZoneCompanion  = "companion"
+	ZoneMaybeboard = "maybeboard"
+)
+
+// Режимы пакетной обработки карт
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+// Операции пакетной обработки карт
+const (
+	CardOpAdd    = "add"
+	CardOpSet    = "set"
+	CardOpRemove = "remove"
+)
+
+// BatchCardsRequest — пакет операций с картами коллекции
+// @Description Операции выполняются по порядку и сохраняются одной записью.
+// @Description atomic (по умолчанию): если хоть одна операция не прошла, ничего не сохраняется;
+// @Description best_effort: неудачные операции пропускаются, остальные сохраняются
+// @example { "mode": "atomic", "operations": [{ "op": "add", "scryfall_id": "0000579f-7b35-4ed3-b44c-db2a538066fe", "count": 4 }] }
+type BatchCardsRequest struct {
+	Mode       string          `json:"mode,omitempty" binding:"omitempty,oneof=atomic best_effort" example:"atomic"`
+	Operations []CardOperation `json:"operations" binding:"required,min=1,max=1000"`
+}
+
+// CardOperation — одна операция пакета
+// @Description add — добавить копии; set — установить количество (0 удаляет карту);
+// @Description remove — убрать count копий или всю карту, если count не указан.
+// @Description zone — зона колоды карты, без нее mainboard; finish — отделка, без нее nonfoil
+type CardOperation struct {
+	Op         string `json:"op" example:"add"`
+	ScryfallID string `json:"scryfall_id" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Zone       string `json:"zone,omitempty" example:"sideboard"`
+	Finish     string `json:"finish,omitempty" example:"foil"`
+	Name       string `json:"name,omitempty" example:"Fury Sliver"`
+	CardUrl    string `json:"card_url,omitempty" example:"https://scryfall.com/card/tsp/157/fury-sliver"`
+	Count      int    `json:"count,omitempty" example:"4"`
+}
+
+// BatchCardsResponse — результат пакета операций
+// @Description Результаты операций в том же порядке, что и в запросе
+type BatchCardsResponse struct {
+	Committed bool                  `json:"committed" example:"true"`
+	Applied   int                   `json:"applied" example:"1"`
+	Failed    int                   `json:"failed" example:"0"`
+	Results   []CardOperationResult `json:"results"`
+}
+
+// CardOperationResult — результат одной операции пакета
+// @Description status — HTTP-статус операции; count — сколько копий осталось после неё
+type CardOperationResult struct {
+	Index      int    `json:"index" example:"0"`
+	Op         string `json:"op" example:"add"`
+	ScryfallID string `json:"scryfall_id" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Zone       string `json:"zone,omitempty" example:"sideboard"`
+	Finish     string `json:"finish,omitempty" example:"foil"`
+	Status     int    `json:"status" example:"200"`
+	Count      int    `json:"count" example:"4"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Режимы переноса карты
+const (
+	TransferModeMove = "move"
+	TransferModeCopy = "copy"
+)
+
+// TransferCardRequest — запрос на перенос карты в другую коллекцию
+// @Description move (по умолчанию) переносит копии, copy оставляет исходную коллекцию как есть
+// @example { "to_collection_id": "60c72b2f9b1e8d001c8e4f5a", "count": 2, "mode": "move" }
+type TransferCardRequest struct {
+	ToCollectionID string `json:"to_collection_id" binding:"required" example:"60c72b2f9b1e8d001c8e4f5a"`
+	Count          int    `json:"count" binding:"required,min=1" example:"2"`
+	Mode           string `json:"mode,omitempty" binding:"omitempty,oneof=move copy" example:"move"`
+}
+
+// TransferCardResponse — количество копий после переноса
+// @Description Сколько копий карты осталось в исходной коллекции и стало в целевой
+type TransferCardResponse struct {
+	FromCount int `json:"from_count" example:"1"`
+	ToCount   int `json:"to_count" example:"2"`
+}
+
+// ChangeSourceHeader — заголовок, в котором клиент передает источник изменения карт.
+// Без заголовка изменение считается сделанным через API
+const ChangeSourceHeader = "X-Change-Source"
+
+// Источники изменений карт
+const (
+	ChangeSourceAPI    = "api"
+	ChangeSourceImport = "import"
+	ChangeSourceBot    = "bot"
+	ChangeSourceTrade  = "trade"
+)
+
+// CardHistoryEntry — запись истории изменений карт
+// @Description Изменение количества копий карты в коллекции. Записи, сделанные одним запросом, имеют общий batch_id.
+// @Description action — add, update, remove, batch, transfer, merge, split или undo;
+// @Description source — api, import, bot или trade
+type CardHistoryEntry struct {
+	ID                  string    `json:"id" example:"66f1c2a79b1e8d001c8e4f60"`
+	BatchID             string    `json:"batch_id" example:"66f1c2a79b1e8d001c8e4f5f"`
+	UserID              string    `json:"user_id" example:"60c72b2f9b1e8d001c8e4f5b"`
+	CollectionID        string    `json:"collection_id" example:"60c72b2f9b1e8d001c8e4f5a"`
+	ScryfallID          string    `json:"scryfall_id" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Name                string    `json:"name,omitempty" example:"Fury Sliver"`
+	Zone                string    `json:"zone,omitempty" example:"sideboard"`
+	Finish              string    `json:"finish,omitempty" example:"foil"`
+	Delta               int       `json:"delta" example:"2"`
+	Action              string    `json:"action" example:"add"`
+	Source              string    `json:"source" example:"api"`
+	RelatedCollectionID string    `json:"related_collection_id,omitempty" example:"60c72b2f9b1e8d001c8e4f5c"`
+	UndoOf              string    `json:"undo_of,omitempty" example:""`
+	CreatedAt           time.Time `json:"created_at" example:"2024-09-23T12:00:00Z"`
+}
+
+// CardHistoryQuery — параметры выборки истории
+// @Description Записи идут от новых к старым; before — ID последней записи предыдущей страницы
+type CardHistoryQuery struct {
+	ScryfallID string `form:"scryfall_id" json:"scryfall_id,omitempty" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Before     string `form:"before" json:"before,omitempty" example:"66f1c2a79b1e8d001c8e4f60"`
+	Limit      int    `form:"limit" json:"limit,omitempty" binding:"omitempty,min=1,max=200" example:"50"`
+}
+
+// UndoChangeRequest — запрос на отмену изменения
+// @Description batch — отменить все изменения, сделанные тем же запросом, а не только эту запись
+// @example { "batch": true }
+type UndoChangeRequest struct {
+	Batch bool `json:"batch" example:"false"`
+}
+
+// UndoChangeResponse — записи истории, сделанные отменой
+// @Description Каждая запись ссылается на отмененную через undo_of
+type UndoChangeResponse struct {
+	Entries []CardHistoryEntry `json:"entries"`
+}
@@ -1,10 +1,21 @@
 package collections
 
+import (
+	"time"
+
+	"github.com/ShenokZlob/collector-ouphe/pkg/contracts/locations"
+)
+
 // CreateCollectionRequest — запрос для создания новой коллекции
-// @Description Запрос для создания коллекции с указанным именем
+// @Description Запрос для создания коллекции с указанным именем и необязательными описанием, типом, форматом и тегами.
+// @Description Формат имеет смысл для колод: по нему проверяется их легальность
 // @example { "name": "My cool collection" }
 type CreateCollectionRequest struct {
-	Name string `json:"name" binding:"required" example:"My cool collection"`
+	Name        string   `json:"name" binding:"required" example:"My cool collection"`
+	Description string   `json:"description,omitempty" binding:"max=1000" example:"Trade binder for FNM"`
+	Kind        string   `json:"kind,omitempty" binding:"omitempty,oneof=binder deck cube trade wishlist" example:"binder"`
+	Format      string   `json:"format,omitempty" example:"modern"`
+	Tags        []string `json:"tags,omitempty" example:"trade"`
 }
 
 // RenameCollectionRequest — запрос для переименования коллекции
@@ -14,12 +25,475 @@ type RenameCollectionRequest struct {
 	Name string `json:"name" binding:"required" example:"Renamed collection"`
 }
 
+// UpdateCollectionRequest — запрос для изменения коллекции
+// @Description Меняет только переданные поля. Пустая строка очищает описание, тип, формат или обложку, пустой список — теги.
+// @Description cover_card должна быть картой этой коллекции. location — место карт коллекции без своего места,
+// @Description пустой location_id убирает коллекцию с места
+// @example { "kind": "deck", "tags": ["modern", "elves"], "pinned": true }
+type UpdateCollectionRequest struct {
+	Name        *string             `json:"name,omitempty" example:"Renamed collection"`
+	Description *string             `json:"description,omitempty" binding:"omitempty,max=1000" example:"Trade binder for FNM"`
+	Kind        *string             `json:"kind,omitempty" example:"deck"`
+	Format      *string             `json:"format,omitempty" example:"modern"`
+	Tags        *[]string           `json:"tags,omitempty" example:"modern"`
+	CoverCard   *string             `json:"cover_card,omitempty" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Pinned      *bool               `json:"pinned,omitempty" example:"true"`
+	SortOrder   *int                `json:"sort_order,omitempty" example:"1"`
+	Location    *locations.Location `json:"location,omitempty"`
+}
+
+// MergeCollectionRequest — запрос для слияния коллекций
+// @Description Карты исходной коллекции добавляются в текущую, количество одинаковых карт суммируется.
+// @Description Исходная коллекция удаляется, если не указан keep_source
+// @example { "source_collection_id": "64a9b66b2db8b91234a6e8e4" }
+type MergeCollectionRequest struct {
+	SourceCollectionID string `json:"source_collection_id" binding:"required" example:"64a9b66b2db8b91234a6e8e4"`
+	KeepSource         bool   `json:"keep_source,omitempty" example:"false"`
+}
+
+// DuplicateCollectionRequest — запрос для копирования коллекции
+// @Description Создает копию коллекции со всеми картами под новым именем
+// @example { "name": "My cool collection (copy)" }
+type DuplicateCollectionRequest struct {
+	Name string `json:"name" binding:"required" example:"My cool collection (copy)"`
+}
+
+// SplitCollectionRequest — запрос для выделения карт в новую коллекцию
+// @Description Переносит карты, подходящие под фильтр, в новую коллекцию с указанным именем
+// @example { "name": "Duskmourn", "filter": { "sets": ["dsk"] } }
+type SplitCollectionRequest struct {
+	Name   string     `json:"name" binding:"required" example:"Duskmourn"`
+	Filter CardFilter `json:"filter"`
+}
+
+// CardFilter — фильтр карт коллекции
+// @Description Карта подходит, если совпадают все заданные поля; для списков достаточно одного значения.
+// @Description name ищет подстроку без учета регистра; sets и rarities берутся из каталога
+type CardFilter struct {
+	ScryfallIDs []string `json:"scryfall_ids,omitempty"`
+	Name        string   `json:"name,omitempty" example:"sliver"`
+	Sets        []string `json:"sets,omitempty" example:"dsk"`
+	Rarities    []string `json:"rarities,omitempty" example:"rare"`
+	Conditions  []string `json:"conditions,omitempty" example:"NM"`
+}
+
 // Collection — модель коллекции в ответах
-// @Description Модель коллекции с ID и именем
-// @example { "id": "64a9b66b2db8b91234a6e8e3", "name": "My cool collection" }
+// @Description Модель коллекции с ID, именем, метаданными и количеством карт.
+// @Description card_count — число всех копий, unique_cards — число разных карт.
+// @Description version растет с каждым изменением коллекции и ее карт; он же приходит в ETag.
+// @Description location — место, где лежат карты коллекции без своего места
+// @example { "id": "64a9b66b2db8b91234a6e8e3", "name": "My cool collection", "kind": "binder", "card_count": 42, "unique_cards": 30, "version": 7 }
 type Collection struct {
-	ID   string `json:"id" example:"64a9b66b2db8b91234a6e8e3"`
-	Name string `json:"name" example:"My cool collection"`
+	ID          string              `json:"id" example:"64a9b66b2db8b91234a6e8e3"`
+	Name        string              `json:"name" example:"My cool collection"`
+	Description string              `json:"description,omitempty" example:"Trade binder for FNM"`
+	Kind        string              `json:"kind,omitempty" example:"binder"`
+	Format      string              `json:"format,omitempty" example:"modern"`
+	Tags        []string            `json:"tags,omitempty" example:"trade"`
+	CoverCard   string              `json:"cover_card,omitempty" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Pinned      bool                `json:"pinned,omitempty" example:"true"`
+	SortOrder   int                 `json:"sort_order,omitempty" example:"1"`
+	CardCount   int                 `json:"card_count" example:"42"`
+	UniqueCards int                 `json:"unique_cards" example:"30"`
+	Version     int64               `json:"version" example:"7"`
+	Location    *locations.Location `json:"location,omitempty"`
+}
+
+// DeletedCollection — коллекция в корзине
+// @Description Коллекция в корзине и время ее удаления
+type DeletedCollection struct {
+	Collection
+	DeletedAt time.Time `json:"deleted_at" example:"2025-01-02T15:04:05Z"`
+}
+
+// CreateSnapshotRequest — запрос для создания снимка коллекции
+// @Description Сохраняет текущие карты коллекции под именем, уникальным в пределах коллекции
+// @example { "name": "Before rotation" }
+type CreateSnapshotRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Before rotation"`
+}
+
+// Snapshot — снимок коллекции
+// @Description Именованный снимок карт коллекции. Список снимков возвращается без карт
+// @example { "id": "64a9b66b2db8b91234a6e8e5", "collection_id": "64a9b66b2db8b91234a6e8e3", "name": "Before rotation", "card_count": 42, "unique_cards": 30 }
+type Snapshot struct {
+	ID           string         `json:"id" example:"64a9b66b2db8b91234a6e8e5"`
+	CollectionID string         `json:"collection_id" example:"64a9b66b2db8b91234a6e8e3"`
+	Name         string         `json:"name" example:"Before rotation"`
+	CardCount    int            `json:"card_count" example:"42"`
+	UniqueCards  int            `json:"unique_cards" example:"30"`
+	CreatedAt    time.Time      `json:"created_at" example:"2025-01-02T15:04:05Z"`
+	Cards        []SnapshotCard `json:"cards,omitempty"`
+}
+
+// SnapshotCard — карта в снимке коллекции
+// @Description Печать карты в одной отделке и количество ее копий во всех зонах на момент снимка; пустая отделка — nonfoil
+type SnapshotCard struct {
+	ScryfallID string `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name       string `json:"name,omitempty" example:"Lightning Bolt"`
+	Finish     string `json:"finish,omitempty" example:"foil"`
+	Count      int    `json:"count" example:"4"`
+}
+
+// SnapshotDiff — разница между снимками коллекции
+// @Description Карты, которые появились, пропали или изменили количество.
+// @Description Пустой to означает текущее состояние коллекции
+type SnapshotDiff struct {
+	From    string     `json:"from" example:"64a9b66b2db8b91234a6e8e5"`
+	To      string     `json:"to,omitempty" example:"64a9b66b2db8b91234a6e8e6"`
+	Added   []CardDiff `json:"added"`
+	Removed []CardDiff `json:"removed"`
+	Changed []CardDiff `json:"changed"`
+}
+
+// CardDiff — изменение количества карты
+// @Description Количество копий печати карты в одной отделке до и после; delta — разница.
+// @Description Смена отделки — это удаление в одной отделке и добавление в другой
+type CardDiff struct {
+	ScryfallID string `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name       string `json:"name,omitempty" example:"Lightning Bolt"`
+	Finish     string `json:"finish,omitempty" example:"foil"`
+	Before     int    `json:"before" example:"2"`
+	After      int    `json:"after" example:"4"`
+	Delta      int    `json:"delta" example:"2"`
+}
+
+// Collection kinds.
+const (
+	KindBinder   = "binder"
+	KindDeck     = "deck"
+	KindCube     = "cube"
+	KindTrade    = "trade"
+	KindWishlist = "wishlist"
+)
+
+// Форматы колод
+const (
+	FormatStandard  = "standard"
+	FormatPioneer   = "pioneer"
+	FormatModern    = "modern"
+	FormatLegacy    = "legacy"
+	FormatVintage   = "vintage"
+	FormatPauper    = "pauper"
+	FormatCommander = "commander"
+)
+
+// Правила, которые может нарушить колода
+const (
+	DeckRuleSize          = "deck_size"
+	DeckRuleSideboard     = "sideboard_size"
+	DeckRuleCommander     = "commander"
+	DeckRuleCompanion     = "companion"
+	DeckRuleCopies        = "copies"
+	DeckRuleRestricted    = "restricted"
+	DeckRuleBanned        = "banned"
+	DeckRuleNotLegal      = "not_legal"
+	DeckRuleColorIdentity = "color_identity"
+)
+
+// DeckValidation — проверка колоды по правилам ее формата
+// @Description Размер колоды и сайдборда, лимит копий, синглтон, командиры и их цветовая идентичность,
+// @Description запрещенные и ограниченные карты. mainboard считает и командиров; карты из maybeboard не проверяются.
+// @Description unchecked — карты, которых нет в каталоге: для них известны только размер и копии
+// @example { "format": "modern", "legal": false, "mainboard": 58, "sideboard": 15, "problems": [{ "rule": "deck_size", "message": "Deck has 58 cards, modern needs at least 60" }] }
+type DeckValidation struct {
+	Format        string        `json:"format" example:"modern"`
+	Legal         bool          `json:"legal" example:"false"`
+	Mainboard     int           `json:"mainboard" example:"58"`
+	Sideboard     int           `json:"sideboard" example:"15"`
+	Commanders    []string      `json:"commanders,omitempty" example:"Kenrith, the Returned King"`
+	ColorIdentity []string      `json:"color_identity,omitempty" example:"W"`
+	Problems      []DeckProblem `json:"problems"`
+	Unchecked     []string      `json:"unchecked,omitempty" example:"Llanowar Elves"`
+}
+
+// DeckProblem — нарушенное правило формата
+// @Description rule — одно из deck_size, sideboard_size, commander, companion, copies, restricted, banned,
+// @Description not_legal, color_identity; cards — карты, которые его нарушают
+type DeckProblem struct {
+	Rule    string   `json:"rule" example:"copies"`
+	Message string   `json:"message" example:"At most 4 copies of a card are allowed"`
+	Cards   []string `json:"cards,omitempty" example:"Lightning Bolt"`
+}
+
+// BuildabilityRequest — запрос для проверки, можно ли собрать колоду из своих карт
+// @Description Колода задается коллекцией (collection_id) или списком (decklist) — одним из двух.
+// @Description Список — по карте в строке: "4 Lightning Bolt" или "1 Lightning Bolt (M10) 146";
+// @Description секции Sideboard, Commander, Maybeboard и префикс "SB:" понимаются, карты из maybeboard не учитываются.
+// @Description any_printing засчитывает любую печать карты, а не только указанную; строки без печати подходят под любую
+// @example { "decklist": "4 Lightning Bolt\n20 Mountain", "any_printing": true }
+type BuildabilityRequest struct {
+	CollectionID string `json:"collection_id,omitempty" example:"64a9b66b2db8b91234a6e8e3"`
+	Decklist     string `json:"decklist,omitempty" example:"4 Lightning Bolt"`
+	AnyPrinting  bool   `json:"any_printing,omitempty" example:"true"`
+}
+
+// DeckBuildability — чего не хватает, чтобы собрать колоду
+// @Description Карты колоды, сколько их копий нашлось в других коллекциях пользователя (кроме коллекций типа wishlist)
+// @Description и сколько не хватает. estimated_cost — цена недостающих копий по ценам Scryfall в долларах,
+// @Description unpriced — недостающие карты без известной цены
+// @example { "any_printing": true, "buildable": false, "needed": 60, "covered": 56, "missing": 4, "estimated_cost": 7.96, "currency": "usd" }
+type DeckBuildability struct {
+	AnyPrinting   bool               `json:"any_printing" example:"true"`
+	Buildable     bool               `json:"buildable" example:"false"`
+	Needed        int                `json:"needed" example:"60"`
+	Covered       int                `json:"covered" example:"56"`
+	Missing       int                `json:"missing" example:"4"`
+	EstimatedCost float64            `json:"estimated_cost" example:"7.96"`
+	Currency      string             `json:"currency" example:"usd"`
+	Unpriced      []string           `json:"unpriced,omitempty" example:"Llanowar Elves"`
+	Cards         []BuildabilityCard `json:"cards"`
+}
+
+// BuildabilityCard — карта колоды и ее копии в коллекциях
+// @Description sources — из каких коллекций и каких печатей берутся копии; price — цена одной недостающей копии.
+// @Description Сначала берутся копии, зарезервированные для этой колоды, затем свободные, затем зарезервированные для других колод
+type BuildabilityCard struct {
+	ScryfallID string       `json:"scryfall_id,omitempty" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name       string       `json:"name" example:"Lightning Bolt"`
+	Needed     int          `json:"needed" example:"4"`
+	Covered    int          `json:"covered" example:"2"`
+	Missing    int          `json:"missing" example:"2"`
+	Price      float64      `json:"price,omitempty" example:"1.99"`
+	Sources    []CardSource `json:"sources"`
+}
+
+// CardSource — копии карты в одной из коллекций пользователя
+// @Description reserved — копии зарезервированы для проверяемой колоды. deck_id и deck_name — колода,
+// @Description для которой зарезервированы копии: карту нужно забрать из нее
+type CardSource struct {
+	CollectionID   string `json:"collection_id" example:"64a9b66b2db8b91234a6e8e4"`
+	CollectionName string `json:"collection_name" example:"Red binder"`
+	ScryfallID     string `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Count          int    `json:"count" example:"2"`
+	Reserved       bool   `json:"reserved,omitempty" example:"false"`
+	DeckID         string `json:"deck_id,omitempty" example:"64a9b66b2db8b91234a6e8e5"`
+	DeckName       string `json:"deck_name,omitempty" example:"Krenko"`
+}
+
+// ReserveCardRequest — запрос для резервирования копий карты для колоды
+// @Description Резервирует копии печати из коллекции-источника (не колоды и не wishlist) для колоды, в которой есть карта.
+// @Description Для колоды нельзя зарезервировать больше копий, чем в ней есть; из источника — можно, это будет конфликт
+// @example { "scryfall_id": "e3285e6b-3e79-4d7c-bf96-d920f973b122", "source_collection_id": "64a9b66b2db8b91234a6e8e4", "count": 1 }
+type ReserveCardRequest struct {
+	ScryfallID         string `json:"scryfall_id" binding:"required" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	SourceCollectionID string `json:"source_collection_id" binding:"required" example:"64a9b66b2db8b91234a6e8e4"`
+	Count              int    `json:"count" binding:"required,min=1" example:"1"`
+}
+
+// Allocation — копии карты, зарезервированные для колоды
+// @Description Копии печати из коллекции-источника, которые физически лежат в колоде
+type Allocation struct {
+	ID                 string    `json:"id" example:"66f1c2a79b1e8d001c8e4f62"`
+	CollectionID       string    `json:"collection_id" example:"64a9b66b2db8b91234a6e8e5"`
+	SourceCollectionID string    `json:"source_collection_id" example:"64a9b66b2db8b91234a6e8e4"`
+	ScryfallID         string    `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name               string    `json:"name,omitempty" example:"Sol Ring"`
+	Count              int       `json:"count" example:"1"`
+	CreatedAt          time.Time `json:"created_at" example:"2025-01-02T15:04:05Z"`
+}
+
+// AllocationConflict — карта, зарезервированная больше раз, чем есть копий
+// @Description owned — копий печати в коллекции-источнике, allocated — зарезервировано для колод, over — лишних резервов.
+// @Description Резерв колоды не превышает число копий карты в ней
+type AllocationConflict struct {
+	SourceCollectionID   string          `json:"source_collection_id" example:"64a9b66b2db8b91234a6e8e4"`
+	SourceCollectionName string          `json:"source_collection_name" example:"Commander staples"`
+	ScryfallID           string          `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name                 string          `json:"name,omitempty" example:"Sol Ring"`
+	Owned                int             `json:"owned" example:"2"`
+	Allocated            int             `json:"allocated" example:"3"`
+	Over                 int             `json:"over" example:"1"`
+	Decks                []AllocatedDeck `json:"decks"`
+}
+
+// AllocatedDeck — колода, для которой зарезервированы копии
+type AllocatedDeck struct {
+	CollectionID string `json:"collection_id" example:"64a9b66b2db8b91234a6e8e5"`
+	Name         string `json:"name" example:"Krenko"`
+	AllocationID string `json:"allocation_id" example:"66f1c2a79b1e8d001c8e4f62"`
+	Count        int    `json:"count" example:"1"`
+}
+
+// CollectionValueQuery — параметры оценки коллекции
+// @Description currency — usd, eur, tix или rub, по умолчанию валюта из настроек пользователя или usd;
+// @Description top оставляет в списке только самые дорогие карты
+type CollectionValueQuery struct {
+	Currency string `form:"currency" json:"currency,omitempty" example:"usd"`
+	Top      int    `form:"top" json:"top,omitempty" binding:"omitempty,min=1" example:"10"`
+}
+
+// CollectionValue — стоимость коллекции
+// @Description Стоимость коллекции по последним известным ценам каждой печати с учетом отделки копий.
+// @Description total, priced_count и unpriced_count считаются по всей коллекции, даже если задан top;
+// @Description cards — оцененные карты, самые дорогие первыми, unpriced — имена (или Scryfall ID) карт без известной цены.
+// @Description priced_at — день самой свежей из использованных цен. Цены в rub пересчитываются из usd по последнему курсу,
+// @Description rates_date — день курса, если цены пересчитывались
+// @example { "collection_id": "64a9b66b2db8b91234a6e8e3", "currency": "usd", "total": 152.4, "priced_count": 98, "unpriced_count": 2 }
+type CollectionValue struct {
+	CollectionID  string      `json:"collection_id" example:"64a9b66b2db8b91234a6e8e3"`
+	Currency      string      `json:"currency" example:"usd"`
+	Total         float64     `json:"total" example:"152.4"`
+	PricedCount   int         `json:"priced_count" example:"98"`
+	UnpricedCount int         `json:"unpriced_count" example:"2"`
+	PricedAt      *time.Time  `json:"priced_at,omitempty"`
+	Cards         []CardValue `json:"cards"`
+	Unpriced      []string    `json:"unpriced,omitempty" example:"Llanowar Elves"`
+	RatesDate     *time.Time  `json:"rates_date,omitempty"`
+}
+
+// CardValue — стоимость копий карты в коллекции
+// @Description unit_price — цена одной копии с отделкой карты, value — цена всех копий, priced_at — день цены
+type CardValue struct {
+	ScryfallID string    `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name       string    `json:"name,omitempty" example:"Sol Ring"`
+	Finish     string    `json:"finish,omitempty" example:"foil"`
+	Count      int       `json:"count" example:"2"`
+	UnitPrice  float64   `json:"unit_price" example:"3.5"`
+	Value      float64   `json:"value" example:"7"`
+	PricedAt   time.Time `json:"priced_at"`
+}
+
+// Интервалы точек истории стоимости
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// ValueHistoryQuery — параметры истории стоимости
+// @Description interval — day (по умолчанию), week или month; from и to — даты вида 2025-03-01.
+// @Description По умолчанию история за последние 30 дней, 12 недель или 12 месяцев до сегодня.
+// @Description movers — сколько карт с самым большим изменением стоимости вернуть, по умолчанию 5
+type ValueHistoryQuery struct {
+	Currency string    `form:"currency" json:"currency,omitempty" example:"usd"`
+	Interval string    `form:"interval" json:"interval,omitempty" example:"week"`
+	From     time.Time `form:"from" time_format:"2006-01-02" time_utc:"1" json:"from,omitempty"`
+	To       time.Time `form:"to" time_format:"2006-01-02" time_utc:"1" json:"to,omitempty"`
+	Movers   int       `form:"movers" json:"movers,omitempty" binding:"omitempty,min=1,max=50" example:"5"`
+}
+
+// ValueHistory — история стоимости коллекции или всех коллекций пользователя
+// @Description Как менялась стоимость карт: точка — стоимость карт, которые были на конец дня, недели (с понедельника)
+// @Description или месяца, по ценам того времени; date — первый день периода. Состав карт восстанавливается по истории
+// @Description изменений, карты, добавленные до её появления, считаются бывшими всегда; коллекции в корзине не учитываются.
+// @Description Цена карты, которая не обновлялась больше 30 дней, не учитывается.
+// @Description change и change_percent — изменение от первой точки до последней, movers — карты с самым большим изменением.
+// @Description Цены во всех точках пересчитываются по последнему курсу, rates_date — его день
+// @example { "currency": "usd", "interval": "week", "change": 12.5, "change_percent": 8.2 }
+type ValueHistory struct {
+	CollectionID  string       `json:"collection_id,omitempty" example:"64a9b66b2db8b91234a6e8e3"`
+	Currency      string       `json:"currency" example:"usd"`
+	Interval      string       `json:"interval" example:"week"`
+	From          time.Time    `json:"from"`
+	To            time.Time    `json:"to"`
+	Points        []ValuePoint `json:"points"`
+	Change        float64      `json:"change" example:"12.5"`
+	ChangePercent *float64     `json:"change_percent,omitempty" example:"8.2"`
+	Movers        []CardMove   `json:"movers"`
+	RatesDate     *time.Time   `json:"rates_date,omitempty"`
+}
+
+// ValuePoint — стоимость карт на конец периода
+// @Description change и change_percent — изменение от предыдущей точки; процента нет, если прежняя стоимость нулевая
+type ValuePoint struct {
+	Date          time.Time `json:"date"`
+	Value         float64   `json:"value" example:"152.4"`
+	PricedCount   int       `json:"priced_count" example:"98"`
+	Change        float64   `json:"change" example:"-1.2"`
+	ChangePercent *float64  `json:"change_percent,omitempty" example:"-0.78"`
+}
+
+// CardMove — изменение стоимости копий карты
+// @Description start_price и end_price — цена одной копии в первой и последней точках, change — изменение цены копий, которые есть в последней точке
+type CardMove struct {
+	ScryfallID    string   `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name          string   `json:"name,omitempty" example:"Sol Ring"`
+	Finish        string   `json:"finish,omitempty" example:"foil"`
+	Count         int      `json:"count" example:"2"`
+	StartPrice    float64  `json:"start_price" example:"1.5"`
+	EndPrice      float64  `json:"end_price" example:"2"`
+	Change        float64  `json:"change" example:"1"`
+	ChangePercent *float64 `json:"change_percent,omitempty" example:"33.33"`
+}
+
+// ProfitLossQuery — параметры отчета о прибыли и убытках
+// @Description currency — usd, eur или rub, по умолчанию валюта из настроек пользователя или usd
+type ProfitLossQuery struct {
+	Currency string `form:"currency" json:"currency,omitempty" example:"usd"`
+}
+
+// ProfitLoss — прибыль и убытки коллекции или всех коллекций пользователя
+// @Description Себестоимость полученных копий против их стоимости по последним известным ценам.
+// @Description cost_basis, market_value и unrealized_gain считаются по копиям, у которых известны и себестоимость, и цена;
+// @Description realized_gain — по копиям, которые удалены из коллекций или отданы в обмен, по цене дня удаления.
+// @Description ordered_cost — стоимость заказанных, но еще не полученных копий. Покупки в другой валюте пересчитываются
+// @Description по последнему курсу, rates_date — его день; без курса такие покупки не учитываются.
+// @Description collections — отчет по каждой коллекции, кроме вишлистов, только в отчете по всем коллекциям;
+// @Description untracked — карты без себестоимости в валюте отчета, unpriced — карты с себестоимостью, но без цены
+// @example { "currency": "usd", "cost_basis": 120, "market_value": 152.4, "unrealized_gain": 32.4, "unrealized_gain_percent": 27, "realized_gain": 4.5 }
+type ProfitLoss struct {
+	CollectionID          string                 `json:"collection_id,omitempty" example:"64a9b66b2db8b91234a6e8e3"`
+	Currency              string                 `json:"currency" example:"usd"`
+	CostBasis             float64                `json:"cost_basis" example:"120"`
+	MarketValue           float64                `json:"market_value" example:"152.4"`
+	UnrealizedGain        float64                `json:"unrealized_gain" example:"32.4"`
+	UnrealizedGainPercent *float64               `json:"unrealized_gain_percent,omitempty" example:"27"`
+	RealizedGain          float64                `json:"realized_gain" example:"4.5"`
+	OrderedCost           float64                `json:"ordered_cost" example:"15"`
+	Collections           []CollectionProfitLoss `json:"collections,omitempty"`
+	Cards                 []CardProfitLoss       `json:"cards"`
+	Realized              []RealizedGain         `json:"realized"`
+	Untracked             []string               `json:"untracked,omitempty" example:"Llanowar Elves"`
+	Unpriced              []string               `json:"unpriced,omitempty" example:"Collector Ouphe"`
+	RatesDate             *time.Time             `json:"rates_date,omitempty"`
+}
+
+// CollectionProfitLoss — прибыль и убытки одной коллекции
+// @Description Итоги коллекции в отчете по всем коллекциям пользователя
+type CollectionProfitLoss struct {
+	CollectionID          string   `json:"collection_id" example:"64a9b66b2db8b91234a6e8e3"`
+	Name                  string   `json:"name" example:"Red binder"`
+	CostBasis             float64  `json:"cost_basis" example:"120"`
+	MarketValue           float64  `json:"market_value" example:"152.4"`
+	UnrealizedGain        float64  `json:"unrealized_gain" example:"32.4"`
+	UnrealizedGainPercent *float64 `json:"unrealized_gain_percent,omitempty" example:"27"`
+	RealizedGain          float64  `json:"realized_gain" example:"4.5"`
+	OrderedCost           float64  `json:"ordered_cost" example:"15"`
+}
+
+// CardProfitLoss — прибыль и убытки копий карты
+// @Description count — копии с известной себестоимостью, unit_cost — средняя цена покупки копии,
+// @Description market_value — их стоимость по последней известной цене. Карты с самым большим изменением первыми
+type CardProfitLoss struct {
+	CollectionID string   `json:"collection_id" example:"64a9b66b2db8b91234a6e8e3"`
+	ScryfallID   string   `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name         string   `json:"name,omitempty" example:"Sol Ring"`
+	Finish       string   `json:"finish,omitempty" example:"foil"`
+	Count        int      `json:"count" example:"2"`
+	UnitCost     float64  `json:"unit_cost" example:"1.5"`
+	CostBasis    float64  `json:"cost_basis" example:"3"`
+	MarketValue  float64  `json:"market_value" example:"4"`
+	Gain         float64  `json:"gain" example:"1"`
+	GainPercent  *float64 `json:"gain_percent,omitempty" example:"33.33"`
+}
+
+// RealizedGain — прибыль от копий, которые ушли из коллекций
+// @Description Копии с известной себестоимостью, удаленные из коллекции или отданные в обмен (source — trade).
+// @Description proceeds — их стоимость по цене дня удаления; без известной цены proceeds и gain нет.
+// @Description Перенос между коллекциями и отмененные изменения не считаются. Новые первыми
+type RealizedGain struct {
+	CollectionID string    `json:"collection_id" example:"64a9b66b2db8b91234a6e8e3"`
+	ScryfallID   string    `json:"scryfall_id" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	Name         string    `json:"name,omitempty" example:"Sol Ring"`
+	Finish       string    `json:"finish,omitempty" example:"foil"`
+	Count        int       `json:"count" example:"1"`
+	Date         time.Time `json:"date"`
+	Source       string    `json:"source" example:"trade"`
+	CostBasis    float64   `json:"cost_basis" example:"1.5"`
+	Proceeds     *float64  `json:"proceeds,omitempty" example:"3"`
+	Gain         *float64  `json:"gain,omitempty" example:"1.5"`
+	GainPercent  *float64  `json:"gain_percent,omitempty" example:"100"`
 }
 
 // ErrorResponse — стандартная структура ошибки
@@ -29,3 +503,12 @@ type ErrorResponse struct {
 	Message string `json:"message" example:"unauthorized"`
 	Status  int    `json:"status,omitempty"` // Optional, can be used to indicate HTTP status code
 }
+
+// ExchangeRates — курсы валют
+// @Description Сколько единиц каждой валюты стоит одна единица base на день date; курсы обновляются вместе с ценами
+// @example { "base": "usd", "date": "2025-03-01T00:00:00Z", "rates": { "usd": 1, "eur": 0.92, "rub": 88.5 } }
+type ExchangeRates struct {
+	Base  string             `json:"base" example:"usd"`
+	Date  time.Time          `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
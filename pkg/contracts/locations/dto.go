@@ -0,0 +1,82 @@
+package locations
+
+import "time"
+
+// Виды мест хранения
+const (
+	KindBox    = "box"
+	KindBinder = "binder"
+)
+
+// CreateLocationRequest — запрос для создания места хранения
+// @Description Коробка (box) или альбом (binder). Имя уникально у пользователя
+// @example { "name": "Red box", "kind": "box" }
+type CreateLocationRequest struct {
+	Name        string `json:"name" binding:"required" example:"Red box"`
+	Kind        string `json:"kind" binding:"required,oneof=box binder" example:"box"`
+	Description string `json:"description,omitempty" binding:"max=1000" example:"Under the desk"`
+}
+
+// StorageLocation — место хранения
+// @Description Коробка или альбом, в которых лежат карты
+type StorageLocation struct {
+	ID          string    `json:"id" example:"66f1c2a79b1e8d001c8e4f70"`
+	Name        string    `json:"name" example:"Red box"`
+	Kind        string    `json:"kind" example:"box"`
+	Description string    `json:"description,omitempty" example:"Under the desk"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-09-23T12:00:00Z"`
+}
+
+// Location — место карты или коллекции
+// @Description Место хранения и, если известны, раздел, страница и ячейка. Страницы и ячейки есть только у альбомов.
+// @Description location_name заполняется в ответах, где известно место хранения
+// @example { "location_id": "66f1c2a79b1e8d001c8e4f70", "section": "Red" }
+type Location struct {
+	LocationID   string `json:"location_id" example:"66f1c2a79b1e8d001c8e4f70"`
+	LocationName string `json:"location_name,omitempty" example:"Red box"`
+	Section      string `json:"section,omitempty" example:"Red"`
+	Page         int    `json:"page,omitempty" example:"3"`
+	Slot         int    `json:"slot,omitempty" example:"5"`
+}
+
+// LocationCardsQuery — параметры просмотра места хранения
+// @Description Только карты раздела или страницы; пустые параметры не фильтруют
+type LocationCardsQuery struct {
+	Section string `form:"section" json:"section,omitempty" example:"Red"`
+	Page    int    `form:"page" json:"page,omitempty" binding:"omitempty,min=1" example:"3"`
+}
+
+// LocationCards — содержимое места хранения
+// @Description Карты места хранения по разделам, страницам и ячейкам
+type LocationCards struct {
+	Location StorageLocation `json:"location"`
+	Count    int             `json:"count" example:"12"`
+	Cards    []LocatedCard   `json:"cards"`
+}
+
+// LocatedCard — карта в месте хранения
+// @Description Карта коллекции и ее место. inherited — у карты нет своего места, она лежит там же, где коллекция
+type LocatedCard struct {
+	CollectionID   string   `json:"collection_id" example:"64a9b66b2db8b91234a6e8e4"`
+	CollectionName string   `json:"collection_name" example:"Trades"`
+	ScryfallID     string   `json:"scryfall_id" example:"0000579f-7b35-4ed3-b44c-db2a538066fe"`
+	Name           string   `json:"name" example:"Fury Sliver"`
+	Count          int      `json:"count" example:"2"`
+	Location       Location `json:"location"`
+	Inherited      bool     `json:"inherited,omitempty" example:"false"`
+}
+
+// RelocateCardsRequest — запрос для перемещения карт коллекции
+// @Description Перемещает карты scryfall_ids, а без них — все карты коллекции. Без location карты
+// @Description теряют свое место и лежат там же, где коллекция
+// @example { "scryfall_ids": ["0000579f-7b35-4ed3-b44c-db2a538066fe"], "location": { "location_id": "66f1c2a79b1e8d001c8e4f70", "page": 3, "slot": 5 } }
+type RelocateCardsRequest struct {
+	ScryfallIDs []string  `json:"scryfall_ids,omitempty"`
+	Location    *Location `json:"location,omitempty"`
+}
+
+// RelocateCardsResponse — результат перемещения карт
+// @Description Сколько карт перемещено
+type RelocateCardsResponse struct {
+	Relocated int `json:"relocated" example:"3"`
+}
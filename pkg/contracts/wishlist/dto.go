@@ -0,0 +1,66 @@
+package wishlist
+
+import "time"
+
+// Отделки печатей карт
+const (
+	FinishNonfoil = "nonfoil"
+	FinishFoil    = "foil"
+	FinishEtched  = "etched"
+)
+
+// AddEntryRequest — запрос для добавления карты в список желаний
+// @Description Карта задается печатью (scryfall_id) или, с any_printing либо oracle_id, любой своей печатью.
+// @Description Карте, которой нет в каталоге, для any_printing нужно имя. finish — nonfoil, foil или etched;
+// @Description priority — от 1 до 5, по умолчанию 3; max_price 0 — без ограничения цены.
+// @Description currency — валюта max_price: usd, eur или rub, по умолчанию валюта из настроек пользователя
+// @example { "scryfall_id": "e3285e6b-3e79-4d7c-bf96-d920f973b122", "any_printing": true, "quantity": 4, "max_price": 2.5, "currency": "usd", "priority": 4 }
+type AddEntryRequest struct {
+	ScryfallID  string  `json:"scryfall_id,omitempty" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	OracleID    string  `json:"oracle_id,omitempty" example:"4457ed35-7c10-48c8-9776-456485fdf070"`
+	Name        string  `json:"name,omitempty" example:"Lightning Bolt"`
+	AnyPrinting bool    `json:"any_printing,omitempty" example:"true"`
+	Quantity    int     `json:"quantity" binding:"required,min=1" example:"4"`
+	MaxPrice    float64 `json:"max_price,omitempty" example:"2.5"`
+	Currency    string  `json:"currency,omitempty" example:"usd"`
+	Finish      string  `json:"finish,omitempty" example:"nonfoil"`
+	Priority    int     `json:"priority,omitempty" example:"4"`
+}
+
+// UpdateEntryRequest — запрос для изменения записи списка желаний
+// @Description Меняет только переданные поля; пустая строка в finish означает любую отделку
+// @example { "quantity": 2, "priority": 5 }
+type UpdateEntryRequest struct {
+	Quantity *int     `json:"quantity,omitempty" example:"2"`
+	MaxPrice *float64 `json:"max_price,omitempty" example:"3"`
+	Currency *string  `json:"currency,omitempty" example:"eur"`
+	Finish   *string  `json:"finish,omitempty" example:"foil"`
+	Priority *int     `json:"priority,omitempty" example:"5"`
+}
+
+// Entry — карта в списке желаний
+// @Description Карта, которую пользователь хочет получить, и сколько ее копий нужно.
+// @Description В списке max_price пересчитывается в валюту из настроек пользователя по последнему курсу,
+// @Description rates_date — день курса, если цена пересчитывалась
+type Entry struct {
+	ID          string     `json:"id" example:"66f1c2a79b1e8d001c8e4f61"`
+	ScryfallID  string     `json:"scryfall_id,omitempty" example:"e3285e6b-3e79-4d7c-bf96-d920f973b122"`
+	OracleID    string     `json:"oracle_id,omitempty" example:"4457ed35-7c10-48c8-9776-456485fdf070"`
+	Name        string     `json:"name,omitempty" example:"Lightning Bolt"`
+	AnyPrinting bool       `json:"any_printing" example:"true"`
+	Quantity    int        `json:"quantity" example:"4"`
+	MaxPrice    float64    `json:"max_price,omitempty" example:"2.5"`
+	Currency    string     `json:"currency,omitempty" example:"usd"`
+	RatesDate   *time.Time `json:"rates_date,omitempty"`
+	Finish      string     `json:"finish,omitempty" example:"nonfoil"`
+	Priority    int        `json:"priority" example:"4"`
+	CreatedAt   time.Time  `json:"created_at" example:"2025-01-02T15:04:05Z"`
+}
+
+// MissingEntry — карта, которой не хватает до списка желаний
+// @Description owned — сколько копий уже есть в коллекциях пользователя, missing — сколько еще нужно
+type MissingEntry struct {
+	Entry
+	Owned   int `json:"owned" example:"1"`
+	Missing int `json:"missing" example:"3"`
+}
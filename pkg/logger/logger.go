@@ -1,6 +1,10 @@
 package logger
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -14,32 +18,63 @@ type Logger interface {
 	Sync() error
 }
 
+// Leveler is implemented by loggers whose level can be changed at runtime.
+// Loggers derived through With share the level of their parent.
+type Leveler interface {
+	Level() string
+	SetLevel(level string) error
+}
+
+// Config describes how the logger is built.
+// Level is one of debug, info, warn, error; empty means the mode default.
+type Config struct {
+	Production    bool     `mapstructure:"production"`
+	Level         string   `mapstructure:"level"`
+	SensitiveKeys []string `mapstructure:"sensitive_keys"`
+}
+
 type zapLogger struct {
-	l *zap.Logger
+	l     *zap.Logger
+	level zap.AtomicLevel
 }
 
 func NewZapLogger(isProduction bool) (Logger, error) {
-	var cfg zap.Config
-	var err error
-
-	if isProduction {
-		cfg = zap.NewProductionConfig()
-		cfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-		cfg.EncoderConfig.TimeKey = "timestamp"
-		cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-		cfg.DisableStacktrace = true
-		cfg.EncoderConfig.StacktraceKey = ""
+	return New(Config{Production: isProduction})
+}
+
+// New builds a zap backed logger from cfg.
+// SensitiveKeys are added to the global redaction list.
+func New(cfg Config) (Logger, error) {
+	var zcfg zap.Config
+
+	if cfg.Production {
+		zcfg = zap.NewProductionConfig()
+		zcfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		zcfg.EncoderConfig.TimeKey = "timestamp"
+		zcfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		zcfg.DisableStacktrace = true
+		zcfg.EncoderConfig.StacktraceKey = ""
 	} else {
-		cfg = zap.NewDevelopmentConfig()
-		cfg.DisableStacktrace = true
-		cfg.EncoderConfig.StacktraceKey = ""
+		zcfg = zap.NewDevelopmentConfig()
+		zcfg.DisableStacktrace = true
+		zcfg.EncoderConfig.StacktraceKey = ""
 	}
 
-	log, err := cfg.Build()
+	if cfg.Level != "" {
+		lvl, err := parseLevel(cfg.Level)
+		if err != nil {
+			return nil, err
+		}
+		zcfg.Level.SetLevel(lvl)
+	}
+
+	RegisterSensitiveKeys(cfg.SensitiveKeys...)
+
+	log, err := zcfg.Build()
 	if err != nil {
 		return nil, err
 	}
-	return &zapLogger{l: log}, nil
+	return &zapLogger{l: log, level: zcfg.Level}, nil
 }
 
 func (zl *zapLogger) Info(msg string, fields ...Field) {
@@ -59,13 +94,34 @@ func (zl *zapLogger) Debug(msg string, fields ...Field) {
 }
 
 func (zl *zapLogger) With(fields ...Field) Logger {
-	return &zapLogger{l: zl.l.With(toZapFields(fields)...)}
+	return &zapLogger{l: zl.l.With(toZapFields(fields)...), level: zl.level}
 }
 
 func (zl *zapLogger) Sync() error {
 	return zl.l.Sync()
 }
 
+func (zl *zapLogger) Level() string {
+	return zl.level.Level().String()
+}
+
+func (zl *zapLogger) SetLevel(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	zl.level.SetLevel(lvl)
+	return nil
+}
+
+func parseLevel(level string) (zapcore.Level, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(strings.ToLower(level))); err != nil {
+		return lvl, fmt.Errorf("unknown log level %q", level)
+	}
+	return lvl, nil
+}
+
 type Field struct {
 	zapField zap.Field
 }
@@ -82,10 +138,32 @@ func Int(key string, value int) Field {
 	return Field{zapField: zap.Int(key, value)}
 }
 
+func Int64(key string, value int64) Field {
+	return Field{zapField: zap.Int64(key, value)}
+}
+
+func Bool(key string, value bool) Field {
+	return Field{zapField: zap.Bool(key, value)}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{zapField: zap.Duration(key, value)}
+}
+
+func Time(key string, value time.Time) Field {
+	return Field{zapField: zap.Time(key, value)}
+}
+
+// Any picks the best encoding for value.
+// Strings and errors passed through Any are still redacted.
+func Any(key string, value any) Field {
+	return Field{zapField: zap.Any(key, value)}
+}
+
 func toZapFields(fields []Field) []zap.Field {
 	zapFields := make([]zap.Field, len(fields))
 	for i, f := range fields {
-		zapFields[i] = f.zapField
+		zapFields[i] = redactField(f.zapField)
 	}
 	return zapFields
 }
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedValue replaces the value of a sensitive field.
+const RedactedValue = "[REDACTED]"
+
+// jwtPattern matches compact JWS values: three base64url segments,
+// the first one being a JSON header ("{"...) encoded as "eyJ".
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+var sensitiveKeys = struct {
+	sync.RWMutex
+	keys map[string]struct{}
+}{
+	keys: map[string]struct{}{
+		"token":         {},
+		"token_auth":    {},
+		"jwt":           {},
+		"authorization": {},
+		"password":      {},
+		"secret":        {},
+		"jwt_secret":    {},
+		"bot_token":     {},
+	},
+}
+
+// RegisterSensitiveKeys marks field keys whose values must never be logged.
+// Keys are matched case-insensitively.
+func RegisterSensitiveKeys(keys ...string) {
+	sensitiveKeys.Lock()
+	defer sensitiveKeys.Unlock()
+
+	for _, k := range keys {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			sensitiveKeys.keys[k] = struct{}{}
+		}
+	}
+}
+
+// IsSensitiveKey reports whether values logged under key are redacted.
+func IsSensitiveKey(key string) bool {
+	sensitiveKeys.RLock()
+	defer sensitiveKeys.RUnlock()
+
+	_, ok := sensitiveKeys.keys[strings.ToLower(key)]
+	return ok
+}
+
+// Redact masks every JWT-shaped substring of s.
+func Redact(s string) string {
+	if !strings.Contains(s, "eyJ") {
+		return s
+	}
+	return jwtPattern.ReplaceAllString(s, RedactedValue)
+}
+
+func redactField(f zap.Field) zap.Field {
+	if IsSensitiveKey(f.Key) {
+		return zap.String(f.Key, RedactedValue)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		f.String = Redact(f.String)
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			if msg := Redact(err.Error()); msg != err.Error() {
+				f.Interface = errors.New(msg)
+			}
+		}
+	case zapcore.StringerType, zapcore.ReflectType:
+		if s, ok := f.Interface.(string); ok {
+			return zap.String(f.Key, Redact(s))
+		}
+	}
+	return f
+}